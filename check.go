@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// runCheck validates the context docs of a project for CI and returns the exit code:
+// 0 when no problem is an error, 1 when one is, 2 when the check couldn't run
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	missing := fs.String("missing", groups.SeverityError, "`severity` of missing docs and required fields (error, warning or off)")
+	broken := fs.String("broken", groups.SeverityError, "`severity` of key files that don't exist")
+	stale := fs.String("stale", groups.SeverityError, "`severity` of docs whose key files changed since they were updated")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s check [flags] [path]\n\nChecks the context docs in .context-docs.md and exits 1 if a problem with severity error is found.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	severity := groups.DefaultCheckSeverity()
+	for _, f := range []struct {
+		value *string
+		kinds []string
+	}{
+		{missing, []string{groups.CheckMissingDoc, groups.CheckMissingFields}},
+		{broken, []string{groups.CheckBrokenKeyFile}},
+		{stale, []string{groups.CheckStale}},
+	} {
+		sev, err := groups.ParseSeverity(*f.value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		for _, kind := range f.kinds {
+			severity[kind] = sev
+		}
+	}
+
+	rootPath := "."
+	if fs.NArg() > 0 {
+		rootPath = fs.Arg(0)
+	}
+	report, err := groups.CheckRegistry(rootPath, severity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error checking context docs: %v\n", err)
+		return 2
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		// One problem per line, like compiler output, so CI can annotate it
+		for _, issue := range report.Issues {
+			fmt.Printf("%s: %s: %s (%s)\n", issue.Doc, issue.Severity, issue.Message, issue.Kind)
+		}
+		fmt.Printf("%d docs checked: %d errors, %d warnings\n", report.Docs, report.Errors, report.Warnings)
+	}
+
+	if report.Errors > 0 {
+		return 1
+	}
+	return 0
+}
@@ -1,11 +1,35 @@
 package main_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+	"github.com/connorleisz/contexTUI/internal/control"
+	"github.com/connorleisz/contexTUI/internal/frecency"
 	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/imports"
 )
 
+// writeFixture creates files (paths relative to dir, with forward slashes) and the
+// directories holding them
+func writeFixture(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func TestLoadContextDocRegistry(t *testing.T) {
 	registry, err := groups.LoadContextDocRegistry(".")
 	if err != nil {
@@ -49,3 +73,555 @@ func TestParseContextDoc(t *testing.T) {
 	t.Logf("Key Files: %v", doc.KeyFiles)
 	t.Logf("Missing: %v", doc.MissingFields)
 }
+
+func TestKeyFileRefs(t *testing.T) {
+	doc := groups.ContextDoc{
+		KeyFiles: []string{"internal/app/model.go", "../other-repo/src/api.ts", "/etc/hosts"},
+	}
+
+	refs := doc.KeyFileRefs("/work/contexTUI")
+	want := []string{"internal/app/model.go", "/work/other-repo/src/api.ts", "/etc/hosts"}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Errorf("ref %d: got %q, want %q", i, refs[i], want[i])
+		}
+	}
+
+	if groups.IsExternalKeyFile("/work/contexTUI", "internal/app/model.go") {
+		t.Error("in-root key file reported as external")
+	}
+	if !groups.IsExternalKeyFile("/work/contexTUI", "../other-repo/src/api.ts") {
+		t.Error("sibling repo key file not reported as external")
+	}
+}
+
+func TestApplyStructureAnnotation(t *testing.T) {
+	day1 := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 7)
+	original := "# Doc\n\nSome notes\n"
+
+	annotated := groups.ApplyStructureAnnotation(original, []string{"Category", "Key Files"}, day1)
+	if !strings.Contains(annotated, "missing: Category, Key Files") || !strings.Contains(annotated, "added: 2024-01-31") {
+		t.Fatalf("unexpected annotation:\n%s", annotated)
+	}
+	if again := groups.ApplyStructureAnnotation(annotated, []string{"Category", "Key Files"}, day2); again != annotated {
+		t.Errorf("re-applying the same fields changed the file:\n%s", again)
+	}
+
+	// Filling a field rewrites the block in place and keeps the original date
+	partial := groups.ApplyStructureAnnotation(annotated, []string{"Key Files"}, day2)
+	if !strings.Contains(partial, "missing: Key Files\nadded: 2024-01-31") || strings.Count(partial, groups.StructureAnnotationMarker) != 1 {
+		t.Errorf("unexpected updated annotation:\n%s", partial)
+	}
+
+	if done := groups.ApplyStructureAnnotation(partial, nil, day2); done != original {
+		t.Errorf("annotation not removed cleanly:\n%q", done)
+	}
+
+	// Legacy single-line tag is upgraded
+	legacy := "<!-- contexTUI: structure-needed -->\n" + original
+	if got := groups.RemoveStructureAnnotation(legacy); got != original {
+		t.Errorf("legacy tag not removed:\n%q", got)
+	}
+}
+
+func TestApplyAgentsSection(t *testing.T) {
+	section := groups.AgentsSectionStart + "\n## Context Docs\n\n- one\n" + groups.AgentsSectionEnd + "\n"
+
+	created := groups.ApplyAgentsSection("", section, "CLAUDE.md")
+	if !strings.HasPrefix(created, "# CLAUDE.md\n\n") || !strings.Contains(created, section) {
+		t.Fatalf("unexpected skeleton:\n%s", created)
+	}
+
+	// Hand-written content around the section is preserved and updates are idempotent
+	existing := "# Project\n\nBuild with make.\n"
+	appended := groups.ApplyAgentsSection(existing, section, "CLAUDE.md")
+	if !strings.HasPrefix(appended, existing) {
+		t.Fatalf("existing content not preserved:\n%s", appended)
+	}
+	if again := groups.ApplyAgentsSection(appended, section, "CLAUDE.md"); again != appended {
+		t.Errorf("re-applying the same section changed the file:\n%s", again)
+	}
+
+	updated := groups.ApplyAgentsSection(appended+"\nFooter\n", strings.Replace(section, "one", "two", 1), "CLAUDE.md")
+	if strings.Contains(updated, "- one") || !strings.Contains(updated, "- two") || !strings.HasSuffix(updated, "\nFooter\n") {
+		t.Errorf("section not replaced in place:\n%s", updated)
+	}
+}
+
+func TestAddKeyFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"doc.md":  "# Doc\n\n## Key Files\n\n- a.go - entry\n\n## Out of Scope\n\nNothing\n",
+		"bare.md": "# Bare\n",
+	})
+
+	added, err := groups.AddKeyFiles(root, "doc.md", []string{"a.go", "b.go"}, false)
+	if err != nil || added != 1 {
+		t.Fatalf("added %d (err %v), want 1", added, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(root, "doc.md"))
+	want := "# Doc\n\n## Key Files\n\n- a.go - entry\n- b.go\n\n## Out of Scope\n\nNothing\n"
+	if string(got) != want {
+		t.Errorf("unexpected doc:\n%s", got)
+	}
+
+	// A doc without the section gets one at the end
+	groups.AddKeyFiles(root, "bare.md", []string{"c.go"}, false)
+	got, _ = os.ReadFile(filepath.Join(root, "bare.md"))
+	if string(got) != "# Bare\n\n## Key Files\n\n- c.go\n" {
+		t.Errorf("unexpected doc:\n%s", got)
+	}
+}
+
+func TestRenameKeyFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"docs/doc.md":      "# Doc\n\n## Key Files\n\n- `pkg/a.go` - entry\n- pkg/sub/b.go\n- pkgx/c.go\n\n```\n- pkg/a.go\n```\n",
+		".context-docs.md": "## Active Docs\n\n- docs/doc.md (Architecture, Active)\n",
+	})
+
+	updated, err := groups.RenameKeyFiles(root, "pkg", "lib", []string{"docs/doc.md"}, false)
+	if err != nil || len(updated) != 1 || updated[0] != "docs/doc.md" {
+		t.Fatalf("updated %v (err %v), want [docs/doc.md]", updated, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(root, "docs", "doc.md"))
+	want := "# Doc\n\n## Key Files\n\n- `lib/a.go` - entry\n- lib/sub/b.go\n- pkgx/c.go\n\n```\n- pkg/a.go\n```\n"
+	if string(got) != want {
+		t.Errorf("unexpected doc:\n%s", got)
+	}
+
+	// Moving a doc rewrites its registry entry
+	os.Rename(filepath.Join(root, "docs"), filepath.Join(root, "guides"))
+	updated, _ = groups.RenameKeyFiles(root, "docs", "guides", []string{"docs/doc.md"}, false)
+	if len(updated) != 1 || updated[0] != ".context-docs.md" {
+		t.Errorf("updated %v, want [.context-docs.md]", updated)
+	}
+	got, _ = os.ReadFile(filepath.Join(root, ".context-docs.md"))
+	if string(got) != "## Active Docs\n\n- guides/doc.md (Architecture, Active)\n" {
+		t.Errorf("unexpected registry:\n%s", got)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"a.md":             "# A\n\n**Category:** Feature\n**Status:** Active\n",
+		"b.md":             "# B\n\n**Status:** Active\n",
+		".context-docs.md": "## Categories\n\n- Feature\n- Empty\n- Meta\n\n## Active Docs\n\n- a.md\n- b.md\n",
+	})
+
+	registry, err := groups.LoadContextDocRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, c := range registry.Categories {
+		ids = append(ids, c.ID)
+	}
+	if strings.Join(ids, ",") != "uncategorized,feature,empty,meta" {
+		t.Fatalf("categories %v, want registry order with empty categories kept", ids)
+	}
+
+	if _, err := registry.MoveDocs(root, []string{"b.md"}, "Empty", false); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filepath.Join(root, "b.md"))
+	if string(got) != "# B\n\n**Category:** Empty\n\n**Status:** Active\n" {
+		t.Errorf("unexpected doc after move:\n%s", got)
+	}
+
+	if err := registry.RenameCategory(root, "feature", "Features", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.DeleteCategory(root, "empty", "Features", false); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(filepath.Join(root, "b.md"))
+	if !strings.Contains(string(got), "**Category:** Features\n") {
+		t.Errorf("deleted category's doc not migrated:\n%s", got)
+	}
+	if len(registry.ByCategory["features"]) != 2 || registry.CategoryIndex("empty") >= 0 {
+		t.Errorf("unexpected grouping %v", registry.ByCategory)
+	}
+}
+
+func TestCheckRegistry(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"a.md":             "# A\n\n**Category:** Meta\n**Status:** Active\n\n## Description\n\nA.\n\n## Key Files\n\n- gone.go\n",
+		".context-docs.md": "## Active Docs\n\n- a.md\n- missing.md\n",
+	})
+
+	severity := groups.DefaultCheckSeverity()
+	report, err := groups.CheckRegistry(root, severity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Docs != 2 || report.Errors != 2 || len(report.Issues) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if report.Issues[0].Kind != groups.CheckBrokenKeyFile || report.Issues[1].Kind != groups.CheckMissingDoc {
+		t.Errorf("unexpected issues %+v", report.Issues)
+	}
+
+	severity[groups.CheckBrokenKeyFile] = groups.SeverityWarning
+	severity[groups.CheckMissingDoc] = groups.SeverityOff
+	report, _ = groups.CheckRegistry(root, severity)
+	if report.Errors != 0 || report.Warnings != 1 || len(report.Issues) != 1 {
+		t.Errorf("severities not applied: %+v", report)
+	}
+}
+
+func TestDocTags(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"a.md":             "# A\n\n**Tags:** auth, `#Logging`\n",
+		"b.md":             "# B\n\n**Tags:** logging\n",
+		".context-docs.md": "## Active Docs\n\n- a.md\n- b.md\n",
+	})
+
+	registry, err := groups.LoadContextDocRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(registry.Docs[0].Tags, ","); got != "auth,Logging" {
+		t.Errorf("tags %q, want auth,Logging", got)
+	}
+	if got := strings.Join(registry.Tags(), ","); got != "auth,Logging" {
+		t.Errorf("registry tags %q, want auth,Logging", got)
+	}
+	if !registry.Docs[1].HasTag("LOGGING") || registry.Docs[1].HasTag("auth") {
+		t.Error("HasTag should match tags ignoring case")
+	}
+}
+
+func TestNestedRegistries(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "packages", "api")
+	writeFixture(t, root, map[string]string{
+		"top.md":                        "# Top\n\n**Category:** Meta\n",
+		".context-docs.md":              "## Active Docs\n\n- top.md\n",
+		"packages/api/server.go":        "package api\n",
+		"packages/api/api.md":           "# API\n\n**Category:** Backend\n\n## Key Files\n\n- server.go\n",
+		"packages/api/.context-docs.md": "## Active Docs\n\n- api.md\n",
+	})
+
+	registry, err := groups.LoadContextDocRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(registry.Docs) != 2 {
+		t.Fatalf("got %d docs, want 2", len(registry.Docs))
+	}
+	api := registry.Docs[1]
+	if api.Package != filepath.Join("packages", "api") || api.FilePath != filepath.Join("packages", "api", "api.md") {
+		t.Errorf("nested doc at %q in package %q", api.FilePath, api.Package)
+	}
+	if len(api.KeyFiles) != 1 || api.KeyFiles[0] != filepath.Join("packages", "api", "server.go") || len(api.BrokenKeyFiles) > 0 {
+		t.Errorf("key files %v (broken %v) not rebased onto the root", api.KeyFiles, api.BrokenKeyFiles)
+	}
+
+	// Saving writes each doc back to the registry that lists it
+	if err := groups.SaveContextDocRegistry(root, registry, false); err != nil {
+		t.Fatal(err)
+	}
+	top, _ := os.ReadFile(filepath.Join(root, ".context-docs.md"))
+	nested, _ := os.ReadFile(filepath.Join(pkg, ".context-docs.md"))
+	if strings.Contains(string(top), "api.md") || strings.Contains(string(top), "Backend") {
+		t.Errorf("root registry lists the nested doc:\n%s", top)
+	}
+	if !strings.Contains(string(nested), "- api.md (Backend") {
+		t.Errorf("nested registry lost its doc:\n%s", nested)
+	}
+}
+
+func TestMergeContextDocRegistry(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{".context-docs.md": "## Active Docs\n\n- a.md\n- b.md\n"}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		files[name+".md"] = "# " + strings.ToUpper(name) + "\n\n**Category:** Meta\n"
+	}
+	writeFixture(t, root, files)
+	registryPath := filepath.Join(root, ".context-docs.md")
+
+	ours, err := groups.LoadContextDocRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Here a.md is removed and c.md added...
+	ours.Docs = ours.Docs[1:]
+	c, _ := groups.ParseContextDoc(root, "c.md")
+	ours.AddDoc(*c)
+
+	// ...while d.md comes in on disk
+	writeFixture(t, root, map[string]string{".context-docs.md": "## Active Docs\n\n- a.md\n- b.md\n- d.md\n"})
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(registryPath, later, later)
+	if err := groups.SaveContextDocRegistry(root, ours, false); err != groups.ErrRegistryChanged {
+		t.Fatalf("save over a changed registry returned %v", err)
+	}
+
+	merged, err := groups.MergeContextDocRegistry(root, ours)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range merged.Docs {
+		got = append(got, d.FilePath)
+	}
+	if strings.Join(got, " ") != "b.md d.md c.md" {
+		t.Errorf("merged docs %v, want [b.md d.md c.md]", got)
+	}
+	if err := groups.SaveContextDocRegistry(root, merged, false); err != nil {
+		t.Errorf("saving the merge: %v", err)
+	}
+}
+
+func TestFrontmatter(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"main.go":    "package main\n",
+		"billing.md": "---\ntitle: Billing\ncategory: Feature\nstatus: \"Active\"\ntags: [billing, payments]\nkeyFiles:\n  - main.go\ndescription: >\n  How customers\n  are charged.\n---\n\nBody text.\n",
+	})
+
+	doc, err := groups.ParseContextDoc(root, "billing.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "Billing" || doc.Category != "Feature" || doc.Status != "Active" {
+		t.Errorf("got name %q, category %q, status %q", doc.Name, doc.Category, doc.Status)
+	}
+	if strings.Join(doc.Tags, ",") != "billing,payments" || strings.Join(doc.KeyFiles, ",") != "main.go" {
+		t.Errorf("got tags %v, key files %v", doc.Tags, doc.KeyFiles)
+	}
+	if doc.Description != "How customers are charged." || len(doc.MissingFields) > 0 {
+		t.Errorf("got description %q, missing %v", doc.Description, doc.MissingFields)
+	}
+
+	// Moving the doc rewrites its frontmatter, not the body
+	if err := groups.SetDocCategory(root, "billing.md", "Payments", false); err != nil {
+		t.Fatal(err)
+	}
+	written, _ := os.ReadFile(filepath.Join(root, "billing.md"))
+	if !strings.Contains(string(written), "\ncategory: Payments\n") || strings.Contains(string(written), "**Category:**") {
+		t.Errorf("category not rewritten in frontmatter:\n%s", written)
+	}
+}
+
+func TestFindGroup(t *testing.T) {
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{
+		"a.md":             "# Auth\n\n**Category:** Feature\n\n## Key Files\n\n- auth.go\n- shared.go\n",
+		"b.md":             "# Billing\n\n**Category:** Feature\n\n## Key Files\n\n- shared.go\n",
+		".context-docs.md": "## Active Docs\n\n- a.md\n- b.md\n",
+	})
+
+	registry, err := groups.LoadContextDocRegistry(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs := groups.GroupRefs(root, registry.FindGroup("feature")); strings.Join(refs, " ") != "@a.md @auth.go @shared.go @b.md" {
+		t.Errorf("category refs %v", refs)
+	}
+	if refs := groups.GroupRefs(root, registry.FindGroup("billing")); strings.Join(refs, " ") != "@b.md @shared.go" {
+		t.Errorf("doc refs %v", refs)
+	}
+	if docs := registry.FindGroup("nope"); docs != nil {
+		t.Errorf("unexpected match %v", docs)
+	}
+}
+
+func TestAtomicWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string // Content before the write, none if empty
+		mode     os.FileMode
+		backup   bool
+		check    error // Returned by the check before the swap
+		want     string
+		wantMode os.FileMode
+		wantBak  bool
+	}{
+		{name: "new file", want: "new", wantMode: 0644},
+		{name: "keeps mode", existing: "old", mode: 0600, want: "new", wantMode: 0600},
+		{name: "backup", existing: "old", mode: 0644, backup: true, want: "new", wantMode: 0644, wantBak: true},
+		{name: "no backup of a new file", backup: true, want: "new", wantMode: 0644},
+		{name: "check fails", existing: "old", mode: 0644, check: errors.New("changed"), want: "old", wantMode: 0644},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "file.md")
+			if tt.existing != "" {
+				writeFixture(t, dir, map[string]string{"file.md": tt.existing})
+				os.Chmod(path, tt.mode)
+			}
+
+			err := atomicfile.WriteIf(path, []byte("new"), tt.backup, func() error { return tt.check })
+			if err != tt.check {
+				t.Fatalf("got error %v, want %v", err, tt.check)
+			}
+			got, _ := os.ReadFile(path)
+			info, _ := os.Stat(path)
+			if string(got) != tt.want || info.Mode().Perm() != tt.wantMode {
+				t.Errorf("got %q with mode %v, want %q with mode %v", got, info.Mode().Perm(), tt.want, tt.wantMode)
+			}
+			bak, err := os.ReadFile(path + ".bak")
+			if tt.wantBak && string(bak) != tt.existing || !tt.wantBak && err == nil {
+				t.Errorf("backup %q (err %v), want it: %v", bak, err, tt.wantBak)
+			}
+			entries, _ := os.ReadDir(dir)
+			for _, e := range entries {
+				if e.Name() != "file.md" && e.Name() != "file.md.bak" {
+					t.Errorf("temporary file %s left behind", e.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestControlRoundTrip(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	root := t.TempDir()
+	writeFixture(t, root, map[string]string{"pkg/main.go": "package main\n"})
+
+	srv, err := control.Listen(control.SocketPath(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	go srv.Serve(func(msg any) {
+		req := msg.(control.Request)
+		switch req.Method {
+		case "echo":
+			req.Reply(req.Params, nil)
+		case "fail":
+			req.Reply(nil, errors.New("boom"))
+		default:
+			req.Reply(nil, control.ErrUnknownMethod)
+		}
+	})
+
+	// Clients find the session from any path inside the project
+	socket, ok := control.Find(filepath.Join(root, "pkg", "main.go"))
+	if !ok {
+		t.Fatal("listening session not found")
+	}
+	if info, err := os.Stat(socket); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode %v (err %v), want 0600", info.Mode().Perm(), err)
+	}
+
+	tests := []struct {
+		method  string
+		params  any
+		want    string
+		wantErr string
+	}{
+		{method: "echo", params: map[string]int{"line": 3}, want: `{"line":3}`},
+		{method: "fail", wantErr: "boom"},
+		{method: "nope", wantErr: control.ErrUnknownMethod.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			result, err := control.Call(socket, tt.method, tt.params)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || string(result) != tt.want {
+				t.Errorf("got %s (err %v), want %s", result, err, tt.want)
+			}
+		})
+	}
+
+	// A second session on the same project is refused while the first listens
+	if _, err := control.Listen(socket); err == nil {
+		t.Error("second listener on a live socket")
+	}
+}
+
+func TestFrecencyDecay(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		age  time.Duration
+		want float64 // Score of two visits
+	}{
+		{"within the hour", 10 * time.Minute, 8},
+		{"today", 5 * time.Hour, 4},
+		{"this week", 3 * 24 * time.Hour, 2},
+		{"this month", 10 * 24 * time.Hour, 1},
+		{"older", 90 * 24 * time.Hour, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := make(frecency.Store)
+			s.Visit("a.go", now.Add(-tt.age-time.Minute))
+			s.Visit("a.go", now.Add(-tt.age))
+			if got := s.Score("a.go", now); got != tt.want {
+				t.Errorf("score %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	s := make(frecency.Store)
+	if s.Score("never.go", now) != 0 || s.Boost("never.go", now) != 0 {
+		t.Error("a file never opened should score 0")
+	}
+	s.Visit("often.go", now)
+	s.Visit("often.go", now)
+	s.Visit("once.go", now)
+	if s.Boost("often.go", now) <= s.Boost("once.go", now) {
+		t.Error("a file opened more often should get the bigger boost")
+	}
+}
+
+func TestImportGraph(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/app\n",
+		"main.go":               "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/store\"\n)\n",
+		"store/store.go":        "package store\n",
+		"store/store_test.go":   "package store\n",
+		"web/app.ts":            "import { api } from './api'\nimport x from 'react'\nconst y = require(\"../web/util.js\")\n",
+		"web/api.ts":            "export const api = 1\n",
+		"web/util.ts":           "import './widgets'\n",
+		"web/widgets/index.tsx": "export {}\n",
+		"py/app.py":             "import py.models\nfrom . import views\nfrom .missing import thing\n",
+		"py/models.py":          "",
+		"py/views.py":           "",
+		"py/__init__.py":        "",
+	}
+	writeFixture(t, root, files)
+	var paths []string
+	for name := range files {
+		paths = append(paths, name)
+	}
+	graph := imports.Build(root, paths)
+
+	tests := []struct {
+		file       string
+		imports    string
+		importedBy string
+	}{
+		{file: "main.go", imports: "store/store.go"},
+		{file: "store/store.go", importedBy: "main.go"},
+		{file: "web/app.ts", imports: "web/api.ts web/util.ts"},
+		{file: "web/util.ts", imports: "web/widgets/index.tsx", importedBy: "web/app.ts"},
+		{file: "py/app.py", imports: "py/__init__.py py/models.py py/views.py"}, // from . imports the package too
+		{file: "py/views.py", importedBy: "py/app.py"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if got := strings.Join(graph.Imports(tt.file), " "); got != tt.imports {
+				t.Errorf("imports %q, want %q", got, tt.imports)
+			}
+			if got := strings.Join(graph.ImportedBy(tt.file), " "); got != tt.importedBy {
+				t.Errorf("imported by %q, want %q", got, tt.importedBy)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// runInit sets a project up for contexTUI and returns the exit code. Opening a
+// project never writes to it, so this is where its files are first created.
+func runInit(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s init [path]\n\nCreates .contextui/, where the file index, visits and notes are kept, and an empty .context-docs.md registry.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	rootPath := "."
+	if fs.NArg() > 0 {
+		rootPath = fs.Arg(0)
+	}
+	if info, err := os.Stat(rootPath); err != nil || !info.IsDir() {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a directory\n", rootPath)
+		return 2
+	}
+
+	created := 0
+	dataDir := filepath.Join(rootPath, ".contextui")
+	if _, err := os.Stat(dataDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dataDir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating .contextui/: %v\n", err)
+			return 1
+		}
+		fmt.Println("Created .contextui/")
+		created++
+	}
+
+	if _, err := os.Stat(filepath.Join(rootPath, ".context-docs.md")); os.IsNotExist(err) {
+		registry := &groups.ContextDocRegistry{
+			Categories: groups.DefaultCategories(),
+			ByCategory: make(map[string][]groups.ContextDoc),
+		}
+		if err := groups.SaveContextDocRegistry(rootPath, registry, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating .context-docs.md: %v\n", err)
+			return 1
+		}
+		fmt.Println("Created .context-docs.md")
+		created++
+	}
+
+	if created == 0 {
+		fmt.Println("Already initialized")
+	}
+	return 0
+}
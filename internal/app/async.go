@@ -2,6 +2,8 @@ package app
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/fileindex"
 	"github.com/connorleisz/contexTUI/internal/git"
 	"github.com/connorleisz/contexTUI/internal/groups"
 )
@@ -10,28 +12,63 @@ import (
 func (m Model) loadDirectoryAsync() tea.Cmd {
 	rootPath := m.rootPath
 	showDotfiles := m.showDotfiles
+	roots := rootEntries(m.extraRoots)
 	return func() tea.Msg {
 		entries := LoadDirectoryWithRoot(rootPath, rootPath, 0, showDotfiles)
-		return DirectoryLoadedMsg{Entries: entries}
+		return DirectoryLoadedMsg{Root: rootPath, Entries: append(entries, roots...)}
 	}
 }
 
 // loadAllFilesAsync returns a command that collects all file paths in the background
 func (m Model) loadAllFilesAsync() tea.Cmd {
+	rootPath := m.rootPath
+	showDotfiles, followSymlinks := m.showDotfiles, m.config.FollowSymlinks
+	keepIndex := m.keepsProjectData()
+	return func() tea.Msg {
+		files, denied := CollectAllFiles(rootPath, showDotfiles, followSymlinks)
+		if keepIndex {
+			debuglog.Error("save file index", fileindex.Save(rootPath, showDotfiles, files, denied))
+		}
+		return AllFilesLoadedMsg{Root: rootPath, Files: files, Denied: denied}
+	}
+}
+
+// loadFileIndexAsync returns a command that loads the file list saved by the last
+// session, so search works before the crawl started alongside it finishes
+func (m Model) loadFileIndexAsync() tea.Cmd {
 	rootPath := m.rootPath
 	showDotfiles := m.showDotfiles
 	return func() tea.Msg {
-		files := CollectAllFiles(rootPath, showDotfiles)
-		return AllFilesLoadedMsg{Files: files}
+		files, denied, ok := fileindex.Load(rootPath, showDotfiles)
+		if !ok {
+			return nil
+		}
+		return AllFilesLoadedMsg{Root: rootPath, Files: files, Denied: denied, Saved: true}
 	}
 }
 
 // loadRegistryAsync returns a command that loads the doc registry in the background
 func (m Model) loadRegistryAsync() tea.Cmd {
-	rootPath := m.rootPath
+	rootPath, readOnly := m.rootPath, m.readOnly
+	implicit, backup := m.config.ImplicitWrites, m.config.BackupFiles
 	return func() tea.Msg {
 		registry, _ := groups.LoadContextDocRegistry(rootPath)
-		return RegistryLoadedMsg{Registry: registry}
+		var outdated []string
+		if registry != nil && !readOnly {
+			// Keep structure annotations in step with validation (removed once complete),
+			// or leave them for the user to update (u in the docs panel)
+			for _, doc := range registry.Docs {
+				if !groups.StructureAnnotationOutdated(doc.RawContent, doc.MissingFields) {
+					continue
+				}
+				if implicit {
+					groups.SyncStructureAnnotation(rootPath, doc.FilePath, doc.MissingFields, backup)
+				} else {
+					outdated = append(outdated, doc.FilePath)
+				}
+			}
+		}
+		return RegistryLoadedMsg{Root: rootPath, Registry: registry, Outdated: outdated}
 	}
 }
 
@@ -40,24 +77,49 @@ func (m Model) loadGitStatusAsync() tea.Cmd {
 	if !m.isGitRepo {
 		return nil
 	}
-	repoRoot := m.gitRepoRoot
+	rootPath, repoRoot := m.rootPath, m.gitRepoRoot
 	return func() tea.Msg {
 		status, changes := git.LoadStatus(repoRoot)
 		dirStatus := git.ComputeDirStatus(status)
-		branch := git.GetBranch(repoRoot)
-		ahead, behind, hasUpstream := git.GetAheadBehind(repoRoot)
+		branch := git.GetBranchInfo(repoRoot)
 		return GitStatusLoadedMsg{
+			Root:        rootPath,
 			Status:      status,
 			Changes:     changes,
 			DirStatus:   dirStatus,
-			Branch:      branch,
-			Ahead:       ahead,
-			Behind:      behind,
-			HasUpstream: hasUpstream,
+			Branch:      branch.Branch,
+			Ahead:       branch.Ahead,
+			Behind:      branch.Behind,
+			HasUpstream: branch.HasUpstream,
 		}
 	}
 }
 
+// refreshGitStatus starts a background git status refresh unless one is running
+func (m *Model) refreshGitStatus() tea.Cmd {
+	if !m.isGitRepo || m.gitRefreshing {
+		return nil
+	}
+	m.gitRefreshing = true
+	return m.refreshGitStatusAsync()
+}
+
+// refreshGitStatusAsync reloads git status and branch info without a loading
+// indicator, to pick up git commands run outside the app
+func (m Model) refreshGitStatusAsync() tea.Cmd {
+	load := m.loadGitStatusAsync()
+	if load == nil {
+		return nil
+	}
+	repoRoot := m.gitRepoRoot
+	return func() tea.Msg {
+		git.InvalidateBranchInfo(repoRoot)
+		msg := load().(GitStatusLoadedMsg)
+		msg.Background = true
+		return msg
+	}
+}
+
 // checkLoadingComplete decrements the pending load counter and clears loading state when done
 func (m *Model) checkLoadingComplete() {
 	if m.pendingLoads > 0 {
@@ -0,0 +1,278 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/basket"
+	"github.com/connorleisz/contexTUI/internal/clipboard"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/tokens"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// basketTokens returns the estimated tokens of everything in the basket
+func (m Model) basketTokens() int {
+	total := 0
+	for _, it := range m.basket {
+		total += it.Tokens(m.rootPath)
+	}
+	return total
+}
+
+// stageFiles adds files to the basket by path, relative to the working directory or the
+// root. grep-style "path:line:text" entries add their path. Returns how many were added.
+func stageFiles(rootPath string, items []basket.Item, paths []string) ([]basket.Item, int) {
+	added := 0
+	for _, p := range paths {
+		rel := resolveSelect(rootPath, p)
+		if rel == "" {
+			if before, _, ok := strings.Cut(p, ":"); ok {
+				rel = resolveSelect(rootPath, before)
+			}
+		}
+		if rel == "" {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(rootPath, rel)); err != nil || info.IsDir() {
+			continue
+		}
+		var ok bool
+		if items, ok = basket.Add(items, basket.Item{Kind: basket.KindFile, Path: rel}); ok {
+			added++
+		}
+	}
+	return items, added
+}
+
+// saveBasket keeps the basket for the next session, except in read-only mode
+func (m Model) saveBasket() error {
+	if m.readOnly {
+		return nil
+	}
+	return basket.Save(m.rootPath, m.basket)
+}
+
+// addToBasket stages items in the basket and saves it
+func (m Model) addToBasket(items ...basket.Item) (tea.Model, tea.Cmd) {
+	added := 0
+	for _, it := range items {
+		var ok bool
+		if m.basket, ok = basket.Add(m.basket, it); ok {
+			added++
+		}
+	}
+	switch {
+	case added == 0:
+		m.statusMessage = "Already in the basket"
+	case m.saveBasket() != nil:
+		m.statusMessage = "Failed to save basket"
+	case len(items) == 1:
+		m.statusMessage = fmt.Sprintf("Added %s to basket (%s in total)", items[0].Label(), tokens.Format(m.basketTokens()))
+	default:
+		m.statusMessage = fmt.Sprintf("Added %d items to basket (%s in total)", added, tokens.Format(m.basketTokens()))
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// addTreeEntryToBasket stages the file under the tree cursor
+func (m Model) addTreeEntryToBasket() (tea.Model, tea.Cmd) {
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) || flat[m.cursor].IsDir {
+		m.statusMessage = "Only files can be added to the basket"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m.addToBasket(basket.Item{Kind: basket.KindFile, Path: flat[m.cursor].RelPath})
+}
+
+// addSelectionToBasket stages the copy-mode selection with its text
+func (m Model) addSelectionToBasket() (tea.Model, tea.Cmd) {
+	start, end, startLine, endLine, ok := m.selectionSource()
+	if !ok {
+		m.statusMessage = "Select lines first"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m.addToBasket(basket.Item{
+		Kind:  basket.KindSelection,
+		Path:  m.previewRelPath(),
+		Start: startLine,
+		End:   endLine,
+		Text:  clipboard.ExtractLines(m.previewLines, start, end, StripLineNumbers),
+	})
+}
+
+// addGitChangeToBasket stages the diff of the change under the git status cursor
+// Untracked files have no diff, so the file itself is added.
+func (m Model) addGitChangeToBasket() (tea.Model, tea.Cmd) {
+	change, ok := m.selectedGitChange()
+	if !ok {
+		return m, nil
+	}
+	relPath, err := filepath.Rel(m.rootPath, filepath.Join(m.gitRepoRoot, change.Path))
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		m.statusMessage = "Outside the project: " + change.Path
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if change.Status == "?" {
+		return m.addToBasket(basket.Item{Kind: basket.KindFile, Path: relPath})
+	}
+	diff, err := git.LoadDiff(m.gitRepoRoot, change.Path, change.Staged, 3)
+	if err != nil || diff == "" {
+		m.statusMessage = "No diff for " + change.Path
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m.addToBasket(basket.Item{Kind: basket.KindDiff, Path: relPath, Staged: change.Staged, Text: diff})
+}
+
+// openBasket shows the staged items
+func (m Model) openBasket() (tea.Model, tea.Cmd) {
+	m.clearAllOverlays()
+	m.showingBasket = true
+	m.basketCursor = 0
+	return m, nil
+}
+
+// updateBasket handles input in the basket overlay
+func (m Model) updateBasket(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	changed := false
+	switch keyMsg.String() {
+	case "esc", "q", "A":
+		m.showingBasket = false
+
+	case "j", "down":
+		if m.basketCursor < len(m.basket)-1 {
+			m.basketCursor++
+		}
+
+	case "k", "up":
+		if m.basketCursor > 0 {
+			m.basketCursor--
+		}
+
+	case "J", "shift+down":
+		if i := m.basketCursor; i < len(m.basket)-1 {
+			m.basket[i], m.basket[i+1] = m.basket[i+1], m.basket[i]
+			m.basketCursor++
+			changed = true
+		}
+
+	case "K", "shift+up":
+		if i := m.basketCursor; i > 0 && i < len(m.basket) {
+			m.basket[i], m.basket[i-1] = m.basket[i-1], m.basket[i]
+			m.basketCursor--
+			changed = true
+		}
+
+	case "d", "x":
+		if m.basketCursor < len(m.basket) {
+			m.basket = append(m.basket[:m.basketCursor], m.basket[m.basketCursor+1:]...)
+			if m.basketCursor >= len(m.basket) && m.basketCursor > 0 {
+				m.basketCursor--
+			}
+			changed = true
+		}
+
+	case "D":
+		m.basket = nil
+		m.basketCursor = 0
+		changed = true
+
+	case "w":
+		if len(m.basket) > 0 {
+			return m.openExport("basket", basket.Format(m.basket))
+		}
+
+	case "enter", "c":
+		if len(m.basket) == 0 {
+			return m, nil
+		}
+		if err := m.copyText("basket", basket.Format(m.basket)); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.statusMessage = fmt.Sprintf("Copied %d basket items (%s)", len(m.basket), tokens.Format(m.basketTokens()))
+			m.showingBasket = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	if changed && m.saveBasket() != nil {
+		m.statusMessage = "Failed to save basket"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m, nil
+}
+
+// renderBasketOverlay renders the staged items with their token estimates
+func (m Model) renderBasketOverlay(background string) string {
+	boxWidth := m.overlayWidth(72)
+	maxVisible := m.height - 14
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Basket"))
+	lines = append(lines, "")
+
+	if len(m.basket) == 0 {
+		lines = append(lines, styles.Muted.Render("The basket is empty."))
+		lines = append(lines, "")
+		lines = append(lines, styles.Faint.Render("Add files with a (tree, git status), docs with B, selections with a"))
+		lines = append(lines, styles.Faint.Render("in copy mode and search results with ctrl+s."))
+	}
+
+	start := 0
+	if m.basketCursor >= maxVisible {
+		start = m.basketCursor - maxVisible + 1
+	}
+	end := min(start+maxVisible, len(m.basket))
+	for i := start; i < end; i++ {
+		it := m.basket[i]
+		est := tokens.Format(it.Tokens(m.rootPath))
+		labelWidth := boxWidth - 8 - 10 - ansi.StringWidth(est) - 2
+		label := fmt.Sprintf("%-10s%s", it.Kind, ansi.Truncate(it.Label(), labelWidth, "…"))
+		label = padRight(label, boxWidth-8-ansi.StringWidth(est)) + est
+		if i == m.basketCursor {
+			lines = append(lines, styles.Selected.Render(label))
+		} else {
+			lines = append(lines, styles.Normal.Render(label))
+		}
+	}
+
+	if len(m.basket) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, styles.Faint.Render(fmt.Sprintf("%d items · %s", len(m.basket), tokens.Format(m.basketTokens()))))
+	}
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] nav  [J/K] move  [c] copy  [w] write  [d/D] remove/empty  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
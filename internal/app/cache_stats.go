@@ -0,0 +1,31 @@
+package app
+
+import (
+	"github.com/connorleisz/contexTUI/internal/cache"
+	"github.com/connorleisz/contexTUI/internal/config"
+)
+
+// Entry limits for the render caches; size limits come from the config
+const (
+	maxPreviewCacheEntries = 200
+	maxDiffCacheEntries    = 200
+	maxImageCacheEntries   = 32
+)
+
+// newPreviewCache creates the cache of rendered file previews
+func newPreviewCache(cfg config.Config) *cache.Cache[string, CachedPreview] {
+	return cache.New[string, CachedPreview]("Previews", maxPreviewCacheEntries, cfg.PreviewCacheBytes(),
+		func(p CachedPreview) int64 { return int64(len(p.Content)) })
+}
+
+// newDiffCache creates the cache of rendered git diffs
+func newDiffCache(cfg config.Config) *cache.Cache[DiffCacheKey, CachedDiff] {
+	return cache.New[DiffCacheKey, CachedDiff]("Diffs", maxDiffCacheEntries, cfg.DiffCacheBytes(),
+		func(d CachedDiff) int64 { return int64(len(d.Content)) })
+}
+
+// newImageCache creates the cache of rendered images
+func newImageCache(cfg config.Config) *cache.Cache[string, CachedImage] {
+	return cache.New[string, CachedImage]("Images", maxImageCacheEntries, cfg.ImageCacheBytes(),
+		func(i CachedImage) int64 { return int64(len(i.RenderData)) })
+}
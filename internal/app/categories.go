@@ -0,0 +1,344 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/backups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// Category manager modes
+const (
+	categoryBrowse = ""       // Moving around the list
+	categoryNew    = "new"    // Typing a new category's name
+	categoryRename = "rename" // Typing the selected category's new name
+	categoryDelete = "delete" // Picking where a deleted category's docs go
+	categoryMove   = "move"   // Picking where the marked docs go
+)
+
+// newCategoryInput creates the category manager's name prompt
+func newCategoryInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "Category name"
+	ti.CharLimit = 60
+	ti.Width = 40
+	return ti
+}
+
+// openCategories shows the category manager over the docs overlay
+func (m Model) openCategories() (tea.Model, tea.Cmd) {
+	if m.docRegistry == nil {
+		return m, nil
+	}
+	m.managingCategories = true
+	m.categoryMode = categoryBrowse
+	m.categoryCursor = min(m.selectedCategory, max(0, len(m.docRegistry.Categories)-1))
+	return m, nil
+}
+
+// openMoveDocs picks a category for the selected docs (or the current one)
+func (m Model) openMoveDocs() (tea.Model, tea.Cmd) {
+	if m.docRegistry == nil {
+		return m, nil
+	}
+	m.categoryMoveDocs = nil
+	for _, ref := range m.docRefs() {
+		m.categoryMoveDocs = append(m.categoryMoveDocs, strings.TrimPrefix(ref, "@"))
+	}
+	if len(m.categoryMoveDocs) == 0 {
+		return m, nil
+	}
+	m.managingCategories = true
+	m.categoryMode = categoryMove
+	m.categoryCursor = min(m.selectedCategory, max(0, len(m.docRegistry.Categories)-1))
+	return m, nil
+}
+
+// updateCategories handles input in the category manager
+func (m Model) updateCategories(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.categoryInput.Focused() {
+			var cmd tea.Cmd
+			m.categoryInput, cmd = m.categoryInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+	cats := m.docRegistry.Categories
+
+	if m.categoryInput.Focused() {
+		switch keyMsg.String() {
+		case "esc":
+			m.categoryInput.Blur()
+			m.categoryMode = categoryBrowse
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(m.categoryInput.Value())
+			var err error
+			snap := m.takeUndo("Change categories")
+			if m.categoryMode == categoryNew {
+				err = m.docRegistry.AddCategory(name)
+				if err == nil {
+					m.categoryCursor = len(m.docRegistry.Categories) - 1
+				}
+			} else if m.categoryCursor < len(cats) {
+				err = m.docRegistry.RenameCategory(m.rootPath, cats[m.categoryCursor].ID, name, m.config.BackupFiles)
+			}
+			if err != nil {
+				m.categoryError = err.Error()
+				return m, nil
+			}
+			m.categoryInput.Blur()
+			m.categoryMode = categoryBrowse
+			return m.saveCategories(snap, fmt.Sprintf("Saved category %s", name))
+		}
+		m.categoryError = ""
+		var cmd tea.Cmd
+		m.categoryInput, cmd = m.categoryInput.Update(msg)
+		return m, cmd
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		if m.categoryMode == categoryDelete {
+			m.categoryMode = categoryBrowse
+			return m, nil
+		}
+		m.managingCategories = false
+
+	case "j", "down":
+		if m.categoryCursor < len(cats)-1 {
+			m.categoryCursor++
+		}
+
+	case "k", "up":
+		if m.categoryCursor > 0 {
+			m.categoryCursor--
+		}
+
+	case "K", "shift+up", "J", "shift+down":
+		if m.categoryMode != categoryBrowse {
+			return m, nil
+		}
+		if m.readOnly {
+			return m.readOnlyNotice()
+		}
+		to := m.categoryCursor + 1
+		if keyMsg.String() == "K" || keyMsg.String() == "shift+up" {
+			to = m.categoryCursor - 1
+		}
+		if to < 0 || to >= len(cats) {
+			return m, nil
+		}
+		m.recordUndo("Reorder categories")
+		m.docRegistry.MoveCategory(m.categoryCursor, to)
+		m.categoryCursor = to
+		m.registryDirty = true
+		return m, ScheduleRegistrySave(150 * time.Millisecond)
+
+	case "n":
+		if m.categoryMode == categoryBrowse && m.readOnly {
+			return m.readOnlyNotice()
+		}
+		if m.categoryMode == categoryBrowse {
+			m.categoryMode = categoryNew
+			m.categoryError = ""
+			m.categoryInput.SetValue("")
+			m.categoryInput.Focus()
+			return m, textinput.Blink
+		}
+
+	case "r":
+		if m.categoryMode == categoryBrowse && m.readOnly {
+			return m.readOnlyNotice()
+		}
+		if m.categoryMode == categoryBrowse && m.categoryCursor < len(cats) {
+			m.categoryMode = categoryRename
+			m.categoryError = ""
+			m.categoryInput.SetValue(cats[m.categoryCursor].Name)
+			m.categoryInput.CursorEnd()
+			m.categoryInput.Focus()
+			return m, textinput.Blink
+		}
+
+	case "d", "x":
+		if m.categoryMode != categoryBrowse || m.categoryCursor >= len(cats) {
+			return m, nil
+		}
+		if m.readOnly {
+			return m.readOnlyNotice()
+		}
+		cat := cats[m.categoryCursor]
+		if len(m.docRegistry.ByCategory[cat.ID]) == 0 {
+			if !m.confirmAgain("category\x00"+cat.ID, true, "Press d again to delete category "+cat.Name) {
+				return m, ClearStatusAfter(3 * time.Second)
+			}
+			snap := m.takeUndo("Delete category " + cat.Name)
+			if err := m.docRegistry.DeleteCategory(m.rootPath, cat.ID, "", m.config.BackupFiles); err != nil {
+				m.categoryError = err.Error()
+				return m, nil
+			}
+			m.categoryCursor = min(m.categoryCursor, max(0, len(m.docRegistry.Categories)-1))
+			return m.saveCategories(snap, "Deleted category "+cat.Name)
+		}
+		if len(cats) < 2 {
+			m.categoryError = "Create another category to move its docs to first"
+			return m, nil
+		}
+		// Docs have to go somewhere: pick the target next
+		m.categoryMode = categoryDelete
+		m.categoryFrom = cat.ID
+		m.categoryError = ""
+		if m.categoryCursor == 0 {
+			m.categoryCursor = 1
+		} else {
+			m.categoryCursor--
+		}
+
+	case "enter":
+		if m.categoryCursor >= len(cats) {
+			return m, nil
+		}
+		target := cats[m.categoryCursor]
+		switch m.categoryMode {
+		case categoryBrowse:
+			// Show the category's docs
+			m.managingCategories = false
+			m.selectedCategory = m.categoryCursor
+			m.docCursor = 0
+			m.docsScrollOffset = 0
+
+		case categoryDelete:
+			if target.ID == m.categoryFrom {
+				return m, nil
+			}
+			i := m.docRegistry.CategoryIndex(m.categoryFrom)
+			if i < 0 {
+				m.categoryMode = categoryBrowse
+				return m, nil
+			}
+			from := cats[i]
+			snap := m.takeUndo("Delete category " + from.Name)
+			if err := m.docRegistry.DeleteCategory(m.rootPath, from.ID, target.Name, m.config.BackupFiles); err != nil {
+				m.categoryError = err.Error()
+				return m, nil
+			}
+			m.categoryMode = categoryBrowse
+			m.categoryCursor = max(0, m.docRegistry.CategoryIndex(target.ID))
+			return m.saveCategories(snap, fmt.Sprintf("Deleted %s, docs moved to %s", from.Name, target.Name))
+
+		case categoryMove:
+			snap := m.takeUndo("Move docs to " + target.Name)
+			moved, err := m.docRegistry.MoveDocs(m.rootPath, m.categoryMoveDocs, target.Name, m.config.BackupFiles)
+			if err != nil {
+				m.categoryError = err.Error()
+				return m, nil
+			}
+			m.managingCategories = false
+			m.selectedDocs = make(map[string]bool)
+			m.selectedCategory = max(0, m.docRegistry.CategoryIndex(target.ID))
+			m.docCursor = 0
+			m.docsScrollOffset = 0
+			return m.saveCategories(snap, fmt.Sprintf("Moved %d doc(s) to %s", moved, target.Name))
+		}
+	}
+	return m, nil
+}
+
+// saveCategories writes the registry after a category change, making snap its undo
+// step once written, and keeps the docs overlay's selected category in range
+func (m Model) saveCategories(snap backups.Snapshot, status string) (tea.Model, tea.Cmd) {
+	m.categoryError = ""
+	if n := len(m.docRegistry.Categories); m.selectedCategory >= n {
+		m.selectedCategory = max(0, n-1)
+	}
+	if docs := m.getDocsForSelectedCategory(); m.docCursor >= len(docs) {
+		m.docCursor = max(0, len(docs)-1)
+	}
+	if err := m.saveRegistry(); err != nil {
+		m.statusMessage = fmt.Sprintf("Error: %v", err)
+	} else {
+		m.pushUndo(snap)
+		m.registryDirty = false
+		m.statusMessage = status
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(5 * time.Second)
+}
+
+// renderCategoriesOverlay renders the category manager
+func (m Model) renderCategoriesOverlay(background string) string {
+	boxWidth := m.overlayWidth(60)
+	cats := m.docRegistry.Categories
+
+	var lines []string
+	switch m.categoryMode {
+	case categoryDelete:
+		name := m.categoryFrom
+		if i := m.docRegistry.CategoryIndex(m.categoryFrom); i >= 0 {
+			name = cats[i].Name
+		}
+		lines = append(lines, styles.Title.Render("Delete "+name))
+		lines = append(lines, styles.Faint.Render("Move its docs to:"))
+	case categoryMove:
+		lines = append(lines, styles.Title.Render("Move Docs"))
+		lines = append(lines, styles.Faint.Render(fmt.Sprintf("Move %d doc(s) to:", len(m.categoryMoveDocs))))
+	default:
+		lines = append(lines, styles.Title.Render("Categories"))
+	}
+	lines = append(lines, "")
+
+	for i, cat := range cats {
+		label := fmt.Sprintf("%s %s", padRight(cat.Name, 30), styles.Faint.Render(fmt.Sprintf("%d docs", len(m.docRegistry.ByCategory[cat.ID]))))
+		if m.categoryMode == categoryDelete && cat.ID == m.categoryFrom {
+			label = styles.Faint.Render(cat.Name + " (deleting)")
+		}
+		if i == m.categoryCursor && !m.categoryInput.Focused() {
+			lines = append(lines, styles.Selected.Render(" "+label+" "))
+		} else {
+			lines = append(lines, " "+styles.Normal.Render(label))
+		}
+	}
+
+	if m.categoryInput.Focused() {
+		lines = append(lines, "")
+		if m.categoryMode == categoryNew {
+			lines = append(lines, styles.Muted.Render("New category:"))
+		} else {
+			lines = append(lines, styles.Muted.Render("Rename to:"))
+		}
+		lines = append(lines, m.categoryInput.View())
+	}
+	if m.categoryError != "" {
+		lines = append(lines, "")
+		lines = append(lines, styles.StatusError.Render(m.categoryError))
+	}
+
+	lines = append(lines, "")
+	switch {
+	case m.categoryInput.Focused():
+		lines = append(lines, styles.Faint.Render("[enter] save  [esc] cancel"))
+	case m.categoryMode == categoryBrowse:
+		lines = append(lines, styles.Faint.Render("[j/k] navigate  [J/K] reorder  [n] new  [r] rename  [d] delete  [enter] open  [esc] back"))
+	default:
+		lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] move here  [esc] cancel"))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
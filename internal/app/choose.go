@@ -0,0 +1,27 @@
+package app
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// choose records the path under the cursor for -choose / -choose-dir and quits
+// With -choose-dir a file picks its directory.
+func (m Model) choose() (tea.Model, tea.Cmd) {
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) {
+		return m, nil
+	}
+	e := flat[m.cursor]
+	m.chosen = e.Path
+	if m.options.ChooseDir && !e.IsDir {
+		m.chosen = filepath.Dir(e.Path)
+	}
+	return m, tea.Quit
+}
+
+// Chosen returns the path picked with enter in choose mode, or "" if none was
+func (m Model) Chosen() string {
+	return m.chosen
+}
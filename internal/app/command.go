@@ -0,0 +1,331 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/shell"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+	"github.com/muesli/reflow/truncate"
+)
+
+const (
+	commandOutputBatch = 500   // Most output lines one CommandOutputMsg carries
+	maxCommandOutput   = 10000 // Output lines kept by the command runner; older lines are dropped
+)
+
+// commandRunIDs numbers command runs so output of a replaced run is told apart; runs
+// outlive the model that started them (e.g. across project switches), so it is shared
+var commandRunIDs atomic.Int64
+
+// runStreaming starts a command in the background and returns its run ID, a function
+// that stops it, and the command that delivers its output in batches
+func runStreaming(run func(ctx context.Context, output func(string)) error) (int64, context.CancelFunc, tea.Cmd) {
+	id := commandRunIDs.Add(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan string, commandOutputBatch)
+	result := make(chan CommandOutputMsg, 1)
+	go func() {
+		start := time.Now()
+		err := run(ctx, func(line string) {
+			// Once stopped, nothing may read the output anymore
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+			}
+		})
+		close(lines)
+		result <- CommandOutputMsg{RunID: id, Done: true, Err: err, Duration: time.Since(start)}
+	}()
+	return id, cancel, waitForCommandOutput(id, lines, result)
+}
+
+// waitForCommandOutput returns a command that delivers the next batch of output,
+// or the result once the output ends
+func waitForCommandOutput(id int64, lines <-chan string, result <-chan CommandOutputMsg) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-lines
+		if !ok {
+			return <-result
+		}
+		// Take whatever else is already buffered
+		batch := []string{line}
+		for len(batch) < commandOutputBatch && len(lines) > 0 {
+			batch = append(batch, <-lines)
+		}
+		return CommandOutputMsg{RunID: id, Lines: batch, next: waitForCommandOutput(id, lines, result)}
+	}
+}
+
+// newCommandInput creates the command runner's prompt
+func newCommandInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "shell command, e.g. go build ./..."
+	ti.Prompt = "$ "
+	ti.CharLimit = 1000
+	ti.Width = 60
+	return ti
+}
+
+// openCommand shows the command runner with its prompt focused
+// The last command's output stays until another one runs.
+func (m Model) openCommand() (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	m.clearAllOverlays()
+	m.showingCommand = true
+	m.commandHistoryIdx = len(m.commandHistory)
+	if m.commandRunning {
+		return m, nil
+	}
+	m.commandInput.SetValue("")
+	m.commandInput.Focus()
+	return m, textinput.Blink
+}
+
+// startCommand runs a command from the project root, streaming its output into the overlay
+func (m Model) startCommand(command string) (tea.Model, tea.Cmd) {
+	command = strings.TrimSpace(command)
+	if command == "" || m.commandRunning {
+		return m, nil
+	}
+	if n := len(m.commandHistory); n == 0 || m.commandHistory[n-1] != command {
+		m.commandHistory = append(m.commandHistory, command)
+	}
+	m.commandInput.Blur()
+	m.commandLine = command
+	m.commandRunning = true
+	m.commandOutput = nil
+	m.commandErr = nil
+	m.commandScroll = 0
+	m.commandFollow = true
+
+	rootPath := m.rootPath
+	id, cancel, cmd := runStreaming(func(ctx context.Context, output func(string)) error {
+		return shell.Run(ctx, rootPath, command, output)
+	})
+	m.commandRunID, m.commandCancel = id, cancel
+	return m, tea.Batch(cmd, SpinnerTick())
+}
+
+// handleCommandOutput adds streamed output and records how the command ended
+func (m Model) handleCommandOutput(msg CommandOutputMsg) (tea.Model, tea.Cmd) {
+	m.commandOutput = append(m.commandOutput, msg.Lines...)
+	if over := len(m.commandOutput) - maxCommandOutput; over > 0 {
+		m.commandOutput = m.commandOutput[over:]
+		m.commandScroll = max(0, m.commandScroll-over)
+	}
+	if !msg.Done {
+		return m, msg.next
+	}
+
+	m.commandRunning = false
+	m.commandCancel = nil
+	m.commandErr = msg.Err
+	m.commandDuration = msg.Duration
+	if !m.showingCommand {
+		if msg.Err == nil {
+			m.statusMessage = "Command finished: " + m.commandLine
+		} else {
+			m.statusMessage = "Command failed: " + m.commandLine
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(5 * time.Second)
+	}
+	return m, nil
+}
+
+// commandOutputRows returns how many output lines the overlay shows
+func (m Model) commandOutputRows() int {
+	return max(3, m.height-17)
+}
+
+// scrollCommand moves the output view, following new output again at the bottom
+func (m *Model) scrollCommand(delta int) {
+	maxScroll := max(0, len(m.commandOutput)-m.commandOutputRows())
+	if m.commandFollow {
+		m.commandScroll = maxScroll
+	}
+	m.commandScroll = min(max(0, m.commandScroll+delta), maxScroll)
+	m.commandFollow = m.commandScroll == maxScroll
+}
+
+// commandContext formats the command and its output for pasting into an agent
+func (m Model) commandContext() string {
+	status := "exit status 0"
+	if m.commandErr != nil {
+		status = m.commandErr.Error()
+	}
+	return fmt.Sprintf("$ %s (%s)\n```\n%s\n```\n", m.commandLine, status, strings.Join(m.commandOutput, "\n"))
+}
+
+// updateCommand handles input in the command runner overlay
+func (m Model) updateCommand(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.commandInput.Focused() {
+			var cmd tea.Cmd
+			m.commandInput, cmd = m.commandInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	if m.commandInput.Focused() {
+		switch keyMsg.String() {
+		case "esc":
+			m.commandInput.Blur()
+			if m.commandLine == "" {
+				m.showingCommand = false
+			}
+			return m, nil
+		case "enter":
+			return m.startCommand(m.commandInput.Value())
+		case "up":
+			if m.commandHistoryIdx > 0 {
+				m.commandHistoryIdx--
+				m.commandInput.SetValue(m.commandHistory[m.commandHistoryIdx])
+				m.commandInput.CursorEnd()
+			}
+			return m, nil
+		case "down":
+			if m.commandHistoryIdx < len(m.commandHistory) {
+				m.commandHistoryIdx++
+				value := ""
+				if m.commandHistoryIdx < len(m.commandHistory) {
+					value = m.commandHistory[m.commandHistoryIdx]
+				}
+				m.commandInput.SetValue(value)
+				m.commandInput.CursorEnd()
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.commandInput, cmd = m.commandInput.Update(msg)
+		return m, cmd
+	}
+
+	half := m.commandOutputRows() / 2
+	switch keyMsg.String() {
+	case "esc", "q":
+		// A running command keeps going in the background
+		m.showingCommand = false
+	case "!", "i":
+		if !m.commandRunning {
+			m.commandInput.SetValue("")
+			m.commandHistoryIdx = len(m.commandHistory)
+			m.commandInput.Focus()
+			return m, textinput.Blink
+		}
+	case "r":
+		return m.startCommand(m.commandLine)
+	case "x":
+		if m.commandRunning && m.commandCancel != nil {
+			m.commandCancel()
+		}
+	case "j", "down":
+		m.scrollCommand(1)
+	case "k", "up":
+		m.scrollCommand(-1)
+	case "ctrl+d", "J":
+		m.scrollCommand(half)
+	case "ctrl+u", "K":
+		m.scrollCommand(-half)
+	case "g":
+		m.commandFollow = false
+		m.commandScroll = 0
+	case "G":
+		m.commandFollow = true
+	case "c":
+		if m.commandLine != "" && !m.commandRunning {
+			if err := m.copyText("command", m.commandContext()); err != nil {
+				m.statusMessage = "Clipboard unavailable"
+			} else {
+				m.statusMessage = fmt.Sprintf("Copied output of %s (%d lines)", m.commandLine, len(m.commandOutput))
+			}
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(3 * time.Second)
+		}
+	}
+	return m, nil
+}
+
+// renderCommandOverlay renders the command prompt and the streamed output
+func (m Model) renderCommandOverlay(background string) string {
+	boxWidth := m.overlayWidth(min(max(m.width*80/100, 50), 120))
+	rows := m.commandOutputRows()
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Run Command")+"  "+styles.Faint.Render(m.rootPath))
+	lines = append(lines, "")
+	if m.commandInput.Focused() || m.commandLine == "" {
+		m.commandInput.Width = boxWidth - 10
+		lines = append(lines, m.commandInput.View())
+	} else {
+		lines = append(lines, styles.Key.Render("$ "+m.commandLine))
+	}
+	lines = append(lines, "")
+
+	var footer string
+	switch {
+	case m.commandInput.Focused():
+		footer = "[enter] run  [↑/↓] history  [esc] back"
+	case m.commandRunning:
+		spinner := string(SpinnerChars[m.spinnerFrame])
+		lines = append(lines, styles.StatusWarning.Render(spinner+" Running..."))
+		footer = "[j/k] scroll  [G] follow  [x] stop  [esc] close (keeps running)"
+	case m.commandLine != "":
+		took := m.commandDuration.Round(time.Millisecond)
+		switch {
+		case m.commandErr == nil:
+			lines = append(lines, styles.StatusSuccess.Render(fmt.Sprintf("✓ Exit 0 in %s", took)))
+		case errors.Is(m.commandErr, context.Canceled):
+			lines = append(lines, styles.StatusWarning.Render(fmt.Sprintf("■ Stopped after %s", took)))
+		default:
+			lines = append(lines, styles.StatusError.Render(fmt.Sprintf("✗ %v after %s", m.commandErr, took)))
+		}
+		footer = "[j/k] scroll  [c] copy as context  [r] re-run  [!] new command  [esc] close"
+	}
+
+	if m.commandLine != "" && !m.commandInput.Focused() {
+		lines = append(lines, "")
+		maxScroll := max(0, len(m.commandOutput)-rows)
+		scroll := min(m.commandScroll, maxScroll)
+		if m.commandFollow {
+			scroll = maxScroll
+		}
+		end := min(scroll+rows, len(m.commandOutput))
+		if scroll > 0 {
+			lines = append(lines, styles.Faint.Render(fmt.Sprintf("  ▲ %d lines above", scroll)))
+		}
+		for _, line := range m.commandOutput[scroll:end] {
+			lines = append(lines, styles.Normal.Render(truncate.StringWithTail(line, uint(boxWidth-8), "…")))
+		}
+		if end < len(m.commandOutput) {
+			lines = append(lines, styles.Faint.Render(fmt.Sprintf("  ▼ %d lines below", len(m.commandOutput)-end)))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render(footer))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// commitPromptFile overrides the built-in commit message prompt
+const commitPromptFile = ".contextui/commit-prompt.md"
+
+// defaultCommitPrompt asks for a conventional commit message
+// {{branch}}, {{files}}, {{docs}} and {{diff}} are filled in.
+const defaultCommitPrompt = "Write a conventional commit message for the staged changes below.\n\n" +
+	"Use the form `type(scope): summary` (feat, fix, docs, refactor, perf, test, build, ci or chore), " +
+	"with the summary in the imperative mood and under 72 characters. Add a short body when the " +
+	"reason for the change isn't obvious from the diff. Reply with the message only.\n\n" +
+	"Branch: {{branch}}\n\n" +
+	"Staged files:\n{{files}}\n\n" +
+	"Context docs for these files:\n{{docs}}\n\n" +
+	"```diff\n{{diff}}\n```\n"
+
+// promptTemplate returns the project's template at file, or the built-in one
+func promptTemplate(rootPath, file, builtin string) string {
+	data, err := os.ReadFile(filepath.Join(rootPath, file))
+	if err != nil || strings.TrimSpace(string(data)) == "" {
+		return builtin
+	}
+	return string(data)
+}
+
+// promptDocs lists the context docs with a key file among the changes, "(none)"
+// if there are none
+func (m Model) promptDocs(changes []git.FileStatus) string {
+	if m.docRegistry == nil {
+		return "(none)"
+	}
+	paths := make(map[string]bool, len(changes))
+	for _, c := range changes {
+		if rel, err := filepath.Rel(m.rootPath, filepath.Join(m.gitRepoRoot, c.Path)); err == nil {
+			paths[filepath.ToSlash(rel)] = true
+		}
+	}
+	var docs []string
+	for _, d := range m.docRegistry.Docs {
+		for _, ref := range d.KeyFileRefs(m.rootPath) {
+			if paths[filepath.ToSlash(ref)] {
+				line := fmt.Sprintf("- %s (@%s)", d.Name, d.FilePath)
+				if d.Description != "" {
+					line += ": " + d.Description
+				}
+				docs = append(docs, line)
+				break
+			}
+		}
+	}
+	if len(docs) == 0 {
+		return "(none)"
+	}
+	return strings.Join(docs, "\n")
+}
+
+// copyCommitPrompt copies a prompt asking for a commit message for the staged
+// changes, with the context docs that cover them
+func (m Model) copyCommitPrompt() (tea.Model, tea.Cmd) {
+	var staged []git.FileStatus
+	var files []string
+	for _, c := range m.gitChanges {
+		if c.Staged {
+			staged = append(staged, c)
+			files = append(files, fmt.Sprintf("- %s %s", c.Status, c.Path))
+		}
+	}
+	diff, err := git.LoadStagedDiff(m.gitRepoRoot)
+	if err != nil || len(staged) == 0 || strings.TrimSpace(diff) == "" {
+		m.statusMessage = "Nothing staged"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	branch := m.gitBranch
+	if branch == "" {
+		branch = "(detached)"
+	}
+	prompt := strings.NewReplacer(
+		"{{branch}}", branch,
+		"{{files}}", strings.Join(files, "\n"),
+		"{{docs}}", m.promptDocs(staged),
+		"{{diff}}", strings.TrimRight(diff, "\n"),
+	).Replace(promptTemplate(m.rootPath, commitPromptFile, defaultCommitPrompt))
+
+	if err := m.copyText("commit prompt", prompt); err != nil {
+		m.statusMessage = "Clipboard unavailable"
+	} else {
+		m.statusMessage = fmt.Sprintf("Copied commit message prompt (%d staged files)", len(staged))
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/config"
+)
+
+// confirmPolicy returns when to ask before a change (-confirm, else the config)
+func (m Model) confirmPolicy() string {
+	if config.ValidConfirm(m.options.Confirm) {
+		return m.options.Confirm
+	}
+	return m.config.ConfirmPolicy()
+}
+
+// shouldConfirm returns true if the policy asks before an action: a destructive
+// one unless it is "never", any other only when it is "always"
+func (m Model) shouldConfirm(destructive bool) bool {
+	switch m.confirmPolicy() {
+	case config.ConfirmNever:
+		return false
+	case config.ConfirmAlways:
+		return true
+	}
+	return destructive
+}
+
+// confirmAgain returns true if the action named key may run: right away when the
+// policy doesn't ask, otherwise on the second press in a row, after prompting
+func (m *Model) confirmAgain(key string, destructive bool, prompt string) bool {
+	if !m.shouldConfirm(destructive) || m.confirmArmed == key {
+		m.pendingConfirm = ""
+		return true
+	}
+	m.pendingConfirm = key
+	m.statusMessage = prompt
+	m.statusMessageTime = time.Now()
+	return false
+}
@@ -0,0 +1,320 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// Actions of the tree's right-click menu
+const (
+	menuCopyPath     = "Copy path"
+	menuCopyContents = "Copy contents"
+	menuAddToDoc     = "Add to doc…"
+	menuRename       = "Rename"
+	menuDelete       = "Delete"
+)
+
+// menuKeys are the tree keys doing the same as a menu action, shown beside it
+var menuKeys = map[string]string{menuRename: "r", menuDelete: "d"}
+
+// maxMenuDocs bounds the docs listed at once when picking one to add a file to
+const maxMenuDocs = 10
+
+// treeRowAt returns the index in FlatEntries of the tree row at a screen position, -1 if none
+func (m Model) treeRowAt(x, y int) int {
+	if m.gitStatusMode || x >= m.DividerX() {
+		return -1
+	}
+	// Header (1 line) + border (1 line), as for clicks
+	row := y - 2
+	if row < 0 || row >= m.tree.Height {
+		return -1
+	}
+	if i := row + m.treeOffset; i < len(m.FlatEntries()) {
+		return i
+	}
+	return -1
+}
+
+// handleMouseMotion moves the hover highlight in the tree, or in the menu while it's open
+// Terminals that don't report motion without a button held simply never show it.
+func (m Model) handleMouseMotion(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.showingContextMenu {
+		if i := m.contextMenuRowAt(msg.X, msg.Y); i >= 0 {
+			m.contextMenuCursor = i
+		}
+		return m, nil
+	}
+	m.treeHover = m.treeRowAt(msg.X, msg.Y)
+	return m, nil
+}
+
+// openContextMenu selects the tree row that was right-clicked and shows its menu there
+func (m Model) openContextMenu(x, y int) (tea.Model, tea.Cmd) {
+	i := m.treeRowAt(x, y)
+	flat := m.FlatEntries()
+	if i < 0 || flat[i].More > 0 {
+		return m, nil
+	}
+	m.cursor = i
+	m.clearAllOverlays()
+	m.showingContextMenu = true
+	m.contextMenuPath = flat[i].Path
+	m.contextMenuX, m.contextMenuY = x, y
+	m.contextMenuCursor = 0
+	m.contextMenuDocs = false
+	return m.UpdatePreview()
+}
+
+// contextMenuEntry returns the tree entry the menu acts on
+func (m Model) contextMenuEntry() (Entry, bool) {
+	flat := m.FlatEntries()
+	if m.cursor < len(flat) && flat[m.cursor].Path == m.contextMenuPath {
+		return flat[m.cursor], true
+	}
+	return Entry{}, false
+}
+
+// contextMenuItems returns the actions the menu offers for its entry
+func (m Model) contextMenuItems() []string {
+	e, ok := m.contextMenuEntry()
+	if !ok {
+		return nil
+	}
+	items := []string{menuCopyPath}
+	if !e.IsDir {
+		items = append(items, menuCopyContents)
+		if m.docRegistry != nil && len(m.docRegistry.Docs) > 0 {
+			items = append(items, menuAddToDoc)
+		}
+	}
+	if !e.Root {
+		items = append(items, menuRename, menuDelete)
+	}
+	return items
+}
+
+// contextMenuRows returns the rows the menu shows: its actions, or the docs to add the file to
+func (m Model) contextMenuRows() []string {
+	if !m.contextMenuDocs {
+		return m.contextMenuItems()
+	}
+	var rows []string
+	for _, doc := range m.docRegistry.Docs {
+		rows = append(rows, doc.Name)
+	}
+	return rows
+}
+
+// contextMenuBox returns the menu's rendered box and its top-left corner, kept on screen
+func (m Model) contextMenuBox() (string, int, int) {
+	rows := m.contextMenuRows()
+	start := 0
+	if m.contextMenuDocs && m.contextMenuCursor >= maxMenuDocs {
+		start = m.contextMenuCursor - maxMenuDocs + 1
+	}
+	end := len(rows)
+	if m.contextMenuDocs {
+		end = min(end, start+maxMenuDocs)
+	}
+
+	width := 0
+	for _, row := range rows[start:end] {
+		width = max(width, ansi.StringWidth(row)+3)
+	}
+	width = min(width, 40)
+
+	var lines []string
+	for i := start; i < end; i++ {
+		label := ansi.Truncate(rows[i], width-3, "…")
+		key := ""
+		if !m.contextMenuDocs {
+			key = menuKeys[rows[i]]
+		}
+		line := padRight(label, width-1) + styles.Faint.Render(key)
+		if i == m.contextMenuCursor {
+			line = styles.Selected.Render(padRight(label, width-1) + key)
+		}
+		lines = append(lines, line)
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+	x := min(m.contextMenuX, max(0, m.width-lipgloss.Width(box)))
+	y := min(m.contextMenuY, max(0, m.height-lipgloss.Height(box)))
+	return box, x, y
+}
+
+// contextMenuRowAt returns the menu row at a screen position, -1 if none
+func (m Model) contextMenuRowAt(x, y int) int {
+	box, left, top := m.contextMenuBox()
+	if x <= left || x >= left+lipgloss.Width(box)-1 {
+		return -1
+	}
+	row := y - top - 1 // Below the border
+	start := 0
+	if m.contextMenuDocs && m.contextMenuCursor >= maxMenuDocs {
+		start = m.contextMenuCursor - maxMenuDocs + 1
+	}
+	if row < 0 || row >= lipgloss.Height(box)-2 {
+		return -1
+	}
+	return start + row
+}
+
+// updateContextMenu handles input in the tree's right-click menu
+func (m Model) updateContextMenu(msg tea.Msg) (tea.Model, tea.Cmd) {
+	rows := m.contextMenuRows()
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		if msg.Action != tea.MouseActionPress {
+			return m, nil
+		}
+		i := m.contextMenuRowAt(msg.X, msg.Y)
+		if i < 0 {
+			// A click anywhere else closes it
+			m.showingContextMenu = false
+			return m, nil
+		}
+		if msg.Button == tea.MouseButtonLeft {
+			m.contextMenuCursor = i
+			return m.runContextMenuRow()
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			if m.contextMenuDocs {
+				m.contextMenuDocs = false
+				m.contextMenuCursor = 0
+			} else {
+				m.showingContextMenu = false
+			}
+		case "j", "down":
+			if m.contextMenuCursor < len(rows)-1 {
+				m.contextMenuCursor++
+			}
+		case "k", "up":
+			if m.contextMenuCursor > 0 {
+				m.contextMenuCursor--
+			}
+		case "enter", " ":
+			return m.runContextMenuRow()
+		}
+	}
+	return m, nil
+}
+
+// runContextMenuRow runs the action, or adds the file to the doc, under the menu's cursor
+func (m Model) runContextMenuRow() (tea.Model, tea.Cmd) {
+	e, ok := m.contextMenuEntry()
+	rows := m.contextMenuRows()
+	if !ok || m.contextMenuCursor >= len(rows) {
+		m.showingContextMenu = false
+		return m, nil
+	}
+	if m.contextMenuDocs {
+		m.showingContextMenu = false
+		return m.addKeyFileToDoc(m.docRegistry.Docs[m.contextMenuCursor], e.Path)
+	}
+
+	action := rows[m.contextMenuCursor]
+	if action == menuAddToDoc {
+		if m.readOnly {
+			m.showingContextMenu = false
+			return m.readOnlyNotice()
+		}
+		m.contextMenuDocs = true
+		m.contextMenuCursor = 0
+		return m, nil
+	}
+	m.showingContextMenu = false
+	m.activePane = TreePane
+	switch action {
+	case menuCopyPath:
+		path := e.Path
+		if rel, err := filepath.Rel(m.rootPath, e.Path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		if err := m.copyText("path", path); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.statusMessage = "Copied " + path
+		}
+	case menuCopyContents:
+		data, err := os.ReadFile(e.Path)
+		switch {
+		case err != nil:
+			m.statusMessage = "Error: " + err.Error()
+		case bytes.IndexByte(data, 0) >= 0:
+			m.statusMessage = "Binary files can't be copied as text"
+		case m.copyText("contents", string(data)) != nil:
+			m.statusMessage = "Clipboard unavailable"
+		default:
+			m.statusMessage = fmt.Sprintf("Copied %s (%d lines)", e.Name, strings.Count(string(data), "\n"))
+		}
+	case menuRename:
+		return m.startRename()
+	case menuDelete:
+		return m.startDelete()
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// addKeyFileToDoc adds a file to a doc's Key Files
+func (m Model) addKeyFileToDoc(doc groups.ContextDoc, path string) (tea.Model, tea.Cmd) {
+	if rel, err := filepath.Rel(m.rootPath, path); err == nil {
+		path = rel
+	}
+	snap := m.takeUndo("Add key files to " + doc.Name)
+	added, err := groups.AddKeyFiles(filepath.Join(m.rootPath, doc.Package), doc.LocalPath(doc.FilePath), []string{doc.LocalPath(path)}, m.config.BackupFiles)
+	switch {
+	case err != nil:
+		m.statusMessage = fmt.Sprintf("Error: %v", err)
+	case added == 0:
+		m.statusMessage = "Already a key file of " + doc.Name
+	default:
+		m.pushUndo(snap)
+		m.statusMessage = "Added to the key files of " + doc.Name
+	}
+	m.statusMessageTime = time.Now()
+	return m, tea.Batch(ClearStatusAfter(3*time.Second), m.loadRegistryAsync())
+}
+
+// renderContextMenu draws the right-click menu over the view at the pointer
+func (m Model) renderContextMenu(background string) string {
+	box, x, y := m.contextMenuBox()
+	return placeAt(background, box, x, y)
+}
+
+// placeAt draws box over background with its top-left corner at x, y
+func placeAt(background, box string, x, y int) string {
+	bg := strings.Split(background, "\n")
+	for i, line := range strings.Split(box, "\n") {
+		row := y + i
+		if row < 0 || row >= len(bg) {
+			continue
+		}
+		left := ansi.Truncate(bg[row], x, "")
+		if gap := x - ansi.StringWidth(left); gap > 0 {
+			left += strings.Repeat(" ", gap)
+		}
+		right := ansi.TruncateLeft(bg[row], x+ansi.StringWidth(line), "")
+		bg[row] = left + "\x1b[0m" + line + "\x1b[0m" + right
+	}
+	return strings.Join(bg, "\n")
+}
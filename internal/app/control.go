@@ -0,0 +1,111 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/control"
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// controlState is the result of the "state" call
+type controlState struct {
+	Root   string   `json:"root"`
+	Roots  []string `json:"roots,omitempty"` // Extra roots
+	Path   string   `json:"path,omitempty"`  // Previewed file, relative to the root
+	Branch string   `json:"branch,omitempty"`
+}
+
+// handleControl runs a call received on the control socket (-socket)
+func (m Model) handleControl(req control.Request) (tea.Model, tea.Cmd) {
+	var params struct {
+		Path string `json:"path"`
+		Name string `json:"name"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			req.Reply(nil, errors.New("invalid params: "+err.Error()))
+			return m, nil
+		}
+	}
+
+	switch req.Method {
+	case "select":
+		// Select and preview a file, e.g. the buffer open in an editor
+		rel := m.controlPath(params.Path)
+		if rel == "" {
+			req.Reply(nil, errors.New("not in the project: "+params.Path))
+			return m, nil
+		}
+		m = m.NavigateToFile(rel)
+		m.ensureTreeCursorVisible()
+		req.Reply(map[string]string{"path": filepath.ToSlash(rel)}, nil)
+		return m.UpdatePreview()
+
+	case "copyGroup":
+		// Copy a doc category, or a single doc, with its key files as @ references
+		if m.docRegistry == nil {
+			req.Reply(nil, errors.New("context docs are still loading"))
+			return m, nil
+		}
+		docs := m.docRegistry.FindGroup(params.Name)
+		if len(docs) == 0 {
+			req.Reply(nil, errors.New("no category or doc named "+params.Name))
+			return m, nil
+		}
+		refs := groups.GroupRefs(m.rootPath, docs)
+		if err := m.copyText("group", strings.Join(refs, "\n")); err != nil {
+			req.Reply(nil, errors.New("clipboard unavailable"))
+			return m, nil
+		}
+		req.Reply(map[string][]string{"refs": refs}, nil)
+		return m, nil
+
+	case "refreshGit":
+		req.Reply(nil, nil)
+		return m, tea.Batch(m.refreshGitStatus(), m.loadExtraRootsAsync())
+
+	case "state":
+		state := controlState{Root: m.rootPath, Branch: m.gitBranch}
+		for _, r := range m.extraRoots {
+			state.Roots = append(state.Roots, r.Path)
+		}
+		if m.previewPath != "" && !m.gitStatusMode && !m.fileHistoryMode {
+			if rel, err := filepath.Rel(m.rootPath, m.previewPath); err == nil {
+				state.Path = filepath.ToSlash(rel)
+			}
+		}
+		req.Reply(state, nil)
+		return m, nil
+	}
+
+	req.Reply(nil, control.ErrUnknownMethod)
+	return m, nil
+}
+
+// controlPath resolves a path from a control call (absolute, or relative to the
+// root) to one relative to the root, or "" when it isn't a file of the tree
+func (m Model) controlPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.rootPath, path)
+	}
+	path = filepath.Clean(path)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(m.rootPath, path)
+	if err != nil || rel == "." {
+		return ""
+	}
+	if strings.HasPrefix(rel, "..") && m.extraRootFor(path) == nil {
+		return ""
+	}
+	return rel
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/clipboard"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// copyHistoryLog is where copies are appended when copyHistoryLog is set in the config
+const copyHistoryLog = ".contextui/history.log"
+
+// CopyEntry is one thing copied to the clipboard during the session
+type CopyEntry struct {
+	Time time.Time
+	Kind string // What was copied, e.g. "file", "selection", "regions"
+	Text string
+}
+
+// Summary returns the first line of the copied text and how much more there is
+func (e CopyEntry) Summary() string {
+	lines := strings.Split(strings.TrimRight(e.Text, "\n"), "\n")
+	if len(lines) == 1 {
+		return lines[0]
+	}
+	return fmt.Sprintf("%s (+%d lines)", lines[0], len(lines)-1)
+}
+
+// copyText copies text to the clipboard and records it in the session history
+func (m *Model) copyText(kind, text string) error {
+	if err := clipboard.CopyRaw(text); err != nil {
+		debuglog.Error("clipboard", err)
+		return err
+	}
+	entry := CopyEntry{Time: time.Now(), Kind: kind, Text: text}
+	m.copyHistory = append(m.copyHistory, entry)
+	if m.config.CopyHistoryLog && !m.readOnly {
+		debuglog.Report("log copy", appendCopyLog(m.rootPath, entry))
+	}
+	return nil
+}
+
+// appendCopyLog appends an entry to the project's history log
+func appendCopyLog(rootPath string, e CopyEntry) error {
+	path := filepath.Join(rootPath, copyHistoryLog)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "## %s %s\n%s\n\n", e.Time.Format(time.RFC3339), e.Kind, strings.TrimRight(e.Text, "\n")); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// updateCopyHistory handles input in the copy history overlay (newest entry first)
+func (m Model) updateCopyHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q", "Y":
+		m.showingCopyHistory = false
+
+	case "j", "down":
+		if m.copyHistoryCursor < len(m.copyHistory)-1 {
+			m.copyHistoryCursor++
+		}
+
+	case "k", "up":
+		if m.copyHistoryCursor > 0 {
+			m.copyHistoryCursor--
+		}
+
+	case "enter", "c":
+		if len(m.copyHistory) == 0 {
+			return m, nil
+		}
+		entry := m.copyHistory[len(m.copyHistory)-1-m.copyHistoryCursor]
+		if err := m.copyText(entry.Kind, entry.Text); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.statusMessage = "Copied again: " + entry.Summary()
+			m.showingCopyHistory = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m, nil
+}
+
+// renderCopyHistoryOverlay renders everything copied this session
+func (m Model) renderCopyHistoryOverlay(background string) string {
+	boxWidth := m.overlayWidth(72)
+	maxVisible := m.height - 14
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Copy History"))
+	lines = append(lines, "")
+
+	if len(m.copyHistory) == 0 {
+		lines = append(lines, styles.Muted.Render("Nothing copied yet this session."))
+	} else {
+		start := 0
+		if m.copyHistoryCursor >= maxVisible {
+			start = m.copyHistoryCursor - maxVisible + 1
+		}
+		end := start + maxVisible
+		if end > len(m.copyHistory) {
+			end = len(m.copyHistory)
+		}
+
+		for i := start; i < end; i++ {
+			e := m.copyHistory[len(m.copyHistory)-1-i]
+			label := fmt.Sprintf("%s  %-13s %s", e.Time.Format("15:04:05"), e.Kind, e.Summary())
+			label = ansi.Truncate(label, boxWidth-8, "…")
+			if i == m.copyHistoryCursor {
+				lines = append(lines, styles.Selected.Render(" "+label+" "))
+			} else {
+				lines = append(lines, " "+styles.Normal.Render(label))
+			}
+		}
+	}
+
+	lines = append(lines, "")
+	if m.config.CopyHistoryLog && !m.readOnly {
+		lines = append(lines, styles.Muted.Render("Logging to "+copyHistoryLog))
+		lines = append(lines, "")
+	}
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] copy again  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// coverageState is how well a tree entry is covered by context docs' Key Files
+type coverageState int
+
+const (
+	coverageNone    coverageState = iota // Not listed by any doc
+	coveragePartial                      // Directory with some covered entries below it
+	coverageStale                        // Listed only by stale docs
+	coverageCovered                      // Listed by an up-to-date doc
+)
+
+// coverageBadges are the tree markers for each coverage state
+var coverageBadges = map[coverageState]string{
+	coverageNone:    "○",
+	coveragePartial: "◐",
+	coverageStale:   "●",
+	coverageCovered: "●",
+}
+
+// coverageTextBadges replace coverageBadges in accessible mode, where stale and
+// covered can't be told apart by color
+var coverageTextBadges = map[coverageState]string{
+	coverageNone:    "no doc",
+	coveragePartial: "partly",
+	coverageStale:   "stale",
+	coverageCovered: "doc",
+}
+
+// coverageBadge returns the tree marker for a coverage state
+func coverageBadge(state coverageState) string {
+	if styles.Accessible() {
+		return styles.Badge(coverageTextBadges[state])
+	}
+	return coverageBadges[state]
+}
+
+// coverageIndex answers coverage lookups for tree entries
+type coverageIndex struct {
+	listed    map[string]coverageState // Key files (and directories) as listed by docs
+	ancestors map[string]bool          // Directories containing a listed path
+}
+
+// newCoverageIndex indexes the Key Files of every registered doc
+// A key file listed by both a stale and an up-to-date doc counts as covered.
+func newCoverageIndex(rootPath string, registry *groups.ContextDocRegistry) coverageIndex {
+	idx := coverageIndex{listed: make(map[string]coverageState), ancestors: make(map[string]bool)}
+	if registry == nil {
+		return idx
+	}
+	for _, d := range registry.Docs {
+		state := coverageCovered
+		if d.IsStale {
+			state = coverageStale
+		}
+		for _, kf := range d.KeyFiles {
+			if groups.IsExternalKeyFile(rootPath, kf) {
+				continue
+			}
+			path := filepath.Clean(kf)
+			if state > idx.listed[path] {
+				idx.listed[path] = state
+			}
+			for dir := filepath.Dir(path); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+				idx.ancestors[dir] = true
+			}
+		}
+	}
+	return idx
+}
+
+// state returns the coverage of a path relative to the root
+// Entries inside a listed directory take its state.
+func (c coverageIndex) state(relPath string, isDir bool) coverageState {
+	for p := relPath; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		if s, ok := c.listed[p]; ok {
+			return s
+		}
+	}
+	if isDir && c.ancestors[relPath] {
+		return coveragePartial
+	}
+	return coverageNone
+}
+
+// coverageStyles colors the coverage badges
+func coverageStyles() map[coverageState]lipgloss.Style {
+	return map[coverageState]lipgloss.Style{
+		coverageNone:    lipgloss.NewStyle().Foreground(styles.TextFaint),
+		coveragePartial: lipgloss.NewStyle().Foreground(styles.Info),
+		coverageStale:   styles.StatusWarning,
+		coverageCovered: styles.StatusSuccess,
+	}
+}
+
+// coverageLegend explains the badges in the footer while coverage is shown
+func coverageLegend() string {
+	s := coverageStyles()
+	return strings.Join([]string{
+		s[coverageCovered].Render(coverageBadge(coverageCovered) + " covered"),
+		s[coverageStale].Render(coverageBadge(coverageStale) + " stale doc"),
+		s[coveragePartial].Render(coverageBadge(coveragePartial) + " partly"),
+		s[coverageNone].Render(coverageBadge(coverageNone) + " no doc"),
+	}, " ") + "  "
+}
+
+// toggleCoverage shows or hides doc coverage badges in the tree
+func (m Model) toggleCoverage() (tea.Model, tea.Cmd) {
+	m.showCoverage = !m.showCoverage
+	if m.showCoverage {
+		m.statusMessage = "Doc coverage shown (u to hide)"
+	} else {
+		m.statusMessage = "Doc coverage hidden"
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
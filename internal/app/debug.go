@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/cache"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// debugSections are the logged events the debug overlay lists, latest first
+var debugSections = []struct{ kind, title string }{
+	{debuglog.KindError, "Errors"},
+	{debuglog.KindGit, "Git commands"},
+	{debuglog.KindWatch, "Watcher events"},
+}
+
+// updateDebug handles input for the debug overlay
+func (m Model) updateDebug(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc", "q", "I":
+			m.showingDebug = false
+		}
+	}
+	return m, nil
+}
+
+// renderDebugOverlay shows the usage and hit rate of each render cache, and the
+// latest errors, git commands and watcher events
+func (m Model) renderDebugOverlay(background string) string {
+	headerStyle := lipgloss.NewStyle().Foreground(styles.TextMuted).Bold(true)
+	row := "%-9s %9s %17s %7s %9s %9s"
+	width := m.overlayWidth(min(max(m.width-10, 40), 100))
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Debug"))
+	lines = append(lines, "")
+	lines = append(lines, headerStyle.Render(fmt.Sprintf(row, "Cache", "Entries", "Size", "Hits", "Misses", "Evicted")))
+	for _, s := range []cache.Stats{m.previewCache.Stats(), m.diffCache.Stats(), m.imageCache.Stats()} {
+		lines = append(lines, fmt.Sprintf(row,
+			s.Name,
+			fmt.Sprintf("%d/%d", s.Entries, s.MaxEntries),
+			humanSize(s.Bytes)+" / "+humanSize(s.MaxBytes),
+			fmt.Sprintf("%.0f%%", s.HitRate()*100),
+			fmt.Sprint(s.Misses),
+			fmt.Sprint(s.Evictions)))
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render(fmt.Sprintf("Heap in use: %s   Goroutines: %d", humanSize(int64(mem.HeapInuse)), runtime.NumGoroutine())))
+	lines = append(lines, styles.Faint.Render("Size limits: previewCacheMB, diffCacheMB, imageCacheMB in "+config.FileName))
+
+	// What's left of the screen is shared by the event sections
+	perSection := max(2, (m.height-len(lines)-16)/len(debugSections))
+	for _, sec := range debugSections {
+		entries := debuglog.Recent(sec.kind)
+		lines = append(lines, "")
+		lines = append(lines, headerStyle.Render(fmt.Sprintf("%s (%d)", sec.title, len(entries))))
+		if len(entries) == 0 {
+			lines = append(lines, styles.Faint.Render("  None yet"))
+			continue
+		}
+		for i := len(entries) - 1; i >= max(0, len(entries)-perSection); i-- {
+			e := entries[i]
+			line := ansi.Truncate("  "+e.Time.Format("15:04:05")+"  "+e.Message, width, "…")
+			if sec.kind == debuglog.KindError {
+				line = styles.StatusError.Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	lines = append(lines, "")
+	if path := debuglog.Path(); path != "" {
+		lines = append(lines, styles.Faint.Render("Log: "+path))
+	} else {
+		lines = append(lines, styles.Faint.Render("Start with -log to also write these to a log file"))
+	}
+	lines = append(lines, styles.Faint.Render("[esc] close"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// ErrorReportedMsg is sent when a failure was reported with debuglog.Report
+type ErrorReportedMsg struct {
+	Entry debuglog.Entry
+}
+
+// waitForErrorReport returns a command that waits for the next reported failure
+func waitForErrorReport() tea.Cmd {
+	return func() tea.Msg {
+		return ErrorReportedMsg{Entry: <-debuglog.Reports()}
+	}
+}
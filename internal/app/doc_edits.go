@@ -0,0 +1,316 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// What a doc edit does, shown above its diff
+const (
+	docEditAnnotate = "Add structure tags"
+	docEditUpdate   = "Update structure tags"
+	docEditStrip    = "Remove contexTUI metadata"
+)
+
+// docEdit is a change to a doc's markdown waiting to be accepted or skipped
+type docEdit struct {
+	Kind     string // docEdit* constant
+	Path     string // Relative to the root
+	Original string
+	Updated  string
+}
+
+// docEditFor reads a doc and returns the edit change makes to it, false if it makes none
+func docEditFor(rootPath, kind, path string, change func(string) string) (docEdit, bool) {
+	content, err := os.ReadFile(filepath.Join(rootPath, path))
+	if err != nil {
+		return docEdit{}, false
+	}
+	updated := change(string(content))
+	if updated == string(content) {
+		return docEdit{}, false
+	}
+	return docEdit{Kind: kind, Path: path, Original: string(content), Updated: updated}, true
+}
+
+// reviewDocEdits shows edits one by one to accept or skip, or writes them right away
+// when the confirm policy is never; the returned command reloads the registry then
+func (m *Model) reviewDocEdits(edits []docEdit) tea.Cmd {
+	if len(edits) == 0 {
+		return nil
+	}
+	if m.confirmPolicy() == config.ConfirmNever {
+		for _, edit := range edits {
+			snap := m.takeUndo(edit.Kind+" in "+edit.Path, edit.Path)
+			if err := groups.ReplaceDoc(m.rootPath, edit.Path, edit.Original, edit.Updated, m.config.BackupFiles); err != nil {
+				m.statusMessage = "Error: " + docEditFailure(edit, err)
+				m.statusMessageTime = time.Now()
+				continue
+			}
+			m.pushUndo(snap)
+		}
+		return m.loadRegistryAsync()
+	}
+	m.docEdits = append(m.docEdits, edits...)
+	m.showingDocEdits = true
+	m.docEditScroll = 0
+	return nil
+}
+
+// docEditFailure describes why an edit couldn't be written
+func docEditFailure(edit docEdit, err error) string {
+	if errors.Is(err, groups.ErrConcurrentEdit) {
+		return edit.Path + " changed since this preview; skipped"
+	}
+	return fmt.Sprintf("%s: %v", edit.Path, err)
+}
+
+// nextDocEdit moves on from the edit shown, reporting and reloading the docs after the last
+func (m Model) nextDocEdit(accept bool) (tea.Model, tea.Cmd) {
+	if len(m.docEdits) == 0 {
+		m.showingDocEdits = false
+		return m, nil
+	}
+	if accept {
+		edit := m.docEdits[0]
+		snap := m.takeUndo(edit.Kind+" in "+edit.Path, edit.Path)
+		if err := groups.ReplaceDoc(m.rootPath, edit.Path, edit.Original, edit.Updated, m.config.BackupFiles); err != nil {
+			m.docEditError = docEditFailure(edit, err)
+		} else {
+			m.pushUndo(snap)
+			m.docEditsWritten++
+		}
+	} else {
+		m.docEditsSkipped++
+	}
+	m.docEdits = m.docEdits[1:]
+	m.docEditScroll = 0
+	if len(m.docEdits) > 0 {
+		return m, nil
+	}
+
+	m.showingDocEdits = false
+	switch {
+	case m.docEditError != "":
+		m.statusMessage = "Error: " + m.docEditError
+	case m.docEditsSkipped > 0:
+		m.statusMessage = fmt.Sprintf("Wrote %d doc edit(s), skipped %d", m.docEditsWritten, m.docEditsSkipped)
+	default:
+		m.statusMessage = fmt.Sprintf("Wrote %d doc edit(s)", m.docEditsWritten)
+	}
+	m.statusMessageTime = time.Now()
+	m.docEditsWritten, m.docEditsSkipped, m.docEditError = 0, 0, ""
+	return m, tea.Batch(ClearStatusAfter(5*time.Second), m.loadRegistryAsync())
+}
+
+// updateDocEdits handles input in the doc edit preview
+func (m Model) updateDocEdits(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch keyMsg.String() {
+	case "y", "enter":
+		return m.nextDocEdit(true)
+	case "n", "s":
+		return m.nextDocEdit(false)
+	case "A":
+		// Accept this and every edit after it
+		for len(m.docEdits) > 1 {
+			next, _ := m.nextDocEdit(true)
+			m = next.(Model)
+		}
+		return m.nextDocEdit(true)
+	case "esc", "q":
+		// Skip the rest
+		for len(m.docEdits) > 1 {
+			next, _ := m.nextDocEdit(false)
+			m = next.(Model)
+		}
+		return m.nextDocEdit(false)
+	case "j", "down":
+		m.docEditScroll++
+	case "k", "up":
+		if m.docEditScroll > 0 {
+			m.docEditScroll--
+		}
+	}
+	return m, nil
+}
+
+// diffLine is a line of a doc edit's diff: ' ' kept, '-' removed, '+' added
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// lineDiff returns the line changes turning before into after. Edits to docs touch a
+// few lines, so after trimming what both share at the ends the rest is compared fully.
+func lineDiff(before, after string) []diffLine {
+	a, b := strings.Split(before, "\n"), strings.Split(after, "\n")
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var diff []diffLine
+	for _, line := range a[:prefix] {
+		diff = append(diff, diffLine{' ', line})
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > 4_000_000 {
+		// Too big to compare line by line; show it as replaced
+		for _, line := range midA {
+			diff = append(diff, diffLine{'-', line})
+		}
+		for _, line := range midB {
+			diff = append(diff, diffLine{'+', line})
+		}
+	} else {
+		// Longest common subsequence, walked from the front
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(midA) || j < len(midB) {
+			switch {
+			case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+				diff = append(diff, diffLine{' ', midA[i]})
+				i++
+				j++
+			case j < len(midB) && (i == len(midA) || lcs[i][j+1] >= lcs[i+1][j]):
+				diff = append(diff, diffLine{'+', midB[j]})
+				j++
+			default:
+				diff = append(diff, diffLine{'-', midA[i]})
+				i++
+			}
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, diffLine{' ', line})
+	}
+	return diff
+}
+
+// renderDocEditDiff renders an edit's changed lines with a few lines of context
+// around each, and … where unchanged lines are left out
+func renderDocEditDiff(edit docEdit, width int) []string {
+	const context = 2
+	diff := lineDiff(edit.Original, edit.Updated)
+	near := make([]bool, len(diff))
+	for i, d := range diff {
+		if d.op == ' ' {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(diff)-1, i+context); j++ {
+			near[j] = true
+		}
+	}
+
+	addStyle := lipgloss.NewStyle().Foreground(styles.GitAdded)
+	removeStyle := lipgloss.NewStyle().Foreground(styles.GitDeleted)
+	var lines []string
+	skipped := false
+	for i, d := range diff {
+		if !near[i] {
+			skipped = true
+			continue
+		}
+		if skipped && len(lines) > 0 {
+			lines = append(lines, styles.Faint.Render("  …"))
+		}
+		skipped = false
+		line := ansi.Truncate(string(d.op)+" "+d.text, width, "…")
+		switch d.op {
+		case '+':
+			line = addStyle.Render(line)
+		case '-':
+			line = removeStyle.Render(line)
+		default:
+			line = styles.Faint.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// renderDocEditsOverlay renders the edit waiting to be accepted or skipped
+func (m Model) renderDocEditsOverlay(background string) string {
+	boxWidth := m.overlayWidth(min(max(m.width*80/100, 50), 100))
+	fixedHeight := max(m.height-6, 12)
+	if len(m.docEdits) == 0 {
+		return background
+	}
+	edit := m.docEdits[0]
+
+	var lines []string
+	title := edit.Kind
+	if len(m.docEdits) > 1 {
+		title += fmt.Sprintf(" (1 of %d)", len(m.docEdits))
+	}
+	lines = append(lines, styles.Title.Render(title))
+	lines = append(lines, styles.Faint.Render(ansi.Truncate(filepath.ToSlash(edit.Path), boxWidth-6, "…")))
+	lines = append(lines, "")
+
+	diff := renderDocEditDiff(edit, boxWidth-8)
+	rows := max(3, fixedHeight-len(lines)-6)
+	scroll := min(m.docEditScroll, max(0, len(diff)-rows))
+	end := min(scroll+rows, len(diff))
+	lines = append(lines, diff[scroll:end]...)
+	if end < len(diff) {
+		lines = append(lines, styles.Faint.Render("  ▼ more below"))
+	}
+
+	lines = append(lines, "")
+	footer := "[y] write  [n] skip  [esc] skip all  [j/k] scroll"
+	if len(m.docEdits) > 1 {
+		footer = "[y] write  [n] skip  [A] write all  [esc] skip all  [j/k] scroll"
+	}
+	lines = append(lines, styles.Faint.Render(footer))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
+
+// structureTagEdit returns the edit bringing a doc's structure tags in line with
+// its missing fields, false if they already are
+func structureTagEdit(rootPath, kind string, doc groups.ContextDoc) (docEdit, bool) {
+	return docEditFor(rootPath, kind, doc.FilePath, func(content string) string {
+		return groups.ApplyStructureAnnotation(content, doc.MissingFields, time.Now())
+	})
+}
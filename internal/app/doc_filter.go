@@ -0,0 +1,108 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// filterTreeToDoc limits the tree to a doc's key files, expanded to show them,
+// until the filter is cleared with esc
+func (m Model) filterTreeToDoc(doc groups.ContextDoc) (tea.Model, tea.Cmd) {
+	// Listed paths show everything below them; their ancestors only lead to them
+	filter := make(map[string]bool)
+	var keyFiles []string
+	for _, kf := range doc.KeyFiles {
+		if groups.IsExternalKeyFile(m.rootPath, kf) {
+			continue
+		}
+		if _, err := os.Stat(groups.ResolveKeyFile(m.rootPath, kf)); err != nil {
+			continue
+		}
+		path := filepath.Clean(kf)
+		keyFiles = append(keyFiles, path)
+		filter[path] = true
+		for dir := filepath.Dir(path); dir != "."; dir = filepath.Dir(dir) {
+			if !filter[dir] {
+				filter[dir] = false
+			}
+		}
+	}
+	if len(keyFiles) == 0 {
+		m.statusMessage = "No key files in the tree for " + doc.Name
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	m.clearAllOverlays()
+	m.activePane = TreePane
+	m.treeFilter = filter
+	m.treeFilterDoc = doc.Name
+	m.treeOffset = 0
+	// Open the parents of every key file, and listed directories themselves
+	for _, kf := range keyFiles {
+		m = m.NavigateToFile(kf)
+		if info, err := os.Stat(filepath.Join(m.rootPath, kf)); err == nil && info.IsDir() {
+			m.entries = expandPath(m.entries, filepath.Join(m.rootPath, kf), m.rootPath, m.showDotfiles)
+		}
+	}
+	m = m.NavigateToFile(keyFiles[0])
+	m.ensureTreeCursorVisible()
+
+	m.statusMessage = fmt.Sprintf("Showing %d key files of %s (esc to show all)", len(keyFiles), doc.Name)
+	m.statusMessageTime = time.Now()
+	var cmd tea.Cmd
+	m, cmd = m.UpdatePreview()
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
+
+// clearTreeFilter shows the whole tree again, keeping the cursor on the same entry
+func (m Model) clearTreeFilter() (tea.Model, tea.Cmd) {
+	var current string
+	if flat := m.FlatEntries(); m.cursor < len(flat) {
+		current = flat[m.cursor].Path
+	}
+	m.treeFilter = nil
+	m.treeFilterDoc = ""
+	m.InvalidateTreeCache()
+	for i, e := range m.FlatEntries() {
+		if e.Path == current {
+			m.cursor = i
+			break
+		}
+	}
+	m.ensureTreeCursorVisible()
+	return m, nil
+}
+
+// flatten lists the visible tree entries, keeping only the doc filter's paths when set
+func (m Model) flatten() []Entry {
+	if m.treeFilter == nil {
+		return flattenEntries(m.entries)
+	}
+	return filterEntries(m.entries, m.rootPath, m.treeFilter, false)
+}
+
+// filterEntries flattens entries listed in filter (or below a listed directory when all is set)
+func filterEntries(entries []Entry, rootPath string, filter map[string]bool, all bool) []Entry {
+	var flat []Entry
+	for _, e := range entries {
+		rel, err := filepath.Rel(rootPath, e.Path)
+		if err != nil {
+			continue
+		}
+		listed, ok := filter[rel]
+		if !all && !ok {
+			continue
+		}
+		flat = append(flat, e)
+		if e.IsDir && e.Expanded {
+			flat = append(flat, filterEntries(e.Children, rootPath, filter, all || listed)...)
+		}
+	}
+	return flat
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// readerIndexWidth is the width of the reader's key files index
+const readerIndexWidth = 36
+
+// openDocReader shows a doc rendered full-screen, read fresh from disk
+func (m Model) openDocReader(doc groups.ContextDoc) (tea.Model, tea.Cmd) {
+	m.showingReader = true
+	m.readerDoc = doc
+	m.readerScroll = 0
+	m.readerCursor = 0
+	m.readerIndexFocus = false
+	m.renderDocReader()
+	return m, nil
+}
+
+// renderDocReader renders the reader's doc with glamour at the current width
+func (m *Model) renderDocReader() {
+	content, err := os.ReadFile(filepath.Join(m.rootPath, m.readerDoc.FilePath))
+	if err != nil {
+		m.readerLines = []string{styles.StatusError.Render("Error: " + err.Error())}
+		return
+	}
+	text := linkIssueRefs(m.issueRules, string(content))
+	wrap := max(m.readerTextWidth()-4, 20)
+	if renderer, err := glamour.NewTermRenderer(glamourStyleOption(), glamour.WithWordWrap(wrap)); err == nil {
+		if rendered, err := renderer.Render(text); err == nil {
+			text = rendered
+		}
+	}
+	m.readerLines = strings.Split(strings.TrimRight(text, "\n"), "\n")
+}
+
+// readerTextWidth returns the width of the rendered doc column
+func (m Model) readerTextWidth() int {
+	if len(m.readerDoc.KeyFiles) == 0 {
+		return min(m.width-4, 120)
+	}
+	return min(m.width-readerIndexWidth-6, 120)
+}
+
+// readerRows returns how many doc lines the reader shows
+func (m Model) readerRows() int {
+	return max(3, m.height-6)
+}
+
+// scrollReader moves the rendered doc, clamped to its length
+func (m *Model) scrollReader(delta int) {
+	maxScroll := max(0, len(m.readerLines)-m.readerRows())
+	m.readerScroll = min(max(0, m.readerScroll+delta), maxScroll)
+}
+
+// jumpToKeyFile closes the overlays and selects a key file in the tree
+func (m Model) jumpToKeyFile(keyFile string) (tea.Model, tea.Cmd) {
+	if groups.IsExternalKeyFile(m.rootPath, keyFile) {
+		m.statusMessage = "Outside the project: " + keyFile
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if _, err := os.Stat(groups.ResolveKeyFile(m.rootPath, keyFile)); err != nil {
+		m.statusMessage = "Key file not found: " + keyFile
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	m.clearAllOverlays()
+	m.showingReader = false
+	m.activePane = TreePane
+	m = m.NavigateToFile(filepath.Clean(keyFile))
+	m.ensureTreeCursorVisible()
+	return m.UpdatePreview()
+}
+
+// updateDocReader handles input in the full-screen doc reader
+func (m Model) updateDocReader(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.renderDocReader()
+		m.scrollReader(0)
+		return m, nil
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.scrollReader(-3)
+		case tea.MouseButtonWheelDown:
+			m.scrollReader(3)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		keyFiles := m.readerDoc.KeyFiles
+		half := m.readerRows() / 2
+		key := msg.String()
+
+		// 1-9 jump straight to a key file
+		if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+			if i := int(key[0] - '1'); i < len(keyFiles) {
+				return m.jumpToKeyFile(keyFiles[i])
+			}
+			return m, nil
+		}
+
+		switch key {
+		case "esc", "q", "o":
+			m.showingReader = false
+		case "tab":
+			m.readerIndexFocus = !m.readerIndexFocus && len(keyFiles) > 0
+		case "enter":
+			if m.readerIndexFocus && m.readerCursor < len(keyFiles) {
+				return m.jumpToKeyFile(keyFiles[m.readerCursor])
+			}
+		case "j", "down":
+			if m.readerIndexFocus {
+				m.readerCursor = min(m.readerCursor+1, max(0, len(keyFiles)-1))
+			} else {
+				m.scrollReader(1)
+			}
+		case "k", "up":
+			if m.readerIndexFocus {
+				m.readerCursor = max(m.readerCursor-1, 0)
+			} else {
+				m.scrollReader(-1)
+			}
+		case "ctrl+d", "J", "pgdown", " ":
+			m.scrollReader(half)
+		case "ctrl+u", "K", "pgup":
+			m.scrollReader(-half)
+		case "g", "home":
+			m.readerScroll = 0
+		case "G", "end":
+			m.scrollReader(len(m.readerLines))
+		case "c":
+			if err := m.copyText("doc", "@"+m.readerDoc.FilePath); err != nil {
+				m.statusMessage = "Clipboard unavailable"
+			} else {
+				m.statusMessage = "Copied: @" + m.readerDoc.FilePath
+			}
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(3 * time.Second)
+		}
+	}
+	return m, nil
+}
+
+// renderDocReaderOverlay renders the doc full-screen with its key files alongside
+func (m Model) renderDocReaderOverlay(background string) string {
+	rows := m.readerRows()
+	textWidth := m.readerTextWidth()
+
+	end := min(m.readerScroll+rows, len(m.readerLines))
+	var body []string
+	for _, line := range m.readerLines[m.readerScroll:end] {
+		body = append(body, ansi.Truncate(line, textWidth, ""))
+	}
+	text := lipgloss.NewStyle().Width(textWidth).Height(rows).Render(strings.Join(body, "\n"))
+
+	if keyFiles := m.readerDoc.KeyFiles; len(keyFiles) > 0 {
+		var index []string
+		index = append(index, styles.Title.Render("Key Files"), "")
+		for i, kf := range keyFiles {
+			label := "  " + kf
+			if i < 9 {
+				label = fmt.Sprintf("%d %s", i+1, kf)
+			}
+			label = ansi.Truncate(label, readerIndexWidth-2, "…")
+			switch {
+			case m.readerIndexFocus && i == m.readerCursor:
+				index = append(index, styles.Selected.Render(label))
+			case slices.Contains(m.readerDoc.BrokenKeyFiles, kf):
+				index = append(index, styles.StatusError.Render(label))
+			default:
+				index = append(index, styles.Normal.Render(label))
+			}
+		}
+		indexBox := lipgloss.NewStyle().
+			Width(readerIndexWidth).
+			Height(rows).
+			PaddingLeft(2).
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(styles.BorderInactive).
+			Render(strings.Join(index, "\n"))
+		text = lipgloss.JoinHorizontal(lipgloss.Top, text, indexBox)
+	}
+
+	position := ""
+	if len(m.readerLines) > rows {
+		position = fmt.Sprintf("  %d%%", (end*100)/len(m.readerLines))
+	}
+	header := styles.Title.Render(m.readerDoc.Name) + "  " + styles.Faint.Render(m.readerDoc.FilePath+position)
+	footer := "[j/k] scroll  [J/K] page  [g/G] top/bottom  [1-9] jump to key file  [tab] key files  [c] copy @ref  [esc] back"
+	if m.statusMessage != "" && time.Since(m.statusMessageTime) < 3*time.Second {
+		footer = m.statusMessage
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(0, 1).
+		Width(m.width - 2).
+		Height(m.height - 2)
+
+	footer = ansi.Truncate(footer, m.width-6, "…")
+	return boxStyle.Render(header + "\n\n" + text + "\n" + styles.Faint.Render(footer))
+}
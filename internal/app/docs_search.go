@@ -0,0 +1,121 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/sahilm/fuzzy"
+)
+
+// newDocsSearchInput creates the docs overlay's filter prompt
+func newDocsSearchInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "name, description or key file"
+	ti.Prompt = "/ "
+	ti.CharLimit = 100
+	ti.Width = 40
+	return ti
+}
+
+// searchDocs returns the docs of every category matching query, best first
+// Names and key file paths match fuzzily; descriptions need every word of the query.
+// A query starting with #tag keeps only docs with that tag.
+func (m Model) searchDocs(query string) []groups.ContextDoc {
+	if m.docRegistry == nil {
+		return nil
+	}
+	var tag string
+	if strings.HasPrefix(query, "#") {
+		tag, query, _ = strings.Cut(strings.TrimPrefix(query, "#"), " ")
+		query = strings.TrimSpace(query)
+	}
+	words := strings.Fields(strings.ToLower(query))
+	type scored struct {
+		doc   groups.ContextDoc
+		score int
+	}
+	var results []scored
+	for _, d := range m.docRegistry.Docs {
+		if tag != "" && !d.HasTag(tag) {
+			continue
+		}
+		if query == "" {
+			results = append(results, scored{d, 0})
+			continue
+		}
+		best, found := 0, false
+		for _, match := range fuzzy.Find(query, append([]string{d.Name}, d.KeyFiles...)) {
+			if !found || match.Score > best {
+				best, found = match.Score, true
+			}
+		}
+		if !found {
+			desc := strings.ToLower(d.Description)
+			found = true
+			for _, w := range words {
+				found = found && strings.Contains(desc, w)
+			}
+			best = -1000 // Below any name or path match
+		}
+		if found {
+			results = append(results, scored{d, best})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	docs := make([]groups.ContextDoc, len(results))
+	for i, r := range results {
+		docs[i] = r.doc
+	}
+	return docs
+}
+
+// updateDocsSearch handles typing in the docs overlay's filter
+func (m Model) updateDocsSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.docsSearchInput.Blur()
+			m.docsQuery = ""
+			m.docCursor = 0
+			m.docsScrollOffset = 0
+			return m, nil
+		case "enter", "down", "up":
+			// Keep the filter and go back to browsing its results
+			m.docsSearchInput.Blur()
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.docsSearchInput, cmd = m.docsSearchInput.Update(msg)
+	if query := strings.TrimSpace(m.docsSearchInput.Value()); query != m.docsQuery {
+		m.docsQuery = query
+		m.docCursor = 0
+		m.docsScrollOffset = 0
+	}
+	return m, cmd
+}
+
+// nextTagQuery returns the filter for the tag after the one in query,
+// or "" after the last tag
+func nextTagQuery(tags []string, query string) string {
+	var current string
+	if strings.HasPrefix(query, "#") {
+		current, _, _ = strings.Cut(strings.TrimPrefix(query, "#"), " ")
+	}
+	if current == "" {
+		if len(tags) > 0 {
+			return "#" + tags[0]
+		}
+		return ""
+	}
+	for i, tag := range tags {
+		if strings.EqualFold(tag, current) && i+1 < len(tags) {
+			return "#" + tags[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// updateErrors handles input in the errors overlay
+func (m Model) updateErrors(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q", "E":
+		m.showingErrors = false
+	case "r":
+		// Retry - permissions may have been fixed since the last load
+		m.showingErrors = false
+		m.loadingMessage = "Refreshing..."
+		m.pendingLoads = 2
+		return m, tea.Batch(
+			m.loadDirectoryAsync(),
+			m.loadAllFilesAsync(),
+			SpinnerTick(),
+		)
+	}
+	return m, nil
+}
+
+// renderErrorsOverlay renders problems encountered while loading the project
+func (m Model) renderErrorsOverlay(background string) string {
+	boxWidth := m.width * 60 / 100
+	if boxWidth > 80 {
+		boxWidth = 80
+	}
+	if boxWidth < 40 {
+		boxWidth = 40
+	}
+	boxWidth = m.overlayWidth(boxWidth)
+	maxPaths := m.height - 16
+	if maxPaths < 3 {
+		maxPaths = 3
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Errors"))
+	lines = append(lines, "")
+
+	if len(m.deniedPaths) == 0 {
+		lines = append(lines, styles.Muted.Render("No problems."))
+	} else {
+		lines = append(lines, styles.StatusWarning.Render(
+			fmt.Sprintf("%d paths skipped due to permissions", len(m.deniedPaths))))
+		lines = append(lines, "")
+		for i, path := range m.deniedPaths {
+			if i == maxPaths {
+				lines = append(lines, styles.Faint.Render(fmt.Sprintf("  … and %d more", len(m.deniedPaths)-maxPaths)))
+				break
+			}
+			lines = append(lines, "  🔒 "+styles.Normal.Render(path))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[r] retry  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
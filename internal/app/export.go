@@ -0,0 +1,160 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// defaultExportPath is suggested the first time something is written to a file
+const defaultExportPath = "context-bundle.md"
+
+// newExportInput creates the export overlay's filename prompt
+func newExportInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = defaultExportPath
+	ti.CharLimit = 255
+	ti.Width = 60
+	return ti
+}
+
+// openExport asks where to write payload, instead of copying it to the clipboard
+// what describes the payload in the overlay, e.g. "basket".
+func (m Model) openExport(what, payload string) (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	m.clearAllOverlays()
+	m.showingExport = true
+	m.exportWhat = what
+	m.exportPayload = payload
+	m.exportConfirm = false
+	m.exportError = ""
+	path := m.lastExportPath
+	if path == "" {
+		path = defaultExportPath
+	}
+	m.exportInput.SetValue(path)
+	m.exportInput.CursorEnd()
+	m.exportInput.Focus()
+	return m, textinput.Blink
+}
+
+// exportTarget resolves the typed filename against the project root
+func (m Model) exportTarget() string {
+	path := strings.TrimSpace(m.exportInput.Value())
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.rootPath, path)
+}
+
+// writeExport writes the payload, asking before an existing file is replaced
+func (m Model) writeExport() (tea.Model, tea.Cmd) {
+	target := m.exportTarget()
+	if target == "" {
+		m.exportError = "Enter a filename"
+		return m, nil
+	}
+	if info, err := os.Stat(target); err == nil {
+		if info.IsDir() {
+			m.exportError = "That's a directory"
+			return m, nil
+		}
+		if !m.exportConfirm {
+			m.exportConfirm = true
+			m.exportError = ""
+			return m, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		m.exportError = err.Error()
+		return m, nil
+	}
+	if err := atomicfile.Write(target, []byte(m.exportPayload), m.config.BackupFiles); err != nil {
+		m.exportError = err.Error()
+		m.exportConfirm = false
+		return m, nil
+	}
+
+	m.lastExportPath = strings.TrimSpace(m.exportInput.Value())
+	m.showingExport = false
+	m.exportInput.Blur()
+	m.statusMessage = fmt.Sprintf("Wrote %s to %s", m.exportWhat, m.lastExportPath)
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(5 * time.Second)
+}
+
+// updateExport handles input in the export overlay
+func (m Model) updateExport(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			if m.exportConfirm {
+				m.exportConfirm = false
+				return m, nil
+			}
+			m.showingExport = false
+			m.exportInput.Blur()
+			return m, nil
+		case "enter":
+			return m.writeExport()
+		case "y":
+			if m.exportConfirm {
+				return m.writeExport()
+			}
+		}
+		// Editing the name asks again before overwriting
+		m.exportConfirm = false
+		m.exportError = ""
+	}
+
+	var cmd tea.Cmd
+	m.exportInput, cmd = m.exportInput.Update(msg)
+	return m, cmd
+}
+
+// renderExportOverlay renders the filename prompt for writing a payload to a file
+func (m Model) renderExportOverlay(background string) string {
+	boxWidth := m.overlayWidth(min(max(m.width*70/100, 50), 80))
+	m.exportInput.Width = boxWidth - 10
+
+	var lines []string
+	lines = append(lines, styles.Header.Render("Write "+m.exportWhat+" to file"))
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render(fmt.Sprintf("%d lines, relative to %s", strings.Count(m.exportPayload, "\n"), abbreviateHome(m.rootPath))))
+	lines = append(lines, "")
+	lines = append(lines, m.exportInput.View())
+	lines = append(lines, "")
+
+	switch {
+	case m.exportError != "":
+		lines = append(lines, styles.StatusError.Render(m.exportError))
+	case m.exportConfirm:
+		lines = append(lines, styles.StatusWarning.Render("File exists - press Enter or 'y' to overwrite"))
+	default:
+		lines = append(lines, styles.Faint.Render("e.g. context-bundle.md or .claude/commands/mycontext.md"))
+	}
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[enter] write  [esc] cancel"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
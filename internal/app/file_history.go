@@ -0,0 +1,202 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// FileHistoryLoadedMsg is sent when the commits touching a file have been listed
+type FileHistoryLoadedMsg struct {
+	Path    string
+	Commits []git.FileCommit
+	Err     error
+}
+
+// FileHistoryDiffMsg is sent when a commit's diff for the history file has been rendered
+type FileHistoryDiffMsg struct {
+	Key     string // Preview cache key of the diff
+	Content string
+}
+
+// fileHistoryCacheKey keys a commit's diff in the preview cache
+func fileHistoryCacheKey(hash, path string) string {
+	return "history\x00" + hash + "\x00" + path
+}
+
+// openFileHistory lists the commits touching the file under the cursor
+func (m Model) openFileHistory() (tea.Model, tea.Cmd) {
+	flat := m.FlatEntries()
+	if !m.isGitRepo || m.cursor >= len(flat) || flat[m.cursor].IsDir {
+		return m, nil
+	}
+	path := flat[m.cursor].Path
+	rel, err := filepath.Rel(m.gitRepoRoot, path)
+	if err != nil {
+		return m, nil
+	}
+
+	m.clearAllOverlays()
+	m.fileHistoryMode = true
+	m.fileHistoryPath = path
+	m.fileHistoryCommits = nil
+	m.fileHistoryCursor = 0
+	m.loading = true
+	m.preview.SetContent("Loading history...")
+
+	repoRoot := m.gitRepoRoot
+	return m, func() tea.Msg {
+		commits, err := git.FileLog(repoRoot, filepath.ToSlash(rel))
+		return FileHistoryLoadedMsg{Path: path, Commits: commits, Err: err}
+	}
+}
+
+// handleFileHistoryLoaded shows the commit list and the newest commit's diff
+func (m Model) handleFileHistoryLoaded(msg FileHistoryLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.fileHistoryMode || msg.Path != m.fileHistoryPath {
+		return m, nil
+	}
+	m.fileHistoryCommits = msg.Commits
+	if msg.Err != nil || len(msg.Commits) == 0 {
+		m.loading = false
+		m.preview.SetContent(styles.Faint.Render("No commits touch this file"))
+		m.previewLines = nil
+		return m, nil
+	}
+	return m.showFileHistoryCommit()
+}
+
+// showFileHistoryCommit loads the diff of the commit under the cursor into the preview
+func (m Model) showFileHistoryCommit() (tea.Model, tea.Cmd) {
+	if m.fileHistoryCursor >= len(m.fileHistoryCommits) {
+		return m, nil
+	}
+	c := m.fileHistoryCommits[m.fileHistoryCursor]
+	key := fileHistoryCacheKey(c.Hash, c.Path)
+	m.previewPath = key
+	m.previewXOffset = 0
+	m.structured = nil
+
+	if cached, ok := m.previewCache.Get(key); ok {
+		m.loading = false
+		m.preview.SetContent(cached.Content)
+		m.previewLines = strings.Split(cached.Content, "\n")
+		m.preview.GotoTop()
+		return m, nil
+	}
+
+	m.loading = true
+	m.preview.SetContent("Loading...")
+	repoRoot, previewWidth := m.gitRepoRoot, m.previewRenderWidth()
+	return m, func() tea.Msg {
+		diff, err := git.ShowFileDiff(repoRoot, c.Hash, c.Path)
+		if err != nil {
+			diff = "Error: " + err.Error()
+		}
+		header := styles.Title.Render(c.Subject) + "\n" +
+			styles.Faint.Render(fmt.Sprintf("%s  %s  %s  %s", c.Hash, c.Author, c.Date, c.Path)) + "\n\n"
+		return FileHistoryDiffMsg{Key: key, Content: header + HighlightDiff(strings.TrimRight(diff, "\n"), previewWidth)}
+	}
+}
+
+// handleFileHistoryDiff caches a rendered diff and shows it if it is still selected
+func (m Model) handleFileHistoryDiff(msg FileHistoryDiffMsg) (tea.Model, tea.Cmd) {
+	m.previewCache.Put(msg.Key, CachedPreview{Content: msg.Content})
+	if m.fileHistoryMode && m.previewPath == msg.Key {
+		m.loading = false
+		m.preview.SetContent(msg.Content)
+		m.previewLines = strings.Split(msg.Content, "\n")
+		m.preview.GotoTop()
+	}
+	return m, nil
+}
+
+// updateFileHistoryKey handles keys while browsing a file's history
+func (m Model) updateFileHistoryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "G":
+		m.fileHistoryMode = false
+		m.fileHistoryCommits = nil
+		return m.UpdatePreview()
+
+	case "#":
+		// Open an issue reference of the commit message
+		return m.openIssueRefs()
+
+	case "j", "down":
+		if m.fileHistoryCursor < len(m.fileHistoryCommits)-1 {
+			m.fileHistoryCursor++
+			return m.showFileHistoryCommit()
+		}
+
+	case "k", "up":
+		if m.fileHistoryCursor > 0 {
+			m.fileHistoryCursor--
+			return m.showFileHistoryCommit()
+		}
+
+	case "ctrl+d", "J":
+		m.preview.HalfViewDown()
+
+	case "ctrl+u", "K":
+		m.preview.HalfViewUp()
+
+	case "w":
+		return m.togglePreviewWrap()
+
+	case "c":
+		// Copy the commit reference
+		if m.fileHistoryCursor < len(m.fileHistoryCommits) {
+			c := m.fileHistoryCommits[m.fileHistoryCursor]
+			if err := m.copyText("commit", c.Hash+" "+c.Subject); err != nil {
+				m.statusMessage = "Clipboard unavailable"
+			} else {
+				m.statusMessage = "Copied " + c.Hash
+			}
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(3 * time.Second)
+		}
+	}
+	return m, nil
+}
+
+// renderFileHistoryList renders the commit list shown in place of the tree
+func (m Model) renderFileHistoryList(width, height int) string {
+	rel, _ := filepath.Rel(m.rootPath, m.fileHistoryPath)
+	lines := []string{
+		styles.Header.Render("History"),
+		styles.Faint.Render(ansi.Truncate(rel, width, "…")),
+		"",
+	}
+	if m.fileHistoryCommits == nil {
+		return strings.Join(append(lines, styles.Faint.Render("Loading...")), "\n")
+	}
+
+	visible := height - len(lines)
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if m.fileHistoryCursor >= visible {
+		start = m.fileHistoryCursor - visible + 1
+	}
+	dateStyle := lipgloss.NewStyle().Foreground(styles.Info)
+	for i := start; i < len(m.fileHistoryCommits) && i < start+visible; i++ {
+		c := m.fileHistoryCommits[i]
+		if i == m.fileHistoryCursor {
+			line := ansi.Truncate(fmt.Sprintf("%s %s %s", c.Hash, c.Date, c.Subject), width, "…")
+			lines = append(lines, styles.Selected.Render(line+strings.Repeat(" ", max(0, width-ansi.StringWidth(line)))))
+		} else {
+			line := styles.Faint.Render(c.Hash) + " " + dateStyle.Render(c.Date) + " " + styleIssueRefs(m.issueRules, c.Subject)
+			lines = append(lines, ansi.Truncate(line, width, "…"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
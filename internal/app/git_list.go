@@ -0,0 +1,279 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// gitRowKind is what a line of the git status list shows
+type gitRowKind int
+
+const (
+	gitRowSection gitRowKind = iota // Staged / not staged / untracked heading
+	gitRowDir                       // Directory of changed files, foldable
+	gitRowFile                      // Changed file
+	gitRowBlank                     // Space between sections
+)
+
+// gitRow is a line of the git status list; the cursor moves over dirs and files
+type gitRow struct {
+	kind    gitRowKind
+	section string // "staged", "unstaged" or "untracked"
+	dir     string // Directory relative to the repo root, "." at the top
+	change  int    // For files, the index in gitChanges
+	count   int    // For dirs, how many changed files they hold
+}
+
+// gitSections are the list's sections in display order, with their headings
+var gitSections = []struct{ id, title string }{
+	{"staged", "Staged Changes"},
+	{"unstaged", "Changes not staged"},
+	{"untracked", "Untracked files"},
+}
+
+// gitSection returns which section a change is listed in
+func gitSection(c git.FileStatus) string {
+	switch {
+	case c.Status == "?":
+		return "untracked"
+	case c.Staged:
+		return "staged"
+	}
+	return "unstaged"
+}
+
+// gitChangeDir returns the directory a change is grouped under; an untracked
+// directory, listed as "dir/", is grouped under its parent
+func gitChangeDir(c git.FileStatus) string {
+	return filepath.Dir(filepath.FromSlash(strings.TrimSuffix(c.Path, "/")))
+}
+
+// gitFoldKey keys a directory's fold state within its section
+func gitFoldKey(section, dir string) string {
+	return section + "\x00" + dir
+}
+
+// gitRows lays out the git status list: each section groups its files by directory,
+// and folded directories hide their files
+func (m Model) gitRows() []gitRow {
+	var rows []gitRow
+	for _, sec := range gitSections {
+		byDir := make(map[string][]int)
+		for i, c := range m.gitChanges {
+			if gitSection(c) == sec.id {
+				dir := gitChangeDir(c)
+				byDir[dir] = append(byDir[dir], i)
+			}
+		}
+		if len(byDir) == 0 {
+			continue
+		}
+		dirs := make([]string, 0, len(byDir))
+		for dir := range byDir {
+			dirs = append(dirs, dir)
+		}
+		sort.Strings(dirs)
+
+		if len(rows) > 0 {
+			rows = append(rows, gitRow{kind: gitRowBlank})
+		}
+		rows = append(rows, gitRow{kind: gitRowSection, section: sec.id})
+		for _, dir := range dirs {
+			files := byDir[dir]
+			rows = append(rows, gitRow{kind: gitRowDir, section: sec.id, dir: dir, count: len(files)})
+			if m.gitFolds[gitFoldKey(sec.id, dir)] {
+				continue
+			}
+			sort.Slice(files, func(a, b int) bool { return m.gitChanges[files[a]].Path < m.gitChanges[files[b]].Path })
+			for _, i := range files {
+				rows = append(rows, gitRow{kind: gitRowFile, section: sec.id, dir: dir, change: i})
+			}
+		}
+	}
+	return rows
+}
+
+// selectable returns true if the cursor can stop on the row
+func (r gitRow) selectable() bool {
+	return r.kind == gitRowDir || r.kind == gitRowFile
+}
+
+// selectedGitRow returns the row under the git status cursor
+func (m Model) selectedGitRow() (gitRow, bool) {
+	rows := m.gitRows()
+	if m.gitStatusCursor < 0 || m.gitStatusCursor >= len(rows) {
+		return gitRow{}, false
+	}
+	return rows[m.gitStatusCursor], true
+}
+
+// selectedGitChange returns the change under the git status cursor, if it is on a file
+func (m Model) selectedGitChange() (git.FileStatus, bool) {
+	row, ok := m.selectedGitRow()
+	if !ok || row.kind != gitRowFile {
+		return git.FileStatus{}, false
+	}
+	return m.gitChanges[row.change], true
+}
+
+// moveGitCursor moves the cursor to the next (delta 1) or previous (delta -1)
+// directory or file, returning false at either end
+func (m *Model) moveGitCursor(delta int) bool {
+	rows := m.gitRows()
+	for i := m.gitStatusCursor + delta; i >= 0 && i < len(rows); i += delta {
+		if rows[i].selectable() {
+			m.gitStatusCursor = i
+			return true
+		}
+	}
+	return false
+}
+
+// clampGitCursor keeps the cursor on a directory or file after the list changed
+func (m *Model) clampGitCursor() {
+	rows := m.gitRows()
+	if m.gitStatusCursor >= len(rows) {
+		m.gitStatusCursor = len(rows) - 1
+	}
+	if m.gitStatusCursor < 0 {
+		m.gitStatusCursor = 0
+	}
+	if m.gitStatusCursor < len(rows) && !rows[m.gitStatusCursor].selectable() {
+		if !m.moveGitCursor(1) {
+			m.moveGitCursor(-1)
+		}
+	}
+}
+
+// toggleGitFold folds or unfolds the directory under the cursor, or the directory
+// of the file under it (fold only), keeping the cursor on the directory
+func (m *Model) toggleGitFold(foldOnly bool) {
+	row, ok := m.selectedGitRow()
+	if !ok || !row.selectable() {
+		return
+	}
+	key := gitFoldKey(row.section, row.dir)
+	if m.gitFolds == nil {
+		m.gitFolds = make(map[string]bool)
+	}
+	if row.kind == gitRowFile || foldOnly {
+		m.gitFolds[key] = true
+	} else {
+		m.gitFolds[key] = !m.gitFolds[key]
+	}
+	// The directory row stays where it is, so find it
+	for i, r := range m.gitRows() {
+		if r.kind == gitRowDir && r.section == row.section && r.dir == row.dir {
+			m.gitStatusCursor = i
+			break
+		}
+	}
+}
+
+// toggleAllGitFolds folds every directory, or unfolds them all if any is folded
+func (m *Model) toggleAllGitFolds() {
+	row, _ := m.selectedGitRow()
+	if len(m.gitFolds) > 0 {
+		m.gitFolds = nil
+	} else {
+		m.gitFolds = make(map[string]bool)
+		for _, r := range m.gitRows() {
+			if r.kind == gitRowDir {
+				m.gitFolds[gitFoldKey(r.section, r.dir)] = true
+			}
+		}
+	}
+	// Stay on the same directory
+	for i, r := range m.gitRows() {
+		if r.kind == gitRowDir && r.section == row.section && r.dir == row.dir {
+			m.gitStatusCursor = i
+			break
+		}
+	}
+	m.clampGitCursor()
+}
+
+// renderGitDirSummary lists a directory's changes, shown when its row is selected
+func (m Model) renderGitDirSummary(row gitRow) string {
+	statusStyles := styles.GitStatusStyles()
+	var b strings.Builder
+	b.WriteString(styles.Title.Render(gitDirLabel(row.dir)) + "\n")
+	b.WriteString(styles.Faint.Render(fmt.Sprintf("%d changed file(s)", row.count)) + "\n\n")
+	for _, c := range m.gitChanges {
+		if gitSection(c) == row.section && gitChangeDir(c) == row.dir {
+			b.WriteString(fmt.Sprintf("  %s %s\n", statusStyles[c.Status].Render(c.Status), c.Path))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// gitDirLabel shows a directory of the git status list
+func gitDirLabel(dir string) string {
+	if dir == "." {
+		return "./"
+	}
+	return filepath.ToSlash(dir) + "/"
+}
+
+// renderGitFileList renders the grouped file list for the git viewport
+func (m Model) renderGitFileList() string {
+	leftWidth := m.LeftPaneWidth()
+
+	if len(m.gitChanges) == 0 {
+		return styles.Faint.Render("Working tree clean")
+	}
+
+	sectionStyles := map[string]lipgloss.Style{
+		"staged":    lipgloss.NewStyle().Foreground(styles.GitAdded).Bold(true),
+		"unstaged":  lipgloss.NewStyle().Foreground(styles.GitModified).Bold(true),
+		"untracked": lipgloss.NewStyle().Foreground(styles.GitUntracked),
+	}
+	statusStyles := styles.GitStatusStyles()
+
+	var lines []string
+	for i, r := range m.gitRows() {
+		var line string
+		switch r.kind {
+		case gitRowBlank:
+		case gitRowSection:
+			for _, sec := range gitSections {
+				if sec.id == r.section {
+					line = sectionStyles[r.section].Render(sec.title)
+				}
+			}
+		case gitRowDir:
+			icon := "v "
+			folded := m.gitFolds[gitFoldKey(r.section, r.dir)]
+			switch {
+			case styles.Accessible() && folded:
+				icon = "+ "
+			case styles.Accessible():
+				icon = "- "
+			case folded:
+				icon = "> "
+			}
+			count := fmt.Sprintf("(%d)", r.count)
+			if i == m.gitStatusCursor {
+				line = styles.Selected.Render(padRight("  "+icon+gitDirLabel(r.dir)+" "+count, leftWidth-4))
+			} else {
+				line = "  " + icon + lipgloss.NewStyle().Bold(true).Render(gitDirLabel(r.dir)) + " " + styles.Faint.Render(count)
+			}
+		case gitRowFile:
+			c := m.gitChanges[r.change]
+			name := filepath.Base(filepath.FromSlash(c.Path))
+			if i == m.gitStatusCursor {
+				line = styles.Selected.Render(padRight(fmt.Sprintf("      %s %s", c.Status, name), leftWidth-4))
+			} else {
+				line = fmt.Sprintf("      %s %s", statusStyles[c.Status].Render(c.Status), name)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
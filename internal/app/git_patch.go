@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// gitPatchChanges returns the changes under the git status cursor: the file, or
+// every file of a directory (folded or not)
+func (m Model) gitPatchChanges() ([]git.FileStatus, string) {
+	row, ok := m.selectedGitRow()
+	if !ok {
+		return nil, ""
+	}
+	if row.kind == gitRowFile {
+		c := m.gitChanges[row.change]
+		return []git.FileStatus{c}, c.Path
+	}
+	var changes []git.FileStatus
+	for _, c := range m.gitChanges {
+		if gitSection(c) == row.section && gitChangeDir(c) == row.dir {
+			changes = append(changes, c)
+		}
+	}
+	return changes, gitDirLabel(row.dir)
+}
+
+// formatGitPatch wraps diffs in a fenced diff block under a short header
+func formatGitPatch(section, what string, files int, diff string) string {
+	kind := map[string]string{"staged": "Staged changes", "unstaged": "Unstaged changes", "untracked": "New files"}[section]
+	header := fmt.Sprintf("%s to %s", kind, what)
+	if files > 1 {
+		header += fmt.Sprintf(" (%d files)", files)
+	}
+	return header + ":\n\n```diff\n" + strings.TrimRight(diff, "\n") + "\n```\n"
+}
+
+// copyGitPatch copies the diff of the file or directory under the git status
+// cursor as a fenced block, ready to paste into a review prompt
+func (m Model) copyGitPatch() (tea.Model, tea.Cmd) {
+	changes, what := m.gitPatchChanges()
+	if len(changes) == 0 {
+		return m, nil
+	}
+
+	var diffs []string
+	for _, c := range changes {
+		var diff string
+		if c.Status == "?" {
+			diff, _ = git.LoadNewFileDiff(m.gitRepoRoot, c.Path)
+		} else {
+			diff, _ = git.LoadDiff(m.gitRepoRoot, c.Path, c.Staged, 3)
+		}
+		if diff != "" {
+			diffs = append(diffs, strings.TrimRight(diff, "\n"))
+		}
+	}
+	if len(diffs) == 0 {
+		m.statusMessage = "No diff for " + what
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	patch := formatGitPatch(gitSection(changes[0]), what, len(diffs), strings.Join(diffs, "\n"))
+	if err := m.copyText("diff", patch); err != nil {
+		m.statusMessage = "Clipboard unavailable"
+	} else {
+		m.statusMessage = fmt.Sprintf("Copied diff of %s (%d lines)", what, strings.Count(patch, "\n"))
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	maxHighlightWorkers = 4   // Upper bound on concurrent preview renders
+	highlightChunkLines = 300 // Big files show this many highlighted lines first
+)
+
+// highlightPool renders file previews on a fixed set of workers
+// Every job gets a request ID, like the diff loader; a job superseded by a newer request
+// is dropped before it starts and stops between render stages.
+type highlightPool struct {
+	jobs   chan highlightJob
+	latest atomic.Int64 // ID of the newest submitted job
+	nextID atomic.Int64
+}
+
+// highlightJob renders one preview, reporting partial results through partial
+// partial returns false once the job has been superseded.
+type highlightJob struct {
+	id   int64
+	out  chan FileLoadedMsg
+	load func(partial func(FileLoadedMsg) bool) FileLoadedMsg
+}
+
+// newHighlightPool starts the preview workers
+func newHighlightPool() *highlightPool {
+	workers := min(runtime.NumCPU(), maxHighlightWorkers)
+	p := &highlightPool{jobs: make(chan highlightJob, 64)}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// work runs jobs until the pool is discarded with the program
+func (p *highlightPool) work() {
+	for job := range p.jobs {
+		p.run(job)
+	}
+}
+
+// run renders a job unless it was superseded, sending partial and final results
+func (p *highlightPool) run(job highlightJob) {
+	defer close(job.out)
+	current := func() bool { return p.latest.Load() == job.id }
+	if !current() {
+		return
+	}
+	msg := job.load(func(partial FileLoadedMsg) bool {
+		if !current() {
+			return false
+		}
+		partial.RequestID = job.id
+		partial.Partial = true
+		job.out <- partial
+		return true
+	})
+	if current() {
+		msg.RequestID = job.id
+		job.out <- msg
+	}
+}
+
+// Submit queues a preview render, superseding all earlier ones, and returns its request
+// ID and the command that delivers its first result
+func (p *highlightPool) Submit(load func(partial func(FileLoadedMsg) bool) FileLoadedMsg) (int64, tea.Cmd) {
+	job := highlightJob{
+		id:   p.nextID.Add(1),
+		out:  make(chan FileLoadedMsg, 2), // A partial and the final result never block a worker
+		load: load,
+	}
+	p.latest.Store(job.id)
+	select {
+	case p.jobs <- job:
+	default:
+		// Queue full of superseded jobs; hand over without blocking the UI
+		go func() { p.jobs <- job }()
+	}
+	return job.id, waitForHighlight(job.out)
+}
+
+// waitForHighlight returns a command that delivers the next result of a job
+// Superseded jobs close their channel without a result, and the command returns nil.
+func waitForHighlight(out <-chan FileLoadedMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-out
+		if !ok {
+			return nil
+		}
+		if msg.Partial {
+			msg.next = out
+		}
+		return msg
+	}
+}
+
+// nthIndex returns the index of the nth occurrence of sep in s, or -1
+func nthIndex(s, sep string, n int) int {
+	offset := 0
+	for i := 0; i < n; i++ {
+		idx := strings.Index(s[offset:], sep)
+		if idx < 0 {
+			return -1
+		}
+		offset += idx + len(sep)
+	}
+	return offset - len(sep)
+}
@@ -15,8 +15,11 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/connorleisz/contexTUI/internal/filetype"
 	"github.com/connorleisz/contexTUI/internal/terminal"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
 	"github.com/nfnt/resize"
 	"github.com/tdewolff/canvas"
 	"github.com/tdewolff/canvas/renderers/rasterizer"
@@ -32,11 +35,20 @@ func loadImageAsync(path string, caps terminal.Capabilities, maxW, maxH int) tea
 
 // ANSI color constants for overlay
 const (
-	overlayBorderColor = "\x1b[38;5;205m" // Pink accent (matches app style)
-	overlayDimColor    = "\x1b[2m"         // Dim for metadata
-	overlayReset       = "\x1b[0m"
+	overlayDimColor = "\x1b[2m" // Dim for metadata
+	overlayReset    = "\x1b[0m"
 )
 
+// overlayBorderColor returns the escape sequence coloring the overlay border like the
+// theme's active borders
+func overlayBorderColor() string {
+	seq := lipgloss.ColorProfile().Color(string(styles.BorderActive)).Sequence(false)
+	if seq == "" {
+		return "" // No colors in this terminal
+	}
+	return "\x1b[" + seq + "m"
+}
+
 // LoadImageForOverlay loads an image and returns Kitty overlay escape sequences
 // maxW and maxH are the full screen dimensions in terminal cells
 func LoadImageForOverlay(path string, maxW, maxH int) (string, error) {
@@ -105,27 +117,28 @@ func LoadImageForOverlay(path string, maxW, maxH int) (string, error) {
 	}
 
 	var result strings.Builder
+	border := overlayBorderColor()
 
 	// Clear screen
 	result.WriteString("\x1b[2J")
 
 	// Draw top border with header: ╭─ filename │ dims │ format ─────╮
 	result.WriteString(positionCursor(frameY, frameX))
-	result.WriteString(renderOverlayTopBorder(frameW, filename, dims, format))
+	result.WriteString(renderOverlayTopBorder(frameW, border, filename, dims, format))
 
 	// Draw left and right borders for each row
 	for row := 1; row <= displayRows; row++ {
 		// Left border
 		result.WriteString(positionCursor(frameY+row, frameX))
-		result.WriteString(overlayBorderColor + "│" + overlayReset)
+		result.WriteString(border + "│" + overlayReset)
 		// Right border
 		result.WriteString(positionCursor(frameY+row, frameX+frameW-1))
-		result.WriteString(overlayBorderColor + "│" + overlayReset)
+		result.WriteString(border + "│" + overlayReset)
 	}
 
 	// Draw bottom border with hint: ╰──────── Esc to exit ────────╯
 	result.WriteString(positionCursor(frameY+displayRows+1, frameX))
-	result.WriteString(renderOverlayBottomBorder(frameW, "Esc to exit"))
+	result.WriteString(renderOverlayBottomBorder(frameW, border, "Esc to exit"))
 
 	// Position and render Kitty image inside frame
 	result.WriteString(positionCursor(frameY+1, frameX+1))
@@ -140,7 +153,7 @@ func positionCursor(row, col int) string {
 }
 
 // renderOverlayTopBorder creates: ╭─ filename │ dims │ format ─────╮
-func renderOverlayTopBorder(width int, filename, dims, format string) string {
+func renderOverlayTopBorder(width int, border, filename, dims, format string) string {
 	// Build the metadata string
 	meta := fmt.Sprintf(" %s │ %s │ %s ", filename, dims, format)
 
@@ -148,27 +161,27 @@ func renderOverlayTopBorder(width int, filename, dims, format string) string {
 	// Corner + dash + meta + dashes + corner = width
 	// ╭─ meta ────────╮
 	contentWidth := width - 2 // minus corners
-	metaLen := len(meta)
+	metaLen := ansi.StringWidth(meta)
 
 	var b strings.Builder
-	b.WriteString(overlayBorderColor)
+	b.WriteString(border)
 	b.WriteString("╭")
 
 	if metaLen >= contentWidth {
 		// Truncate metadata if too long
 		b.WriteString("─")
 		truncated := meta
-		if len(truncated) > contentWidth-2 {
-			truncated = truncated[:contentWidth-5] + "... "
+		if metaLen > contentWidth-2 {
+			truncated = ansi.Truncate(truncated, contentWidth-5, "") + "... "
 		}
 		b.WriteString(overlayDimColor)
 		b.WriteString(truncated)
-		b.WriteString(overlayBorderColor)
+		b.WriteString(border)
 	} else {
 		b.WriteString("─")
 		b.WriteString(overlayDimColor)
 		b.WriteString(meta)
-		b.WriteString(overlayBorderColor)
+		b.WriteString(border)
 		// Fill remaining with dashes
 		remaining := contentWidth - 1 - metaLen
 		for i := 0; i < remaining; i++ {
@@ -182,14 +195,14 @@ func renderOverlayTopBorder(width int, filename, dims, format string) string {
 }
 
 // renderOverlayBottomBorder creates: ╰──────── hint ────────╯
-func renderOverlayBottomBorder(width int, hint string) string {
+func renderOverlayBottomBorder(width int, border, hint string) string {
 	// Calculate centering for hint
 	contentWidth := width - 2 // minus corners
 	hintWithSpaces := " " + hint + " "
-	hintLen := len(hintWithSpaces)
+	hintLen := ansi.StringWidth(hintWithSpaces)
 
 	var b strings.Builder
-	b.WriteString(overlayBorderColor)
+	b.WriteString(border)
 	b.WriteString("╰")
 
 	if hintLen >= contentWidth {
@@ -207,7 +220,7 @@ func renderOverlayBottomBorder(width int, hint string) string {
 		}
 		b.WriteString(overlayDimColor)
 		b.WriteString(hintWithSpaces)
-		b.WriteString(overlayBorderColor)
+		b.WriteString(border)
 		for i := 0; i < rightPad; i++ {
 			b.WriteString("─")
 		}
@@ -510,7 +523,11 @@ func rgbTo256(r, g, b uint8) int {
 }
 
 // validateImageCache checks if a cached image is still valid
-func validateImageCache(cached CachedImage, path string, viewportW, viewportH int) bool {
+// Renders are tied to the graphics protocol, color profile and theme they were made for.
+func validateImageCache(cached CachedImage, path string, viewportW, viewportH int, caps terminal.Capabilities, theme string) bool {
+	if cached.Protocol != caps.Graphics || cached.TrueColor != caps.TrueColor || cached.Theme != theme {
+		return false
+	}
 	info, err := os.Stat(path)
 	if err != nil {
 		return false
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// issueRule links references matching re to url, with {id} replaced
+type issueRule struct {
+	re  *regexp.Regexp
+	url string
+}
+
+// loadIssueRules compiles the configured patterns, plus #123 for the origin
+// remote's tracker; invalid patterns, and those matching empty text, are skipped
+func loadIssueRules(repoRoot string, links []config.IssueLink) []issueRule {
+	var rules []issueRule
+	for _, l := range links {
+		if re, err := regexp.Compile(l.Pattern); err == nil && l.URL != "" && !re.MatchString("") {
+			rules = append(rules, issueRule{re: re, url: l.URL})
+		}
+	}
+	if repoRoot != "" {
+		if url := git.IssueURLTemplate(repoRoot); url != "" {
+			rules = append(rules, issueRule{re: regexp.MustCompile(`\B#(\d+)\b`), url: url})
+		}
+	}
+	return rules
+}
+
+// issueRef is an issue or pull request reference found in text
+type issueRef struct {
+	Text string // As written, e.g. "#123" or "JIRA-456"
+	URL  string
+}
+
+// issueMatch is where a rule matched in a line
+type issueMatch struct {
+	start, end int
+	ref        issueRef
+}
+
+// matchIssueRefs returns the references in a line, leftmost rule first where they overlap
+func matchIssueRefs(rules []issueRule, line string) []issueMatch {
+	var matches []issueMatch
+	taken := make([]bool, len(line))
+	for _, r := range rules {
+		for _, loc := range r.re.FindAllStringSubmatchIndex(line, -1) {
+			if loc[0] == loc[1] || taken[loc[0]] || taken[loc[1]-1] {
+				continue
+			}
+			// &#123; is an HTML entity, not an issue
+			if line[loc[0]] == '#' && loc[0] > 0 && line[loc[0]-1] == '&' {
+				continue
+			}
+			id := line[loc[0]:loc[1]]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				id = line[loc[2]:loc[3]]
+			}
+			for i := loc[0]; i < loc[1]; i++ {
+				taken[i] = true
+			}
+			matches = append(matches, issueMatch{loc[0], loc[1],
+				issueRef{Text: line[loc[0]:loc[1]], URL: strings.ReplaceAll(r.url, "{id}", id)}})
+		}
+	}
+	// Back in line order
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].start < matches[j-1].start; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+	return matches
+}
+
+// findIssueRefs returns the references in text in order, without duplicates
+func findIssueRefs(rules []issueRule, text string) []issueRef {
+	if len(rules) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var refs []issueRef
+	for _, line := range strings.Split(text, "\n") {
+		for _, im := range matchIssueRefs(rules, line) {
+			if !seen[im.ref.URL] {
+				seen[im.ref.URL] = true
+				refs = append(refs, im.ref)
+			}
+		}
+	}
+	return refs
+}
+
+// linkIssueRefs turns references outside code and existing links into markdown
+// links, so glamour styles them (an anchor target keeps the URL out of the text)
+func linkIssueRefs(rules []issueRule, text string) string {
+	if len(rules) == 0 {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	inCodeBlock := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		// Leave inline code and links alone
+		var skip [][]int
+		skip = append(skip, markdownLinkRe.FindAllStringIndex(line, -1)...)
+		skip = append(skip, inlineCodeRe.FindAllStringIndex(line, -1)...)
+		var b strings.Builder
+		last := 0
+		for _, im := range matchIssueRefs(rules, line) {
+			if within(im.start, skip) {
+				continue
+			}
+			b.WriteString(line[last:im.start])
+			b.WriteString("[" + im.ref.Text + "](#)")
+			last = im.end
+		}
+		b.WriteString(line[last:])
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inlineCodeRe matches `inline code`
+var inlineCodeRe = regexp.MustCompile("`[^`]*`")
+
+// within returns true if pos lies in one of the spans
+func within(pos int, spans [][]int) bool {
+	for _, s := range spans {
+		if pos >= s[0] && pos < s[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// styleIssueRefs underlines the references in a line of plain text
+func styleIssueRefs(rules []issueRule, line string) string {
+	matches := matchIssueRefs(rules, line)
+	if len(matches) == 0 {
+		return line
+	}
+	style := lipgloss.NewStyle().Foreground(styles.Info).Underline(true)
+	var b strings.Builder
+	last := 0
+	for _, im := range matches {
+		b.WriteString(line[last:im.start])
+		b.WriteString(style.Render(im.ref.Text))
+		last = im.end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// issueLinks returns the references as entries of the links overlay
+func issueLinks(refs []issueRef) []previewLink {
+	links := make([]previewLink, 0, len(refs))
+	for _, r := range refs {
+		links = append(links, previewLink{Label: r.Text, Raw: r.URL, URL: r.URL})
+	}
+	return links
+}
+
+// openIssueRefs lists the issue references of what is shown: the selected commit
+// in file history, or the previewed file. A single reference is opened directly.
+func (m Model) openIssueRefs() (tea.Model, tea.Cmd) {
+	var text, title string
+	switch {
+	case m.fileHistoryMode:
+		if m.fileHistoryCursor < len(m.fileHistoryCommits) {
+			c := m.fileHistoryCommits[m.fileHistoryCursor]
+			text, title = c.Subject, c.Hash
+		}
+	case m.previewPath != "":
+		text, _, _, _ = readPreviewChunk(m.previewPath, 0)
+		title = filepath.Base(m.previewPath)
+	}
+
+	links := issueLinks(findIssueRefs(m.issueRules, text))
+	if len(links) == 0 {
+		m.statusMessage = "No issue references"
+		if len(m.issueRules) == 0 {
+			m.statusMessage += " (no web remote or issueLinks configured)"
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if len(links) == 1 {
+		return m.followLink(links[0])
+	}
+	// Shown over file history, which stays open underneath
+	m.showingLinks = true
+	m.linksTitle = title
+	m.previewLinks = links
+	m.linkCursor = 0
+	return m, nil
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Pane layouts: both panes side by side at splitRatio, or one zoomed to the full width
+const (
+	layoutSplit   = ""
+	layoutTree    = "tree"
+	layoutPreview = "preview"
+)
+
+// narrowWidth is the terminal width below which only one pane fits; tab flips between them
+const narrowWidth = 80
+
+// narrow returns true if the terminal is too narrow for both panes side by side
+func (m Model) narrow() bool {
+	return m.layout == layoutSplit && m.width < narrowWidth
+}
+
+// shownLayout returns the layout on screen: in a narrow terminal, the active pane alone
+func (m Model) shownLayout() string {
+	if !m.narrow() {
+		return m.layout
+	}
+	if m.activePane == TreePane {
+		return layoutTree
+	}
+	return layoutPreview
+}
+
+// narrowHint returns the key hint for flipping panes in a narrow terminal
+func (m Model) narrowHint() string {
+	if !m.narrow() {
+		return ""
+	}
+	if m.activePane == TreePane {
+		return "tab preview  "
+	}
+	return "tab tree  "
+}
+
+// validLayout returns true if layout is a pane layout
+func validLayout(layout string) bool {
+	return layout == layoutSplit || layout == layoutTree || layout == layoutPreview
+}
+
+// setLayout switches the pane layout and remembers it for the project. Zooming the
+// pane that is already zoomed goes back to both panes.
+func (m Model) setLayout(layout string) (tea.Model, tea.Cmd) {
+	if layout != layoutSplit && layout == m.layout {
+		layout = layoutSplit
+	}
+	m.layout = layout
+	switch layout {
+	case layoutTree:
+		m.activePane = TreePane
+		m.statusMessage = "Tree zoomed ([ or | for both panes)"
+	case layoutPreview:
+		m.activePane = PreviewPane
+		m.statusMessage = "Preview zoomed (] or | for both panes)"
+	default:
+		m.statusMessage = "Both panes"
+	}
+	m.applyPaneWidths()
+	m.saveConfig()
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// resetLayout shows both panes at an even split
+func (m Model) resetLayout() (tea.Model, tea.Cmd) {
+	m.splitRatio = 0.5
+	return m.setLayout(layoutSplit)
+}
+
+// followActivePane zooms the pane tab switched to when a pane is zoomed
+func (m *Model) followActivePane() {
+	if m.layout == layoutSplit {
+		return
+	}
+	m.layout = layoutPreview
+	if m.activePane == TreePane {
+		m.layout = layoutTree
+	}
+	m.applyPaneWidths()
+	m.saveConfig()
+}
+
+// applyPaneWidths sizes the pane viewports for the layout and split ratio
+func (m *Model) applyPaneWidths() {
+	m.tree.Width = max(m.LeftPaneWidth()-2, 0)
+	m.preview.Width = max(m.RightPaneWidth()-2, 0)
+	m.gitList.Width = m.tree.Width
+	if m.gitStatusMode && !m.gitStashMode {
+		m.gitList.SetContent(m.renderGitFileList())
+	}
+}
+
+// overlayWidth caps an overlay box's width so it fits the terminal
+func (m Model) overlayWidth(width int) int {
+	return max(min(width, m.width-2), 20)
+}
+
+// docsCardWidth returns the width of the docs overlay's cards, narrower in a narrow terminal
+func (m Model) docsCardWidth() int {
+	return max(min(68, m.width-16), 20)
+}
+
+// joinPanes lays the rendered panes side by side, leaving out a pane hidden by a zoom
+func (m Model) joinPanes(left, right string) string {
+	switch m.shownLayout() {
+	case layoutTree:
+		return left
+	case layoutPreview:
+		return right
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
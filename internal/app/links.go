@@ -0,0 +1,301 @@
+package app
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// wikiLinkRe matches [[target]], [[target#heading]] and [[target|label]]
+var wikiLinkRe = regexp.MustCompile(`\[\[([^\[\]|#]+)(#[^\[\]|]*)?(?:\|([^\[\]]+))?\]\]`)
+
+// markdownLinkRe matches [label](target) and ![alt](target)
+var markdownLinkRe = regexp.MustCompile(`\[([^\[\]]*)\]\(<?([^()\s<>]+)>?(?:\s+"[^"]*")?\)`)
+
+// previewLink is a link found in a previewed markdown file
+type previewLink struct {
+	Label  string
+	Raw    string // Target as written
+	Target string // Resolved path relative to the root, or "" when it doesn't exist
+	URL    string // Web address of an issue reference, opened in the browser instead
+}
+
+// highlightWikiLinks turns [[wiki links]] outside code blocks into markdown links, so
+// glamour styles them like any other link (an anchor target keeps the URL out of the text)
+func highlightWikiLinks(text string) string {
+	if !strings.Contains(text, "[[") {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	inCodeBlock := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		lines[i] = wikiLinkRe.ReplaceAllStringFunc(line, func(link string) string {
+			m := wikiLinkRe.FindStringSubmatch(link)
+			label := strings.TrimSpace(m[1]) + m[2]
+			if m[3] != "" {
+				label = strings.TrimSpace(m[3])
+			}
+			return "[" + label + "](#)"
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findMarkdownLinks returns the wiki links and relative links of a markdown file in
+// order, without duplicates. allFiles (relative to the root) resolves wiki links by name.
+func findMarkdownLinks(rootPath, filePath string, allFiles []string) []previewLink {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	dir := filepath.Dir(filePath)
+	seen := make(map[string]bool)
+	var links []previewLink
+	add := func(label, raw, target string) {
+		if seen[raw] {
+			return
+		}
+		seen[raw] = true
+		if label == "" {
+			label = raw
+		}
+		links = append(links, previewLink{Label: label, Raw: raw, Target: target})
+	}
+
+	inCodeBlock := false
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		for _, m := range wikiLinkRe.FindAllStringSubmatch(line, -1) {
+			name := strings.TrimSpace(m[1])
+			add(strings.TrimSpace(m[3]), name, resolveWikiLink(rootPath, dir, name, allFiles))
+		}
+		for _, m := range markdownLinkRe.FindAllStringSubmatch(line, -1) {
+			raw := m[2]
+			if strings.HasPrefix(raw, "#") || strings.Contains(raw, "://") || strings.HasPrefix(raw, "mailto:") {
+				continue // Anchors and external links
+			}
+			add(m[1], raw, resolveRelativeLink(rootPath, dir, raw))
+		}
+	}
+	return links
+}
+
+// resolveRelativeLink resolves a markdown link against the linking file's directory
+// (or the root, for links starting with /)
+func resolveRelativeLink(rootPath, dir, raw string) string {
+	path, _, _ := strings.Cut(raw, "#")
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	full := filepath.Join(dir, filepath.FromSlash(path))
+	if strings.HasPrefix(path, "/") {
+		full = filepath.Join(rootPath, filepath.FromSlash(path))
+	}
+	return existingRelPath(rootPath, full)
+}
+
+// resolveWikiLink resolves a wiki link like Obsidian: next to the linking file, then
+// from the root, then the file with that name anywhere in the project (shortest path wins)
+func resolveWikiLink(rootPath, dir, name string, allFiles []string) string {
+	if filepath.Ext(name) == "" {
+		name += ".md"
+	}
+	name = filepath.FromSlash(name)
+	for _, base := range []string{dir, rootPath} {
+		if rel := existingRelPath(rootPath, filepath.Join(base, name)); rel != "" {
+			return rel
+		}
+	}
+	best := ""
+	for _, f := range allFiles {
+		if (strings.EqualFold(f, name) || strings.HasSuffix(strings.ToLower(f), strings.ToLower(string(filepath.Separator)+name))) &&
+			(best == "" || len(f) < len(best)) {
+			best = f
+		}
+	}
+	return best
+}
+
+// existingRelPath returns a file's path relative to the root, or "" if it doesn't exist
+// or lies outside the root
+func existingRelPath(rootPath, full string) string {
+	info, err := os.Stat(full)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+	rel, err := filepath.Rel(rootPath, full)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return rel
+}
+
+// openLinks lists the links of the previewed markdown file; a single link is followed directly
+func (m Model) openLinks() (tea.Model, tea.Cmd) {
+	if !strings.HasSuffix(strings.ToLower(m.previewPath), ".md") {
+		return m, nil
+	}
+	links := findMarkdownLinks(m.rootPath, m.previewPath, m.allFiles)
+	if text, _, _, err := readPreviewChunk(m.previewPath, 0); err == nil {
+		links = append(links, issueLinks(findIssueRefs(m.issueRules, text))...)
+	}
+	if len(links) == 0 {
+		m.statusMessage = "No links in this file"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if len(links) == 1 {
+		return m.followLink(links[0])
+	}
+	m.clearAllOverlays()
+	m.showingLinks = true
+	m.linksTitle = filepath.Base(m.previewPath)
+	m.previewLinks = links
+	m.linkCursor = 0
+	return m, nil
+}
+
+// followLink selects a link's target in the tree and previews it
+// The file it was followed from is remembered as the ' mark, to jump back.
+func (m Model) followLink(link previewLink) (tea.Model, tea.Cmd) {
+	if link.URL != "" {
+		m.showingLinks = false
+		m.statusMessage = "Opening " + link.URL
+		m.statusMessageTime = time.Now()
+		return m, tea.Batch(openInOS(link.URL), ClearStatusAfter(3*time.Second))
+	}
+	if link.Target == "" {
+		m.statusMessage = "Link target not found: " + link.Raw
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if from, err := filepath.Rel(m.rootPath, m.previewPath); err == nil {
+		m.lastJumpPath = from
+	}
+	m.showingLinks = false
+	m = m.NavigateToFile(link.Target)
+	m.ensureTreeCursorVisible()
+	return m.UpdatePreview()
+}
+
+// updateLinks handles input in the links overlay
+func (m Model) updateLinks(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.showingLinks = false
+
+	case "j", "down":
+		if m.linkCursor < len(m.previewLinks)-1 {
+			m.linkCursor++
+		}
+
+	case "k", "up":
+		if m.linkCursor > 0 {
+			m.linkCursor--
+		}
+
+	case "enter", "l":
+		if m.linkCursor < len(m.previewLinks) {
+			return m.followLink(m.previewLinks[m.linkCursor])
+		}
+
+	case "c":
+		// Copy the URL, or the file as an @ reference
+		if m.linkCursor < len(m.previewLinks) {
+			link := m.previewLinks[m.linkCursor]
+			text := link.URL
+			if text == "" && link.Target != "" {
+				text = "@" + link.Target
+			}
+			if text == "" {
+				return m, nil
+			}
+			if err := m.copyText("link", text); err != nil {
+				m.statusMessage = "Clipboard unavailable"
+			} else {
+				m.statusMessage = "Copied " + text
+			}
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(3 * time.Second)
+		}
+	}
+	return m, nil
+}
+
+// renderLinksOverlay renders the links of the previewed file
+func (m Model) renderLinksOverlay(background string) string {
+	boxWidth := m.overlayWidth(72)
+	maxVisible := m.height - 14
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Links: "+m.linksTitle))
+	lines = append(lines, "")
+
+	start := 0
+	if m.linkCursor >= maxVisible {
+		start = m.linkCursor - maxVisible + 1
+	}
+	end := min(start+maxVisible, len(m.previewLinks))
+	for i := start; i < end; i++ {
+		link := m.previewLinks[i]
+		target := link.Target
+		if link.URL != "" {
+			target = link.URL
+		} else if target == "" {
+			target = link.Raw + " (not found)"
+		}
+		label := ansi.Truncate(padRight(ansi.Truncate(link.Label, 24, "…"), 24)+" "+target, boxWidth-8, "…")
+		switch {
+		case i == m.linkCursor:
+			lines = append(lines, styles.Selected.Render(" "+label+" "))
+		case link.Target == "" && link.URL == "":
+			lines = append(lines, " "+styles.Faint.Render(label))
+		default:
+			lines = append(lines, " "+styles.Normal.Render(label))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] follow/open  [c] copy  [''] back  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
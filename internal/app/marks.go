@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Mark prefixes: m{a-z} sets a mark, '{a-z} jumps to it
+const (
+	markSet  = "m"
+	markJump = "'"
+)
+
+// isMarkName reports whether key is a valid mark name (a-z)
+func isMarkName(key string) bool {
+	return len(key) == 1 && key[0] >= 'a' && key[0] <= 'z'
+}
+
+// handleMarkKey completes a pending m{a-z} or '{a-z} sequence
+func (m Model) handleMarkKey(key string) (tea.Model, tea.Cmd) {
+	prefix := m.pendingMarkKey
+	m.pendingMarkKey = ""
+
+	switch {
+	case prefix == markSet && isMarkName(key):
+		return m.setMark(key)
+	case prefix == markJump && (isMarkName(key) || key == markJump):
+		return m.jumpToMark(key)
+	}
+
+	// Anything else cancels the sequence
+	m.statusMessage = ""
+	return m, nil
+}
+
+// setMark records the entry under the cursor as mark name
+func (m Model) setMark(name string) (tea.Model, tea.Cmd) {
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) {
+		return m, nil
+	}
+
+	relPath, err := filepath.Rel(m.rootPath, flat[m.cursor].Path)
+	if err != nil {
+		return m, nil
+	}
+	if m.marks == nil {
+		m.marks = make(map[string]string)
+	}
+	m.marks[name] = relPath
+	m.saveConfig()
+
+	m.statusMessage = fmt.Sprintf("Mark '%s' → %s", name, relPath)
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// jumpToMark moves the tree cursor to a mark
+// Jumping to the ' mark returns to the position before the last jump
+func (m Model) jumpToMark(name string) (tea.Model, tea.Cmd) {
+	var relPath string
+	if name == markJump {
+		relPath = m.lastJumpPath
+	} else {
+		relPath = m.marks[name]
+	}
+
+	if relPath == "" {
+		m.statusMessage = fmt.Sprintf("Mark '%s' not set", name)
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if _, err := os.Stat(filepath.Join(m.rootPath, relPath)); err != nil {
+		m.statusMessage = fmt.Sprintf("Mark '%s' target no longer exists: %s", name, relPath)
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	// Remember where we came from so '' can return
+	flat := m.FlatEntries()
+	if m.cursor < len(flat) {
+		if from, err := filepath.Rel(m.rootPath, flat[m.cursor].Path); err == nil {
+			m.lastJumpPath = from
+		}
+	}
+
+	m.activePane = TreePane
+	m = m.NavigateToFile(relPath)
+	m.ensureTreeCursorVisible()
+	return m.UpdatePreview()
+}
@@ -7,19 +7,59 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/basket"
 	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/frecency"
 	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/notes"
 	"github.com/connorleisz/contexTUI/internal/terminal"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
 	"github.com/fsnotify/fsnotify"
 )
 
+// Options are startup settings given on the command line
+type Options struct {
+	NoWatch    bool     // Don't watch the filesystem for changes
+	ReadOnly   bool     // Disable changes to files, docs and the repository
+	Confirm    string   // Overrides the configured confirm policy
+	Theme      string   // Overrides the configured theme
+	Accessible bool     // Mark selections and statuses with text, not color alone
+	Select     string   // File to select and preview once the tree has loaded
+	Stage      []string // Files to add to the basket on startup, e.g. piped in on stdin
+	Roots      []string // More directories shown as top-level nodes of the tree
+
+	// Shell integration: enter quits and Chosen returns the path under the cursor
+	Choose    bool
+	ChooseDir bool // Choose directories (a file picks its parent)
+}
+
 // NewModel creates and initializes a new application model
 // Heavy loading is deferred to Init() for async execution
 func NewModel(rootPath string) Model {
-	absPath, _ := filepath.Abs(rootPath)
+	return NewModelWithOptions(rootPath, Options{})
+}
+
+// NewModelWithOptions creates the model with command line options applied
+func NewModelWithOptions(rootPath string, opts Options) Model {
+	return newModel(rootPath, opts, newHighlightPool())
+}
+
+// newModel creates the model for a project, rendering previews on an existing pool
+// so switching projects doesn't start more workers
+func newModel(rootPath string, opts Options, highlighter *highlightPool) Model {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		debuglog.Report("resolve "+rootPath, err)
+		absPath = rootPath
+	}
 
 	// Load user config (fast, local file)
 	cfg := config.Load(absPath)
+	if opts.Theme != "" {
+		cfg.Theme = opts.Theme
+	}
+	readOnly := opts.ReadOnly || cfg.ReadOnly
 
 	// Determine split ratio (config or default)
 	splitRatio := 0.5
@@ -27,12 +67,22 @@ func NewModel(rootPath string) Model {
 		splitRatio = cfg.SplitRatio
 	}
 
+	// Zoomed pane, if any, from the last session
+	layout := layoutSplit
+	if validLayout(cfg.Layout) {
+		layout = cfg.Layout
+	}
+
 	// Determine dotfile visibility (config or default)
 	showDotfiles := cfg.ShowDotfiles
 
+	// Apply the configured theme before any styles are rendered
+	styles.SetAccessible(opts.Accessible || cfg.Accessible)
+	styles.Apply(styles.LoadTheme(absPath, cfg.Theme))
+
 	// Set up search input
 	ti := textinput.New()
-	ti.Placeholder = "Search files..."
+	ti.Placeholder = "Search files... (ext:go dir:internal group:auth)"
 	ti.CharLimit = 100
 	ti.Width = 40
 
@@ -44,28 +94,32 @@ func NewModel(rootPath string) Model {
 
 	// Check for git repository (fast check)
 	isGit, gitRoot := git.IsRepo(absPath)
+	extraRoots := newExtraRoots(absPath, opts.Roots)
 
 	// Set up file watcher
-	watcher, _ := fsnotify.NewWatcher()
+	var watcher *fsnotify.Watcher
+	if !opts.NoWatch {
+		if watcher, err = fsnotify.NewWatcher(); err != nil {
+			debuglog.Report("file watcher (changes on disk won't show until restart)", err)
+		}
+	}
 	if watcher != nil {
 		// Watch root and all subdirectories
-		filepath.Walk(absPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				name := info.Name()
-				// Skip hidden and common ignore dirs
-				if strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" {
-					return filepath.SkipDir
-				}
-				watcher.Add(path)
+		for _, dir := range watchDirs(absPath, cfg.FollowSymlinks) {
+			debuglog.Report("watch "+dir, watcher.Add(dir))
+		}
+		for _, r := range extraRoots {
+			for _, dir := range watchDirs(r.Path, cfg.FollowSymlinks) {
+				debuglog.Report("watch "+dir, watcher.Add(dir))
 			}
-			return nil
-		})
+		}
 		// Explicitly watch .context-docs.md for auto-reload
 		contextDocsPath := filepath.Join(absPath, ".context-docs.md")
 		watcher.Add(contextDocsPath)
+		// Commits and checkouts made outside the app append to the reflog
+		if isGit {
+			watcher.Add(filepath.Join(git.GitDir(gitRoot), "logs"))
+		}
 	}
 
 	// Calculate pending loads count
@@ -77,13 +131,25 @@ func NewModel(rootPath string) Model {
 	// Detect terminal capabilities
 	termCaps := terminal.Detect()
 
+	// Files passed in on startup join the basket kept from earlier sessions
+	staged, added := stageFiles(absPath, basket.Load(absPath), opts.Stage)
+	if added > 0 && !readOnly {
+		debuglog.Report("save basket", basket.Save(absPath, staged))
+	}
+
 	return Model{
 		rootPath:     absPath,
+		extraRoots:   extraRoots,
+		config:       cfg,
 		entries:      nil, // Loaded async in Init()
 		cursor:       0,
 		activePane:   TreePane,
 		splitRatio:   splitRatio,
-		previewCache: make(map[string]CachedPreview),
+		layout:       layout,
+		previewCache: newPreviewCache(cfg),
+		treeLines:    make(map[string]treeLine),
+		panes:        make(map[string]framedPane),
+		highlighter:  highlighter,
 		searchInput:  ti,
 		allFiles:     nil, // Loaded async in Init()
 		watcher:      watcher,
@@ -91,68 +157,125 @@ func NewModel(rootPath string) Model {
 		docRegistry:      nil,
 		selectedDocs:     make(map[string]bool),
 		selectedAddFiles: make(map[string]bool),
+		treeHover:        -1,
+		verifyResults:    make(map[string]VerifyResult),
 		// Git integration - loaded async in Init()
 		isGitRepo:    isGit,
 		gitRepoRoot:  gitRoot,
 		gitStatus:    make(map[string]git.FileStatus),
 		gitDirStatus: make(map[string]string),
-		diffCache:    make(map[DiffCacheKey]CachedDiff),
+		diffCache:    newDiffCache(cfg),
 		// Dotfile visibility
-		showDotfiles: showDotfiles,
+		showDotfiles:  showDotfiles,
+		previewNoWrap: cfg.NoWrap,
+		marks:         cfg.Marks,
+		basket:        staged,
+		frecency:      frecency.Load(absPath),
+		notes:         notes.Load(absPath),
+		showingBasket: added > 0,
+		themeName:     cfg.Theme,
+		issueRules:    loadIssueRules(gitRoot, cfg.IssueLinks),
+		options:       opts,
+		readOnly:      readOnly,
+		pendingSelect: resolveSelect(absPath, opts.Select),
 		// File operations
-		fileOpInput: foInput,
+		fileOpInput:     foInput,
+		commandInput:    newCommandInput(),
+		exportInput:     newExportInput(),
+		noteInput:       newNoteInput(),
+		categoryInput:   newCategoryInput(),
+		docsSearchInput: newDocsSearchInput(),
 		// Terminal capabilities and image preview
 		termCaps:   termCaps,
-		imageCache: make(map[string]CachedImage),
+		imageCache: newImageCache(cfg),
 		// Start with loading state
 		loadingMessage: "Starting up...",
 		pendingLoads:   pendingLoads,
 	}
 }
 
+// resolveSelect returns the --select file relative to the root, or "" when it isn't in the project
+// The path may be relative to the working directory or to the root.
+func resolveSelect(rootPath, path string) string {
+	if path == "" {
+		return ""
+	}
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		abs, _ := filepath.Abs(path)
+		candidates = []string{abs, filepath.Join(rootPath, path)}
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(rootPath, c); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return ""
+}
+
 // CollectAllFiles recursively collects all file paths from a directory
-func CollectAllFiles(root string, showDotfiles bool) []string {
-	var files []string
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+// Also returns the relative paths skipped because they could not be read.
+// Symlinked directories are walked only when followSymlinks is set (see linkWalker).
+func CollectAllFiles(root string, showDotfiles, followSymlinks bool) ([]string, []string) {
+	var files, denied []string
+	ignored := hiddenIgnored(root, showDotfiles)
+	links := newLinkWalker(root)
+	var walk func(dir string)
+	walk = func(dir string) {
+		entries, err := os.ReadDir(dir)
 		if err != nil {
-			return nil
+			if os.IsPermission(err) {
+				relPath, _ := filepath.Rel(root, dir)
+				denied = append(denied, relPath)
+			}
+			return
 		}
-		name := info.Name()
-		// Handle dotfiles
-		if strings.HasPrefix(name, ".") {
-			// .git is always hidden
-			if name == ".git" {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
+		for _, f := range entries {
+			name := f.Name()
+			path := filepath.Join(dir, name)
+			// Same skip rules as the tree, plus git-ignored paths
+			if skipIndexedName(name, showDotfiles) || (ignored[path] && name != ".context-docs.md") {
+				continue
 			}
-			// .context-docs.md is always visible
-			if name == ".context-docs.md" {
-				// continue to add it
-			} else if !showDotfiles {
-				// Skip other dotfiles/dirs unless toggle is on
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
-				return nil
+			if f.IsDir() {
+				walk(path)
+				continue
 			}
-		}
-		// Always skip common package/build directories
-		if name == "node_modules" || name == "vendor" || name == "__pycache__" {
-			if info.IsDir() {
-				return filepath.SkipDir
+			if linkedDir(path, f) {
+				if followSymlinks && links.follow(path) {
+					walk(path)
+				}
+				continue
 			}
-			return nil
-		}
-		if !info.IsDir() {
 			// Store relative path for display
 			relPath, _ := filepath.Rel(root, path)
 			files = append(files, relPath)
 		}
+	}
+	walk(root)
+	return files, denied
+}
+
+// hiddenIgnored returns the paths git ignores under root, or nothing when hidden
+// files are shown
+func hiddenIgnored(root string, showDotfiles bool) map[string]bool {
+	if showDotfiles {
 		return nil
-	})
-	return files
+	}
+	return git.Ignored(root)
+}
+
+// isReadableDir reports whether a directory can be opened for listing
+func isReadableDir(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return !os.IsPermission(err)
+	}
+	f.Close()
+	return true
 }
 
 // LoadDirectory loads directory entries at the specified depth
@@ -169,6 +292,7 @@ func LoadDirectoryWithRoot(path, rootPath string, depth int, showDotfiles bool)
 	if err != nil {
 		return entries
 	}
+	ignored := hiddenIgnored(rootPath, showDotfiles)
 
 	for _, f := range files {
 		name := f.Name()
@@ -186,12 +310,11 @@ func LoadDirectoryWithRoot(path, rootPath string, depth int, showDotfiles bool)
 				continue
 			}
 		}
-		// Always skip common package/build directories
-		if name == "node_modules" || name == "vendor" || name == "__pycache__" {
+		fullPath := filepath.Join(path, name)
+		// Always skip common package/build directories, and git-ignored paths
+		if name == "node_modules" || name == "vendor" || name == "__pycache__" || (ignored[fullPath] && name != ".context-docs.md") {
 			continue
 		}
-
-		fullPath := filepath.Join(path, name)
 		relPath, _ := filepath.Rel(rootPath, fullPath)
 		e := Entry{
 			Name:    name,
@@ -200,24 +323,46 @@ func LoadDirectoryWithRoot(path, rootPath string, depth int, showDotfiles bool)
 			Depth:   depth,
 			RelPath: relPath,
 		}
+		// Symlinks show their target; linked directories can be expanded
+		if f.Type()&os.ModeSymlink != 0 {
+			e.Link, _ = os.Readlink(fullPath)
+			e.IsDir = linkedDir(fullPath, f)
+		}
+		if e.IsDir {
+			e.Denied = !isReadableDir(fullPath)
+		}
 		entries = append(entries, e)
 	}
 
-	return entries
+	return pageEntries(entries, path, depth)
 }
 
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
+	cmds := []tea.Cmd{m.startLoading(), waitForErrorReport()}
+	if m.showsSegment(segmentTime) {
+		cmds = append(cmds, clockTick())
+	}
+	return tea.Batch(cmds...)
+}
+
+// startLoading starts loading the project, also after switching to it
+func (m Model) startLoading() tea.Cmd {
 	// Start async loading of all heavy operations
 	cmds := []tea.Cmd{
 		m.loadDirectoryAsync(),
+		m.loadFileIndexAsync(),
 		m.loadAllFilesAsync(),
 		m.loadRegistryAsync(),
+		m.loadExtraRootsAsync(),
 		SpinnerTick(),
 		m.waitForFsEvent(),
 	}
 	if m.isGitRepo {
 		cmds = append(cmds, m.loadGitStatusAsync())
+		if poll := m.config.GitPoll(); poll > 0 {
+			cmds = append(cmds, ScheduleGitPoll(poll))
+		}
 	}
 	return tea.Batch(cmds...)
 }
@@ -227,22 +372,36 @@ func (m Model) waitForFsEvent() tea.Cmd {
 	if m.watcher == nil {
 		return nil
 	}
+	rootPath := m.rootPath
 	return func() tea.Msg {
 		select {
-		case _, ok := <-m.watcher.Events:
+		case event, ok := <-m.watcher.Events:
 			if !ok {
 				return nil
 			}
+			var msg FsEventMsg
+			add := func(event fsnotify.Event) {
+				if rel, err := filepath.Rel(rootPath, event.Name); err == nil && event.Op != fsnotify.Chmod {
+					debuglog.Watch(event.Op.String(), rel)
+					msg.Paths = append(msg.Paths, rel)
+				}
+			}
+			add(event)
 			// Debounce: wait a bit for rapid changes to settle
 			// Drain any additional events that came in
 			for {
 				select {
-				case <-m.watcher.Events:
+				case event, ok := <-m.watcher.Events:
+					if !ok {
+						return msg
+					}
+					add(event)
 				default:
-					return FsEventMsg{}
+					return msg
 				}
 			}
-		case <-m.watcher.Errors:
+		case err := <-m.watcher.Errors:
+			debuglog.Report("file watcher", err)
 			return nil
 		}
 	}
@@ -254,21 +413,23 @@ func (m Model) FlatEntries() []Entry {
 	if m.treeCache.valid && m.treeCache.flatEntries != nil {
 		return m.treeCache.flatEntries
 	}
-	return flattenEntries(m.entries)
+	return m.flatten()
 }
 
 // FlatEntriesCached returns cached flat entries, rebuilding cache if needed
 func (m *Model) FlatEntriesCached() []Entry {
 	if !m.treeCache.valid || m.treeCache.flatEntries == nil {
-		m.treeCache.flatEntries = flattenEntries(m.entries)
+		m.treeCache.flatEntries = m.flatten()
 		m.treeCache.valid = true
 	}
 	return m.treeCache.flatEntries
 }
 
-// InvalidateTreeCache marks the tree cache as stale
+// InvalidateTreeCache rebuilds the flattened entries after the tree changed
+// Done eagerly so reads through FlatEntries, which can't store the result, stay cheap.
 func (m *Model) InvalidateTreeCache() {
-	m.treeCache.valid = false
+	m.treeCache.flatEntries = m.flatten()
+	m.treeCache.valid = true
 }
 
 func flattenEntries(entries []Entry) []Entry {
@@ -283,7 +444,14 @@ func flattenEntries(entries []Entry) []Entry {
 }
 
 // LeftPaneWidth returns the width of the left (tree) pane
+// In a narrow terminal both panes take the full width, one shown at a time.
 func (m Model) LeftPaneWidth() int {
+	switch {
+	case m.layout == layoutTree || m.narrow():
+		return m.width - 2
+	case m.layout == layoutPreview:
+		return 0
+	}
 	// Total usable width minus borders and gap
 	usable := m.width - 4 // 2 for each pane's border
 	return int(float64(usable) * m.splitRatio)
@@ -291,17 +459,32 @@ func (m Model) LeftPaneWidth() int {
 
 // RightPaneWidth returns the width of the right (preview) pane
 func (m Model) RightPaneWidth() int {
+	switch {
+	case m.layout == layoutPreview || m.narrow():
+		return m.width - 2
+	case m.layout == layoutTree:
+		return 0
+	}
 	usable := m.width - 4
 	return usable - m.LeftPaneWidth()
 }
 
 // DividerX returns the X position of the divider between panes
+// With a pane zoomed, it sits at the screen edge away from it.
 func (m Model) DividerX() int {
+	switch m.shownLayout() {
+	case layoutTree:
+		return m.width
+	case layoutPreview:
+		return 0
+	}
 	return m.LeftPaneWidth() + 2 // +2 for left pane border
 }
 
 // HandlePaneResize adjusts the split ratio between left and right panes
+// Resizing a zoomed layout goes back to both panes.
 func (m *Model) HandlePaneResize(direction string) {
+	m.layout = layoutSplit
 	switch direction {
 	case "left":
 		if m.splitRatio > 0.2 {
@@ -312,10 +495,24 @@ func (m *Model) HandlePaneResize(direction string) {
 			m.splitRatio += 0.05
 		}
 	}
-	m.tree.Width = m.LeftPaneWidth() - 2
-	m.preview.Width = m.RightPaneWidth() - 2
-	m.tree.SetContent(m.RenderTree())
-	config.Save(m.rootPath, config.Config{SplitRatio: m.splitRatio})
+	m.applyPaneWidths()
+	m.saveConfig()
+}
+
+// saveConfig persists the current user preferences
+// Settings without in-app controls are preserved from the loaded config
+func (m *Model) saveConfig() {
+	// Settings changed in read-only mode last for the session
+	if m.readOnly {
+		return
+	}
+	m.config.SplitRatio = m.splitRatio
+	m.config.Layout = m.layout
+	m.config.ShowDotfiles = m.showDotfiles
+	m.config.NoWrap = m.previewNoWrap
+	m.config.Theme = m.themeName
+	m.config.Marks = m.marks
+	debuglog.Report("save settings", config.Save(m.rootPath, m.config))
 }
 
 // HandlePreviewScroll scrolls the preview pane
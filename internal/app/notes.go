@@ -0,0 +1,150 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/notes"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// newNoteInput creates the note editor's prompt
+func newNoteInput() textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = "e.g. generated by make proto, don't edit"
+	ti.CharLimit = 500
+	ti.Width = 60
+	return ti
+}
+
+// openNoteEditor edits the note on the file or folder under the cursor
+func (m Model) openNoteEditor() (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) || flat[m.cursor].More > 0 {
+		return m, nil
+	}
+	rel, err := filepath.Rel(m.rootPath, flat[m.cursor].Path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		m.statusMessage = "Notes can only be attached inside the project"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	m.clearAllOverlays()
+	m.showingNote = true
+	m.notePath = rel
+	m.noteInput.SetValue(m.notes.Get(rel))
+	m.noteInput.CursorEnd()
+	m.noteInput.Focus()
+	return m, textinput.Blink
+}
+
+// saveNote stores the typed note, or removes the note when it was cleared
+func (m Model) saveNote() (tea.Model, tea.Cmd) {
+	m.showingNote = false
+	m.noteInput.Blur()
+	had := m.notes.Get(m.notePath) != ""
+	m.notes.Set(m.notePath, m.noteInput.Value())
+
+	switch {
+	case notes.Save(m.rootPath, m.notes) != nil:
+		m.statusMessage = "Error saving " + notes.FileName
+	case m.notes.Get(m.notePath) != "":
+		m.statusMessage = "Saved note on " + filepath.Base(m.notePath)
+	case had:
+		m.statusMessage = "Removed note from " + filepath.Base(m.notePath)
+	default:
+		return m, nil
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// moveNotes keeps notes attached to a renamed file or folder, and drops those of a
+// deleted one (newPath empty)
+func (m Model) moveNotes(oldPath, newPath string) {
+	oldRel, err := filepath.Rel(m.rootPath, oldPath)
+	if err != nil {
+		return
+	}
+	newRel := ""
+	if newPath != "" {
+		if newRel, err = filepath.Rel(m.rootPath, newPath); err != nil {
+			return
+		}
+	}
+	if m.notes.Move(oldRel, newRel) {
+		debuglog.Report("save notes", notes.Save(m.rootPath, m.notes))
+	}
+}
+
+// previewNote returns the note on the previewed entry, or on its closest folder with one
+func (m Model) previewNote() string {
+	if m.previewPath == "" || len(m.notes) == 0 {
+		return ""
+	}
+	rel, err := filepath.Rel(m.rootPath, m.previewPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	note, at := m.notes.Nearest(rel)
+	if note != "" && at != filepath.ToSlash(rel) {
+		note = at + "/: " + note
+	}
+	return note
+}
+
+// updateNote handles input in the note editor
+func (m Model) updateNote(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			m.showingNote = false
+			m.noteInput.Blur()
+			return m, nil
+		case "enter":
+			return m.saveNote()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+// renderNoteOverlay renders the note editor
+func (m Model) renderNoteOverlay(background string) string {
+	boxWidth := m.overlayWidth(min(max(m.width*70/100, 50), 80))
+	m.noteInput.Width = boxWidth - 10
+
+	var lines []string
+	lines = append(lines, styles.Header.Render("Note"))
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render(ansi.Truncate(filepath.ToSlash(m.notePath), boxWidth-6, "…")))
+	lines = append(lines, "")
+	lines = append(lines, m.noteInput.View())
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("Shown in the header when previewed; search with note:word"))
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[enter] save (empty removes)  [esc] cancel"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,398 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// Steps of the onboarding wizard
+const (
+	onboardIntro      = iota // What context docs are
+	onboardFiles             // Picking markdown files to register
+	onboardCategories        // Picking the categories to list
+	onboardConfirm           // Reviewing what will be written
+)
+
+// onboardSuggestedCategories are offered besides the defaults and those the picked docs use
+var onboardSuggestedCategories = []string{"Architecture", "Guide", "Reference"}
+
+// onboardCategory is a category the wizard can list in the new registry
+type onboardCategory struct {
+	Name   string
+	Picked bool
+	Used   bool // A picked doc is in it, so it is always listed
+}
+
+// shouldOnboard returns true if a project has no registry yet and the wizard
+// hasn't been shown for it
+func (m Model) shouldOnboard(registry *groups.ContextDocRegistry) bool {
+	if m.readOnly || registry == nil || !registry.ModTime.IsZero() || len(registry.Packages) > 0 {
+		return false
+	}
+	return !config.Onboarded(m.rootPath)
+}
+
+// openOnboarding shows the onboarding wizard from its first step
+func (m Model) openOnboarding() Model {
+	m.clearAllOverlays()
+	m.showingOnboarding = true
+	m.onboardStep = onboardIntro
+	m.onboardFiles = nil
+	m.onboardPicked = make(map[string]bool)
+	m.onboardCategories = nil
+	m.onboardCursor = 0
+	m.categoryInput = newCategoryInput()
+	return m
+}
+
+// closeOnboarding hides the wizard for good in this project
+func (m Model) closeOnboarding() Model {
+	m.showingOnboarding = false
+	m.categoryInput.Blur()
+	debuglog.Report("save onboarding state", config.SetOnboarded(m.rootPath))
+	return m
+}
+
+// onboardScan lists the project's markdown files for the files step
+func (m Model) onboardScan() Model {
+	files, _ := groups.FindMarkdownFiles(m.rootPath)
+	m.onboardFiles = files
+	m.onboardStep = onboardFiles
+	m.onboardCursor = 0
+	return m
+}
+
+// onboardPickCategories moves to the categories step, listing the defaults, the
+// categories of the picked docs and a few suggestions
+func (m Model) onboardPickCategories() Model {
+	// Categories the picked docs are in, in file order
+	var usedNames []string
+	used := make(map[string]bool)
+	for _, path := range m.onboardFiles {
+		if !m.onboardPicked[path] {
+			continue
+		}
+		if doc, err := groups.ParseContextDoc(m.rootPath, path); err == nil && doc.Category != "" {
+			usedNames = append(usedNames, doc.Category)
+			used[groups.CategoryID(doc.Category)] = true
+		}
+	}
+
+	var cats []onboardCategory
+	seen := make(map[string]bool)
+	add := func(name string, picked bool) {
+		id := groups.CategoryID(name)
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		cats = append(cats, onboardCategory{Name: name, Picked: picked || used[id], Used: used[id]})
+	}
+	for _, c := range groups.DefaultCategories() {
+		add(c.Name, true)
+	}
+	for _, name := range usedNames {
+		add(name, true)
+	}
+	for _, name := range onboardSuggestedCategories {
+		add(name, false)
+	}
+	m.onboardCategories = cats
+	m.onboardStep = onboardCategories
+	m.onboardCursor = 0
+	return m
+}
+
+// onboardGenerate writes the new registry with the picked docs and categories, then
+// offers to add structure tags to the docs missing required fields
+func (m Model) onboardGenerate() (tea.Model, tea.Cmd) {
+	registry := &groups.ContextDocRegistry{
+		Docs:       []groups.ContextDoc{},
+		ByCategory: make(map[string][]groups.ContextDoc),
+	}
+	for _, c := range m.onboardCategories {
+		if c.Picked {
+			registry.AddCategory(c.Name)
+		}
+	}
+
+	var edits []docEdit
+	added, incomplete := 0, 0
+	for _, path := range m.onboardFiles {
+		if !m.onboardPicked[path] {
+			continue
+		}
+		doc, err := groups.ParseContextDoc(m.rootPath, path)
+		if err != nil {
+			continue
+		}
+		doc.ValidateKeyFiles(m.rootPath)
+		doc.CheckStaleness(m.rootPath)
+		if edit, ok := structureTagEdit(m.rootPath, docEditAnnotate, *doc); ok {
+			edits = append(edits, edit)
+		}
+		if len(doc.MissingFields) > 0 {
+			incomplete++
+		}
+		registry.AddDoc(*doc)
+		added++
+	}
+
+	snap := m.takeUndo("Create .context-docs.md")
+	m.docRegistry = registry
+	m = m.closeOnboarding()
+	if err := m.saveRegistry(); err != nil {
+		m.statusMessage = fmt.Sprintf("Error saving: %v", err)
+	} else {
+		m.pushUndo(snap)
+		if incomplete > 0 {
+			m.statusMessage = fmt.Sprintf("Created .context-docs.md with %d doc(s) (%d incomplete): press g to see them", added, incomplete)
+		} else {
+			m.statusMessage = fmt.Sprintf("Created .context-docs.md with %d doc(s): press g to see them", added)
+		}
+	}
+	m.statusMessageTime = time.Now()
+	reviewCmd := m.reviewDocEdits(edits)
+	return m, tea.Batch(ClearStatusAfter(5*time.Second), reviewCmd, m.loadRegistryAsync())
+}
+
+// onboardListLen returns how many rows the current step's list has
+func (m Model) onboardListLen() int {
+	switch m.onboardStep {
+	case onboardFiles:
+		return len(m.onboardFiles)
+	case onboardCategories:
+		return len(m.onboardCategories)
+	}
+	return 0
+}
+
+// updateOnboarding handles input in the onboarding wizard
+func (m Model) updateOnboarding(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	// Naming a category of one's own
+	if m.categoryInput.Focused() {
+		switch keyMsg.String() {
+		case "esc":
+			m.categoryInput.Blur()
+		case "enter":
+			name := strings.TrimSpace(m.categoryInput.Value())
+			m.categoryInput.Blur()
+			if groups.CategoryID(name) == "" {
+				return m, nil
+			}
+			for i, c := range m.onboardCategories {
+				if groups.CategoryID(c.Name) == groups.CategoryID(name) {
+					m.onboardCategories[i].Picked = true
+					m.onboardCursor = i
+					return m, nil
+				}
+			}
+			m.onboardCategories = append(m.onboardCategories, onboardCategory{Name: name, Picked: true})
+			m.onboardCursor = len(m.onboardCategories) - 1
+		default:
+			var cmd tea.Cmd
+			m.categoryInput, cmd = m.categoryInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		// Skip setup; the docs panel can add docs later
+		m = m.closeOnboarding()
+		m.statusMessage = "Skipped setup: press g, then a to add context docs"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(5 * time.Second)
+
+	case "enter":
+		switch m.onboardStep {
+		case onboardIntro:
+			return m.onboardScan(), nil
+		case onboardFiles:
+			return m.onboardPickCategories(), nil
+		case onboardCategories:
+			m.onboardStep = onboardConfirm
+			return m, nil
+		case onboardConfirm:
+			return m.onboardGenerate()
+		}
+
+	case "backspace", "h", "left":
+		if m.onboardStep > onboardIntro {
+			m.onboardStep--
+			m.onboardCursor = 0
+		}
+
+	case "j", "down":
+		if m.onboardCursor < m.onboardListLen()-1 {
+			m.onboardCursor++
+		}
+
+	case "k", "up":
+		if m.onboardCursor > 0 {
+			m.onboardCursor--
+		}
+
+	case " ":
+		switch m.onboardStep {
+		case onboardFiles:
+			if m.onboardCursor < len(m.onboardFiles) {
+				path := m.onboardFiles[m.onboardCursor]
+				if m.onboardPicked[path] {
+					delete(m.onboardPicked, path)
+				} else {
+					m.onboardPicked[path] = true
+				}
+			}
+		case onboardCategories:
+			if m.onboardCursor < len(m.onboardCategories) && !m.onboardCategories[m.onboardCursor].Used {
+				m.onboardCategories[m.onboardCursor].Picked = !m.onboardCategories[m.onboardCursor].Picked
+			}
+		}
+
+	case "a":
+		// Pick every file, or none when all are picked
+		if m.onboardStep == onboardFiles {
+			all := len(m.onboardPicked) == len(m.onboardFiles)
+			m.onboardPicked = make(map[string]bool)
+			if !all {
+				for _, path := range m.onboardFiles {
+					m.onboardPicked[path] = true
+				}
+			}
+		}
+
+	case "n":
+		if m.onboardStep == onboardCategories {
+			m.categoryInput.SetValue("")
+			m.categoryInput.Focus()
+		}
+	}
+	return m, nil
+}
+
+// renderOnboardingOverlay renders the current step of the onboarding wizard
+func (m Model) renderOnboardingOverlay(background string) string {
+	boxWidth := m.overlayWidth(min(max(m.width*70/100, 50), 84))
+	rows := max(m.height-18, 4)
+	check := lipgloss.NewStyle().Foreground(styles.SuccessBold).Render("✓ ")
+
+	// list renders a step's rows around the cursor
+	list := func(n int, row func(i int) string) []string {
+		start := 0
+		if m.onboardCursor >= rows {
+			start = m.onboardCursor - rows + 1
+		}
+		var lines []string
+		for i := start; i < n && i < start+rows; i++ {
+			line := ansi.Truncate(row(i), boxWidth-6, "…")
+			if i == m.onboardCursor {
+				line = styles.Selected.Render(padRight(line, boxWidth-6))
+			}
+			lines = append(lines, line)
+		}
+		if start+rows < n {
+			lines = append(lines, styles.Faint.Render(fmt.Sprintf("  ▼ %d more", n-start-rows)))
+		}
+		return lines
+	}
+
+	var lines []string
+	var footer string
+	switch m.onboardStep {
+	case onboardIntro:
+		lines = append(lines, styles.Header.Render("Welcome to contexTUI"))
+		lines = append(lines, "")
+		lines = append(lines, styles.Muted.Render("Context docs are markdown files that explain a part of the project"))
+		lines = append(lines, styles.Muted.Render("to you and to coding agents: what it does, and the key files behind it."))
+		lines = append(lines, styles.Muted.Render("They are listed by category in .context-docs.md, the registry, and"))
+		lines = append(lines, styles.Muted.Render("contexTUI flags docs that go stale as their key files change."))
+		lines = append(lines, "")
+		lines = append(lines, styles.Muted.Render("This project has no registry yet. Setup looks for markdown files you"))
+		lines = append(lines, styles.Muted.Render("already have, lets you pick categories, and writes one. Nothing is"))
+		lines = append(lines, styles.Muted.Render("written until the last step."))
+		footer = "[enter] scan for markdown files  [esc] skip"
+
+	case onboardFiles:
+		lines = append(lines, styles.Title.Render(fmt.Sprintf("Pick docs (%d of %d)", len(m.onboardPicked), len(m.onboardFiles))))
+		lines = append(lines, "")
+		if len(m.onboardFiles) == 0 {
+			lines = append(lines, styles.Faint.Render("No markdown files found; the registry will start empty"))
+		}
+		lines = append(lines, list(len(m.onboardFiles), func(i int) string {
+			if m.onboardPicked[m.onboardFiles[i]] {
+				return check + m.onboardFiles[i]
+			}
+			return "  " + m.onboardFiles[i]
+		})...)
+		footer = "[space] pick  [a] all/none  [enter] next  [h] back  [esc] skip"
+
+	case onboardCategories:
+		lines = append(lines, styles.Title.Render("Pick categories"))
+		lines = append(lines, styles.Faint.Render("Docs set theirs with a **Category:** line"))
+		lines = append(lines, "")
+		lines = append(lines, list(len(m.onboardCategories), func(i int) string {
+			c := m.onboardCategories[i]
+			line := "  " + c.Name
+			if c.Picked {
+				line = check + c.Name
+			}
+			if c.Used {
+				line += styles.Faint.Render("  (used by picked docs)")
+			}
+			return line
+		})...)
+		if m.categoryInput.Focused() {
+			lines = append(lines, "", m.categoryInput.View())
+			footer = "[enter] add  [esc] cancel"
+		} else {
+			footer = "[space] pick  [n] new  [enter] next  [h] back  [esc] skip"
+		}
+
+	case onboardConfirm:
+		var names []string
+		for _, c := range m.onboardCategories {
+			if c.Picked {
+				names = append(names, c.Name)
+			}
+		}
+		lines = append(lines, styles.Header.Render("Create .context-docs.md"))
+		lines = append(lines, "")
+		lines = append(lines, fmt.Sprintf("  %s %d", styles.Muted.Render("Docs:      "), len(m.onboardPicked)))
+		lines = append(lines, "  "+styles.Muted.Render("Categories: ")+ansi.Truncate(strings.Join(names, ", "), boxWidth-20, "…"))
+		lines = append(lines, "")
+		lines = append(lines, styles.Muted.Render("Docs missing required fields are offered structure tags next,"))
+		lines = append(lines, styles.Muted.Render("each shown as a diff to write or skip."))
+		footer = "[enter] write  [h] back  [esc] skip"
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render(footer))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// copyPermalink copies the web URL of path on the origin remote at HEAD, with a
+// line range when start > 0
+func (m Model) copyPermalink(path string, isDir bool, start, end int) (tea.Model, tea.Cmd) {
+	if !m.isGitRepo {
+		m.statusMessage = "Not a git repository"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	relPath, err := filepath.Rel(m.gitRepoRoot, path)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		m.statusMessage = "Outside the repository: " + path
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	link, err := git.Permalink(m.gitRepoRoot, filepath.ToSlash(relPath), isDir, start, end)
+	if err != nil {
+		m.statusMessage = "No link: " + err.Error()
+	} else if err := m.copyText("link", link); err != nil {
+		m.statusMessage = "Clipboard unavailable"
+	} else if _, changed := m.gitStatus[relPath]; changed {
+		// The link points at the last commit, so local edits aren't in it
+		m.statusMessage = "Copied link (uncommitted changes aren't in it)"
+	} else {
+		m.statusMessage = "Copied " + link
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
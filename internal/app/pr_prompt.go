@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// prPromptFile overrides the built-in pull request description prompt
+const prPromptFile = ".contextui/pr-prompt.md"
+
+// defaultPRPrompt asks for a pull request description
+// {{branch}}, {{base}}, {{commits}}, {{files}}, {{docs}} and {{diff}} are filled in.
+const defaultPRPrompt = "Write a pull request description for merging {{branch}} into {{base}}.\n\n" +
+	"Use this markdown structure:\n\n" +
+	"## Summary\nOne or two sentences on what the change does and why.\n\n" +
+	"## Changes\nA bullet per notable change.\n\n" +
+	"## Testing\nHow the change was verified.\n\n" +
+	"## Notes\nRisks, follow-ups or anything reviewers should look at closely (omit if none).\n\n" +
+	"Commits:\n{{commits}}\n\n" +
+	"Changed files:\n{{files}}\n\n" +
+	"Context docs for these files:\n{{docs}}\n\n" +
+	"```diff\n{{diff}}\n```\n"
+
+// copyPRPrompt copies a prompt asking for a pull request description of the
+// current branch's changes against its base
+func (m Model) copyPRPrompt() (tea.Model, tea.Cmd) {
+	base := git.BaseRef(m.gitRepoRoot)
+	if base == "" {
+		m.statusMessage = "No base branch found"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	changes, err := git.BranchFiles(m.gitRepoRoot, base)
+	diff, _ := git.BranchDiff(m.gitRepoRoot, base)
+	if err != nil || len(changes) == 0 || strings.TrimSpace(diff) == "" {
+		m.statusMessage = "No changes against " + base
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	commits, _ := git.LogRange(m.gitRepoRoot, base, "HEAD")
+	commitLines := []string{"(none)"}
+	if len(commits) > 0 {
+		commitLines = commitLines[:0]
+		for _, c := range commits {
+			commitLines = append(commitLines, fmt.Sprintf("- %s %s", c.Hash, c.Subject))
+		}
+	}
+	var files []string
+	for _, c := range changes {
+		if c.OldPath != "" {
+			files = append(files, fmt.Sprintf("- %s %s -> %s", c.Status, c.OldPath, c.Path))
+		} else {
+			files = append(files, fmt.Sprintf("- %s %s", c.Status, c.Path))
+		}
+	}
+	branch := m.gitBranch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	prompt := strings.NewReplacer(
+		"{{branch}}", branch,
+		"{{base}}", base,
+		"{{commits}}", strings.Join(commitLines, "\n"),
+		"{{files}}", strings.Join(files, "\n"),
+		"{{docs}}", m.promptDocs(changes),
+		"{{diff}}", strings.TrimRight(diff, "\n"),
+	).Replace(promptTemplate(m.rootPath, prPromptFile, defaultPRPrompt))
+
+	if err := m.copyText("pr prompt", prompt); err != nil {
+		m.statusMessage = "Clipboard unavailable"
+	} else {
+		m.statusMessage = fmt.Sprintf("Copied PR description prompt (%d commits, %d files vs %s)", len(commits), len(changes), base)
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
@@ -12,8 +12,12 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/connorleisz/contexTUI/internal/filetype"
 	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/tokens"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
 	"github.com/muesli/reflow/wordwrap"
 )
 
@@ -21,6 +25,10 @@ const (
 	maxPreviewSize  = 512 * 1024 // 512KB - files larger than this are truncated
 	maxPreviewLines = 2000       // Max lines to show for large files
 
+	// noWrapWidth is passed as the preview width to render without wrapping
+	noWrapWidth    = -1
+	previewPanStep = 8 // Columns moved per horizontal pan
+
 	// Diff context sizes for progressive loading
 	quickDiffContext = 10    // Quick initial load - small context
 	fullDiffContext  = 99999 // Full load - entire file context
@@ -34,10 +42,51 @@ func (m Model) UpdatePreview() (Model, tea.Cmd) {
 	}
 
 	e := flat[m.cursor]
+	m.previewXOffset = 0
+	m.previewTokens = 0
+	m.previewReloaded = false
+	m.previewRestoreOffset = 0
+	m.previewPartial = false
+	m.previewStream = nil
+	m.previewPerm = ""
+	m.previewLink = e.Link
+	if e.More > 0 {
+		m.previewIsImage = false
+		m.structured = nil
+		m.loading = false
+		m.previewPath = ""
+		m.preview.SetContent(fmt.Sprintf("%s more entries in this folder\n\nPress x or enter to list the next %d", formatCount(e.More), min(e.More, treePageSize)))
+		m.previewLines = nil
+		return m, nil
+	}
 	if e.IsDir {
+		m.previewIsImage = false
+		m.structured = nil
+		m.loading = true
+		m.previewPath = e.Path
 		m.preview.SetContent("Directory: " + e.Name)
+
+		// Summarize the directory in the background
+		dirPath, rootPath, showDotfiles := e.Path, m.rootPath, m.showDotfiles
+		var docs []groups.ContextDoc
+		if m.docRegistry != nil {
+			docs = m.docRegistry.Docs
+		}
+		return m, func() tea.Msg {
+			return LoadDirectorySummary(dirPath, rootPath, showDotfiles, docs)
+		}
+	}
+
+	info, statErr := os.Stat(e.Path)
+	if statErr == nil {
+		m.previewPerm = info.Mode().String()
+	} else if e.Link != "" {
 		m.previewIsImage = false
+		m.structured = nil
 		m.loading = false
+		m.previewPath = e.Path
+		m.preview.SetContent("Broken link: " + e.Link + " does not exist")
+		m.previewLines = nil
 		return m, nil
 	}
 
@@ -49,17 +98,50 @@ func (m Model) UpdatePreview() (Model, tea.Cmd) {
 	// Clear image preview state for text files
 	m.previewIsImage = false
 	m.currentImage = nil
+	m.structured = nil
+	if statErr == nil {
+		m.previewTokens = tokens.EstimateSize(info.Size())
+	}
 
-	// Check cache first
-	if cached, ok := m.previewCache[e.Path]; ok {
-		info, err := os.Stat(e.Path)
-		if err == nil && info.ModTime().Equal(cached.ModTime) {
-			// Cache hit - use cached content
-			m.preview.SetContent(cached.Content)
+	// The diff against HEAD depends on the index and commits too, so it isn't cached
+	if m.previewDiff && m.isGitRepo {
+		m.previewPath = e.Path
+		if m.gitStatus[e.RelPath].Status == "?" {
+			m.loading = false
+			m.preview.SetContent("Untracked file - nothing committed to compare with")
+			m.previewLines = nil
+			return m, nil
+		}
+		m.loading = true
+		m.preview.SetContent("Loading...")
+		filePath, repoRoot, previewWidth := e.Path, m.gitRepoRoot, m.previewRenderWidth()
+		var cmd tea.Cmd
+		m.previewRequestID, cmd = m.highlighter.Submit(func(func(FileLoadedMsg) bool) FileLoadedMsg {
+			return LoadWorkingDiff(repoRoot, filePath, previewWidth)
+		})
+		return m, cmd
+	}
+
+	// Check cache first (blame annotations are cached separately)
+	blame := m.previewBlame && m.isGitRepo
+	cacheKey := e.Path
+	if blame {
+		cacheKey = blameCacheKey(e.Path)
+	}
+	if cached, ok := m.previewCache.Get(cacheKey); ok {
+		if statErr == nil && info.ModTime().Equal(cached.ModTime) {
+			// Cache hit - use cached content (structured previews re-render with their folds)
+			content := cached.Content
+			if cached.Structured != nil {
+				m.structured = cached.Structured
+				content = m.structured.render(m.previewRenderWidth())
+			}
+			m.preview.SetContent(content)
 			m.previewPath = e.Path
-			m.previewLines = strings.Split(cached.Content, "\n")
+			m.previewLines = strings.Split(content, "\n")
 			m.loading = false
 			m.preview.GotoTop()
+			m.startStream(e.Path, e.Name, content, cached.More, cached.MoreLine)
 			return m, nil
 		}
 	}
@@ -70,12 +152,57 @@ func (m Model) UpdatePreview() (Model, tea.Cmd) {
 	m.preview.SetContent("Loading...")
 
 	// Return command that loads file content
-	previewWidth := m.preview.Width
+	previewWidth := m.previewRenderWidth()
 	fileName := e.Name
 	filePath := e.Path
-	return m, func() tea.Msg {
-		return LoadFileContent(filePath, fileName, previewWidth)
+	repoRoot, rules := m.gitRepoRoot, m.issueRules
+	var cmd tea.Cmd
+	m.previewRequestID, cmd = m.highlighter.Submit(func(partial func(FileLoadedMsg) bool) FileLoadedMsg {
+		if blame {
+			return LoadBlame(repoRoot, filePath, fileName, previewWidth)
+		}
+		return loadFileContent(filePath, fileName, previewWidth, rules, partial)
+	})
+	return m, cmd
+}
+
+// notePreviewChanged remembers that the previewed file changed on disk, so the
+// next refresh reloads it
+func (m *Model) notePreviewChanged(paths []string) {
+	if m.previewPath == "" {
+		return
+	}
+	rel := m.previewRelPath()
+	for _, p := range paths {
+		if filepath.Clean(p) == rel {
+			m.previewChanged = true
+			return
+		}
+	}
+}
+
+// reloadChangedPreview reloads the previewed file after it changed on disk, keeping
+// the scroll position as far as the new content allows
+// Modes that hold on to preview lines (copy mode, contents, history, git) are left alone.
+func (m Model) reloadChangedPreview() (Model, tea.Cmd) {
+	m.previewChanged = false
+	if m.selectMode || m.tocMode || m.fileHistoryMode || m.gitStatusMode {
+		return m, nil
+	}
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) || flat[m.cursor].IsDir || flat[m.cursor].Path != m.previewPath {
+		return m, nil
+	}
+
+	offset := m.preview.YOffset
+	m, cmd := m.UpdatePreview()
+	m.previewReloaded = true
+	if m.loading {
+		m.previewRestoreOffset = offset
+	} else {
+		m.preview.SetYOffset(offset)
 	}
+	return m, cmd
 }
 
 // updateImagePreview handles image file preview
@@ -87,8 +214,8 @@ func (m Model) updateImagePreview(e Entry) (Model, tea.Cmd) {
 	viewportH := m.preview.Height
 
 	// Check image cache first
-	if cached, ok := m.imageCache[e.Path]; ok {
-		if validateImageCache(cached, e.Path, viewportW, viewportH) {
+	if cached, ok := m.imageCache.Get(e.Path); ok {
+		if validateImageCache(cached, e.Path, viewportW, viewportH, m.termCaps, m.themeName) {
 			// Cache hit - use cached render
 			m.currentImage = &ImageLoadedMsg{
 				Path:       e.Path,
@@ -111,8 +238,98 @@ func (m Model) updateImagePreview(e Entry) (Model, tea.Cmd) {
 	return m, loadImageAsync(e.Path, m.termCaps, viewportW, viewportH)
 }
 
+// previewRenderWidth returns the width previews are wrapped to, or noWrapWidth
+func (m Model) previewRenderWidth() int {
+	if m.previewNoWrap {
+		return noWrapWidth
+	}
+	return m.preview.Width
+}
+
+// togglePreviewWrap switches between wrapped and horizontally scrollable previews
+func (m Model) togglePreviewWrap() (tea.Model, tea.Cmd) {
+	m.previewNoWrap = !m.previewNoWrap
+	m.previewXOffset = 0
+	m.saveConfig()
+
+	// Rendered previews and diffs are wrapped at load time, so drop them
+	m.previewCache.Clear()
+	m.diffCache.Clear()
+
+	var cmd tea.Cmd
+	if m.gitStatusMode {
+		m, cmd = m.UpdateGitStatusPreview()
+	} else if m.previewPath != "" && !m.previewIsImage {
+		m, cmd = m.UpdatePreview()
+	}
+
+	m.statusMessage = "Wrap on"
+	if m.previewNoWrap {
+		m.statusMessage = "Wrap off (←/→ or H/L to pan)"
+	}
+	m.statusMessageTime = time.Now()
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
+
+// panPreview scrolls an unwrapped preview horizontally; returns false when wrapping is on
+func (m *Model) panPreview(delta int) bool {
+	if !m.previewNoWrap {
+		return false
+	}
+	longest := 0
+	for _, line := range m.previewLines {
+		if w := ansi.StringWidth(line); w > longest {
+			longest = w
+		}
+	}
+	m.previewXOffset += delta
+	if limit := longest - m.preview.Width; m.previewXOffset > limit {
+		m.previewXOffset = limit
+	}
+	if m.previewXOffset < 0 {
+		m.previewXOffset = 0
+	}
+	return true
+}
+
+// previewView renders the preview viewport, cutting unwrapped lines at the pan offset
+// The line number gutter stays in place while the content scrolls.
+func (m Model) previewView() string {
+	if !m.previewNoWrap || m.previewIsImage || m.loading || len(m.previewLines) == 0 {
+		return m.preview.View()
+	}
+
+	start := m.preview.YOffset
+	end := start + m.preview.Height
+	if end > len(m.previewLines) {
+		end = len(m.previewLines)
+	}
+	if start > end {
+		start = end
+	}
+
+	var out []string
+	for _, line := range m.previewLines[start:end] {
+		gutter := 0
+		if idx := strings.Index(ansi.Strip(line), "│ "); idx != -1 {
+			gutter = ansi.StringWidth(ansi.Strip(line)[:idx]) + 2
+		}
+		width := m.preview.Width - gutter
+		out = append(out, ansi.Cut(line, 0, gutter)+
+			ansi.Cut(line, gutter+m.previewXOffset, gutter+m.previewXOffset+width))
+	}
+	return strings.Join(out, "\n")
+}
+
 // LoadFileContent loads and processes file content for preview
-func LoadFileContent(filePath, fileName string, previewWidth int) FileLoadedMsg {
+func LoadFileContent(filePath, fileName string, previewWidth int, rules []issueRule) FileLoadedMsg {
+	return loadFileContent(filePath, fileName, previewWidth, rules, nil)
+}
+
+// loadFileContent renders a file preview; for big code files the first
+// highlightChunkLines are passed to partial before the whole file is highlighted.
+// It gives up early when partial reports the request was superseded.
+func loadFileContent(filePath, fileName string, previewWidth int, rules []issueRule, partial func(FileLoadedMsg) bool) FileLoadedMsg {
 	// Get file info for cache validation and size check
 	info, err := os.Stat(filePath)
 	if err != nil {
@@ -120,50 +337,39 @@ func LoadFileContent(filePath, fileName string, previewWidth int) FileLoadedMsg
 	}
 	modTime := info.ModTime()
 
-	var content []byte
-	var truncated bool
-
-	// Check file size and truncate if needed
-	if info.Size() > maxPreviewSize {
-		truncated = true
-		// Read only first portion of large files
-		f, err := os.Open(filePath)
-		if err != nil {
-			return FileLoadedMsg{Path: filePath, Content: "Error: " + err.Error()}
-		}
-		defer f.Close()
-		content = make([]byte, maxPreviewSize)
-		n, _ := f.Read(content)
-		content = content[:n]
-	} else {
-		content, err = os.ReadFile(filePath)
-		if err != nil {
-			return FileLoadedMsg{Path: filePath, Content: "Error: " + err.Error()}
-		}
+	// Binary, archive and PDF files get a summary instead of raw bytes
+	if summary, ok := renderNonText(filePath, info.Size()); ok {
+		return FileLoadedMsg{Path: filePath, Content: summary, ModTime: modTime}
 	}
 
-	// For large content, limit by lines
-	text := string(content)
-	if truncated || len(strings.Split(text, "\n")) > maxPreviewLines {
-		lines := strings.Split(text, "\n")
-		if len(lines) > maxPreviewLines {
-			lines = lines[:maxPreviewLines]
-			truncated = true
-		}
-		text = strings.Join(lines, "\n")
+	// Read the first chunk; the rest of a big file streams in as the preview scrolls
+	text, next, eof, err := readPreviewChunk(filePath, 0)
+	if err != nil {
+		return FileLoadedMsg{Path: filePath, Content: "Error: " + err.Error()}
 	}
+	truncated := !eof
 
-	// Add truncation notice
-	if truncated {
-		text = fmt.Sprintf("--- File truncated (showing first %d lines of %s) ---\n\n%s",
-			maxPreviewLines, humanSize(info.Size()), text)
+	// JSON/YAML get a pretty-printed, foldable preview
+	if isStructuredFile(fileName) && !truncated {
+		doc := newStructuredDoc(text, fileName)
+		return FileLoadedMsg{Path: filePath, Content: doc.render(previewWidth), ModTime: modTime, Structured: doc}
 	}
 
-	// Render markdown files with glamour
+	// Render markdown files with glamour (prose still wraps when unwrapped, at a fixed width)
 	if strings.HasSuffix(fileName, ".md") {
+		// Rendered markdown can't be continued chunk by chunk, so it stays truncated
+		if truncated {
+			text = fmt.Sprintf("--- File truncated (showing first %d lines of %s) ---\n\n%s",
+				strings.Count(text, "\n")+1, humanSize(info.Size()), text)
+		}
+		text = linkIssueRefs(rules, highlightWikiLinks(text))
+		wrapWidth := previewWidth
+		if wrapWidth == noWrapWidth {
+			wrapWidth = 80
+		}
 		renderer, err := glamour.NewTermRenderer(
-			glamour.WithAutoStyle(),
-			glamour.WithWordWrap(previewWidth),
+			glamourStyleOption(),
+			glamour.WithWordWrap(wrapWidth),
 		)
 		if err == nil {
 			rendered, err := renderer.Render(text)
@@ -173,27 +379,58 @@ func LoadFileContent(filePath, fileName string, previewWidth int) FileLoadedMsg
 		}
 	}
 
-	// Syntax highlight code files with chroma
+	// Syntax highlight code files with chroma, showing the top first for big files
+	if partial != nil {
+		if idx := nthIndex(text, "\n", highlightChunkLines); idx >= 0 {
+			head := HighlightCode(text[:idx], fileName, previewWidth) + "\n" + styles.Faint.Render("  … highlighting the rest")
+			if !partial(FileLoadedMsg{Path: filePath, Content: head}) {
+				return FileLoadedMsg{Path: filePath}
+			}
+		}
+	}
 	highlighted := HighlightCode(text, fileName, previewWidth)
-	return FileLoadedMsg{Path: filePath, Content: highlighted, ModTime: modTime}
+	msg := FileLoadedMsg{Path: filePath, Content: highlighted, ModTime: modTime}
+	if truncated {
+		msg.More = next
+		msg.MoreLine = strings.Count(highlighted, "\n") + 2
+	}
+	return msg
+}
+
+// glamourStyleOption returns the glamour style matching the active theme
+func glamourStyleOption() glamour.TermRendererOption {
+	style := styles.Current().GlamourStyle
+	if style == "" || style == "auto" {
+		return glamour.WithAutoStyle()
+	}
+	return glamour.WithStandardStyle(style)
 }
 
 // LoadFilePreview returns a command that loads file content asynchronously
-func LoadFilePreview(e Entry, previewWidth int) tea.Cmd {
+func LoadFilePreview(e Entry, previewWidth int, rules []issueRule) tea.Cmd {
 	return func() tea.Msg {
-		return LoadFileContent(e.Path, e.Name, previewWidth)
+		return LoadFileContent(e.Path, e.Name, previewWidth, rules)
 	}
 }
 
 // UpdateGitStatusPreview loads the diff preview for the currently selected git change
 // Uses progressive loading: quick diff first, then full diff in background
 func (m Model) UpdateGitStatusPreview() (Model, tea.Cmd) {
-	if m.gitStatusCursor >= len(m.gitChanges) {
+	change, ok := m.selectedGitChange()
+	if !ok {
+		// A directory lists its changes
+		if row, ok := m.selectedGitRow(); ok && row.kind == gitRowDir {
+			content := m.renderGitDirSummary(row)
+			m.previewPath = ""
+			m.loading = false
+			m.preview.SetContent(content)
+			m.previewLines = strings.Split(content, "\n")
+			m.preview.GotoTop()
+		}
 		return m, nil
 	}
-
-	change := m.gitChanges[m.gitStatusCursor]
 	fullPath := filepath.Join(m.gitRepoRoot, change.Path)
+	m.previewXOffset = 0
 
 	// Untracked files - show file content (no diff exists)
 	if change.Status == "?" {
@@ -201,10 +438,11 @@ func (m Model) UpdateGitStatusPreview() (Model, tea.Cmd) {
 		m.previewPath = fullPath
 		m.preview.SetContent("Loading...")
 
-		previewWidth := m.preview.Width
+		previewWidth := m.previewRenderWidth()
 		fileName := filepath.Base(change.Path)
+		rules := m.issueRules
 		return m, func() tea.Msg {
-			return LoadFileContent(fullPath, fileName, previewWidth)
+			return LoadFileContent(fullPath, fileName, previewWidth, rules)
 		}
 	}
 
@@ -213,19 +451,19 @@ func (m Model) UpdateGitStatusPreview() (Model, tea.Cmd) {
 	requestID := m.diffRequestID
 
 	// Capture values for async commands
-	previewWidth := m.preview.Width
+	previewWidth := m.previewRenderWidth()
 	repoRoot := m.gitRepoRoot
 	staged := change.Staged
 	relPath := change.Path
 
 	// Initialize cache if needed
 	if m.diffCache == nil {
-		m.diffCache = make(map[DiffCacheKey]CachedDiff)
+		m.diffCache = newDiffCache(m.config)
 	}
 
 	// Check cache for full diff first (best case - instant)
 	fullKey := DiffCacheKey{Path: fullPath, Staged: staged, ContextSize: fullDiffContext}
-	if cached, ok := m.diffCache[fullKey]; ok {
+	if cached, ok := m.diffCache.Get(fullKey); ok {
 		m.preview.SetContent(cached.Content)
 		m.previewPath = fullPath
 		m.previewLines = strings.Split(cached.Content, "\n")
@@ -236,7 +474,7 @@ func (m Model) UpdateGitStatusPreview() (Model, tea.Cmd) {
 
 	// Check cache for quick diff (show it, then load full in background)
 	quickKey := DiffCacheKey{Path: fullPath, Staged: staged, ContextSize: quickDiffContext}
-	if cached, ok := m.diffCache[quickKey]; ok {
+	if cached, ok := m.diffCache.Get(quickKey); ok {
 		m.preview.SetContent(cached.Content)
 		m.previewPath = fullPath
 		m.previewLines = strings.Split(cached.Content, "\n")
@@ -327,8 +565,13 @@ func LoadFullDiff(repoRoot, filePath string, staged bool, previewWidth int, requ
 
 // HighlightCode uses chroma to syntax highlight code based on filename
 func HighlightCode(code, filename string, maxWidth int) string {
+	return highlightCodeFrom(code, filename, maxWidth, 1)
+}
+
+// highlightCodeFrom highlights code whose first line is numbered firstLine
+func highlightCodeFrom(code, filename string, maxWidth, firstLine int) string {
 	// Calculate gutter width for line number adjustment
-	lineCount := strings.Count(code, "\n") + 1
+	lineCount := strings.Count(code, "\n") + firstLine
 	gutterWidth := len(fmt.Sprintf("%d", lineCount))
 	if gutterWidth < 4 {
 		gutterWidth = 4
@@ -336,27 +579,27 @@ func HighlightCode(code, filename string, maxWidth int) string {
 	gutterTotal := gutterWidth + 3 // number + " │ "
 
 	// Skip highlighting for certain file types that don't benefit from it
-	skipExtensions := []string{".sum", ".lock", ".txt", ".log", ".csv", ".json"}
+	skipExtensions := []string{".sum", ".lock", ".txt", ".log", ".csv"}
 	for _, ext := range skipExtensions {
 		if strings.HasSuffix(filename, ext) {
 			wrapped := wrapLines(code, maxWidth-gutterTotal)
-			return addLineNumbers(wrapped)
+			return addLineNumbersFrom(wrapped, firstLine)
 		}
 	}
 
 	var buf bytes.Buffer
 
 	// Use filename to detect language, "terminal256" formatter for terminal colors
-	err := quick.Highlight(&buf, code, filename, "terminal256", "monokai")
+	err := quick.Highlight(&buf, code, filename, "terminal256", styles.Current().ChromaStyle)
 	if err != nil {
 		// Fall back to plain text if highlighting fails
 		wrapped := wrapLines(code, maxWidth-gutterTotal)
-		return addLineNumbers(wrapped)
+		return addLineNumbersFrom(wrapped, firstLine)
 	}
 
 	// Word wrap highlighted output and add line numbers
 	wrapped := wrapLines(buf.String(), maxWidth-gutterTotal)
-	return addLineNumbers(wrapped)
+	return addLineNumbersFrom(wrapped, firstLine)
 }
 
 // HighlightDiff applies syntax highlighting to git diff output
@@ -371,10 +614,10 @@ func HighlightDiff(diffText string, maxWidth int) string {
 	gutterTotal := gutterWidth + 3
 
 	// Style definitions for diff output
-	addStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("118"))    // Green
-	removeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // Red
-	hunkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("81"))    // Cyan
-	headerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("226")) // Yellow
+	addStyle := lipgloss.NewStyle().Foreground(styles.GitAdded)
+	removeStyle := lipgloss.NewStyle().Foreground(styles.GitDeleted)
+	hunkStyle := lipgloss.NewStyle().Foreground(styles.DiffHunk)
+	headerStyle := lipgloss.NewStyle().Foreground(styles.GitModified)
 
 	var result strings.Builder
 	for i, line := range lines {
@@ -404,23 +647,28 @@ func HighlightDiff(diffText string, maxWidth int) string {
 
 // addLineNumbers prepends line numbers to each line of content
 func addLineNumbers(content string) string {
+	return addLineNumbersFrom(content, 1)
+}
+
+// addLineNumbersFrom adds line numbers starting at first
+func addLineNumbersFrom(content string, first int) string {
 	lines := strings.Split(content, "\n")
 	if len(lines) == 0 {
 		return content
 	}
 
-	// Calculate gutter width based on total lines
-	gutterWidth := len(fmt.Sprintf("%d", len(lines)))
+	// Calculate gutter width based on the last line number
+	gutterWidth := len(fmt.Sprintf("%d", first+len(lines)-1))
 	if gutterWidth < 4 {
 		gutterWidth = 4 // Minimum 4 chars for alignment
 	}
 
 	// Use lipgloss for consistent styling that won't be affected by syntax highlighting
-	gutterStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	gutterStyle := lipgloss.NewStyle().Foreground(styles.BorderInactive)
 
 	var result strings.Builder
 	for i, line := range lines {
-		lineNum := fmt.Sprintf("%*d", gutterWidth, i+1)
+		lineNum := fmt.Sprintf("%*d", gutterWidth, first+i)
 		// Render the gutter (number + separator) with lipgloss
 		gutter := gutterStyle.Render(lineNum + " │ ")
 		result.WriteString(gutter)
@@ -447,8 +695,12 @@ func humanSize(bytes int64) string {
 }
 
 // wrapLines wraps text at word boundaries to fit within maxWidth
+// A negative width (see noWrapWidth) leaves lines unwrapped.
 func wrapLines(content string, maxWidth int) string {
-	if maxWidth <= 0 {
+	if maxWidth < 0 {
+		return content
+	}
+	if maxWidth == 0 {
 		maxWidth = 80
 	}
 	// Leave buffer for padding and border
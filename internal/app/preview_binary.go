@@ -0,0 +1,182 @@
+package app
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/filetype"
+)
+
+const (
+	maxArchiveEntries = 500       // Entries listed before truncating
+	binarySniffSize   = 64 * 1024 // Bytes scanned for printable strings
+	binaryHexBytes    = 256       // Bytes shown in the hex dump
+	minStringLength   = 4         // Shortest run of printable bytes reported as a string
+	maxStrings        = 200       // Strings listed before truncating
+	pdfPreviewPages   = 3         // Pages extracted from PDFs
+)
+
+// renderNonText renders a preview for binary, archive and PDF files
+// Returns false for plain text files
+func renderNonText(filePath string, size int64) (string, bool) {
+	switch filetype.DetectKind(filePath) {
+	case filetype.KindArchive:
+		listing, err := renderArchiveListing(filePath)
+		if err != nil {
+			return fmt.Sprintf("Archive (%s)\n\nCould not read archive: %v\n\n%s",
+				humanSize(size), err, renderBinarySummary(filePath, size)), true
+		}
+		return listing, true
+	case filetype.KindPDF:
+		return renderPDFText(filePath, size), true
+	case filetype.KindBinary:
+		return renderBinarySummary(filePath, size), true
+	}
+	return "", false
+}
+
+// renderArchiveListing lists the entries of a zip or tar(.gz) archive
+func renderArchiveListing(filePath string) (string, error) {
+	type archiveEntry struct {
+		name string
+		size int64
+	}
+	var entries []archiveEntry
+	more := false
+
+	lower := strings.ToLower(filePath)
+	if strings.HasSuffix(lower, ".zip") || strings.HasSuffix(lower, ".jar") {
+		r, err := zip.OpenReader(filePath)
+		if err != nil {
+			return "", err
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if len(entries) == maxArchiveEntries {
+				more = true
+				break
+			}
+			entries = append(entries, archiveEntry{f.Name, int64(f.UncompressedSize64)})
+		}
+	} else {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		var reader io.Reader = f
+		if !strings.HasSuffix(lower, ".tar") {
+			gz, err := gzip.NewReader(f)
+			if err != nil {
+				return "", err
+			}
+			defer gz.Close()
+			reader = gz
+		}
+
+		tr := tar.NewReader(reader)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			if len(entries) == maxArchiveEntries {
+				more = true
+				break
+			}
+			entries = append(entries, archiveEntry{hdr.Name, hdr.Size})
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Archive: %d entries\n\n", len(entries))
+	for _, e := range entries {
+		if strings.HasSuffix(e.name, "/") {
+			fmt.Fprintf(&b, "%10s  %s\n", "", e.name)
+		} else {
+			fmt.Fprintf(&b, "%10s  %s\n", humanSize(e.size), e.name)
+		}
+	}
+	if more {
+		fmt.Fprintf(&b, "\n--- Listing truncated at %d entries ---\n", maxArchiveEntries)
+	}
+	return b.String(), nil
+}
+
+// renderBinarySummary shows a hex dump of the file header and the printable strings it contains
+func renderBinarySummary(filePath string, size int64) string {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	defer f.Close()
+
+	buf := make([]byte, binarySniffSize)
+	n, _ := io.ReadFull(f, buf)
+	buf = buf[:n]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Binary file (%s)\n\n", humanSize(size))
+
+	head := buf
+	if len(head) > binaryHexBytes {
+		head = head[:binaryHexBytes]
+	}
+	b.WriteString(hex.Dump(head))
+
+	strs := extractStrings(buf)
+	if len(strs) > 0 {
+		fmt.Fprintf(&b, "\nStrings (first %s):\n\n", humanSize(int64(len(buf))))
+		for i, s := range strs {
+			if i == maxStrings {
+				fmt.Fprintf(&b, "... %d more\n", len(strs)-maxStrings)
+				break
+			}
+			b.WriteString(s + "\n")
+		}
+	}
+	return b.String()
+}
+
+// extractStrings returns runs of printable ASCII, like strings(1)
+func extractStrings(data []byte) []string {
+	var result []string
+	start := -1
+	for i, c := range data {
+		printable := c >= 0x20 && c < 0x7f || c == '\t'
+		if printable && start < 0 {
+			start = i
+		} else if !printable && start >= 0 {
+			if i-start >= minStringLength {
+				result = append(result, string(data[start:i]))
+			}
+			start = -1
+		}
+	}
+	if start >= 0 && len(data)-start >= minStringLength {
+		result = append(result, string(data[start:]))
+	}
+	return result
+}
+
+// renderPDFText extracts text from the first pages of a PDF using pdftotext when available
+func renderPDFText(filePath string, size int64) string {
+	if _, err := exec.LookPath("pdftotext"); err == nil {
+		cmd := exec.Command("pdftotext", "-l", fmt.Sprint(pdfPreviewPages), "-layout", filePath, "-")
+		if out, err := cmd.Output(); err == nil && strings.TrimSpace(string(out)) != "" {
+			return fmt.Sprintf("PDF (%s) - text of first %d pages\n\n%s", humanSize(size), pdfPreviewPages, out)
+		}
+	}
+	return "PDF text preview requires pdftotext (poppler-utils)\n\n" + renderBinarySummary(filePath, size)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/chroma/v2/quick"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const blameAuthorWidth = 12 // Author names are cut to this width
+
+// blameCacheKey keys blame previews in the preview cache, next to the plain preview
+func blameCacheKey(path string) string {
+	return path + "\x00blame"
+}
+
+// blameColor colors a line's annotation by how recently it changed
+func blameColor(t time.Time) lipgloss.Color {
+	age := time.Since(t)
+	switch {
+	case age < 7*24*time.Hour:
+		return styles.SuccessBold
+	case age < 30*24*time.Hour:
+		return styles.Success
+	case age < 180*24*time.Hour:
+		return styles.Info
+	case age < 365*24*time.Hour:
+		return styles.TextMuted
+	}
+	return styles.TextFaint
+}
+
+// LoadBlame renders a file with the commit, author and age of every line in front of
+// the usual line number gutter
+func LoadBlame(repoRoot, filePath, fileName string, previewWidth int) FileLoadedMsg {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return FileLoadedMsg{Path: filePath, Blame: true, Content: "Error: " + err.Error()}
+	}
+	rel, err := filepath.Rel(repoRoot, filePath)
+	if err != nil {
+		return FileLoadedMsg{Path: filePath, Blame: true, Content: "Error: " + err.Error()}
+	}
+	lines, err := git.Blame(repoRoot, filepath.ToSlash(rel))
+	if err != nil {
+		return FileLoadedMsg{Path: filePath, Blame: true, Content: "No blame available (file not tracked by git?)"}
+	}
+
+	text := make([]string, len(lines))
+	for i, l := range lines {
+		text[i] = strings.ReplaceAll(l.Text, "\t", "    ")
+	}
+	hlLines := text
+	var buf bytes.Buffer
+	if err := quick.Highlight(&buf, strings.Join(text, "\n"), fileName, "terminal256", styles.Current().ChromaStyle); err == nil {
+		if split := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n"); len(split) == len(text) {
+			hlLines = split
+		}
+	}
+
+	gutterWidth := len(fmt.Sprintf("%d", len(lines)))
+	if gutterWidth < 4 {
+		gutterWidth = 4
+	}
+	annotationWidth := 7 + 1 + blameAuthorWidth + 1 + 8 + 1
+	wrapWidth := previewWidth - annotationWidth - (gutterWidth + 3) - 4
+	if previewWidth == noWrapWidth {
+		wrapWidth = 0 // wordwrap treats 0 as no limit
+	} else if wrapWidth < 20 {
+		wrapWidth = 20
+	}
+	gutterStyle := lipgloss.NewStyle().Foreground(styles.BorderInactive)
+
+	var out []string
+	for i, l := range lines {
+		var annotation string
+		if strings.Trim(l.Hash, "0") == "" {
+			annotation = lipgloss.NewStyle().Foreground(styles.Warning).Render(fmt.Sprintf("%-*s", annotationWidth-1, "not committed"))
+		} else {
+			author := ansi.Truncate(l.Author, blameAuthorWidth, "…")
+			annotation = lipgloss.NewStyle().Foreground(blameColor(l.Time)).Render(
+				fmt.Sprintf("%s %s %-8s", l.Hash, padRight(author, blameAuthorWidth), formatAge(l.Time)))
+		}
+		// Only the first line of a run from the same commit is annotated
+		if i > 0 && lines[i-1].Hash == l.Hash {
+			annotation = strings.Repeat(" ", annotationWidth-1)
+		}
+
+		for seg, part := range strings.Split(wordwrap.String(hlLines[i], wrapWidth), "\n") {
+			num := fmt.Sprintf("%*d", gutterWidth, i+1)
+			prefix := annotation
+			if seg > 0 {
+				num = strings.Repeat(" ", gutterWidth)
+				prefix = strings.Repeat(" ", annotationWidth-1)
+			}
+			out = append(out, prefix+" "+gutterStyle.Render(num+" │ ")+part)
+		}
+	}
+
+	return FileLoadedMsg{Path: filePath, Blame: true, Content: strings.Join(out, "\n"), ModTime: info.ModTime()}
+}
+
+// toggleBlame switches the file preview between plain and blame-annotated
+func (m Model) toggleBlame() (tea.Model, tea.Cmd) {
+	if !m.isGitRepo {
+		return m, nil
+	}
+	m.previewBlame = !m.previewBlame
+	m.statusMessage = "Blame off"
+	if m.previewBlame {
+		m.statusMessage = "Blame on"
+	}
+	m.statusMessageTime = time.Now()
+	var cmd tea.Cmd
+	if !m.gitStatusMode {
+		m, cmd = m.UpdatePreview()
+	}
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// LoadWorkingDiff renders the changes of a file since the last commit, staged and
+// unstaged together, with the same highlighting as git status diffs
+func LoadWorkingDiff(repoRoot, filePath string, previewWidth int) FileLoadedMsg {
+	rel, err := filepath.Rel(repoRoot, filePath)
+	if err != nil {
+		return FileLoadedMsg{Path: filePath, Content: "Error: " + err.Error()}
+	}
+	diffText, err := git.LoadWorkingDiff(repoRoot, filepath.ToSlash(rel), fullDiffContext)
+	switch {
+	case err != nil:
+		return FileLoadedMsg{Path: filePath, Content: "No diff available (file not tracked by git?)"}
+	case diffText == "":
+		return FileLoadedMsg{Path: filePath, Content: "No changes since the last commit"}
+	}
+	return FileLoadedMsg{Path: filePath, Content: HighlightDiff(diffText, previewWidth)}
+}
+
+// toggleFileDiff switches the file preview between its content and its changes
+// since the last commit, without leaving the tree for git status
+func (m Model) toggleFileDiff() (tea.Model, tea.Cmd) {
+	if !m.isGitRepo {
+		return m, nil
+	}
+	m.previewDiff = !m.previewDiff
+	m.statusMessage = "Showing file content"
+	if m.previewDiff {
+		m.statusMessage = "Showing changes since the last commit"
+	}
+	m.statusMessageTime = time.Now()
+	var cmd tea.Cmd
+	if !m.gitStatusMode && !m.fileHistoryMode {
+		m, cmd = m.UpdatePreview()
+	}
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
@@ -0,0 +1,228 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+const (
+	maxSummaryFiles    = 50000 // Files walked before the totals are reported as partial
+	maxSummaryChildren = 30    // Immediate children listed
+	maxSummaryRecent   = 8     // Recently modified files listed
+	maxSummaryLangs    = 8     // Languages listed in the breakdown
+)
+
+// languageNames maps file extensions to the language shown in directory summaries
+var languageNames = map[string]string{
+	".go": "Go", ".ts": "TypeScript", ".tsx": "TypeScript", ".js": "JavaScript",
+	".jsx": "JavaScript", ".mjs": "JavaScript", ".cjs": "JavaScript", ".py": "Python",
+	".rs": "Rust", ".java": "Java", ".kt": "Kotlin", ".rb": "Ruby", ".php": "PHP",
+	".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++", ".hpp": "C++", ".cs": "C#",
+	".swift": "Swift", ".scala": "Scala", ".lua": "Lua", ".sh": "Shell", ".bash": "Shell",
+	".zsh": "Shell", ".md": "Markdown", ".json": "JSON", ".yaml": "YAML", ".yml": "YAML",
+	".toml": "TOML", ".html": "HTML", ".css": "CSS", ".scss": "CSS", ".sql": "SQL",
+	".proto": "Protobuf", ".vue": "Vue", ".svelte": "Svelte",
+}
+
+// dirFile is a file found while summarizing a directory
+type dirFile struct {
+	rel     string
+	size    int64
+	modTime time.Time
+}
+
+// LoadDirectorySummary builds the preview shown when a directory is selected:
+// children, totals, language breakdown, recent files and referencing context docs
+func LoadDirectorySummary(dirPath, rootPath string, showDotfiles bool, docs []groups.ContextDoc) FileLoadedMsg {
+	children, err := os.ReadDir(dirPath)
+	if err != nil {
+		return FileLoadedMsg{Path: dirPath, Content: "Error: " + err.Error()}
+	}
+
+	// Walk with the same skip rules as the file index (a linked directory from its target)
+	walkPath := dirPath
+	if real, err := filepath.EvalSymlinks(dirPath); err == nil {
+		walkPath = real
+	}
+	var files []dirFile
+	partial := false
+	filepath.Walk(walkPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if path != walkPath && skipIndexedName(name, showDotfiles) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if len(files) == maxSummaryFiles {
+			partial = true
+			return filepath.SkipAll
+		}
+		rel, _ := filepath.Rel(walkPath, path)
+		files = append(files, dirFile{rel: rel, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+
+	var b strings.Builder
+	relDir, _ := filepath.Rel(rootPath, dirPath)
+	b.WriteString(styles.Title.Render("📁 "+filepath.Base(dirPath)+"/") + "\n")
+	if relDir != "." {
+		b.WriteString(styles.Faint.Render(relDir) + "\n")
+	}
+	b.WriteString("\n")
+
+	// Totals
+	var totalSize int64
+	for _, f := range files {
+		totalSize += f.size
+	}
+	total := fmt.Sprintf("%d files · %s", len(files), humanSize(totalSize))
+	if partial {
+		total = fmt.Sprintf("%d+ files · %s+ (stopped counting)", len(files), humanSize(totalSize))
+	}
+	b.WriteString(styles.SectionHeader.Render("Total") + "  " + total + "\n\n")
+
+	// Immediate children, folders first
+	var dirs, plain []os.DirEntry
+	for _, c := range children {
+		if skipIndexedName(c.Name(), showDotfiles) {
+			continue
+		}
+		if c.IsDir() {
+			dirs = append(dirs, c)
+		} else {
+			plain = append(plain, c)
+		}
+	}
+	b.WriteString(styles.SectionHeader.Render(fmt.Sprintf("Contents (%d folders, %d files)", len(dirs), len(plain))) + "\n")
+	listed := 0
+	for _, c := range append(dirs, plain...) {
+		if listed == maxSummaryChildren {
+			b.WriteString(styles.Faint.Render(fmt.Sprintf("  … %d more", len(dirs)+len(plain)-listed)) + "\n")
+			break
+		}
+		if c.IsDir() {
+			b.WriteString(fmt.Sprintf("  %10s  %s/\n", "", c.Name()))
+		} else if info, err := c.Info(); err == nil {
+			b.WriteString(fmt.Sprintf("  %10s  %s\n", humanSize(info.Size()), c.Name()))
+		}
+		listed++
+	}
+	b.WriteString("\n")
+
+	// Language breakdown by size
+	if len(files) > 0 {
+		langSize := make(map[string]int64)
+		langCount := make(map[string]int)
+		for _, f := range files {
+			lang, ok := languageNames[strings.ToLower(filepath.Ext(f.rel))]
+			if !ok {
+				lang = "Other"
+			}
+			langSize[lang] += f.size
+			langCount[lang]++
+		}
+		langs := make([]string, 0, len(langSize))
+		for lang := range langSize {
+			langs = append(langs, lang)
+		}
+		sort.Slice(langs, func(i, j int) bool {
+			if langSize[langs[i]] != langSize[langs[j]] {
+				return langSize[langs[i]] > langSize[langs[j]]
+			}
+			return langs[i] < langs[j]
+		})
+		if len(langs) > maxSummaryLangs {
+			langs = langs[:maxSummaryLangs]
+		}
+
+		b.WriteString(styles.SectionHeader.Render("Languages") + "\n")
+		for _, lang := range langs {
+			pct := 0.0
+			if totalSize > 0 {
+				pct = float64(langSize[lang]) * 100 / float64(totalSize)
+			}
+			bar := strings.Repeat("█", int(pct/5+0.5))
+			b.WriteString(fmt.Sprintf("  %-11s %5.1f%%  %-20s %s\n", lang, pct, bar,
+				styles.Faint.Render(fmt.Sprintf("%d files", langCount[lang]))))
+		}
+		b.WriteString("\n")
+
+		// Most recently modified
+		sort.Slice(files, func(i, j int) bool { return files[i].modTime.After(files[j].modTime) })
+		b.WriteString(styles.SectionHeader.Render("Recently modified") + "\n")
+		for i, f := range files {
+			if i == maxSummaryRecent {
+				break
+			}
+			b.WriteString(fmt.Sprintf("  %-10s  %s\n", formatAge(f.modTime), f.rel))
+		}
+		b.WriteString("\n")
+	}
+
+	// Context docs with key files under this directory
+	prefix := filepath.ToSlash(relDir) + "/"
+	var referencing []string
+	for _, d := range docs {
+		count := 0
+		for _, ref := range d.KeyFileRefs(rootPath) {
+			ref = filepath.ToSlash(ref)
+			if (relDir == "." && !filepath.IsAbs(ref)) || strings.HasPrefix(ref, prefix) {
+				count++
+			}
+		}
+		if count > 0 {
+			referencing = append(referencing, fmt.Sprintf("  %s %s", d.Name,
+				styles.Faint.Render(fmt.Sprintf("(%s, %d key files)", d.FilePath, count))))
+		}
+	}
+	b.WriteString(styles.SectionHeader.Render("Context docs") + "\n")
+	if len(referencing) == 0 {
+		b.WriteString(styles.Faint.Render("  No context docs reference files here") + "\n")
+	} else {
+		b.WriteString(strings.Join(referencing, "\n") + "\n")
+	}
+
+	return FileLoadedMsg{Path: dirPath, Content: b.String()}
+}
+
+// skipIndexedName mirrors the file index rules: .git and package dirs are always
+// skipped, other dotfiles unless they are shown
+func skipIndexedName(name string, showDotfiles bool) bool {
+	switch name {
+	case ".git", "node_modules", "vendor", "__pycache__":
+		return true
+	case ".context-docs.md":
+		return false
+	}
+	return strings.HasPrefix(name, ".") && !showDotfiles
+}
+
+// formatAge formats a modification time relative to now
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,203 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// maxStreamChunks is how many chunks of a big file are kept loaded at once
+// Chunks scrolled far out of view are dropped and read again when scrolled back to.
+const maxStreamChunks = 4
+
+// PreviewChunkMsg is sent when another chunk of a streamed file has been rendered
+type PreviewChunkMsg struct {
+	StreamID int64
+	Index    int // Chunk number within the file
+	Content  string
+	Next     int64 // Byte offset of the following chunk
+	EOF      bool
+}
+
+// chunkStart locates a chunk in the file and in the preview's line numbering
+type chunkStart struct {
+	offset int64
+	line   int
+}
+
+// previewStream is a big file previewed in chunks of maxPreviewLines lines
+type previewStream struct {
+	id       int64
+	path     string
+	fileName string
+	width    int
+	starts   []chunkStart // Every chunk located so far
+	first    int          // Index of the first loaded chunk
+	chunks   []string     // Rendered loaded chunks
+	eof      bool         // The last loaded chunk ends the file
+	loading  bool
+}
+
+// readPreviewChunk reads up to maxPreviewLines lines (and at most maxPreviewSize bytes)
+// starting at offset. Returns the text, where the next chunk starts and whether the file ends.
+func readPreviewChunk(filePath string, offset int64) (string, int64, bool, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", 0, false, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", 0, false, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", 0, false, err
+	}
+
+	r := bufio.NewReader(io.LimitReader(f, maxPreviewSize))
+	var sb strings.Builder
+	for lines := 0; lines < maxPreviewLines; lines++ {
+		line, err := r.ReadString('\n')
+		sb.WriteString(line)
+		if err != nil {
+			break
+		}
+	}
+	next := offset + int64(sb.Len())
+	return strings.TrimSuffix(sb.String(), "\n"), next, next >= info.Size(), nil
+}
+
+// startStream continues a preview whose file goes on past its first chunk
+func (m *Model) startStream(path, fileName, content string, more int64, moreLine int) {
+	m.previewStream = nil
+	if more == 0 {
+		return
+	}
+	m.previewStream = &previewStream{
+		id:       streamIDs.Add(1),
+		path:     path,
+		fileName: fileName,
+		width:    m.previewRenderWidth(),
+		starts:   []chunkStart{{0, 1}, {more, moreLine}},
+		chunks:   []string{content},
+	}
+	m.showStream(m.preview.YOffset)
+}
+
+// streamIDs numbers streams so chunks of a replaced stream are ignored
+var streamIDs atomic.Int64
+
+// content joins the loaded chunks with markers for what is not loaded
+func (s *previewStream) content() string {
+	var parts []string
+	if s.first > 0 {
+		parts = append(parts, styles.Faint.Render(fmt.Sprintf("  ↑ lines before %d unloaded, scroll up to reload", s.starts[s.first].line)))
+	}
+	parts = append(parts, s.chunks...)
+	if !s.eof {
+		parts = append(parts, styles.Faint.Render("  ↓ more below, scroll to load"))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// linesBefore counts the preview lines above a loaded chunk
+func (s *previewStream) linesBefore(index int) int {
+	n := 0
+	if s.first > 0 {
+		n = 1 // Unloaded marker
+	}
+	for i := s.first; i < index; i++ {
+		n += strings.Count(s.chunks[i-s.first], "\n") + 1
+	}
+	return n
+}
+
+// showStream puts the loaded chunks in the preview at the given scroll position
+func (m *Model) showStream(yOffset int) {
+	content := m.previewStream.content()
+	m.preview.SetContent(content)
+	m.previewLines = strings.Split(content, "\n")
+	m.preview.SetYOffset(yOffset)
+}
+
+// streamPreview loads the next or previous chunk when the preview scrolls near an edge
+func (m Model) streamPreview() (Model, tea.Cmd) {
+	s := m.previewStream
+	if s == nil || s.loading || s.path != m.previewPath || m.gitStatusMode || m.fileHistoryMode {
+		return m, nil
+	}
+	margin := m.preview.Height
+	index := -1
+	switch {
+	case !s.eof && m.preview.YOffset+m.preview.Height >= m.preview.TotalLineCount()-margin:
+		index = s.first + len(s.chunks)
+	case s.first > 0 && m.preview.YOffset < margin:
+		index = s.first - 1
+	}
+	if index < 0 || index >= len(s.starts) {
+		return m, nil
+	}
+
+	s.loading = true
+	id, start, path, fileName, width := s.id, s.starts[index], s.path, s.fileName, s.width
+	return m, func() tea.Msg {
+		text, next, eof, err := readPreviewChunk(path, start.offset)
+		if err != nil {
+			text, eof = "Error: "+err.Error(), true
+		}
+		return PreviewChunkMsg{
+			StreamID: id,
+			Index:    index,
+			Content:  highlightCodeFrom(text, fileName, width, start.line),
+			Next:     next,
+			EOF:      eof,
+		}
+	}
+}
+
+// handlePreviewChunk adds a chunk to the stream, dropping the farthest one when too many
+// are loaded, and keeps the visible lines in place
+func (m Model) handlePreviewChunk(msg PreviewChunkMsg) (tea.Model, tea.Cmd) {
+	s := m.previewStream
+	if s == nil || s.id != msg.StreamID {
+		return m, nil
+	}
+	s.loading = false
+	last := s.first + len(s.chunks) - 1
+
+	var anchor, before int
+	switch msg.Index {
+	case last + 1:
+		anchor = last
+		before = s.linesBefore(anchor)
+		s.chunks = append(s.chunks, msg.Content)
+		s.eof = msg.EOF
+		if len(s.starts) == msg.Index+1 && !msg.EOF {
+			s.starts = append(s.starts, chunkStart{msg.Next, s.starts[msg.Index].line + strings.Count(msg.Content, "\n") + 1})
+		}
+		if len(s.chunks) > maxStreamChunks {
+			s.chunks = s.chunks[1:]
+			s.first++
+		}
+	case s.first - 1:
+		anchor = s.first
+		before = s.linesBefore(anchor)
+		s.chunks = append([]string{msg.Content}, s.chunks...)
+		s.first--
+		if len(s.chunks) > maxStreamChunks {
+			s.chunks = s.chunks[:len(s.chunks)-1]
+			s.eof = false
+		}
+	default:
+		return m, nil
+	}
+
+	m.showStream(m.preview.YOffset + s.linesBefore(anchor) - before)
+	return m, nil
+}
@@ -0,0 +1,344 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/quick"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// structuredDoc is a pretty-printed JSON/YAML preview with foldable nodes
+type structuredDoc struct {
+	fileName string
+	isJSON   bool
+	lines    []string     // Pretty-printed source
+	ends     []int        // Last line of the node each line opens (-1 if none)
+	depths   []int        // Nesting depth of each line
+	paths    []string     // Key path of each line, e.g. spec.containers[0].image
+	folded   map[int]bool // Opener lines currently collapsed
+	rows     []int        // Rendered row -> source line (set by render)
+}
+
+// isStructuredFile returns true for files previewed as foldable JSON/YAML
+func isStructuredFile(fileName string) bool {
+	lower := strings.ToLower(fileName)
+	return strings.HasSuffix(lower, ".json") ||
+		strings.HasSuffix(lower, ".yaml") ||
+		strings.HasSuffix(lower, ".yml")
+}
+
+// newStructuredDoc pretty-prints text and indexes its nodes
+// Invalid JSON is shown as-is; YAML is already indentation-structured.
+func newStructuredDoc(text, fileName string) *structuredDoc {
+	d := &structuredDoc{
+		fileName: fileName,
+		isJSON:   strings.HasSuffix(strings.ToLower(fileName), ".json"),
+		folded:   make(map[int]bool),
+	}
+
+	if d.isJSON {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(text), "", "  "); err == nil {
+			text = buf.String()
+		}
+	}
+	text = strings.ReplaceAll(text, "\t", "  ")
+	d.lines = strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range d.lines {
+		d.lines[i] = strings.TrimRight(line, " \r")
+	}
+
+	d.indexNodes()
+	d.indexPaths()
+	return d
+}
+
+// structuredIndent returns a line's indentation, counting a "- " list marker as one
+// deeper so YAML list items nest under a key at the same column
+func structuredIndent(line string) int {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := len(line) - len(trimmed)
+	if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+		indent++
+	}
+	return indent
+}
+
+// indexNodes finds the foldable range and depth of every line from indentation
+func (d *structuredDoc) indexNodes() {
+	d.ends = make([]int, len(d.lines))
+	d.depths = make([]int, len(d.lines))
+	for i := range d.ends {
+		d.ends[i] = -1
+	}
+
+	var stack []int // Open lines, innermost last
+	lastContent := -1
+	for i, line := range d.lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := structuredIndent(line)
+		for len(stack) > 0 && structuredIndent(d.lines[stack[len(stack)-1]]) >= indent {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if lastContent > top {
+				d.ends[top] = lastContent
+			}
+		}
+		d.depths[i] = len(stack)
+		stack = append(stack, i)
+		lastContent = i
+	}
+	for _, top := range stack {
+		if lastContent > top {
+			d.ends[top] = lastContent
+		}
+	}
+}
+
+// isClosingBracket reports whether a JSON line only closes an object or array
+func isClosingBracket(line string) bool {
+	switch strings.TrimSpace(line) {
+	case "}", "]", "},", "],":
+		return true
+	}
+	return false
+}
+
+// structuredKey extracts the key a line defines, and whether it is a list item
+func (d *structuredDoc) structuredKey(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if d.isJSON {
+		if !strings.HasPrefix(trimmed, `"`) {
+			return "", true
+		}
+		idx := strings.Index(trimmed, `":`)
+		if idx < 0 {
+			return "", true // String element of an array
+		}
+		var key string
+		if err := json.Unmarshal([]byte(trimmed[:idx+1]), &key); err != nil {
+			key = strings.Trim(trimmed[:idx+1], `"`)
+		}
+		return key, false
+	}
+
+	item := false
+	if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+		item = true
+		trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+	}
+	if strings.HasPrefix(trimmed, "#") {
+		return "", item
+	}
+	idx := strings.Index(trimmed, ": ")
+	if idx < 0 && strings.HasSuffix(trimmed, ":") {
+		idx = len(trimmed) - 1
+	}
+	if idx <= 0 {
+		return "", item
+	}
+	return strings.Trim(trimmed[:idx], `"'`), item
+}
+
+// indexPaths computes the key path of every line
+func (d *structuredDoc) indexPaths() {
+	type level struct {
+		indent int
+		path   string
+		items  int
+	}
+	stack := []level{{indent: -1}}
+	d.paths = make([]string, len(d.lines))
+
+	for i, line := range d.lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			d.paths[i] = stack[len(stack)-1].path
+			continue
+		}
+
+		indent := structuredIndent(line)
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if d.isJSON && isClosingBracket(line) {
+			d.paths[i] = stack[len(stack)-1].path
+			continue
+		}
+		parent := &stack[len(stack)-1]
+
+		key, item := d.structuredKey(line)
+		path := parent.path
+		if item && !(d.isJSON && len(stack) == 1) {
+			path += fmt.Sprintf("[%d]", parent.items)
+			parent.items++
+		}
+		if item && key != "" {
+			// "- key: value" opens the item and its first key
+			stack = append(stack, level{indent: indent, path: path})
+			indent++
+		}
+		if key != "" {
+			if path != "" {
+				path += "."
+			}
+			path += key
+		}
+		stack = append(stack, level{indent: indent, path: path})
+		d.paths[i] = path
+	}
+}
+
+// foldTarget returns the opener line of the node containing line
+func (d *structuredDoc) foldTarget(line int) int {
+	for i := line; i >= 0; i-- {
+		if d.ends[i] > i && d.ends[i] >= line {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleFold collapses or expands the node containing line; returns the opener line
+func (d *structuredDoc) toggleFold(line int) int {
+	if d.folded[line] {
+		delete(d.folded, line)
+		return line
+	}
+	target := d.foldTarget(line)
+	if target < 0 {
+		return line
+	}
+	if d.folded[target] {
+		delete(d.folded, target)
+	} else {
+		d.folded[target] = true
+	}
+	return target
+}
+
+// toggleFoldAll expands everything if anything is folded, otherwise folds the top-level nodes
+func (d *structuredDoc) toggleFoldAll() {
+	if len(d.folded) > 0 {
+		d.folded = make(map[int]bool)
+		return
+	}
+
+	// Fold the top level, looking inside a single root wrapper (e.g. a JSON object)
+	counts := make(map[int]int)
+	for i, end := range d.ends {
+		if end > i {
+			counts[d.depths[i]]++
+		}
+	}
+	depth := 0
+	if counts[0] == 1 && counts[1] > 0 {
+		depth = 1
+	}
+	for i, end := range d.ends {
+		if end > i && d.depths[i] == depth {
+			d.folded[i] = true
+		}
+	}
+}
+
+// sourceLine maps a rendered row back to its source line
+func (d *structuredDoc) sourceLine(row int) int {
+	if len(d.rows) == 0 {
+		return 0
+	}
+	if row < 0 {
+		row = 0
+	}
+	if row >= len(d.rows) {
+		row = len(d.rows) - 1
+	}
+	return d.rows[row]
+}
+
+// rowOf returns the first rendered row of a source line
+func (d *structuredDoc) rowOf(line int) int {
+	for row, src := range d.rows {
+		if src >= line {
+			return row
+		}
+	}
+	return 0
+}
+
+// breadcrumb returns the key path at a rendered row
+func (d *structuredDoc) breadcrumb(row int) string {
+	if len(d.paths) == 0 {
+		return ""
+	}
+	return d.paths[d.sourceLine(row)]
+}
+
+// render highlights the visible lines with folds applied and adds a source line gutter
+func (d *structuredDoc) render(maxWidth int) string {
+	var visible []int
+	for i := 0; i < len(d.lines); i++ {
+		visible = append(visible, i)
+		if d.folded[i] && d.ends[i] > i {
+			i = d.ends[i]
+		}
+	}
+
+	text := make([]string, len(visible))
+	for n, i := range visible {
+		text[n] = d.lines[i]
+	}
+	highlighted := strings.Join(text, "\n")
+	var buf bytes.Buffer
+	if err := quick.Highlight(&buf, highlighted, d.fileName, "terminal256", styles.Current().ChromaStyle); err == nil {
+		highlighted = buf.String()
+	}
+	hlLines := strings.Split(strings.TrimRight(highlighted, "\n"), "\n")
+	if len(hlLines) != len(visible) {
+		hlLines = text // Highlighter changed the line structure, fall back to plain
+	}
+
+	gutterWidth := len(fmt.Sprintf("%d", len(d.lines)))
+	if gutterWidth < 4 {
+		gutterWidth = 4
+	}
+	wrapWidth := maxWidth - (gutterWidth + 3) - 4
+	if maxWidth == noWrapWidth {
+		wrapWidth = 0 // wordwrap treats 0 as no limit
+	} else if wrapWidth <= 0 {
+		wrapWidth = 76
+	}
+	gutterStyle := lipgloss.NewStyle().Foreground(styles.BorderInactive)
+
+	var out []string
+	d.rows = d.rows[:0]
+	for n, i := range visible {
+		line := hlLines[n]
+		if d.folded[i] && d.ends[i] > i {
+			line += styles.Faint.Render(fmt.Sprintf(" ▸ %d lines", d.ends[i]-i))
+		}
+		for seg, part := range strings.Split(wordwrap.String(line, wrapWidth), "\n") {
+			num := fmt.Sprintf("%*d", gutterWidth, i+1)
+			if seg > 0 {
+				num = strings.Repeat(" ", gutterWidth)
+			}
+			out = append(out, gutterStyle.Render(num+" │ ")+part)
+			d.rows = append(d.rows, i)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// refreshStructuredPreview re-renders the structured preview after its folds change
+func (m *Model) refreshStructuredPreview() {
+	content := m.structured.render(m.previewRenderWidth())
+	m.preview.SetContent(content)
+	m.previewLines = strings.Split(content, "\n")
+}
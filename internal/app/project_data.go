@@ -0,0 +1,59 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// projectDataDir holds contexTUI's files in a project (file index, visits, basket, notes)
+const projectDataDir = ".contextui"
+
+// keepsProjectData returns true if caches like the file index and visits may be
+// written: once the project has a .contextui/ directory (contexTUI init, or a deliberate
+// change like a note), or with implicitWrites, so just opening a project changes nothing
+func (m Model) keepsProjectData() bool {
+	if m.readOnly {
+		return false
+	}
+	if m.config.ImplicitWrites {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(m.rootPath, projectDataDir))
+	return err == nil && info.IsDir()
+}
+
+// updateOutdatedTags previews the updates bringing the structure tags of docs found
+// out of date in line with what they are missing, removing them once complete
+func (m Model) updateOutdatedTags() (tea.Model, tea.Cmd) {
+	if len(m.outdatedTags) == 0 {
+		m.statusMessage = "Structure tags are up to date"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	if m.docRegistry == nil {
+		return m, nil
+	}
+
+	outdated := make(map[string]bool)
+	for _, path := range m.outdatedTags {
+		outdated[path] = true
+	}
+	var edits []docEdit
+	for _, doc := range m.docRegistry.Docs {
+		if !outdated[doc.FilePath] {
+			continue
+		}
+		if edit, ok := structureTagEdit(m.rootPath, docEditUpdate, doc); ok {
+			edits = append(edits, edit)
+		}
+	}
+	m.outdatedTags = nil
+	reviewCmd := m.reviewDocEdits(edits)
+	return m, tea.Batch(ClearStatusAfter(5*time.Second), reviewCmd)
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// openProjects shows the recent projects overlay with the current project selected
+func (m Model) openProjects() (tea.Model, tea.Cmd) {
+	m.clearAllOverlays()
+	m.showingProjects = true
+	m.projects = config.RecentProjects()
+	m.projectCursor = 0
+	for i, p := range m.projects {
+		if p == m.rootPath {
+			m.projectCursor = i
+		}
+	}
+	return m, nil
+}
+
+// updateProjects handles input in the recent projects overlay
+func (m Model) updateProjects(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q", "P":
+		m.showingProjects = false
+
+	case "j", "down":
+		if m.projectCursor < len(m.projects)-1 {
+			m.projectCursor++
+		}
+
+	case "k", "up":
+		if m.projectCursor > 0 {
+			m.projectCursor--
+		}
+
+	case "d", "x":
+		// Forget the project (the current one stays listed)
+		if m.projectCursor < len(m.projects) && m.projects[m.projectCursor] != m.rootPath {
+			debuglog.Report("save recent projects", config.RemoveRecentProject(m.projects[m.projectCursor]))
+			m.projects = config.RecentProjects()
+			if m.projectCursor >= len(m.projects) && m.projectCursor > 0 {
+				m.projectCursor--
+			}
+		}
+
+	case "enter":
+		if m.projectCursor < len(m.projects) {
+			return m.switchProject(m.projects[m.projectCursor])
+		}
+	}
+	return m, nil
+}
+
+// switchProject replaces the model with a fresh one rooted at path
+// The old watcher is closed; load results still in flight for the old root are ignored.
+func (m Model) switchProject(path string) (tea.Model, tea.Cmd) {
+	m.showingProjects = false
+	if path == m.rootPath {
+		return m, nil
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		debuglog.Report("save recent projects", config.RemoveRecentProject(path))
+		m.statusMessage = "Project no longer exists: " + path
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	if m.watcher != nil {
+		m.watcher.Close()
+	}
+	debuglog.Report("save recent projects", config.AddRecentProject(path))
+
+	opts := m.options
+	opts.Select = ""
+	opts.Roots = nil // Extra roots belong to the command line's project
+	next := newModel(path, opts, m.highlighter)
+	next.width, next.height = m.width, m.height
+	next.statusMessage = "Switched to " + filepath.Base(path)
+	next.statusMessageTime = time.Now()
+	width, height := m.width, m.height
+	return next, tea.Batch(
+		next.startLoading(),
+		func() tea.Msg { return tea.WindowSizeMsg{Width: width, Height: height} },
+		ClearStatusAfter(3*time.Second),
+	)
+}
+
+// abbreviateHome shortens a path under the home directory to ~/...
+func abbreviateHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// renderProjectsOverlay renders the recently opened projects
+func (m Model) renderProjectsOverlay(background string) string {
+	boxWidth := m.overlayWidth(72)
+	maxVisible := m.height - 14
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Recent Projects"))
+	lines = append(lines, "")
+
+	if len(m.projects) == 0 {
+		lines = append(lines, styles.Muted.Render("No recent projects yet."))
+	} else {
+		start := 0
+		if m.projectCursor >= maxVisible {
+			start = m.projectCursor - maxVisible + 1
+		}
+		end := min(start+maxVisible, len(m.projects))
+
+		for i := start; i < end; i++ {
+			p := m.projects[i]
+			marker := "  "
+			if p == m.rootPath {
+				marker = "● "
+			}
+			label := marker + filepath.Base(p) + "  " + abbreviateHome(p)
+			if _, err := os.Stat(p); err != nil {
+				label += " (missing)"
+			}
+			label = ansi.Truncate(label, boxWidth-8, "…")
+			if i == m.projectCursor {
+				lines = append(lines, styles.Selected.Render(" "+label+" "))
+			} else {
+				lines = append(lines, " "+styles.Normal.Render(label))
+			}
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] switch  [d] forget  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
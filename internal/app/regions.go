@@ -0,0 +1,254 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/clipboard"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// Region is a named line range marked in a preview, kept for the session
+type Region struct {
+	Name    string // Mark name (a-z)
+	Path    string // File path relative to root
+	Start   int    // First source line (1-based)
+	End     int    // Last source line (1-based)
+	Content string // Text of the range when it was marked
+}
+
+// Ref returns the region as a file#Lx-Ly reference
+func (r Region) Ref() string {
+	return lineRef(r.Path, r.Start, r.End)
+}
+
+// lineRef formats a path and source line range as file#Lx-Ly (file#Lx for one line)
+func lineRef(path string, start, end int) string {
+	if start == end {
+		return fmt.Sprintf("%s#L%d", path, start)
+	}
+	return fmt.Sprintf("%s#L%d-L%d", path, start, end)
+}
+
+// sourceLineNumber reads the line number from a preview line's gutter
+// Returns 0 for lines without one (wrapped continuations, rendered markdown)
+func sourceLineNumber(line string) int {
+	clean := ansi.Strip(line)
+	idx := strings.Index(clean, "│")
+	if idx == -1 {
+		return 0
+	}
+	// The number is the last field before the separator (blame annotations come first)
+	fields := strings.Fields(clean[:idx])
+	if len(fields) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// selectionSource maps the copy-mode selection to preview rows and source lines
+// Returns ok=false when nothing is selected.
+func (m Model) selectionSource() (start, end, startLine, endLine int, ok bool) {
+	if m.selectStart < 0 || m.selectEnd < 0 || m.previewPath == "" || len(m.previewLines) == 0 {
+		return 0, 0, 0, 0, false
+	}
+
+	start, end = m.selectStart, m.selectEnd
+	if start > end {
+		start, end = end, start
+	}
+	if end >= len(m.previewLines) {
+		end = len(m.previewLines) - 1
+	}
+	if start > end {
+		return 0, 0, 0, 0, false
+	}
+
+	// Prefer the gutter's line numbers so wrapped lines don't skew the range
+	startLine, endLine = start+1, end+1
+	if n := sourceLineNumber(m.previewLines[start]); n > 0 {
+		startLine = n
+		for i := end; i >= start; i-- {
+			if n := sourceLineNumber(m.previewLines[i]); n > 0 {
+				endLine = n
+				break
+			}
+		}
+	}
+	return start, end, startLine, endLine, true
+}
+
+// previewRelPath returns the previewed file relative to the root
+func (m Model) previewRelPath() string {
+	relPath, err := filepath.Rel(m.rootPath, m.previewPath)
+	if err != nil {
+		return m.previewPath
+	}
+	return relPath
+}
+
+// copySelectionRef copies the selection as an @file#Lx-Ly reference instead of its text
+func (m Model) copySelectionRef() (tea.Model, tea.Cmd) {
+	_, _, startLine, endLine, ok := m.selectionSource()
+	if !ok {
+		m.statusMessage = "Select lines first"
+	} else {
+		ref := "@" + lineRef(m.previewRelPath(), startLine, endLine)
+		if err := m.copyText("ref", ref); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.statusMessage = "Copied " + ref
+		}
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// markRegion stores the current copy-mode selection as region name
+func (m Model) markRegion(name string) (tea.Model, tea.Cmd) {
+	start, end, startLine, endLine, ok := m.selectionSource()
+	if !ok {
+		m.statusMessage = "Select lines first"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	relPath := m.previewRelPath()
+	region := Region{
+		Name:    name,
+		Path:    relPath,
+		Start:   startLine,
+		End:     endLine,
+		Content: clipboard.ExtractLines(m.previewLines, start, end, StripLineNumbers),
+	}
+
+	// Re-marking a name replaces the old region in place
+	replaced := false
+	for i, r := range m.regions {
+		if r.Name == name {
+			m.regions[i] = region
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		m.regions = append(m.regions, region)
+	}
+
+	m.statusMessage = fmt.Sprintf("Region '%s' → %s", name, region.Ref())
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// formatRegions builds one combined payload from all regions
+func formatRegions(regions []Region) string {
+	var b strings.Builder
+	for i, r := range regions {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("@" + r.Ref() + "\n")
+		b.WriteString("```\n")
+		b.WriteString(r.Content)
+		b.WriteString("\n```\n")
+	}
+	return b.String()
+}
+
+// updateRegions handles input in the regions overlay
+func (m Model) updateRegions(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.showingRegions = false
+
+	case "j", "down":
+		if m.regionCursor < len(m.regions)-1 {
+			m.regionCursor++
+		}
+
+	case "k", "up":
+		if m.regionCursor > 0 {
+			m.regionCursor--
+		}
+
+	case "d", "x":
+		if m.regionCursor < len(m.regions) {
+			m.regions = append(m.regions[:m.regionCursor], m.regions[m.regionCursor+1:]...)
+			if m.regionCursor >= len(m.regions) && m.regionCursor > 0 {
+				m.regionCursor--
+			}
+		}
+
+	case "enter", "c":
+		if len(m.regions) == 0 {
+			return m, nil
+		}
+		if err := m.copyText("regions", formatRegions(m.regions)); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.statusMessage = fmt.Sprintf("Copied %d regions", len(m.regions))
+			m.showingRegions = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m, nil
+}
+
+// renderRegionsOverlay renders the list of marked regions
+func (m Model) renderRegionsOverlay(background string) string {
+	var lines []string
+	lines = append(lines, styles.Title.Render("Regions"))
+	lines = append(lines, "")
+
+	if len(m.regions) == 0 {
+		lines = append(lines, styles.Muted.Render("No regions marked."))
+		lines = append(lines, "")
+		lines = append(lines, styles.Faint.Render("In copy mode (v), select lines and press m{a-z}."))
+	}
+
+	totalLines := 0
+	for i, r := range m.regions {
+		label := fmt.Sprintf("%s  %s", r.Name, r.Ref())
+		if i == m.regionCursor {
+			lines = append(lines, styles.Selected.Render(label))
+		} else {
+			lines = append(lines, styles.Normal.Render(label))
+		}
+		totalLines += r.End - r.Start + 1
+	}
+
+	if len(m.regions) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, styles.Faint.Render(fmt.Sprintf("%d regions · %d lines", len(m.regions), totalLines)))
+	}
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [c] copy all  [d] remove  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(m.overlayWidth(70))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
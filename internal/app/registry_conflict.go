@@ -0,0 +1,120 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// saveRegistry writes the registry, asking what to do instead if .context-docs.md
+// changed on disk since it was loaded (e.g. a teammate's change came in with a pull)
+func (m *Model) saveRegistry() error {
+	err := groups.SaveContextDocRegistry(m.rootPath, m.docRegistry, m.config.BackupFiles)
+	if errors.Is(err, groups.ErrRegistryChanged) {
+		m.registryDirty = true
+		m.showingRegistryConflict = true
+	}
+	return err
+}
+
+// updateRegistryConflict handles the choice between the registry on disk and ours
+func (m Model) updateRegistryConflict(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.docRegistry == nil {
+		return m, nil
+	}
+
+	var err error
+	switch keyMsg.String() {
+	case "r":
+		// Drop our changes for what is on disk
+		m.showingRegistryConflict = false
+		m.registryDirty = false
+		m.statusMessage = "Reloaded .context-docs.md"
+		m.statusMessageTime = time.Now()
+		return m, tea.Batch(ClearStatusAfter(3*time.Second), m.loadRegistryAsync())
+
+	case "o":
+		snap := m.takeUndo("Overwrite .context-docs.md")
+		if err = groups.OverwriteContextDocRegistry(m.rootPath, m.docRegistry, m.config.BackupFiles); err == nil {
+			m.pushUndo(snap)
+		}
+		m.statusMessage = "Overwrote .context-docs.md"
+
+	case "m":
+		var merged *groups.ContextDocRegistry
+		merged, err = groups.MergeContextDocRegistry(m.rootPath, m.docRegistry)
+		if err == nil {
+			snap := m.takeUndo("Merge .context-docs.md")
+			m.docRegistry = merged
+			if err = groups.SaveContextDocRegistry(m.rootPath, m.docRegistry, m.config.BackupFiles); err == nil {
+				m.pushUndo(snap)
+			}
+		}
+		m.statusMessage = "Merged with the changes on disk"
+
+	case "esc", "q":
+		// Keep the changes unsaved; the next save asks again
+		m.showingRegistryConflict = false
+		m.statusMessage = "Registry changes not saved"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+
+	default:
+		return m, nil
+	}
+
+	if errors.Is(err, groups.ErrRegistryChanged) {
+		return m, nil // Changed again meanwhile; ask again
+	}
+	m.showingRegistryConflict = false
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Error: %v", err)
+	} else {
+		m.registryDirty = false
+	}
+	m.statusMessageTime = time.Now()
+	if n := len(m.docRegistry.Categories); m.selectedCategory >= n {
+		m.selectedCategory = max(0, n-1)
+	}
+	if docs := m.getDocsForSelectedCategory(); m.docCursor >= len(docs) {
+		m.docCursor = max(0, len(docs)-1)
+	}
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// renderRegistryConflictOverlay renders the prompt about a registry that changed on disk
+func (m Model) renderRegistryConflictOverlay(background string) string {
+	boxWidth := m.overlayWidth(min(max(m.width*60/100, 50), 72))
+
+	var lines []string
+	lines = append(lines, styles.Header.Render(".context-docs.md changed on disk"))
+	lines = append(lines, "")
+	lines = append(lines, styles.Muted.Render("It was changed outside contexTUI, e.g. by a pull or merge,"))
+	lines = append(lines, styles.Muted.Render("while your changes to the docs were waiting to be saved."))
+	lines = append(lines, "")
+	lines = append(lines, "  "+styles.Key.Render("r")+"  Reload it, dropping your changes")
+	lines = append(lines, "  "+styles.Key.Render("o")+"  Overwrite it with your version")
+	lines = append(lines, "  "+styles.Key.Render("m")+"  Merge: keep its docs and order, plus the docs you added")
+	lines = append(lines, "     and minus the ones you removed")
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[esc] decide later (changes stay unsaved)"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,412 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/imports"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// relatedEntry is a file suggested as context for the file the overlay was opened on
+type relatedEntry struct {
+	Path   string // Relative to the project root
+	Group  string // Section heading, e.g. "Imports" or "Imported by"
+	Detail string // Extra info shown after the path
+}
+
+// ImportGraphLoadedMsg is sent when the project's import graph has been built
+type ImportGraphLoadedMsg struct {
+	Graph *imports.Graph
+}
+
+// buildImportGraphAsync builds the import graph from the indexed files in the background
+func (m Model) buildImportGraphAsync() tea.Cmd {
+	rootPath := m.rootPath
+	files := m.allFiles
+	return func() tea.Msg {
+		return ImportGraphLoadedMsg{Graph: imports.Build(rootPath, files)}
+	}
+}
+
+// cursorRelPath returns the tree entry under the cursor relative to the root
+func (m Model) cursorRelPath() (string, bool) {
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) || flat[m.cursor].IsDir {
+		return "", false
+	}
+	rel, err := filepath.Rel(m.rootPath, flat[m.cursor].Path)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// openImports shows the files the file under the cursor imports and is imported by
+func (m Model) openImports() (tea.Model, tea.Cmd) {
+	rel, ok := m.cursorRelPath()
+	if !ok || !imports.IsSource(rel) {
+		m.statusMessage = "Imports: select a Go, JS/TS or Python file"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	m.clearAllOverlays()
+	m.openRelated("Imports", rel)
+	if m.importGraph == nil {
+		m.relatedLoading = true
+		return m, m.buildImportGraphAsync()
+	}
+	m.relatedEntries = importEntries(m.importGraph, rel)
+	return m, nil
+}
+
+// importEntries lists a file's imports followed by its importers
+func importEntries(g *imports.Graph, file string) []relatedEntry {
+	var entries []relatedEntry
+	for _, p := range g.Imports(file) {
+		entries = append(entries, relatedEntry{Path: p, Group: "Imports"})
+	}
+	for _, p := range g.ImportedBy(file) {
+		entries = append(entries, relatedEntry{Path: p, Group: "Imported by"})
+	}
+	return entries
+}
+
+// handleImportGraphLoaded stores the graph and fills the overlay if it is waiting for it
+func (m Model) handleImportGraphLoaded(msg ImportGraphLoadedMsg) (tea.Model, tea.Cmd) {
+	m.importGraph = msg.Graph
+	if m.showingRelated && m.relatedLoading && m.relatedTitle == "Imports" {
+		m.relatedLoading = false
+		m.relatedEntries = importEntries(m.importGraph, m.relatedFile)
+	}
+	return m, nil
+}
+
+// openRelated resets the related files overlay for file
+func (m *Model) openRelated(title, file string) {
+	m.showingRelated = true
+	m.relatedTitle = title
+	m.relatedFile = file
+	m.relatedEntries = nil
+	m.relatedCursor = 0
+	m.relatedSelected = make(map[string]bool)
+	m.relatedLoading = false
+	m.relatedPickDoc = false
+	m.relatedDocCursor = 0
+}
+
+// relatedPicked returns the selected entries, or all of them when none are selected
+func (m Model) relatedPicked() []string {
+	var paths []string
+	for _, e := range m.relatedEntries {
+		if len(m.relatedSelected) == 0 || m.relatedSelected[e.Path] {
+			paths = append(paths, e.Path)
+		}
+	}
+	return paths
+}
+
+// updateRelated handles input in the related files overlay
+func (m Model) updateRelated(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	if m.relatedPickDoc {
+		return m.updateRelatedDocPicker(keyMsg)
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.showingRelated = false
+
+	case "j", "down":
+		if m.relatedCursor < len(m.relatedEntries)-1 {
+			m.relatedCursor++
+		}
+
+	case "k", "up":
+		if m.relatedCursor > 0 {
+			m.relatedCursor--
+		}
+
+	case " ":
+		if m.relatedCursor < len(m.relatedEntries) {
+			path := m.relatedEntries[m.relatedCursor].Path
+			if m.relatedSelected[path] {
+				delete(m.relatedSelected, path)
+			} else {
+				m.relatedSelected[path] = true
+			}
+		}
+
+	case "enter", "c":
+		// Copy the file and the picked files as @references
+		paths := m.relatedPicked()
+		if len(paths) == 0 {
+			return m, nil
+		}
+		refs := []string{"@" + m.relatedFile}
+		for _, p := range paths {
+			refs = append(refs, "@"+p)
+		}
+		if err := m.copyText(strings.ToLower(m.relatedTitle), strings.Join(refs, "\n")); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			m.statusMessage = fmt.Sprintf("Copied %d references", len(refs))
+			m.showingRelated = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+
+	case "a":
+		// Add the picked files to a doc's Key Files
+		if m.readOnly {
+			return m.readOnlyNotice()
+		}
+		if len(m.relatedPicked()) > 0 && m.docRegistry != nil && len(m.docRegistry.Docs) > 0 {
+			m.relatedPickDoc = true
+			m.relatedDocCursor = 0
+		}
+	}
+	return m, nil
+}
+
+// updateRelatedDocPicker handles choosing the doc that receives the picked files
+func (m Model) updateRelatedDocPicker(keyMsg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	docs := m.docRegistry.Docs
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.relatedPickDoc = false
+
+	case "j", "down":
+		if m.relatedDocCursor < len(docs)-1 {
+			m.relatedDocCursor++
+		}
+
+	case "k", "up":
+		if m.relatedDocCursor > 0 {
+			m.relatedDocCursor--
+		}
+
+	case "enter":
+		doc := docs[m.relatedDocCursor]
+		var paths []string
+		for _, p := range append([]string{m.relatedFile}, m.relatedPicked()...) {
+			paths = append(paths, doc.LocalPath(p)) // Key files are relative to the doc's package
+		}
+		snap := m.takeUndo("Add key files to " + doc.Name)
+		added, err := groups.AddKeyFiles(filepath.Join(m.rootPath, doc.Package), doc.LocalPath(doc.FilePath), paths, m.config.BackupFiles)
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", err)
+		} else {
+			if added > 0 {
+				m.pushUndo(snap)
+			}
+			m.statusMessage = fmt.Sprintf("Added %d key files to %s", added, doc.Name)
+			m.showingRelated = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, tea.Batch(ClearStatusAfter(3*time.Second), m.loadRegistryAsync())
+	}
+	return m, nil
+}
+
+// renderRelatedOverlay renders the suggested files, or the doc picker when adding them to a doc
+func (m Model) renderRelatedOverlay(background string) string {
+	boxWidth := m.overlayWidth(72)
+	maxVisible := m.height - 16
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render(m.relatedTitle+": "+m.relatedFile))
+	lines = append(lines, "")
+
+	if m.relatedPickDoc {
+		lines = append(lines, styles.Muted.Render("Add to which doc's Key Files?"))
+		lines = append(lines, "")
+		docs := m.docRegistry.Docs
+		start := 0
+		if m.relatedDocCursor >= maxVisible {
+			start = m.relatedDocCursor - maxVisible + 1
+		}
+		for i := start; i < len(docs) && i < start+maxVisible; i++ {
+			label := ansi.Truncate(docs[i].Name+"  "+styles.Faint.Render(docs[i].FilePath), boxWidth-8, "…")
+			if i == m.relatedDocCursor {
+				lines = append(lines, styles.Selected.Render(" "+ansi.Strip(label)+" "))
+			} else {
+				lines = append(lines, " "+label)
+			}
+		}
+		lines = append(lines, "")
+		lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] add  [esc] back"))
+	} else {
+		switch {
+		case m.relatedLoading:
+			lines = append(lines, styles.Muted.Render("Analyzing..."))
+		case len(m.relatedEntries) == 0:
+			lines = append(lines, styles.Muted.Render("No related files found."))
+		default:
+			// Keep the cursor in view, counting group headings as rows
+			type row struct {
+				text  string
+				entry int // -1 for headings
+			}
+			var rows []row
+			group := ""
+			for i, e := range m.relatedEntries {
+				if e.Group != group {
+					group = e.Group
+					if len(rows) > 0 {
+						rows = append(rows, row{entry: -1})
+					}
+					count := 0
+					for _, other := range m.relatedEntries {
+						if other.Group == group {
+							count++
+						}
+					}
+					rows = append(rows, row{text: styles.SectionHeader.Render(fmt.Sprintf("%s (%d)", group, count)), entry: -1})
+				}
+				rows = append(rows, row{entry: i})
+			}
+			cursorRow := 0
+			for r, rw := range rows {
+				if rw.entry == m.relatedCursor {
+					cursorRow = r
+				}
+			}
+			start := 0
+			if cursorRow >= maxVisible {
+				start = cursorRow - maxVisible + 1
+			}
+			for r := start; r < len(rows) && r < start+maxVisible; r++ {
+				if rows[r].entry < 0 {
+					lines = append(lines, rows[r].text)
+					continue
+				}
+				e := m.relatedEntries[rows[r].entry]
+				check := "[ ]"
+				if m.relatedSelected[e.Path] {
+					check = "[x]"
+				}
+				label := check + " " + e.Path
+				if e.Detail != "" {
+					label += "  " + e.Detail
+				}
+				label = ansi.Truncate(label, boxWidth-8, "…")
+				if rows[r].entry == m.relatedCursor {
+					lines = append(lines, styles.Selected.Render(" "+label+" "))
+				} else {
+					lines = append(lines, " "+styles.Normal.Render(label))
+				}
+			}
+		}
+		if m.relatedTitle == "Imports" && m.importGraph != nil && m.importGraph.Partial {
+			lines = append(lines, "")
+			lines = append(lines, styles.Muted.Render(fmt.Sprintf("Only the first %d source files were analyzed.", imports.MaxFiles)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, styles.Faint.Render("[j/k] navigate  [space] select  [c] copy with file  [a] add to doc  [esc] close"))
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
+
+// maxCoChanged caps the co-changed files listed
+const maxCoChanged = 25
+
+// CoChangeLoadedMsg is sent when the co-change history of a file has been analyzed
+type CoChangeLoadedMsg struct {
+	File    string
+	Entries []relatedEntry
+	Err     error
+}
+
+// openCoChanged shows the files most often committed together with the file under the cursor
+func (m Model) openCoChanged() (tea.Model, tea.Cmd) {
+	rel, ok := m.cursorRelPath()
+	if !ok || !m.isGitRepo {
+		return m, nil
+	}
+
+	m.clearAllOverlays()
+	m.openRelated("Co-changed", rel)
+	m.relatedLoading = true
+
+	rootPath, repoRoot := m.rootPath, m.gitRepoRoot
+	return m, func() tea.Msg {
+		fullPath := filepath.Join(rootPath, rel)
+		repoRel, err := filepath.Rel(repoRoot, fullPath)
+		if err != nil {
+			return CoChangeLoadedMsg{File: rel, Err: err}
+		}
+		changes, commits, err := git.CoChanged(repoRoot, filepath.ToSlash(repoRel))
+		if err != nil {
+			return CoChangeLoadedMsg{File: rel, Err: err}
+		}
+
+		// With a short history, a single shared commit is still a signal
+		minCount := 2
+		if commits < 4 {
+			minCount = 1
+		}
+		var entries []relatedEntry
+		for _, c := range changes {
+			if c.Count < minCount || len(entries) == maxCoChanged {
+				break
+			}
+			full := filepath.Join(repoRoot, c.Path)
+			p, err := filepath.Rel(rootPath, full)
+			if err != nil || strings.HasPrefix(p, "..") {
+				continue // Outside the project root
+			}
+			if _, err := os.Stat(full); err != nil {
+				continue // Deleted since
+			}
+			entries = append(entries, relatedEntry{
+				Path:   filepath.ToSlash(p),
+				Group:  fmt.Sprintf("Committed together (last %d commits)", commits),
+				Detail: fmt.Sprintf("%d×", c.Count),
+			})
+		}
+		return CoChangeLoadedMsg{File: rel, Entries: entries}
+	}
+}
+
+// handleCoChangeLoaded fills the overlay if it is still showing the analyzed file
+func (m Model) handleCoChangeLoaded(msg CoChangeLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.showingRelated || m.relatedTitle != "Co-changed" || m.relatedFile != msg.File {
+		return m, nil
+	}
+	m.relatedLoading = false
+	m.relatedEntries = msg.Entries
+	if msg.Err != nil {
+		m.showingRelated = false
+		m.statusMessage = fmt.Sprintf("Error: %v", msg.Err)
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m, nil
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/history"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// openReleaseNotes shows the release notes picker (HEAD followed by tags, newest first)
+func (m Model) openReleaseNotes() (tea.Model, tea.Cmd) {
+	if !m.isGitRepo {
+		return m, nil
+	}
+	m.showingReleases = true
+	m.releaseRefs = append([]string{history.HEAD}, git.ListTags(m.gitRepoRoot)...)
+	m.releaseCursor = 0
+	m.releaseBase = ""
+	return m, nil
+}
+
+// releaseRange returns the from..to range for the cursor
+// Without an explicit base, the range starts at the next older tag
+func (m Model) releaseRange() (string, string) {
+	to := m.releaseRefs[m.releaseCursor]
+	from := m.releaseBase
+	if from == "" && m.releaseCursor+1 < len(m.releaseRefs) {
+		from = m.releaseRefs[m.releaseCursor+1]
+	}
+	return from, to
+}
+
+// updateReleaseNotes handles input in the release notes picker
+func (m Model) updateReleaseNotes(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.showingReleases = false
+
+	case "j", "down":
+		if m.releaseCursor < len(m.releaseRefs)-1 {
+			m.releaseCursor++
+		}
+
+	case "k", "up":
+		if m.releaseCursor > 0 {
+			m.releaseCursor--
+		}
+
+	case " ":
+		// Toggle the base (from) of the range
+		ref := m.releaseRefs[m.releaseCursor]
+		if m.releaseBase == ref {
+			m.releaseBase = ""
+		} else {
+			m.releaseBase = ref
+		}
+
+	case "enter", "c":
+		from, to := m.releaseRange()
+		notes, err := history.ReleaseNotes(m.rootPath, m.gitRepoRoot, from, to)
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", err)
+		} else if err := m.copyText("release notes", notes); err != nil {
+			m.statusMessage = "Clipboard unavailable"
+		} else {
+			label := to
+			if from != "" {
+				label = from + ".." + to
+			}
+			m.statusMessage = "Copied release notes " + label
+			m.showingReleases = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	return m, nil
+}
+
+// renderReleaseNotesOverlay renders the tag list for picking a release range
+func (m Model) renderReleaseNotesOverlay(background string) string {
+	maxVisible := m.height - 16
+	if maxVisible < 5 {
+		maxVisible = 5
+	}
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Release Notes"))
+	lines = append(lines, "")
+
+	// Keep the cursor in view
+	start := 0
+	if m.releaseCursor >= maxVisible {
+		start = m.releaseCursor - maxVisible + 1
+	}
+	end := start + maxVisible
+	if end > len(m.releaseRefs) {
+		end = len(m.releaseRefs)
+	}
+
+	for i := start; i < end; i++ {
+		ref := m.releaseRefs[i]
+		label := ref
+		if ref == history.HEAD {
+			label = "HEAD (unreleased)"
+		}
+		if ref == m.releaseBase {
+			label += "  ◆ from"
+		}
+		if i == m.releaseCursor {
+			lines = append(lines, styles.Selected.Render(" "+label+" "))
+		} else {
+			lines = append(lines, " "+styles.Normal.Render(label))
+		}
+	}
+	if len(m.releaseRefs) == 1 {
+		lines = append(lines, "")
+		lines = append(lines, styles.Muted.Render("No tags - HEAD covers all history."))
+	}
+
+	from, to := m.releaseRange()
+	rangeLabel := to
+	if from != "" {
+		rangeLabel = from + ".." + to
+	}
+	lines = append(lines, "")
+	lines = append(lines, styles.Muted.Render("Range: "+rangeLabel))
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [space] set from  [enter] copy  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(m.overlayWidth(56))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// extraRoot is a directory given on the command line after the project root, shown
+// as a top-level node of the tree with its own git status and context docs
+type extraRoot struct {
+	Path      string
+	GitStatus map[string]git.FileStatus // Keyed relative to Path, like the tree's paths
+	DirStatus map[string]string
+	Branch    git.BranchInfo
+	IsGit     bool
+	Docs      int // Context docs in its registry
+}
+
+// ExtraRootsLoadedMsg is sent when the extra roots' git status and docs have been read
+type ExtraRootsLoadedMsg struct {
+	Roots []extraRoot
+}
+
+// newExtraRoots returns the readable directories among paths, skipping the project
+// root, directories inside it and repeats
+func newExtraRoots(rootPath string, paths []string) []extraRoot {
+	var roots []extraRoot
+	seen := map[string]bool{rootPath: true}
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil || seen[abs] || isWithin(abs, rootPath) {
+			continue
+		}
+		if info, err := os.Stat(abs); err != nil || !info.IsDir() {
+			continue
+		}
+		seen[abs] = true
+		roots = append(roots, extraRoot{Path: abs})
+	}
+	return roots
+}
+
+// extraRootFor returns the extra root holding path, or nil for the project root
+func (m Model) extraRootFor(path string) *extraRoot {
+	for i := range m.extraRoots {
+		if isWithin(path, m.extraRoots[i].Path) {
+			return &m.extraRoots[i]
+		}
+	}
+	return nil
+}
+
+// treeRoot returns the root that path's tree entries are relative to
+func (m Model) treeRoot(path string) string {
+	if r := m.extraRootFor(path); r != nil {
+		return r.Path
+	}
+	return m.rootPath
+}
+
+// childRoot returns the root of an entry's children: an extra root's own path
+// below its node, otherwise the root of the entry itself
+func childRoot(e Entry, rootPath string) string {
+	if e.Root {
+		return e.Path
+	}
+	return rootPath
+}
+
+// rootEntries returns the tree nodes of the extra roots, listed after the project's entries
+func rootEntries(roots []extraRoot) []Entry {
+	entries := make([]Entry, 0, len(roots))
+	for _, r := range roots {
+		entries = append(entries, Entry{
+			Name:    filepath.Base(r.Path),
+			Path:    r.Path,
+			IsDir:   true,
+			RelPath: ".",
+			Root:    true,
+			Denied:  !isReadableDir(r.Path),
+		})
+	}
+	return entries
+}
+
+// loadExtraRootsAsync returns a command that reads each extra root's git status,
+// branch and docs
+func (m Model) loadExtraRootsAsync() tea.Cmd {
+	if len(m.extraRoots) == 0 {
+		return nil
+	}
+	paths := make([]string, len(m.extraRoots))
+	for i, r := range m.extraRoots {
+		paths[i] = r.Path
+	}
+	return func() tea.Msg {
+		roots := make([]extraRoot, len(paths))
+		for i, path := range paths {
+			r := extraRoot{Path: path, GitStatus: make(map[string]git.FileStatus)}
+			if isGit, gitRoot := git.IsRepo(path); isGit {
+				r.IsGit = true
+				statusMap, _ := git.LoadStatus(gitRoot)
+				for key, status := range statusMap {
+					rel, err := filepath.Rel(path, filepath.Join(gitRoot, key))
+					if err == nil && !strings.HasPrefix(rel, "..") {
+						r.GitStatus[rel] = status
+					}
+				}
+				r.DirStatus = git.ComputeDirStatus(r.GitStatus)
+				r.Branch = git.GetBranchInfo(gitRoot)
+			}
+			if registry, err := groups.LoadContextDocRegistry(path); err == nil {
+				r.Docs = len(registry.Docs)
+			}
+			roots[i] = r
+		}
+		return ExtraRootsLoadedMsg{Roots: roots}
+	}
+}
+
+// handleExtraRootsLoaded keeps the extra roots' state for their tree rows
+func (m Model) handleExtraRootsLoaded(msg ExtraRootsLoadedMsg) (tea.Model, tea.Cmd) {
+	if len(msg.Roots) != len(m.extraRoots) {
+		return m, nil
+	}
+	for i := range msg.Roots {
+		if msg.Roots[i].Path != m.extraRoots[i].Path {
+			return m, nil // From before a project switch
+		}
+	}
+	m.extraRoots = msg.Roots
+	m.InvalidateTreeCache()
+	return m, nil
+}
+
+// summary describes an extra root's branch and docs next to its node
+func (r extraRoot) summary() string {
+	var parts []string
+	if r.IsGit && r.Branch.Branch != "" {
+		branch := r.Branch.Branch
+		if r.Branch.Ahead > 0 {
+			branch += fmt.Sprintf(" ↑%d", r.Branch.Ahead)
+		}
+		if r.Branch.Behind > 0 {
+			branch += fmt.Sprintf(" ↓%d", r.Branch.Behind)
+		}
+		if len(r.GitStatus) > 0 {
+			branch += fmt.Sprintf(" ●%d", len(r.GitStatus))
+		}
+		parts = append(parts, branch)
+	}
+	if r.Docs > 0 {
+		parts = append(parts, fmt.Sprintf("%d docs", r.Docs))
+	}
+	return strings.Join(parts, " · ")
+}
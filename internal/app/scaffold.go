@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// scaffoldDoc drafts a context doc for the directory under the cursor and registers it
+func (m Model) scaffoldDoc() (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) {
+		return m, nil
+	}
+	dir := flat[m.cursor].Path
+	if !flat[m.cursor].IsDir {
+		dir = filepath.Dir(dir)
+	}
+	relDir, err := filepath.Rel(m.rootPath, dir)
+	if err != nil {
+		return m, nil
+	}
+
+	docPath, content, err := groups.ScaffoldDoc(m.rootPath, relDir, m.docRegistry)
+	if err == nil {
+		fullPath := filepath.Join(m.rootPath, docPath)
+		if err = os.MkdirAll(filepath.Dir(fullPath), 0755); err == nil {
+			err = atomicfile.Write(fullPath, []byte(content), m.config.BackupFiles)
+		}
+	}
+	var doc *groups.ContextDoc
+	if err == nil {
+		doc, err = groups.ParseContextDoc(m.rootPath, docPath)
+	}
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Error: %v", err)
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	if m.docRegistry == nil {
+		m.docRegistry = &groups.ContextDocRegistry{
+			Categories: groups.DefaultCategories(),
+			Docs:       []groups.ContextDoc{},
+			ByCategory: make(map[string][]groups.ContextDoc),
+		}
+	}
+	doc.ValidateKeyFiles(m.rootPath)
+	snap := m.takeUndo("Add " + doc.Name)
+	m.docRegistry.AddDoc(*doc)
+	if err := m.saveRegistry(); err != nil {
+		m.statusMessage = fmt.Sprintf("Error saving: %v", err)
+	} else {
+		m.pushUndo(snap)
+		m.statusMessage = fmt.Sprintf("Drafted %s - fill in the TODOs", docPath)
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(5 * time.Second)
+}
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/notes"
+)
+
+// searchFilter narrows the file finder before fuzzy matching
+// Values of one kind are alternatives (ext:go ext:md); different kinds must all match.
+type searchFilter struct {
+	exts   []string // Extensions with their dot, lowercased
+	dirs   []string // Directories relative to the root
+	groups []string // Doc categories, doc names or tags whose key files match
+	notes  []string // Text of notes on the file or a folder above it, lowercased
+}
+
+// parseSearchQuery splits ext:, dir:, group: and note: filters off a search query, returning
+// the filter and the remaining fuzzy term. Values may be comma-separated (ext:go,md);
+// a filter without a value yet (while typing) is ignored.
+func parseSearchQuery(query string) (searchFilter, string) {
+	var f searchFilter
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		key, value, ok := strings.Cut(word, ":")
+		if !ok {
+			terms = append(terms, word)
+			continue
+		}
+		values := strings.Split(value, ",")
+		switch strings.ToLower(key) {
+		case "ext":
+			for _, v := range values {
+				if v = strings.TrimPrefix(v, "."); v != "" {
+					f.exts = append(f.exts, "."+strings.ToLower(v))
+				}
+			}
+		case "dir":
+			for _, v := range values {
+				if v != "" {
+					f.dirs = append(f.dirs, filepath.Clean(filepath.FromSlash(v)))
+				}
+			}
+		case "group":
+			for _, v := range values {
+				if v != "" {
+					f.groups = append(f.groups, v)
+				}
+			}
+		case "note":
+			for _, v := range values {
+				if v != "" {
+					f.notes = append(f.notes, strings.ToLower(v))
+				}
+			}
+		default:
+			terms = append(terms, word)
+		}
+	}
+	return f, strings.Join(terms, " ")
+}
+
+// active returns true if the query had any filter
+func (f searchFilter) active() bool {
+	return len(f.exts) > 0 || len(f.dirs) > 0 || len(f.groups) > 0 || len(f.notes) > 0
+}
+
+// groupPaths returns the key files and doc files of the filter's groups, as paths
+// relative to the root; listed directories cover the files below them
+func (f searchFilter) groupPaths(rootPath string, registry *groups.ContextDocRegistry) map[string]bool {
+	paths := make(map[string]bool)
+	if registry == nil {
+		return paths
+	}
+	for _, name := range f.groups {
+		docs := registry.FindGroup(name)
+		for _, d := range registry.Docs {
+			if d.HasTag(name) {
+				docs = append(docs, d)
+			}
+		}
+		for _, d := range docs {
+			paths[filepath.Clean(d.FilePath)] = true
+			for _, kf := range d.KeyFiles {
+				if !groups.IsExternalKeyFile(rootPath, kf) {
+					paths[filepath.Clean(kf)] = true
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// apply returns the files that pass the filter
+func (f searchFilter) apply(files []string, rootPath string, registry *groups.ContextDocRegistry, fileNotes notes.Store) []string {
+	var listed map[string]bool
+	if len(f.groups) > 0 {
+		listed = f.groupPaths(rootPath, registry)
+	}
+	var kept []string
+	for _, path := range files {
+		if len(f.exts) > 0 && !f.hasExt(path) {
+			continue
+		}
+		if len(f.dirs) > 0 && !f.inDir(path) {
+			continue
+		}
+		if listed != nil && !underListed(path, listed) {
+			continue
+		}
+		if len(f.notes) > 0 && !f.hasNote(path, fileNotes) {
+			continue
+		}
+		kept = append(kept, path)
+	}
+	return kept
+}
+
+// hasExt returns true if path has one of the filter's extensions
+func (f searchFilter) hasExt(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range f.exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNote returns true if the note on path, or on its closest folder with one,
+// contains one of the filter's words
+func (f searchFilter) hasNote(path string, fileNotes notes.Store) bool {
+	note, _ := fileNotes.Nearest(path)
+	note = strings.ToLower(note)
+	for _, n := range f.notes {
+		if note != "" && strings.Contains(note, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// inDir returns true if path is inside one of the filter's directories
+func (f searchFilter) inDir(path string) bool {
+	for _, dir := range f.dirs {
+		if dir == "." || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// underListed returns true if path or one of its parent directories is listed
+func underListed(path string, listed map[string]bool) bool {
+	for p := path; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		if listed[p] {
+			return true
+		}
+	}
+	return false
+}
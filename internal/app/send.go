@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/send"
+)
+
+// sendTarget returns the configured agent session target
+func (m Model) sendTarget() send.Target {
+	return send.Target{TmuxPane: m.config.SendTmuxPane, File: m.config.SendFile}
+}
+
+// sendRefs delivers @references straight to the agent session (S), skipping the paste step
+func (m Model) sendRefs(refs []string) (tea.Model, tea.Cmd) {
+	if len(refs) == 0 {
+		return m, nil
+	}
+	target := m.sendTarget()
+	// Typing into a tmux pane changes nothing on disk; appending to a file does
+	if target.TmuxPane == "" && target.File != "" && m.readOnly {
+		return m.readOnlyNotice()
+	}
+	if err := target.Send(m.rootPath, refs); err != nil {
+		m.statusMessage = fmt.Sprintf("Send failed: %v", err)
+	} else if len(refs) == 1 {
+		m.statusMessage = fmt.Sprintf("Sent %s to %s", refs[0], target)
+	} else {
+		m.statusMessage = fmt.Sprintf("Sent %d references to %s", len(refs), target)
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// docRefs returns the selected docs, or the doc under the cursor, as @references
+func (m Model) docRefs() []string {
+	var refs []string
+	if len(m.selectedDocs) > 0 {
+		for path := range m.selectedDocs {
+			refs = append(refs, "@"+path)
+		}
+		sort.Strings(refs)
+		return refs
+	}
+	currentDocs := m.getDocsForSelectedCategory()
+	if m.docCursor < len(currentDocs) {
+		refs = append(refs, "@"+currentDocs[m.docCursor].FilePath)
+	}
+	return refs
+}
+
+// docGroupRefs returns the selected docs (or the current one) followed by their key
+// files, as @ references without duplicates
+func (m Model) docGroupRefs() []string {
+	var docs []groups.ContextDoc
+	if len(m.selectedDocs) > 0 && m.docRegistry != nil {
+		for _, d := range m.docRegistry.Docs {
+			if m.selectedDocs[d.FilePath] {
+				docs = append(docs, d)
+			}
+		}
+	} else if currentDocs := m.getDocsForSelectedCategory(); m.docCursor < len(currentDocs) {
+		docs = append(docs, currentDocs[m.docCursor])
+	}
+	return groups.GroupRefs(m.rootPath, docs)
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/clipboard"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// pendingSnippet is a copy-mode selection waiting for the doc it is added to
+type pendingSnippet struct {
+	Path       string // Relative to the root
+	Start, End int    // Source lines
+	Text       string
+}
+
+// openSnippetDocPicker leaves copy mode with the selection and asks which doc's
+// examples it joins
+func (m Model) openSnippetDocPicker() (tea.Model, tea.Cmd) {
+	start, end, startLine, endLine, ok := m.selectionSource()
+	switch {
+	case !ok:
+		m.statusMessage = "Select lines first"
+	case m.readOnly:
+		return m.readOnlyNotice()
+	case m.docRegistry == nil || len(m.docRegistry.Docs) == 0:
+		m.statusMessage = "No context docs to add the snippet to"
+	default:
+		snippet := pendingSnippet{
+			Path:  m.previewRelPath(),
+			Start: startLine,
+			End:   endLine,
+			Text:  clipboard.ExtractLines(m.previewLines, start, end, StripLineNumbers),
+		}
+		m.clearAllOverlays()
+		m.snippet = snippet
+		m.showingSnippetDocs = true
+		m.snippetDocCursor = 0
+		return m, nil
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// updateSnippetDocs handles choosing the doc that receives the snippet
+func (m Model) updateSnippetDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.docRegistry == nil {
+		return m, nil
+	}
+	docs := m.docRegistry.Docs
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.showingSnippetDocs = false
+
+	case "j", "down":
+		if m.snippetDocCursor < len(docs)-1 {
+			m.snippetDocCursor++
+		}
+
+	case "k", "up":
+		if m.snippetDocCursor > 0 {
+			m.snippetDocCursor--
+		}
+
+	case "enter":
+		if m.snippetDocCursor >= len(docs) {
+			return m, nil
+		}
+		doc := docs[m.snippetDocCursor]
+		s := m.snippet
+		snap := m.takeUndo("Add example to " + doc.Name)
+		if err := groups.AppendSnippet(m.rootPath, doc.FilePath, s.Path, s.Start, s.End, s.Text, m.config.BackupFiles); err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", err)
+		} else {
+			m.pushUndo(snap)
+			m.statusMessage = fmt.Sprintf("Added %s to %s's examples", lineRef(s.Path, s.Start, s.End), doc.Name)
+			m.showingSnippetDocs = false
+		}
+		m.statusMessageTime = time.Now()
+		return m, tea.Batch(ClearStatusAfter(3*time.Second), m.loadRegistryAsync())
+	}
+	return m, nil
+}
+
+// renderSnippetDocsOverlay renders the doc picker for a snippet
+func (m Model) renderSnippetDocsOverlay(background string) string {
+	boxWidth := m.overlayWidth(72)
+	maxVisible := max(m.height-16, 5)
+
+	var lines []string
+	lines = append(lines, styles.Title.Render("Snippet: "+lineRef(m.snippet.Path, m.snippet.Start, m.snippet.End)))
+	lines = append(lines, "")
+	lines = append(lines, styles.Muted.Render("Add to which doc's Examples?"))
+	lines = append(lines, "")
+	var docs []groups.ContextDoc
+	if m.docRegistry != nil {
+		docs = m.docRegistry.Docs
+	}
+	start := 0
+	if m.snippetDocCursor >= maxVisible {
+		start = m.snippetDocCursor - maxVisible + 1
+	}
+	for i := start; i < len(docs) && i < start+maxVisible; i++ {
+		label := ansi.Truncate(docs[i].Name+"  "+styles.Faint.Render(docs[i].FilePath), boxWidth-8, "…")
+		if i == m.snippetDocCursor {
+			lines = append(lines, styles.Selected.Render(" "+ansi.Strip(label)+" "))
+		} else {
+			lines = append(lines, " "+label)
+		}
+	}
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] add under ## Examples (or ## Snippets)  [esc] cancel"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
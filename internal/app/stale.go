@@ -0,0 +1,68 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/groups"
+)
+
+// noteStaleDocs marks docs stale when the watcher sees one of their key files change
+// Git-based staleness only notices committed changes, so edits made while the app
+// runs are tracked here until the doc itself is edited.
+func (m *Model) noteStaleDocs(paths []string) tea.Cmd {
+	if m.docRegistry == nil || len(paths) == 0 {
+		return nil
+	}
+	changed := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		changed[filepath.Clean(p)] = true
+	}
+
+	var names []string
+	for _, d := range m.docRegistry.Docs {
+		if changed[d.FilePath] {
+			delete(m.watchedStale, d.FilePath)
+			continue
+		}
+		if d.IsStale || m.watchedStale[d.FilePath] {
+			continue
+		}
+		for _, kf := range d.KeyFiles {
+			if !groups.IsExternalKeyFile(m.rootPath, kf) && changed[filepath.Clean(kf)] {
+				if m.watchedStale == nil {
+					m.watchedStale = make(map[string]bool)
+				}
+				m.watchedStale[d.FilePath] = true
+				names = append(names, d.Name)
+				break
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	m.applyWatchedStale()
+
+	if len(names) == 1 {
+		m.statusMessage = fmt.Sprintf("Doc %s may be stale", names[0])
+	} else {
+		m.statusMessage = fmt.Sprintf("%d docs may be stale: %s", len(names), strings.Join(names, ", "))
+	}
+	m.statusMessageTime = time.Now()
+	return ClearStatusAfter(5 * time.Second)
+}
+
+// applyWatchedStale marks the docs tracked by noteStaleDocs in the registry,
+// which reloads with git-based staleness only
+func (m *Model) applyWatchedStale() {
+	if m.docRegistry == nil {
+		return
+	}
+	for path := range m.watchedStale {
+		m.docRegistry.MarkStale(path)
+	}
+}
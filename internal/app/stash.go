@@ -0,0 +1,256 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// StashesLoadedMsg is sent when the stash list has been read
+type StashesLoadedMsg struct {
+	Stashes []git.Stash
+	Err     error
+}
+
+// StashDiffMsg is sent when a stash's diff has been rendered
+type StashDiffMsg struct {
+	Key     string // Preview cache key of the diff
+	Content string
+}
+
+// StashActionDoneMsg is sent when a stash was applied, popped or dropped
+type StashActionDoneMsg struct {
+	Action string
+	Ref    string
+	Err    error
+}
+
+// stashCacheKey keys a stash's diff in the preview cache
+func stashCacheKey(hash string) string {
+	return "stash\x00" + hash
+}
+
+// openStashes switches the git view's list from changes to stashes
+func (m Model) openStashes() (tea.Model, tea.Cmd) {
+	m.gitStashMode = true
+	m.gitStashes = nil
+	m.gitStashCursor = 0
+	m.loading = true
+	m.preview.SetContent("Loading stashes...")
+	return m, m.loadStashesAsync()
+}
+
+// loadStashesAsync returns a command that reads the stash list
+func (m Model) loadStashesAsync() tea.Cmd {
+	repoRoot := m.gitRepoRoot
+	return func() tea.Msg {
+		stashes, err := git.ListStashes(repoRoot)
+		return StashesLoadedMsg{Stashes: stashes, Err: err}
+	}
+}
+
+// handleStashesLoaded shows the stash list and the selected stash's diff
+func (m Model) handleStashesLoaded(msg StashesLoadedMsg) (tea.Model, tea.Cmd) {
+	if !m.gitStatusMode || !m.gitStashMode {
+		return m, nil
+	}
+	m.gitStashes = msg.Stashes
+	if m.gitStashes == nil {
+		m.gitStashes = []git.Stash{} // Loaded, just empty
+	}
+	if m.gitStashCursor >= len(m.gitStashes) {
+		m.gitStashCursor = max(len(m.gitStashes)-1, 0)
+	}
+	if len(m.gitStashes) == 0 {
+		m.loading = false
+		m.previewPath = ""
+		m.preview.SetContent(styles.Faint.Render("No stashes"))
+		m.previewLines = nil
+		return m, nil
+	}
+	return m.showStash()
+}
+
+// showStash loads the diff of the stash under the cursor into the preview
+func (m Model) showStash() (tea.Model, tea.Cmd) {
+	if m.gitStashCursor >= len(m.gitStashes) {
+		return m, nil
+	}
+	s := m.gitStashes[m.gitStashCursor]
+	key := stashCacheKey(s.Hash)
+	m.previewPath = key
+	m.previewXOffset = 0
+	m.structured = nil
+
+	if cached, ok := m.previewCache.Get(key); ok {
+		m.loading = false
+		m.preview.SetContent(cached.Content)
+		m.previewLines = strings.Split(cached.Content, "\n")
+		m.preview.GotoTop()
+		return m, nil
+	}
+
+	m.loading = true
+	m.preview.SetContent("Loading...")
+	repoRoot, previewWidth := m.gitRepoRoot, m.previewRenderWidth()
+	return m, func() tea.Msg {
+		diff, err := git.StashDiff(repoRoot, s.Ref)
+		if err != nil {
+			diff = "Error: " + err.Error()
+		}
+		header := styles.Title.Render(s.Subject) + "\n" +
+			styles.Faint.Render(fmt.Sprintf("%s  %s  %s", s.Ref, s.Hash, s.Age)) + "\n\n"
+		return StashDiffMsg{Key: key, Content: header + HighlightDiff(strings.TrimRight(diff, "\n"), previewWidth)}
+	}
+}
+
+// handleStashDiff caches a rendered stash diff and shows it if it is still selected
+func (m Model) handleStashDiff(msg StashDiffMsg) (tea.Model, tea.Cmd) {
+	m.previewCache.Put(msg.Key, CachedPreview{Content: msg.Content})
+	if m.gitStatusMode && m.gitStashMode && m.previewPath == msg.Key {
+		m.loading = false
+		m.preview.SetContent(msg.Content)
+		m.previewLines = strings.Split(msg.Content, "\n")
+		m.preview.GotoTop()
+	}
+	return m, nil
+}
+
+// runStashAction applies, pops or drops the selected stash
+func (m Model) runStashAction(action string) (tea.Model, tea.Cmd) {
+	if m.gitStashCursor >= len(m.gitStashes) {
+		return m, nil
+	}
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	ref := m.gitStashes[m.gitStashCursor].Ref
+	// Dropping loses the stash, so it takes a second press
+	keys := map[string]string{"apply": "a", "pop": "p", "drop": "d"}
+	if !m.confirmAgain(action+"\x00"+ref, action == "drop", "Press "+keys[action]+" again to "+action+" "+ref) {
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	repoRoot := m.gitRepoRoot
+	return m, func() tea.Msg {
+		return StashActionDoneMsg{Action: action, Ref: ref, Err: git.StashAction(repoRoot, action, ref)}
+	}
+}
+
+// handleStashActionDone reports the result and reloads the stashes and status
+func (m Model) handleStashActionDone(msg StashActionDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.statusMessage = "Error: " + msg.Err.Error()
+	} else {
+		done := map[string]string{"apply": "Applied", "pop": "Popped", "drop": "Dropped"}
+		m.statusMessage = done[msg.Action] + " " + msg.Ref
+	}
+	m.statusMessageTime = time.Now()
+	return m, tea.Batch(m.loadStashesAsync(), m.refreshGitStatus(), ClearStatusAfter(5*time.Second))
+}
+
+// stashSharedKeys are handled by the git view as usual while it lists stashes
+var stashSharedKeys = map[string]bool{
+	"tab": true, "left": true, "right": true, "H": true, "L": true,
+	"/": true, "g": true, "v": true, "f": true, "n": true, "G": true,
+}
+
+// updateStashKey handles keys while the git view lists stashes
+func (m Model) updateStashKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "z":
+		// Back to the changes
+		m.gitStashMode = false
+		m.gitStashes = nil
+		if len(m.gitChanges) > 0 {
+			return m.UpdateGitStatusPreview()
+		}
+		m.preview.SetContent("")
+		m.previewLines = nil
+		return m, nil
+
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "j", "down":
+		if m.activePane == PreviewPane {
+			m.HandlePreviewScroll("down")
+		} else if m.gitStashCursor < len(m.gitStashes)-1 {
+			m.gitStashCursor++
+			return m.showStash()
+		}
+
+	case "k", "up":
+		if m.activePane == PreviewPane {
+			m.HandlePreviewScroll("up")
+		} else if m.gitStashCursor > 0 {
+			m.gitStashCursor--
+			return m.showStash()
+		}
+
+	case "enter", "a":
+		return m.runStashAction("apply")
+
+	case "p":
+		return m.runStashAction("pop")
+
+	case "d", "x":
+		return m.runStashAction("drop")
+
+	case "ctrl+d", "J":
+		m.preview.HalfViewDown()
+
+	case "ctrl+u", "K":
+		m.preview.HalfViewUp()
+
+	case "w":
+		return m.togglePreviewWrap()
+
+	case "c":
+		// Copy the stash reference
+		if m.gitStashCursor < len(m.gitStashes) {
+			s := m.gitStashes[m.gitStashCursor]
+			if err := m.copyText("stash", s.Ref); err != nil {
+				m.statusMessage = "Clipboard unavailable"
+			} else {
+				m.statusMessage = "Copied " + s.Ref
+			}
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(3 * time.Second)
+		}
+	}
+	return m, nil
+}
+
+// renderStashList renders the stash list shown in place of the changed files
+func (m Model) renderStashList(width, height int) string {
+	if m.gitStashes == nil {
+		return styles.Faint.Render("Loading...")
+	}
+	if len(m.gitStashes) == 0 {
+		return styles.Faint.Render("No stashes")
+	}
+
+	start := 0
+	if m.gitStashCursor >= height {
+		start = m.gitStashCursor - height + 1
+	}
+	refStyle := lipgloss.NewStyle().Foreground(styles.Info)
+	var lines []string
+	for i := start; i < len(m.gitStashes) && i < start+height; i++ {
+		s := m.gitStashes[i]
+		if i == m.gitStashCursor {
+			line := ansi.Truncate(fmt.Sprintf("%s %s  %s", s.Ref, s.Subject, s.Age), width, "…")
+			lines = append(lines, styles.Selected.Render(padRight(line, width)))
+		} else {
+			line := refStyle.Render(s.Ref) + " " + s.Subject + "  " + styles.Faint.Render(s.Age)
+			lines = append(lines, ansi.Truncate(line, width, "…"))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
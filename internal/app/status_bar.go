@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/tokens"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// Footer segments statusBar in the config can list, shown in its order
+const (
+	segmentBranch = "branch" // Git branch with ahead/behind
+	segmentDocs   = "docs"   // Registered docs and how many are stale
+	segmentTokens = "tokens" // Token estimate of the last copy
+	segmentPath   = "path"   // Entry under the cursor
+	segmentTime   = "time"   // Clock
+	segmentHints  = "hints"  // Key hints of the view
+)
+
+// defaultStatusBar is the footer when the config lists no segments
+var defaultStatusBar = []string{segmentBranch, segmentHints}
+
+// ClockTickMsg redraws the footer's clock on the minute
+type ClockTickMsg struct{}
+
+// clockTick returns a command that sends a ClockTickMsg at the next minute
+func clockTick() tea.Cmd {
+	next := time.Now().Truncate(time.Minute).Add(time.Minute)
+	return tea.Tick(time.Until(next), func(time.Time) tea.Msg {
+		return ClockTickMsg{}
+	})
+}
+
+// statusBarSegments returns the footer's segments in order
+func (m Model) statusBarSegments() []string {
+	if len(m.config.StatusBar) == 0 {
+		return defaultStatusBar
+	}
+	return m.config.StatusBar
+}
+
+// showsSegment returns true if the footer shows a segment
+func (m Model) showsSegment(name string) bool {
+	for _, s := range m.statusBarSegments() {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// renderStatusBar renders the footer's segments, with hints as the view's key hints
+// Segments with nothing to show, and names it doesn't know, are left out.
+func (m Model) renderStatusBar(hints string) string {
+	var parts []string
+	for _, name := range m.statusBarSegments() {
+		var part string
+		switch name {
+		case segmentBranch:
+			part = m.renderBranchStatus()
+		case segmentDocs:
+			part = m.renderDocsSegment()
+		case segmentTokens:
+			if n := len(m.copyHistory); n > 0 {
+				part = styles.Faint.Render("last copy " + tokens.Format(tokens.Estimate(m.copyHistory[n-1].Text)))
+			}
+		case segmentPath:
+			part = styles.Muted.Render(m.pathSegment())
+		case segmentTime:
+			part = styles.Faint.Render(time.Now().Format("15:04"))
+		case segmentHints:
+			part = styles.Faint.Render(hints)
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// renderDocsSegment renders how many docs are registered and how many are stale
+func (m Model) renderDocsSegment() string {
+	if m.docRegistry == nil || len(m.docRegistry.Docs) == 0 {
+		return ""
+	}
+	stale := 0
+	for _, doc := range m.docRegistry.Docs {
+		if doc.IsStale {
+			stale++
+		}
+	}
+	segment := styles.Faint.Render(fmt.Sprintf("%d docs", len(m.docRegistry.Docs)))
+	if stale > 0 {
+		segment += styles.StatusWarning.Render(fmt.Sprintf(" %d stale", stale))
+	}
+	return segment
+}
+
+// pathSegment returns the path of the entry under the cursor, relative to the project
+func (m Model) pathSegment() string {
+	if m.gitStatusMode {
+		if change, ok := m.selectedGitChange(); ok {
+			return change.Path
+		}
+		return ""
+	}
+	path := m.cursorPath()
+	if rel, err := filepath.Rel(m.rootPath, path); err == nil && path != "" {
+		return rel
+	}
+	return path
+}
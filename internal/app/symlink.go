@@ -0,0 +1,84 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// linkWalker decides which symlinked directories a crawl follows: each target once,
+// never one inside the project (its files are already listed under their real path)
+// and never one containing a directory already walked, which would loop
+type linkWalker struct {
+	walked []string // Real paths of the root and every followed target
+}
+
+// newLinkWalker starts a crawl of root
+func newLinkWalker(root string) *linkWalker {
+	real, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		real = root
+	}
+	return &linkWalker{walked: []string{real}}
+}
+
+// follow returns true if the linked directory at path should be walked, and records it
+func (w *linkWalker) follow(path string) bool {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	for _, dir := range w.walked {
+		if isWithin(real, dir) || isWithin(dir, real) {
+			return false
+		}
+	}
+	w.walked = append(w.walked, real)
+	return true
+}
+
+// isWithin returns true if path is dir or below it
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// linkedDir returns true if the entry is a symlink to a directory
+func linkedDir(path string, f os.DirEntry) bool {
+	if f.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// watchDirs returns the directories to watch for changes: root and everything below
+// it except hidden, package and git-ignored directories, plus linked directories
+// when following symlinks
+func watchDirs(root string, followSymlinks bool) []string {
+	ignored := git.Ignored(root)
+	links := newLinkWalker(root)
+	var dirs []string
+	var walk func(dir string)
+	walk = func(dir string) {
+		dirs = append(dirs, dir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, f := range entries {
+			name := f.Name()
+			path := filepath.Join(dir, name)
+			// Skip hidden, common ignore and git-ignored dirs
+			if strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || ignored[path] {
+				continue
+			}
+			if f.IsDir() || (followSymlinks && linkedDir(path, f) && links.follow(path)) {
+				walk(path)
+			}
+		}
+	}
+	walk(root)
+	return dirs
+}
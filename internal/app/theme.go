@@ -0,0 +1,135 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// openThemePicker shows the theme picker overlay with the active theme under the cursor
+func (m Model) openThemePicker() (tea.Model, tea.Cmd) {
+	m.clearAllOverlays()
+	m.showingThemes = true
+	m.themeNames = styles.ListThemes()
+	m.themeCursor = 0
+	active := m.activeThemeName()
+	for i, name := range m.themeNames {
+		if name == active {
+			m.themeCursor = i
+			break
+		}
+	}
+	return m, nil
+}
+
+// activeThemeName returns the configured theme name, treating unset as auto
+func (m Model) activeThemeName() string {
+	if m.themeName == "" {
+		return styles.ThemeAuto
+	}
+	return m.themeName
+}
+
+// updateThemes handles input in the theme picker overlay
+func (m Model) updateThemes(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "q":
+		m.showingThemes = false
+		return m, nil
+
+	case "j", "down":
+		if m.themeCursor < len(m.themeNames)-1 {
+			m.themeCursor++
+		}
+		return m, nil
+
+	case "k", "up":
+		if m.themeCursor > 0 {
+			m.themeCursor--
+		}
+		return m, nil
+
+	case "enter":
+		if m.themeCursor < len(m.themeNames) {
+			name := m.themeNames[m.themeCursor]
+			m.showingThemes = false
+			return m.applyTheme(name)
+		}
+	}
+	return m, nil
+}
+
+// applyTheme switches the active theme, persists it, and re-renders themed content
+func (m Model) applyTheme(name string) (Model, tea.Cmd) {
+	styles.Apply(styles.LoadTheme(m.rootPath, name))
+	m.themeName = name
+	m.saveConfig()
+
+	// Rendered previews and diffs embed theme colors, so drop them
+	m.previewCache.Clear()
+	m.diffCache.Clear()
+
+	var cmd tea.Cmd
+	if m.ready {
+		clear(m.treeLines)
+		clear(m.panes)
+		if m.previewPath != "" && !m.previewIsImage {
+			m, cmd = m.UpdatePreview()
+		}
+	}
+
+	m.statusMessage = fmt.Sprintf("Theme: %s", name)
+	m.statusMessageTime = time.Now()
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
+
+// renderThemeOverlay renders the theme picker with a color swatch per theme
+func (m Model) renderThemeOverlay(background string) string {
+	var lines []string
+	lines = append(lines, styles.Title.Render("Theme"))
+	lines = append(lines, "")
+
+	for i, name := range m.themeNames {
+		t := styles.LoadTheme(m.rootPath, name)
+		swatch := ""
+		for _, c := range []lipgloss.Color{t.Accent, t.AccentAlt, t.Success, t.Warning, t.Error, t.Info} {
+			swatch += lipgloss.NewStyle().Foreground(c).Render("●")
+		}
+
+		label := fmt.Sprintf("%-20s", name)
+		if name == m.activeThemeName() {
+			label = fmt.Sprintf("%-20s", name+" (active)")
+		}
+		if i == m.themeCursor {
+			lines = append(lines, styles.Selected.Render(label)+" "+swatch)
+		} else {
+			lines = append(lines, styles.Normal.Render(label)+" "+swatch)
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("User themes: "+styles.UserThemeDir()))
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render("[j/k] navigate  [enter] apply  [esc] close"))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(m.overlayWidth(60))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
@@ -0,0 +1,256 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/tokens"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+)
+
+// headingRe matches an ATX markdown heading
+var headingRe = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// inlineLinkRe matches [text](target), keeping text
+var inlineLinkRe = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// headingMarkup removes spaces and emphasis markers when matching rendered headings
+var headingMarkup = strings.NewReplacer(" ", "", "`", "", "*", "", "_", "")
+
+// tocEntry is a heading of the previewed markdown file
+type tocEntry struct {
+	Level  int
+	Title  string // Heading text without inline markup
+	Line   int    // Row of the heading in the rendered preview
+	Source int    // Line of the heading in the markdown source (0-based)
+}
+
+// markdownHeadings returns the headings of markdown source, skipping code blocks
+// and YAML frontmatter
+func markdownHeadings(source string) []tocEntry {
+	lines := strings.Split(source, "\n")
+	start := 0
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	var entries []tocEntry
+	inCodeBlock := false
+	for i := start; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(trimmed); m != nil {
+			title := inlineLinkRe.ReplaceAllString(m[2], "$1")
+			title = strings.NewReplacer("`", "", "**", "", "__", "", "*", "").Replace(title)
+			entries = append(entries, tocEntry{Level: len(m[1]), Title: strings.TrimSpace(title), Source: i})
+		}
+	}
+	return entries
+}
+
+// locateHeadings finds the row of each heading in the rendered preview, in order,
+// dropping headings that can't be found
+func locateHeadings(entries []tocEntry, rendered []string) []tocEntry {
+	var found []tocEntry
+	row := 0
+	for _, e := range entries {
+		// Long headings wrap, so match on their start; rendering pads inline code and
+		// keeps some emphasis markers, so compare without spaces and markup
+		needle := headingMarkup.Replace(e.Title)
+		if r := []rune(needle); len(r) > 20 {
+			needle = string(r[:20])
+		}
+		for i := row; i < len(rendered); i++ {
+			if strings.Contains(headingMarkup.Replace(ansi.Strip(rendered[i])), needle) {
+				e.Line = i
+				found = append(found, e)
+				row = i + 1
+				break
+			}
+		}
+	}
+	return found
+}
+
+// currentTOCEntry returns the index of the section at the top of the preview, or -1
+// Once scrolled to the bottom, the last heading on screen counts as current.
+func (m Model) currentTOCEntry() int {
+	top := m.preview.YOffset + 1
+	if m.preview.AtBottom() {
+		top = m.preview.YOffset + m.preview.Height - 1
+	}
+	current := -1
+	for i, e := range m.tocEntries {
+		if e.Line > top {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+// openTOC shows the headings of the previewed markdown file in place of the tree
+func (m Model) openTOC() (tea.Model, tea.Cmd) {
+	if !strings.HasSuffix(strings.ToLower(m.previewPath), ".md") || m.loading {
+		return m, nil
+	}
+	source, err := os.ReadFile(m.previewPath)
+	if err != nil {
+		return m, nil
+	}
+	entries := locateHeadings(markdownHeadings(string(source)), m.previewLines)
+	if len(entries) == 0 {
+		m.statusMessage = "No headings in this file"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	m.clearAllOverlays()
+	m.tocMode = true
+	m.tocEntries = entries
+	m.tocCursor = max(0, m.currentTOCEntry())
+	return m, nil
+}
+
+// updateTOCKey handles keys while the table of contents is shown
+func (m Model) updateTOCKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	jump := func() {
+		m.preview.SetYOffset(m.tocEntries[m.tocCursor].Line)
+	}
+	switch msg.String() {
+	case "esc", "q", "t", "enter":
+		m.tocMode = false
+
+	case "j", "down":
+		if m.tocCursor < len(m.tocEntries)-1 {
+			m.tocCursor++
+			jump()
+		}
+
+	case "k", "up":
+		if m.tocCursor > 0 {
+			m.tocCursor--
+			jump()
+		}
+
+	case "g", "home":
+		m.tocCursor = 0
+		jump()
+
+	case "G", "end":
+		m.tocCursor = len(m.tocEntries) - 1
+		jump()
+
+	case "ctrl+d", "J":
+		m.preview.HalfViewDown()
+
+	case "ctrl+u", "K":
+		m.preview.HalfViewUp()
+
+	case "c", "r":
+		return m.copySection(m.tocEntries[m.tocCursor], msg.String() == "r")
+	}
+	return m, nil
+}
+
+// markdownSection returns the source of the section starting at a heading, up to the
+// next heading of the same or a higher level
+func markdownSection(source string, heading tocEntry) string {
+	lines := strings.Split(source, "\n")
+	end := len(lines)
+	for _, e := range markdownHeadings(source) {
+		if e.Source > heading.Source && e.Level <= heading.Level {
+			end = e.Source
+			break
+		}
+	}
+	if heading.Source >= end {
+		return ""
+	}
+	return strings.TrimRight(strings.Join(lines[heading.Source:end], "\n"), "\n") + "\n"
+}
+
+// headingSlug returns the anchor of a heading the way GitHub links to it
+func headingSlug(title string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// copySection copies one section of the previewed markdown file, or an @file#heading
+// reference to it
+func (m Model) copySection(heading tocEntry, asRef bool) (tea.Model, tea.Cmd) {
+	kind, text := "section", ""
+	if asRef {
+		kind, text = "ref", "@"+m.previewRelPath()+"#"+headingSlug(heading.Title)
+	} else if source, err := os.ReadFile(m.previewPath); err == nil {
+		text = markdownSection(string(source), heading)
+	}
+	switch {
+	case text == "":
+		m.statusMessage = "Section not found"
+	case m.copyText(kind, text) != nil:
+		m.statusMessage = "Clipboard unavailable"
+	case asRef:
+		m.statusMessage = "Copied " + text
+	default:
+		m.statusMessage = fmt.Sprintf("Copied section %s (%s)", heading.Title, tokens.Format(tokens.Estimate(text)))
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// renderTOC renders the headings shown in place of the tree, marking the section
+// at the top of the preview
+func (m Model) renderTOC(width, height int) string {
+	lines := []string{styles.Header.Render("Contents"), ""}
+
+	visible := max(1, height-len(lines))
+	start := 0
+	if m.tocCursor >= visible {
+		start = m.tocCursor - visible + 1
+	}
+	current := m.currentTOCEntry()
+	for i := start; i < len(m.tocEntries) && i < start+visible; i++ {
+		e := m.tocEntries[i]
+		marker := "  "
+		if i == current {
+			marker = "▸ "
+		}
+		line := ansi.Truncate(marker+strings.Repeat("  ", e.Level-1)+e.Title, width, "…")
+		switch {
+		case i == m.tocCursor:
+			lines = append(lines, styles.Selected.Render(line+strings.Repeat(" ", max(0, width-ansi.StringWidth(line)))))
+		case i == current:
+			lines = append(lines, styles.Title.Render(line))
+		case e.Level == 1:
+			lines = append(lines, styles.Normal.Render(line))
+		default:
+			lines = append(lines, styles.Muted.Render(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,8 +1,12 @@
 package app
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // ToggleExpand expands or collapses a directory entry
@@ -20,12 +24,12 @@ func toggleExpandRecursive(entries []Entry, path, rootPath string, showDotfiles
 				entries[i].Children = nil
 			} else {
 				entries[i].Expanded = true
-				entries[i].Children = LoadDirectoryWithRoot(path, rootPath, e.Depth+1, showDotfiles)
+				entries[i].Children = LoadDirectoryWithRoot(path, childRoot(e, rootPath), e.Depth+1, showDotfiles)
 			}
 			return entries
 		}
 		if e.Expanded && len(e.Children) > 0 {
-			entries[i].Children = toggleExpandRecursive(e.Children, path, rootPath, showDotfiles)
+			entries[i].Children = toggleExpandRecursive(e.Children, path, childRoot(e, rootPath), showDotfiles)
 		}
 	}
 	return entries
@@ -52,22 +56,161 @@ func collapseRecursive(entries []Entry, path string) []Entry {
 	return entries
 }
 
+// CollapseAll collapses every directory in the tree
+func (m Model) CollapseAll() Model {
+	m.entries = collapseAllRecursive(m.entries)
+	m.InvalidateTreeCache()
+	m.cursor = 0
+	return m
+}
+
+func collapseAllRecursive(entries []Entry) []Entry {
+	for i := range entries {
+		entries[i].Expanded = false
+		entries[i].Children = nil
+	}
+	return entries
+}
+
+// maxExpandEntries bounds how many entries a single expand-to-depth loads
+const maxExpandEntries = 5000
+
+// ExpandToDepth expands directories up to depth levels below the root and collapses deeper ones
+// Levels are loaded breadth-first; returns false if it stopped early at maxExpandEntries.
+func (m Model) ExpandToDepth(depth int) (Model, bool) {
+	budget := maxExpandEntries
+	for level := 0; level < depth && budget > 0; level++ {
+		m.entries = expandLevelRecursive(m.entries, level, m.rootPath, m.showDotfiles, &budget)
+	}
+	m.entries = collapseBelowRecursive(m.entries, depth)
+	m.InvalidateTreeCache()
+	return m, budget > 0
+}
+
+func expandLevelRecursive(entries []Entry, level int, rootPath string, showDotfiles bool, budget *int) []Entry {
+	for i, e := range entries {
+		if !e.IsDir || e.Denied {
+			continue
+		}
+		if e.Depth == level {
+			if !e.Expanded && *budget > 0 {
+				entries[i].Expanded = true
+				entries[i].Children = LoadDirectoryWithRoot(e.Path, childRoot(e, rootPath), e.Depth+1, showDotfiles)
+				*budget -= len(entries[i].Children)
+			}
+		} else if e.Expanded && e.Depth < level {
+			entries[i].Children = expandLevelRecursive(e.Children, level, childRoot(e, rootPath), showDotfiles, budget)
+		}
+	}
+	return entries
+}
+
+func collapseBelowRecursive(entries []Entry, depth int) []Entry {
+	for i, e := range entries {
+		if !e.Expanded {
+			continue
+		}
+		if e.Depth >= depth {
+			entries[i].Expanded = false
+			entries[i].Children = nil
+		} else {
+			entries[i].Children = collapseBelowRecursive(e.Children, depth)
+		}
+	}
+	return entries
+}
+
+// ExpandedDepth returns how many levels below the root are currently expanded
+func (m Model) ExpandedDepth() int {
+	depth := 0
+	for _, e := range m.FlatEntries() {
+		if e.IsDir && e.Expanded && e.Depth+1 > depth {
+			depth = e.Depth + 1
+		}
+	}
+	return depth
+}
+
+// CollapseSiblings collapses the directories next to path, keeping path itself open
+func (m Model) CollapseSiblings(path string) Model {
+	parent := filepath.Dir(path)
+	if parent == m.rootPath {
+		m.entries = collapseOthers(m.entries, path)
+	} else {
+		m.entries = collapseSiblingsRecursive(m.entries, parent, path)
+	}
+	m.InvalidateTreeCache()
+	return m
+}
+
+func collapseSiblingsRecursive(entries []Entry, parent, keep string) []Entry {
+	for i, e := range entries {
+		if !e.Expanded {
+			continue
+		}
+		if e.Path == parent {
+			entries[i].Children = collapseOthers(e.Children, keep)
+			return entries
+		}
+		if strings.HasPrefix(parent, e.Path+string(filepath.Separator)) {
+			entries[i].Children = collapseSiblingsRecursive(e.Children, parent, keep)
+			return entries
+		}
+	}
+	return entries
+}
+
+func collapseOthers(entries []Entry, keep string) []Entry {
+	for i, e := range entries {
+		if e.Path != keep && e.Expanded {
+			entries[i].Expanded = false
+			entries[i].Children = nil
+		}
+	}
+	return entries
+}
+
+// restoreCursor moves the cursor to path, or its nearest visible ancestor
+func (m *Model) restoreCursor(path string) {
+	flat := m.FlatEntries()
+	for p := path; p != m.rootPath && p != filepath.Dir(p); p = filepath.Dir(p) {
+		for i, e := range flat {
+			if e.Path == p {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	m.cursor = 0
+}
+
 // NavigateToFile expands parent directories and moves cursor to a file
+// Files of an extra root are given relative to the project root too, e.g. "../web/app.ts".
 func (m Model) NavigateToFile(relPath string) Model {
-	parts := strings.Split(relPath, string(filepath.Separator))
+	// Paths from docs and links use forward slashes
+	relPath = filepath.FromSlash(relPath)
 	currentPath := m.rootPath
+	fullPath := filepath.Join(m.rootPath, relPath)
+	if r := m.extraRootFor(fullPath); r != nil {
+		// Walk down from the extra root's node
+		m.entries = expandPath(m.entries, r.Path, m.rootPath, m.showDotfiles)
+		currentPath = r.Path
+		relPath, _ = filepath.Rel(r.Path, fullPath)
+	}
+	parts := strings.Split(relPath, string(filepath.Separator))
 
-	// Expand each directory in the path
+	// Expand each directory in the path, listing the pages of large ones it is on
+	m.entries = revealEntry(m.entries, fullPath)
 	for i := 0; i < len(parts)-1; i++ {
 		currentPath = filepath.Join(currentPath, parts[i])
 		m.entries = expandPath(m.entries, currentPath, m.rootPath, m.showDotfiles)
+		m.entries = revealEntry(m.entries, fullPath)
 	}
 
 	// Invalidate cache since we may have expanded directories
 	m.InvalidateTreeCache()
 
 	// Find the file in the flat list and set cursor
-	fullPath := filepath.Join(m.rootPath, relPath)
 	flat := m.FlatEntries()
 	for i, e := range flat {
 		if e.Path == fullPath {
@@ -83,12 +226,151 @@ func expandPath(entries []Entry, path, rootPath string, showDotfiles bool) []Ent
 	for i, e := range entries {
 		if e.Path == path && e.IsDir && !e.Expanded {
 			entries[i].Expanded = true
-			entries[i].Children = LoadDirectoryWithRoot(path, rootPath, e.Depth+1, showDotfiles)
+			entries[i].Children = LoadDirectoryWithRoot(path, childRoot(e, rootPath), e.Depth+1, showDotfiles)
 			return entries
 		}
 		if e.Expanded && len(e.Children) > 0 {
-			entries[i].Children = expandPath(e.Children, path, rootPath, showDotfiles)
+			entries[i].Children = expandPath(e.Children, path, childRoot(e, rootPath), showDotfiles)
 		}
 	}
 	return entries
 }
+
+// ensureTreeCursorVisible scrolls the tree viewport so the cursor is on screen
+func (m *Model) ensureTreeCursorVisible() {
+	if m.cursor < m.treeOffset {
+		m.treeOffset = m.cursor
+	} else if m.cursor >= m.treeOffset+m.tree.Height {
+		m.treeOffset = m.cursor - m.tree.Height + 1
+	}
+	m.clampTreeOffset()
+}
+
+// scrollTree moves the tree window by delta rows
+func (m *Model) scrollTree(delta int) {
+	m.treeOffset += delta
+	m.clampTreeOffset()
+}
+
+// clampTreeOffset keeps the tree window within the flattened entries
+func (m *Model) clampTreeOffset() {
+	if maxOffset := len(m.FlatEntries()) - m.tree.Height; m.treeOffset > maxOffset {
+		m.treeOffset = maxOffset
+	}
+	if m.treeOffset < 0 {
+		m.treeOffset = 0
+	}
+}
+
+// treeWindow returns the range of flattened entries shown in the tree pane
+func (m Model) treeWindow(total int) (int, int) {
+	start := m.treeOffset
+	if start > total-m.tree.Height {
+		start = total - m.tree.Height
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start, min(start+m.tree.Height, total)
+}
+
+// cursorPath returns the path of the entry under the cursor
+func (m Model) cursorPath() string {
+	flat := m.FlatEntries()
+	if m.cursor < len(flat) {
+		return flat[m.cursor].Path
+	}
+	return ""
+}
+
+// expandTreeTo expands the tree to depth levels, keeping the cursor on its entry
+func (m Model) expandTreeTo(depth int) (tea.Model, tea.Cmd) {
+	path := m.cursorPath()
+	m, complete := m.ExpandToDepth(depth)
+	status := fmt.Sprintf("Expanded to depth %d", depth)
+	if !complete {
+		status += fmt.Sprintf(" (stopped at %d entries)", maxExpandEntries)
+	}
+	return m.afterTreeReshape(path, status)
+}
+
+// afterTreeReshape restores the cursor and refreshes the tree after bulk expand/collapse
+func (m Model) afterTreeReshape(path, status string) (tea.Model, tea.Cmd) {
+	m.restoreCursor(path)
+	m.ensureTreeCursorVisible()
+	m.statusMessage = status
+	m.statusMessageTime = time.Now()
+
+	var cmd tea.Cmd
+	if m.cursorPath() != path {
+		m, cmd = m.UpdatePreview()
+	}
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
+
+// treePageSize is how many entries of a directory are listed before the rest are
+// held back in a "more" node, so huge generated folders don't flood the tree
+const treePageSize = 500
+
+// pageEntries holds back the entries of dir past the first page in a "more" node
+func pageEntries(entries []Entry, dir string, depth int) []Entry {
+	if len(entries) <= treePageSize {
+		return entries
+	}
+	rest := append([]Entry(nil), entries[treePageSize:]...)
+	more := Entry{
+		Name:     fmt.Sprintf("… %s more (press x to load)", formatCount(len(rest))),
+		Path:     filepath.Join(dir, "\x00more"),
+		Depth:    depth,
+		More:     len(rest),
+		Children: rest,
+	}
+	return append(entries[:treePageSize:treePageSize], more)
+}
+
+// LoadMore lists the next page of a large directory in place of its "more" node
+func (m Model) LoadMore(path string) Model {
+	m.entries = loadMoreEntries(m.entries, path)
+	m.InvalidateTreeCache()
+	return m
+}
+
+func loadMoreEntries(entries []Entry, path string) []Entry {
+	for i, e := range entries {
+		if e.More > 0 && e.Path == path {
+			// The extra roots may follow the project's "more" node
+			return append(append(entries[:i:i], pageEntries(e.Children, filepath.Dir(path), e.Depth)...), entries[i+1:]...)
+		}
+		if e.Expanded && len(e.Children) > 0 {
+			entries[i].Children = loadMoreEntries(e.Children, path)
+		}
+	}
+	return entries
+}
+
+// revealEntry lists all held back entries of the directory holding path (or one of
+// its parents), so it can be selected
+func revealEntry(entries []Entry, path string) []Entry {
+	for i, e := range entries {
+		if e.More > 0 {
+			for _, c := range e.Children {
+				if isWithin(path, c.Path) {
+					return append(append(entries[:i:i], e.Children...), entries[i+1:]...)
+				}
+			}
+		}
+		if e.Expanded && len(e.Children) > 0 {
+			entries[i].Children = revealEntry(e.Children, path)
+		}
+	}
+	return entries
+}
+
+// formatCount formats n with thousands separators, like 4,200
+func formatCount(n int) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
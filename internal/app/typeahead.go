@@ -0,0 +1,93 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	typeAheadKey     = "F"             // Starts type-ahead in the tree
+	typeAheadTimeout = 1 * time.Second // Idle time before typing starts a new prefix
+)
+
+// TypeAheadExpiredMsg resets the type-ahead prefix after the timeout, if no key came in since
+type TypeAheadExpiredMsg struct {
+	Seq int
+}
+
+// typeAheadExpireAfter schedules the timeout for the current keystroke
+func (m Model) typeAheadExpireAfter() tea.Cmd {
+	seq := m.typeAheadSeq
+	return tea.Tick(typeAheadTimeout, func(time.Time) tea.Msg {
+		return TypeAheadExpiredMsg{Seq: seq}
+	})
+}
+
+// startTypeAhead begins collecting a name prefix to jump to
+func (m Model) startTypeAhead() (tea.Model, tea.Cmd) {
+	m.activePane = TreePane
+	m.typeAhead = true
+	m.typeAheadPrefix = ""
+	m.typeAheadSeq++
+	return m, nil
+}
+
+// handleTypeAheadKey extends the prefix and jumps to the next matching entry
+func (m Model) handleTypeAheadKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.typeAheadSeq++
+
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyEnter:
+		m.typeAhead = false
+		m.typeAheadPrefix = ""
+		return m.UpdatePreview()
+
+	case tea.KeyBackspace:
+		if len(m.typeAheadPrefix) > 0 {
+			runes := []rune(m.typeAheadPrefix)
+			m.typeAheadPrefix = string(runes[:len(runes)-1])
+		}
+		return m, m.typeAheadExpireAfter()
+
+	case tea.KeyCtrlN, tea.KeyTab:
+		// Next entry matching the same prefix
+		m.jumpToPrefix(m.cursor + 1)
+		return m, m.typeAheadExpireAfter()
+
+	case tea.KeyRunes, tea.KeySpace:
+		m.typeAheadPrefix += string(msg.Runes)
+		m.jumpToPrefix(m.cursor)
+		return m, m.typeAheadExpireAfter()
+	}
+
+	// Any other key ends type-ahead and is handled normally
+	m.typeAhead = false
+	m.typeAheadPrefix = ""
+	return m.Update(msg)
+}
+
+// jumpToPrefix moves the cursor to the first visible entry at or after from whose
+// name starts with the prefix (falling back to a substring match), wrapping around
+func (m *Model) jumpToPrefix(from int) {
+	if m.typeAheadPrefix == "" {
+		return
+	}
+	flat := m.FlatEntries()
+	prefix := strings.ToLower(m.typeAheadPrefix)
+
+	for _, match := range []func(string) bool{
+		func(name string) bool { return strings.HasPrefix(name, prefix) },
+		func(name string) bool { return strings.Contains(name, prefix) },
+	} {
+		for i := 0; i < len(flat); i++ {
+			idx := (from + i) % len(flat)
+			if match(strings.ToLower(flat[idx].Name)) {
+				m.cursor = idx
+				m.ensureTreeCursorVisible()
+				return
+			}
+		}
+	}
+}
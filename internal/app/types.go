@@ -1,13 +1,22 @@
 package app
 
 import (
+	"context"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/backups"
+	"github.com/connorleisz/contexTUI/internal/basket"
+	"github.com/connorleisz/contexTUI/internal/cache"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/frecency"
 	"github.com/connorleisz/contexTUI/internal/git"
 	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/imports"
+	"github.com/connorleisz/contexTUI/internal/notes"
 	"github.com/connorleisz/contexTUI/internal/terminal"
 	"github.com/fsnotify/fsnotify"
 )
@@ -23,6 +32,8 @@ const (
 // Model is the main application model implementing tea.Model
 type Model struct {
 	rootPath       string
+	extraRoots     []extraRoot   // More directories from the command line, as top-level tree nodes
+	config         config.Config // Loaded user config (source for settings saved back)
 	entries        []Entry
 	cursor         int
 	activePane     Pane
@@ -30,45 +41,129 @@ type Model struct {
 	preview        viewport.Model
 	previewContent string
 	previewPath    string
-	previewCache   map[string]CachedPreview // filepath -> cached rendered content
+	previewTokens  int                                 // Estimated tokens of the previewed file (0 for directories and images)
+	previewPerm    string                              // Permissions of the previewed file, like -rwxr-xr-x
+	previewLink    string                              // Target of the previewed symlink
+	previewCache   *cache.Cache[string, CachedPreview] // filepath -> cached rendered content
+	structured     *structuredDoc                      // Foldable JSON/YAML preview (nil for other files)
 	loading        bool
 	width          int
 	height         int
 	ready          bool
 	lastClickTime  time.Time
 	lastClickIndex int
-	treeCache      TreeCache // Cached tree data for rendering optimization
+	treeHover      int                   // Tree row under the mouse pointer, -1 when none
+	treeCache      TreeCache             // Cached tree data for rendering optimization
+	treeFilter     map[string]bool       // Doc key files (true) and their parents (false) the tree is limited to, or nil
+	treeFilterDoc  string                // Name of the doc the tree is filtered to
+	treeOffset     int                   // First flattened entry shown in the tree pane
+	treeLines      map[string]treeLine   // Styled tree rows by entry path
+	panes          map[string]framedPane // Bordered panes by name, reused while unchanged
 
 	// Pane resizing
 	splitRatio    float64 // 0.2 to 0.8, left pane width ratio
+	layout        string  // layout* constant: both panes, or one zoomed
 	draggingSplit bool    // True when dragging the divider
 
 	// Fuzzy finder
 	searching            bool
 	searchInput          textinput.Model
 	searchResults        []SearchResult
+	frecency             frecency.Store // Files opened from the tree or search, ranking results
 	searchCursor         int
-	searchScrollOffset   int      // Scroll offset for search results viewport
-	lastSearchQuery      string   // Previous query to detect changes
-	pendingSearchQuery   string   // Query waiting for debounce
-	searchDebounceActive bool     // Whether a debounce timer is pending
-	allFiles             []string // Flat list of all file paths for searching
+	searchScrollOffset   int       // Scroll offset for search results viewport
+	lastSearchQuery      string    // Previous query to detect changes
+	pendingSearchQuery   string    // Query waiting for debounce
+	searchDebounceActive bool      // Whether a debounce timer is pending
+	lastSearchKeyTime    time.Time // When the query last changed (detects typing bursts)
+	allFiles             []string  // Flat list of all file paths for searching
+	filesCrawled         bool      // allFiles comes from a crawl, not the saved index
+	deniedPaths          []string  // Paths skipped while indexing due to permissions
+	showingErrors        bool      // True when the errors overlay is visible
 
 	// Context docs (documentation-first)
-	docRegistry        *groups.ContextDocRegistry // Doc-based context docs
-	showingDocs        bool                       // True when docs overlay is visible
-	selectedCategory   int                        // Index of selected category (for filtering)
-	docCursor          int                        // Selected doc in current category view
-	docsScrollOffset   int                        // Scroll offset for docs overlay
-	selectedDocs       map[string]bool            // Selected docs for multi-copy (keyed by filepath)
-	addingDoc          bool                       // True when in "add doc" mode
-	availableMdFiles   []string                   // .md files available to add
-	addDocCursor       int                        // Cursor in add doc picker
-	addDocScroll       int                        // Scroll offset in add doc picker
-	selectedAddFiles   map[string]bool            // Selected files for multi-add
+	docRegistry      *groups.ContextDocRegistry // Doc-based context docs
+	outdatedTags     []string                   // Docs whose structure tags need updating (u in the docs panel)
+	showingDocs      bool                       // True when docs overlay is visible
+	selectedCategory int                        // Index of selected category (for filtering)
+	docCursor        int                        // Selected doc in current category view
+	docsScrollOffset int                        // Scroll offset for docs overlay
+	selectedDocs     map[string]bool            // Selected docs for multi-copy (keyed by filepath)
+	addingDoc        bool                       // True when in "add doc" mode
+	availableMdFiles []string                   // .md files available to add
+	addDocCursor     int                        // Cursor in add doc picker
+	addDocScroll     int                        // Scroll offset in add doc picker
+	selectedAddFiles map[string]bool            // Selected files for multi-add
+	docsSearchInput  textinput.Model            // Filter prompt ('/')
+	docsQuery        string                     // Filter across all categories, or ""
+
+	// Doc reader (full-screen, opened from the docs overlay)
+	showingReader    bool              // True when the doc reader is visible
+	readerDoc        groups.ContextDoc // Doc being read
+	readerLines      []string          // Rendered doc
+	readerScroll     int               // First visible line
+	readerIndexFocus bool              // Whether j/k move through the key files index
+	readerCursor     int               // Selected key file in the index
+
+	// Category manager (over the docs overlay)
+	managingCategories bool            // True when the category manager is visible
+	categoryMode       string          // What the manager is doing (category* constants)
+	categoryCursor     int             // Selected category
+	categoryInput      textinput.Model // Name prompt for new and renamed categories
+	categoryFrom       string          // ID of the category being deleted
+	categoryMoveDocs   []string        // Docs being moved to another category
+	categoryError      string          // Why the last change was refused
+
+	// Right-click menu on a tree row, drawn at the pointer
+	showingContextMenu bool
+	contextMenuPath    string // Entry the menu acts on
+	contextMenuX       int
+	contextMenuY       int
+	contextMenuCursor  int
+	contextMenuDocs    bool // Picking the doc to add the file to
+
+	// First-run setup, shown when a project has no .context-docs.md
+	showingOnboarding bool
+	onboardStep       int // onboard* constant
+	onboardFiles      []string
+	onboardPicked     map[string]bool
+	onboardCategories []onboardCategory
+	onboardCursor     int
+
+	// Asked what to do when .context-docs.md changed on disk before a save
+	showingRegistryConflict bool
+
+	// Changes to the registry and docs u and ctrl+r take back and make again
+	undoStack []backups.Snapshot
+	redoStack []backups.Snapshot
+
+	// Edits to doc markdown, previewed as a diff before they are written
+	showingDocEdits bool
+	docEdits        []docEdit // Waiting to be accepted or skipped, the first shown
+	docEditScroll   int
+	docEditsWritten int    // Counted for the status once the last edit is done
+	docEditsSkipped int    // Counted for the status once the last edit is done
+	docEditError    string // Why the last failed edit wasn't written
+
+	// Doc commands (**Verify:**, **Test:** and **Build:** metadata)
+	showingVerify bool                    // True when the command overlay is visible
+	verifyDoc     groups.ContextDoc       // Doc whose command is shown
+	verifyKind    string                  // Which of the doc's commands is shown (groups.Hook*)
+	verifyRunning bool                    // Whether a command is still running
+	verifyRunKey  string                  // Result key of the running command
+	verifyRunID   int64                   // Identifies the running command's output
+	verifyCancel  context.CancelFunc      // Stops the running command
+	verifyOutput  []string                // Output of the running command so far
+	verifyConfirm bool                    // Waiting for the user to trust the command
+	verifyScroll  int                     // Scroll offset for command output
+	verifyResults map[string]VerifyResult // Last result per doc command (session only)
 
 	// File watcher
-	watcher *fsnotify.Watcher
+	watcher         *fsnotify.Watcher
+	fsReloadPending bool            // Whether a debounced reload timer is pending
+	fsLastEvent     time.Time       // When the most recent fs event arrived
+	fsDebounceDelay time.Duration   // Current reload delay (grows during bursts)
+	watchedStale    map[string]bool // Docs made stale by key file edits seen this session
 
 	// Copy mode with custom selection
 	selectMode   bool
@@ -77,25 +172,32 @@ type Model struct {
 	selectEnd    int      // Line where selection currently ends
 	previewLines []string // Content split by lines for selection/copy
 	scrollDir    int      // -1 for up, 0 for none, 1 for down (for continuous scroll)
+	selectCursor int      // Keyboard cursor line in copy mode
+	visualLine   bool     // True while V extends the selection with the cursor
 
 	// Git integration
 	isGitRepo       bool
-	gitRepoRoot     string                    // Git repo root (may differ from rootPath)
-	gitStatus       map[string]git.FileStatus // relPath -> status
-	gitDirStatus    map[string]string         // dir relPath -> aggregated status indicator
-	gitStatusMode   bool                      // True when showing git status view
-	gitStatusCursor int                       // Cursor in git status view
-	gitChanges      []git.FileStatus          // Flat list of all changes for git view
-	gitList         viewport.Model            // Scrollable git file list viewport
-	diffCache       map[DiffCacheKey]CachedDiff // Cache for diff content
-	diffRequestID   int64                     // Current diff request ID for cancellation
-	fullDiffLoading string                    // Path of file whose full diff is loading
-	fullDiffStaged  bool                      // Whether the loading full diff is staged
-	gitBranch       string                    // Current branch name
-	gitAhead        int                       // Commits ahead of upstream
-	gitBehind       int                       // Commits behind upstream
-	gitHasUpstream  bool                      // Whether branch has upstream configured
-	gitFetching     bool                      // True while fetch is in progress
+	gitRepoRoot     string                                 // Git repo root (may differ from rootPath)
+	gitStatus       map[string]git.FileStatus              // relPath -> status
+	gitDirStatus    map[string]string                      // dir relPath -> aggregated status indicator
+	gitStatusMode   bool                                   // True when showing git status view
+	gitStatusCursor int                                    // Row of the cursor in git status view (see gitRows)
+	gitFolds        map[string]bool                        // Folded directories of the git status list, by gitFoldKey
+	gitChanges      []git.FileStatus                       // Flat list of all changes for git view
+	gitList         viewport.Model                         // Scrollable git file list viewport
+	diffCache       *cache.Cache[DiffCacheKey, CachedDiff] // Cache for diff content
+	diffRequestID   int64                                  // Current diff request ID for cancellation
+	fullDiffLoading string                                 // Path of file whose full diff is loading
+	fullDiffStaged  bool                                   // Whether the loading full diff is staged
+	gitBranch       string                                 // Current branch name
+	gitAhead        int                                    // Commits ahead of upstream
+	gitBehind       int                                    // Commits behind upstream
+	gitHasUpstream  bool                                   // Whether branch has upstream configured
+	gitFetching     bool                                   // True while fetch is in progress
+	gitRefreshing   bool                                   // True while a background status refresh runs
+	gitStashMode    bool                                   // Git view lists stashes instead of changes
+	gitStashes      []git.Stash                            // Stashes, nil while loading
+	gitStashCursor  int                                    // Selected stash
 
 	// Help overlay
 	showingHelp      bool // True when help overlay is visible
@@ -104,6 +206,152 @@ type Model struct {
 	// Dotfile visibility
 	showDotfiles bool // True when dotfiles are visible in tree
 
+	// Preview wrapping (w toggles; unwrapped previews pan horizontally)
+	previewNoWrap  bool
+	previewXOffset int
+
+	// Blame annotations in the file preview (b toggles)
+	previewBlame bool
+
+	// Changes since the last commit in place of the file preview (d in the preview pane)
+	previewDiff bool
+
+	// Preview rendering runs on a worker pool; results of older requests are dropped
+	highlighter      *highlightPool
+	previewRequestID int64
+	previewPartial   bool           // Showing the first part of a big file while the rest renders
+	previewStream    *previewStream // Big file loaded chunk by chunk as it scrolls
+
+	// The previewed file is reloaded when it changes on disk
+	previewChanged       bool // Changed since the last refresh, reloaded with the next one
+	previewReloaded      bool // Shows "changed on disk" in the header until another file is previewed
+	previewRestoreOffset int  // Scroll position to restore once the reloaded preview arrives
+
+	// Per-file history (G): commits touching the file, each commit's diff in the preview
+	fileHistoryMode    bool
+	fileHistoryPath    string // Absolute path of the file
+	fileHistoryCommits []git.FileCommit
+	fileHistoryCursor  int
+
+	// Table of contents of a markdown preview (t), shown in place of the tree
+	tocMode    bool
+	tocEntries []tocEntry
+	tocCursor  int
+
+	// Theme selection
+	themeName     string   // Configured theme name (empty = default)
+	showingThemes bool     // True when theme picker overlay is visible
+	themeNames    []string // Available themes listed in the picker
+	themeCursor   int      // Cursor in theme picker
+
+	// Preview regions (m{a-z} in copy mode), kept for the session
+	regions           []Region
+	pendingRegionMark bool // Waiting for the region name after m
+	showingRegions    bool // True when the regions overlay is visible
+	regionCursor      int
+
+	// Release notes picker (n in git status view)
+	showingReleases bool     // True when the release notes overlay is visible
+	releaseRefs     []string // HEAD followed by tags, newest first
+	releaseCursor   int
+	releaseBase     string // Explicit start of the range (empty = previous tag)
+
+	// Everything copied this session, oldest first (Y shows it newest first)
+	copyHistory        []CopyEntry
+	showingCopyHistory bool
+	copyHistoryCursor  int
+
+	// Files, docs, selections and diffs staged to copy together (A), kept in .contextui/basket.json
+	basket        []basket.Item
+	showingBasket bool
+	basketCursor  int
+
+	// Writing a basket or doc group to a file instead of the clipboard
+	showingExport  bool
+	exportInput    textinput.Model
+	exportWhat     string // What is written, e.g. "basket"
+	exportPayload  string
+	exportConfirm  bool // The file exists and enter again overwrites it
+	exportError    string
+	lastExportPath string // Suggested next time, as typed
+
+	// Actions waiting for a second press under the confirm policy (see confirmAgain)
+	pendingConfirm string // Asked by the last key
+	confirmArmed   string // Asked by the key before this one
+
+	// Notes on files and folders (e), kept in .contextui/notes.json
+	notes       notes.Store
+	showingNote bool
+	noteInput   textinput.Model
+	notePath    string // Relative to the root
+
+	// Links of the previewed markdown file (enter in the preview pane), or issue references (#)
+	showingLinks bool
+	linksTitle   string
+	previewLinks []previewLink
+	linkCursor   int
+	issueRules   []issueRule // Issue reference patterns (see loadIssueRules)
+
+	// Render caches, and the latest errors, git commands and watcher events (I)
+	showingDebug bool
+	errorBanners []debuglog.Entry // Reported failures shown in the footer until dismissed
+
+	// Command line options, and the --select file until the tree has loaded
+	options       Options
+	readOnly      bool // -read-only or this project's readOnly config
+	pendingSelect string
+	chosen        string // Path picked in choose mode
+
+	// Command runner overlay (!)
+	showingCommand    bool
+	commandInput      textinput.Model
+	commandLine       string   // Command whose output is shown
+	commandHistory    []string // Commands run this session, oldest first
+	commandHistoryIdx int      // Position while recalling history with up/down
+	commandRunning    bool
+	commandRunID      int64
+	commandCancel     context.CancelFunc
+	commandOutput     []string
+	commandErr        error
+	commandDuration   time.Duration
+	commandScroll     int
+	commandFollow     bool // Keep the end of the output in view as it streams
+
+	// Badge tree entries by context doc coverage (u)
+	showCoverage bool
+
+	// Recent projects switcher (P)
+	showingProjects bool
+	projects        []string // Recently opened roots, most recent first
+	projectCursor   int
+
+	// Related files overlay (i: imports, C: co-changed), with copy and add-to-doc actions
+	showingRelated   bool
+	relatedTitle     string
+	relatedFile      string // File the overlay was opened on, relative to root
+	relatedEntries   []relatedEntry
+	relatedCursor    int
+	relatedSelected  map[string]bool
+	relatedLoading   bool
+	relatedPickDoc   bool // Choosing the doc that receives the files
+	relatedDocCursor int
+	importGraph      *imports.Graph // Built on first use, dropped when the file list reloads
+
+	// Adding a copy-mode selection to a doc's Examples (e in copy mode)
+	showingSnippetDocs bool
+	snippet            pendingSnippet
+	snippetDocCursor   int
+
+	// Type-ahead jump in the tree (F, then type a name prefix)
+	typeAhead       bool
+	typeAheadPrefix string
+	typeAheadSeq    int // Bumped per keystroke so stale timeouts are ignored
+
+	// Tree marks (m{a-z} to set, '{a-z} to jump)
+	marks          map[string]string // Mark name -> path relative to root
+	pendingMarkKey string            // "m" or "'" while waiting for the mark name
+	lastJumpPath   string            // Position before the last jump, for ''
+
 	// Status message (transient feedback)
 	statusMessage     string
 	statusMessageTime time.Time
@@ -122,17 +370,19 @@ type Model struct {
 	fileOpInput        textinput.Model // Text input for name entry
 	fileOpTargetPath   string          // Path being operated on
 	fileOpError        string          // Error message to display
-	fileOpConfirm      bool            // True when showing delete confirmation
+	fileOpConfirm      bool            // True when waiting for enter again to confirm
 	fileOpScrollOffset int             // Scroll offset for long paths/errors
 	fileOpSourcePath   string          // Source path for import operation
+	fileOpCompletions  []string        // Directory completions cycled by tab, nil when not completing
+	fileOpCompletion   int             // Index of the completion in the input
 
 	// Terminal capabilities
 	termCaps terminal.Capabilities
 
 	// Image preview
-	previewIsImage bool                    // True when previewing an image
-	currentImage   *ImageLoadedMsg         // Current image preview data
-	imageCache     map[string]CachedImage  // Path -> cached image render
+	previewIsImage bool                              // True when previewing an image
+	currentImage   *ImageLoadedMsg                   // Current image preview data
+	imageCache     *cache.Cache[string, CachedImage] // Path -> cached image render
 
 	// Image overlay mode (full-screen Kitty rendering)
 	imageOverlayMode bool   // Whether image overlay is active
@@ -164,19 +414,32 @@ type SearchDebounceMsg struct{}
 
 // FileLoadedMsg is sent when file content is loaded
 type FileLoadedMsg struct {
-	Path    string
-	Content string
-	ModTime time.Time // For cache validation
+	Path       string
+	Content    string
+	ModTime    time.Time      // For cache validation
+	Structured *structuredDoc // Set for foldable JSON/YAML previews
+	Blame      bool           // Rendered with blame annotations
+	RequestID  int64          // Set by the highlight pool; stale IDs are ignored
+	Partial    bool           // First part of a big file; the full render follows
+	More       int64          // Byte offset where the unread rest of a big file starts
+	MoreLine   int            // Line number the rest starts at
+
+	next <-chan FileLoadedMsg // Delivers the rest of a partial result
 }
 
 // CachedPreview stores rendered preview content with modification time
 type CachedPreview struct {
-	Content string
-	ModTime time.Time
+	Content    string
+	ModTime    time.Time
+	Structured *structuredDoc // Fold state is kept with the cached preview
+	More       int64          // Big files: where streaming continues
+	MoreLine   int
 }
 
 // FsEventMsg is sent when filesystem changes
-type FsEventMsg struct{}
+type FsEventMsg struct {
+	Paths []string // Changed paths, relative to the root
+}
 
 // WatchNextMsg is sent to continue watching after an event
 type WatchNextMsg struct{}
@@ -186,6 +449,25 @@ type GitFetchDoneMsg struct {
 	Err error
 }
 
+// CommandOutputMsg carries output of a running shell command, and its result once it finishes
+type CommandOutputMsg struct {
+	RunID    int64
+	Lines    []string
+	Done     bool
+	Err      error
+	Duration time.Duration
+
+	next tea.Cmd // Waits for the following batch
+}
+
+// VerifyResult records the outcome of a doc's command
+type VerifyResult struct {
+	Passed   bool
+	Output   string
+	Err      error
+	Duration time.Duration
+}
+
 // QuickDiffLoadedMsg is sent when the quick (small context) diff is ready
 type QuickDiffLoadedMsg struct {
 	Path      string
@@ -223,7 +505,8 @@ type SaveRegistryMsg struct{}
 
 // RegistrySavedMsg signals save completion
 type RegistrySavedMsg struct {
-	Err error
+	Err   error
+	Saved groups.SavedState // What the saved copy now matches on disk
 }
 
 // ScheduleRegistrySave returns a command that fires after debounce delay
@@ -258,21 +541,28 @@ func ScheduleFsReload(delay time.Duration) tea.Cmd {
 
 // DirectoryLoadedMsg is sent when directory entries are loaded asynchronously
 type DirectoryLoadedMsg struct {
+	Root    string // Project root that was loaded (results for a previous project are ignored)
 	Entries []Entry
 }
 
 // AllFilesLoadedMsg is sent when all files list is collected asynchronously
 type AllFilesLoadedMsg struct {
-	Files []string
+	Root   string // Project root that was loaded
+	Files  []string
+	Denied []string // Paths skipped due to permissions
+	Saved  bool     // From the index saved by the last session, not a crawl
 }
 
 // RegistryLoadedMsg is sent when doc registry is loaded asynchronously
 type RegistryLoadedMsg struct {
+	Root     string // Project root that was loaded
 	Registry *groups.ContextDocRegistry
+	Outdated []string // Docs whose structure tags are out of date, left for the user to update
 }
 
 // GitStatusLoadedMsg is sent when git status is loaded asynchronously
 type GitStatusLoadedMsg struct {
+	Root        string // Project root that was loaded
 	Status      map[string]git.FileStatus
 	Changes     []git.FileStatus
 	DirStatus   map[string]string
@@ -280,6 +570,17 @@ type GitStatusLoadedMsg struct {
 	Ahead       int
 	Behind      int
 	HasUpstream bool
+	Background  bool // Refreshed on focus or by polling, not part of a load
+}
+
+// GitPollMsg is sent when it is time to refresh git status (gitPollSeconds)
+type GitPollMsg struct{}
+
+// ScheduleGitPoll returns a command that sends a GitPollMsg after d
+func ScheduleGitPoll(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return GitPollMsg{}
+	})
 }
 
 // FileOpMode represents the current file operation
@@ -291,7 +592,9 @@ const (
 	FileOpCreateFolder
 	FileOpRename
 	FileOpDelete
-	FileOpImport // Import file via drag-and-drop
+	FileOpImport    // Import file via drag-and-drop
+	FileOpDuplicate // Copy a file next to itself
+	FileOpChmod     // Change permissions
 )
 
 // FileOpCompleteMsg is sent when a file operation completes
@@ -300,30 +603,36 @@ type FileOpCompleteMsg struct {
 	Success bool
 	Error   error
 	NewPath string // For create/rename, the resulting path
+
+	UpdatedDocs []string // For rename, docs whose Key Files referenced the old path
+	DocsErr     error    // For rename, why the docs' references couldn't all be updated
 }
 
 // ImageLoadedMsg is sent when an image is loaded and rendered
 type ImageLoadedMsg struct {
 	Path       string
-	Width      int       // Original image width in pixels
-	Height     int       // Original image height in pixels
-	RenderW    int       // Rendered width in terminal cells
-	RenderH    int       // Rendered height in terminal cells
-	RenderData string    // Pre-rendered terminal escape sequences or block chars
+	Width      int    // Original image width in pixels
+	Height     int    // Original image height in pixels
+	RenderW    int    // Rendered width in terminal cells
+	RenderH    int    // Rendered height in terminal cells
+	RenderData string // Pre-rendered terminal escape sequences or block chars
 	ModTime    time.Time
 	Error      error
 }
 
 // CachedImage stores pre-rendered image data
 type CachedImage struct {
-	RenderData  string
-	Width       int       // Original image width
-	Height      int       // Original image height
-	RenderW     int       // Rendered width in terminal cells
-	RenderH     int       // Rendered height in terminal cells
-	ViewportW   int       // Viewport width when cached (for invalidation)
-	ViewportH   int       // Viewport height when cached (for invalidation)
-	ModTime     time.Time
+	RenderData string
+	Width      int // Original image width
+	Height     int // Original image height
+	RenderW    int // Rendered width in terminal cells
+	RenderH    int // Rendered height in terminal cells
+	ViewportW  int // Viewport width when cached (for invalidation)
+	ViewportH  int // Viewport height when cached (for invalidation)
+	ModTime    time.Time
+	Protocol   terminal.GraphicsProtocol // Protocol the image was rendered for
+	TrueColor  bool                      // Color profile the image was rendered for
+	Theme      string                    // Theme active when rendered
 }
 
 // Entry represents a file or directory in the tree
@@ -335,6 +644,29 @@ type Entry struct {
 	Expanded bool
 	Children []Entry
 	RelPath  string // Cached relative path from root
+	Denied   bool   // Directory can't be read (permission denied)
+	Link     string // Target of a symlink, as written in the link
+	More     int    // For a "more" node, how many entries it holds back in Children
+	Root     bool   // Node of an extra root; its children are relative to Path
+}
+
+// maxTreeLineCache bounds the styled row cache; it is cleared when full
+const maxTreeLineCache = 20000
+
+// treeLine is a styled tree row and the state it was rendered from
+type treeLine struct {
+	key      string // Unstyled row, badge, cursor and directory flags
+	rendered string
+}
+
+// framedPane is a pane rendered inside its border and what it was rendered from
+// Framing measures and pads every line, so a pane whose content didn't change on
+// a keypress (the tree while scrolling the preview, and the reverse) is reused.
+type framedPane struct {
+	content       string
+	width, height int
+	active        bool
+	rendered      string
 }
 
 // TreeCache stores pre-computed tree data to avoid recomputation on every render
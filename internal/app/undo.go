@@ -0,0 +1,105 @@
+package app
+
+import (
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/backups"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+)
+
+// maxUndo is how many changes u can take back in a session
+const maxUndo = 50
+
+// registryFiles returns the registry files (relative to the root) a change to the
+// docs can touch: the root's, each package's, and every registered doc
+func (m Model) registryFiles() []string {
+	files := []string{".context-docs.md"}
+	if m.docRegistry == nil {
+		return files
+	}
+	for _, pkg := range m.docRegistry.Packages {
+		files = append(files, filepath.Join(pkg, ".context-docs.md"))
+	}
+	for _, doc := range m.docRegistry.Docs {
+		files = append(files, doc.FilePath)
+	}
+	return files
+}
+
+// takeUndo snapshots the registry and docs, plus any other files, before a change
+// that can fail; pushUndo the snapshot once the change is made
+func (m Model) takeUndo(label string, extra ...string) backups.Snapshot {
+	return backups.Take(m.rootPath, label, append(m.registryFiles(), extra...))
+}
+
+// pushUndo makes a snapshot from takeUndo the change u takes back; it is also kept
+// in .contextui/backups
+func (m *Model) pushUndo(snap backups.Snapshot) {
+	if m.repeatUndo(snap.Label) {
+		return
+	}
+	m.undoStack = append(m.undoStack, snap)
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+	}
+	m.redoStack = nil
+	debuglog.Report("back up docs", backups.Save(m.rootPath, snap))
+}
+
+// recordUndo snapshots and pushes at once, for changes made in memory that can't fail
+func (m *Model) recordUndo(label string, extra ...string) {
+	if m.repeatUndo(label) {
+		return
+	}
+	m.pushUndo(m.takeUndo(label, extra...))
+}
+
+// repeatUndo reports whether a change repeats the last one in quick succession, like
+// holding J, and so belongs to the same undo step
+func (m *Model) repeatUndo(label string) bool {
+	n := len(m.undoStack)
+	if n == 0 || m.undoStack[n-1].Label != label || time.Since(m.undoStack[n-1].Time) >= 2*time.Second {
+		return false
+	}
+	m.undoStack[n-1].Time = time.Now()
+	return true
+}
+
+// undoDocChange takes back the last change to the registry or docs (undo), or
+// makes it again after it was taken back (redo)
+func (m Model) undoDocChange(redo bool) (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	from, to := &m.undoStack, &m.redoStack
+	verb := "Undid"
+	if redo {
+		from, to = to, from
+		verb = "Redid"
+	}
+	if len(*from) == 0 {
+		m.statusMessage = "Nothing to undo"
+		if redo {
+			m.statusMessage = "Nothing to redo"
+		}
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	snap := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	current, err := snap.Restore(m.rootPath, m.config.BackupFiles)
+	*to = append(*to, current)
+
+	// A pending save would write the registry as it was before
+	m.registryDirty = false
+	if err != nil {
+		m.statusMessage = "Error: " + err.Error()
+	} else {
+		m.statusMessage = verb + ": " + snap.Label
+	}
+	m.statusMessageTime = time.Now()
+	return m, tea.Batch(ClearStatusAfter(5*time.Second), m.loadRegistryAsync())
+}
@@ -1,21 +1,28 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/basket"
 	"github.com/connorleisz/contexTUI/internal/clipboard"
-	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/control"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/frecency"
 	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/notes"
 	"github.com/connorleisz/contexTUI/internal/terminal"
 	"github.com/connorleisz/contexTUI/internal/ui/styles"
 	"github.com/sahilm/fuzzy"
@@ -32,6 +39,11 @@ func (m *Model) clearAllOverlays() {
 	m.lastSearchQuery = ""
 	m.showingDocs = false
 	m.addingDoc = false
+	m.docsQuery = ""
+	m.docsSearchInput.Blur()
+	m.managingCategories = false
+	m.showingReader = false
+	m.categoryInput.Blur()
 	m.docCursor = 0
 	m.docsScrollOffset = 0
 	m.selectMode = false
@@ -44,25 +56,90 @@ func (m *Model) clearAllOverlays() {
 	m.fileOpError = ""
 	m.fileOpConfirm = false
 	m.fileOpScrollOffset = 0
+	m.fileOpCompletions = nil
+	m.showingThemes = false
+	m.showingVerify = false
+	m.showingDocEdits = false
+	m.docEdits = nil
+	m.showingRegistryConflict = false
+	m.showingOnboarding = false
+	m.showingContextMenu = false
+	m.verifyConfirm = false
+	m.showingErrors = false
+	m.showingRegions = false
+	m.pendingRegionMark = false
+	m.showingReleases = false
+	m.showingCopyHistory = false
+	m.showingBasket = false
+	m.showingExport = false
+	m.exportInput.Blur()
+	m.showingNote = false
+	m.noteInput.Blur()
+	m.showingLinks = false
+	m.showingDebug = false
+	m.showingProjects = false
+	m.showingCommand = false
+	m.showingRelated = false
+	m.showingSnippetDocs = false
+	m.fileHistoryMode = false
+	m.tocMode = false
 }
 
 // Update implements tea.Model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	// Big previews load more of the file whenever scrolling gets near the loaded edge
+	if next, ok := model.(Model); ok {
+		if next, more := next.streamPreview(); more != nil {
+			return next, tea.Batch(cmd, more)
+		}
+	}
+	return model, cmd
+}
+
+// update handles a message; Update wraps it with preview streaming
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	// A confirmation only holds for the very next key
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.confirmArmed, m.pendingConfirm = m.pendingConfirm, ""
+	}
+
 	// Handle filesystem events first (before mode checks) so context docs auto-reload
-	// FsEventMsg just schedules a debounced reload (100ms delay)
-	if _, ok := msg.(FsEventMsg); ok {
+	// FsEventMsg just schedules a debounced reload - only one timer at a time
+	if msg, ok := msg.(FsEventMsg); ok {
+		m.fsLastEvent = time.Now()
+		m.notePreviewChanged(msg.Paths)
+		staleCmd := m.noteStaleDocs(msg.Paths)
+		if m.fsReloadPending {
+			return m, tea.Batch(m.waitForFsEvent(), staleCmd)
+		}
+		m.fsReloadPending = true
+		m.fsDebounceDelay = m.config.FsDebounce()
 		return m, tea.Batch(
-			ScheduleFsReload(100*time.Millisecond),
+			ScheduleFsReload(m.fsDebounceDelay),
 			m.waitForFsEvent(),
+			staleCmd,
 		)
 	}
 
-	// DebouncedFsEventMsg triggers the actual async reload
+	// DebouncedFsEventMsg triggers the actual async reload once events go quiet
 	if _, ok := msg.(DebouncedFsEventMsg); ok {
+		// Burst still ongoing - back off (doubling up to the max) instead of thrashing
+		if time.Since(m.fsLastEvent) < m.config.FsDebounce() {
+			m.fsDebounceDelay *= 2
+			if limit := m.config.FsDebounceMax(); m.fsDebounceDelay > limit {
+				m.fsDebounceDelay = limit
+			}
+			return m, ScheduleFsReload(m.fsDebounceDelay)
+		}
+		m.fsReloadPending = false
 		m.loadingMessage = "Refreshing..."
 		m.pendingLoads = 3 // directory, allFiles, registry
+		// A .gitignore may have changed, and notes may have come in with a pull
+		git.InvalidateIgnored(m.rootPath)
+		m.notes = notes.Load(m.rootPath)
 		cmds := []tea.Cmd{
 			m.loadDirectoryAsync(),
 			m.loadAllFilesAsync(),
@@ -73,11 +150,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.pendingLoads = 4 // +git status
 			cmds = append(cmds, m.loadGitStatusAsync())
 		}
+		cmds = append(cmds, m.loadExtraRootsAsync())
+		if m.previewChanged {
+			var cmd tea.Cmd
+			m, cmd = m.reloadChangedPreview()
+			cmds = append(cmds, cmd)
+		}
 		return m, tea.Batch(cmds...)
 	}
 
 	// Handle image overlay mode - intercept all input
 	if m.imageOverlayMode {
+		if sizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
+			return m.resizeImageOverlay(sizeMsg)
+		}
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
 			case "esc", "q":
@@ -93,33 +179,116 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil // Ignore all other input in overlay mode
 	}
 
+	// Lay the panes out for a new size whatever is showing, so overlays fit it too
+	if sizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.resize(sizeMsg)
+		if !m.showingReader {
+			return m, nil
+		}
+	}
+
 	// Handle async directory load completion
 	if msg, ok := msg.(DirectoryLoadedMsg); ok {
+		if msg.Root != m.rootPath {
+			return m, nil
+		}
 		m.entries = msg.Entries
 		m.InvalidateTreeCache()
-		if m.ready {
-			m.tree.SetContent(m.RenderTree())
-		}
+		m.clampTreeOffset()
 		m.checkLoadingComplete()
+		if m.pendingSelect != "" {
+			m = m.NavigateToFile(m.pendingSelect)
+			m.pendingSelect = ""
+			m.ensureTreeCursorVisible()
+			return m.UpdatePreview()
+		}
 		return m, nil
 	}
 
+	// Calls from scripts and editors on the control socket
+	if msg, ok := msg.(control.Request); ok {
+		return m.handleControl(msg)
+	}
+
+	if msg, ok := msg.(ExtraRootsLoadedMsg); ok {
+		return m.handleExtraRootsLoaded(msg)
+	}
+
 	// Handle async all files load completion
 	if msg, ok := msg.(AllFilesLoadedMsg); ok {
+		if msg.Root != m.rootPath {
+			return m, nil
+		}
+		// The saved index only stands in until the first crawl
+		if msg.Saved {
+			if !m.filesCrawled {
+				m.allFiles = msg.Files
+				m.deniedPaths = msg.Denied
+			}
+			return m, nil
+		}
 		m.allFiles = msg.Files
+		m.deniedPaths = msg.Denied
+		m.filesCrawled = true
+		m.importGraph = nil
 		m.checkLoadingComplete()
+		// Results of a search made on the saved index may have changed
+		if m.searching && m.searchInput.Value() != "" {
+			m.lastSearchQuery = ""
+			m.runSearch(m.searchInput.Value())
+			m.searchCursor = min(m.searchCursor, max(0, len(m.searchResults)-1))
+			m.ensureSearchCursorVisible()
+		}
+		return m, nil
+	}
+
+	// Redraw the footer clock each minute while it's shown
+	if _, ok := msg.(ClockTickMsg); ok {
+		if m.showsSegment(segmentTime) {
+			return m, clockTick()
+		}
 		return m, nil
 	}
 
+	// Show failures reported from anywhere, and wait for the next
+	if msg, ok := msg.(ErrorReportedMsg); ok {
+		m.errorBanners = append(m.errorBanners, msg.Entry)
+		return m, waitForErrorReport()
+	}
+
 	// Handle async registry load completion
 	if msg, ok := msg.(RegistryLoadedMsg); ok {
+		if msg.Root != m.rootPath {
+			return m, nil
+		}
+		if m.registryDirty || m.showingRegistryConflict {
+			// Unsaved changes stay; saving them asks about what changed on disk
+			m.checkLoadingComplete()
+			return m, nil
+		}
 		m.docRegistry = msg.Registry
+		m.applyWatchedStale()
 		m.checkLoadingComplete()
+		if m.shouldOnboard(msg.Registry) && !m.showingOnboarding {
+			m = m.openOnboarding()
+			return m, nil
+		}
+		// Mention outdated structure tags when they first show up
+		announce := len(msg.Outdated) > len(m.outdatedTags)
+		m.outdatedTags = msg.Outdated
+		if announce {
+			m.statusMessage = fmt.Sprintf("%d doc(s) have outdated structure tags: press g, then U to update them", len(msg.Outdated))
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(5 * time.Second)
+		}
 		return m, nil
 	}
 
 	// Handle async git status load completion
 	if msg, ok := msg.(GitStatusLoadedMsg); ok {
+		if msg.Root != m.rootPath {
+			return m, nil
+		}
 		m.gitStatus = msg.Status
 		m.gitChanges = msg.Changes
 		m.gitDirStatus = msg.DirStatus
@@ -127,12 +296,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.gitAhead = msg.Ahead
 		m.gitBehind = msg.Behind
 		m.gitHasUpstream = msg.HasUpstream
-		if m.ready {
-			m.tree.SetContent(m.RenderTree())
+		if msg.Background {
+			// Keep the user's place; only the list and badges change
+			m.gitRefreshing = false
+			if m.gitStatusMode {
+				m.clampGitCursor()
+				m.gitList.SetContent(m.renderGitFileList())
+			}
+			return m, nil
 		}
 		m.checkLoadingComplete()
 		// If in git status mode, update the file list and load first preview
-		if m.gitStatusMode {
+		if m.gitStatusMode && !m.gitStashMode {
+			m.clampGitCursor()
 			m.gitList.SetContent(m.renderGitFileList())
 			if len(m.gitChanges) > 0 {
 				var cmd tea.Cmd
@@ -143,6 +319,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Returning to the terminal, e.g. after running git commands elsewhere
+	if _, ok := msg.(tea.FocusMsg); ok {
+		return m, tea.Batch(m.refreshGitStatus(), m.loadExtraRootsAsync())
+	}
+
+	// Periodic git status refresh (gitPollSeconds)
+	if _, ok := msg.(GitPollMsg); ok {
+		return m, tea.Batch(m.refreshGitStatus(), m.loadExtraRootsAsync(), ScheduleGitPoll(m.config.GitPoll()))
+	}
+
 	// Handle spinner animation tick
 	if _, ok := msg.(SpinnerTickMsg); ok {
 		if m.loadingMessage != "" {
@@ -152,6 +338,55 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Handle import graph build completion
+	if graphMsg, ok := msg.(ImportGraphLoadedMsg); ok {
+		return m.handleImportGraphLoaded(graphMsg)
+	}
+
+	// Handle streamed chunks of big previews
+	if chunkMsg, ok := msg.(PreviewChunkMsg); ok {
+		return m.handlePreviewChunk(chunkMsg)
+	}
+
+	// Handle file history loads
+	if histMsg, ok := msg.(FileHistoryLoadedMsg); ok {
+		return m.handleFileHistoryLoaded(histMsg)
+	}
+	if diffMsg, ok := msg.(FileHistoryDiffMsg); ok {
+		return m.handleFileHistoryDiff(diffMsg)
+	}
+
+	// Handle stash list loads, diffs and actions
+	if stashMsg, ok := msg.(StashesLoadedMsg); ok {
+		return m.handleStashesLoaded(stashMsg)
+	}
+	if diffMsg, ok := msg.(StashDiffMsg); ok {
+		return m.handleStashDiff(diffMsg)
+	}
+	if doneMsg, ok := msg.(StashActionDoneMsg); ok {
+		return m.handleStashActionDone(doneMsg)
+	}
+
+	// Handle co-change analysis completion
+	if coMsg, ok := msg.(CoChangeLoadedMsg); ok {
+		return m.handleCoChangeLoaded(coMsg)
+	}
+
+	// Handle shell command output (may arrive after the overlay closed)
+	if outMsg, ok := msg.(CommandOutputMsg); ok {
+		switch outMsg.RunID {
+		case m.verifyRunID:
+			return m.handleHookOutput(outMsg)
+		case m.commandRunID:
+			return m.handleCommandOutput(outMsg)
+		}
+		// A replaced run's output is still read to the end, so it doesn't block
+		if !outMsg.Done {
+			return m, outMsg.next
+		}
+		return m, nil
+	}
+
 	// Handle git fetch completion
 	if fetchMsg, ok := msg.(GitFetchDoneMsg); ok {
 		m.gitFetching = false
@@ -164,6 +399,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Start a fresh type-ahead prefix once typing pauses
+	if msg, ok := msg.(TypeAheadExpiredMsg); ok {
+		if m.typeAhead && msg.Seq == m.typeAheadSeq {
+			m.typeAheadPrefix = ""
+		}
+		return m, nil
+	}
+
 	// Handle status message clear
 	if _, ok := msg.(ClearStatusMsg); ok {
 		m.statusMessage = ""
@@ -184,9 +427,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle registry save completion
 	if saveMsg, ok := msg.(RegistrySavedMsg); ok {
 		m.registrySaving = false
+		if errors.Is(saveMsg.Err, groups.ErrRegistryChanged) {
+			m.registryDirty = true
+			m.showingRegistryConflict = true
+			return m, nil
+		}
 		if saveMsg.Err != nil {
 			m.statusMessage = "Failed to save registry"
 			m.statusMessageTime = time.Now()
+		} else if m.docRegistry != nil {
+			m.docRegistry.SetSaved(saveMsg.Saved)
 		}
 		// If dirty again (user moved more docs while saving), schedule another save
 		if m.registryDirty {
@@ -202,8 +452,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.fileOpError = ""
 		m.fileOpConfirm = false
 		m.fileOpScrollOffset = 0
+		m.fileOpCompletions = nil
 		m.fileOpSourcePath = "" // Clear import source
 
+		var reloadRegistry tea.Cmd
 		if msg.Success {
 			opNames := map[FileOpMode]string{
 				FileOpCreateFile:   "Created",
@@ -211,17 +463,56 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				FileOpRename:       "Renamed to",
 				FileOpDelete:       "Deleted",
 				FileOpImport:       "Imported",
+				FileOpDuplicate:    "Duplicated as",
+				FileOpChmod:        "Changed permissions of",
 			}
 			if msg.NewPath != "" {
 				m.statusMessage = opNames[msg.Op] + " " + filepath.Base(msg.NewPath)
 			} else {
 				m.statusMessage = opNames[msg.Op] + " " + filepath.Base(m.fileOpTargetPath)
 			}
+			if len(msg.UpdatedDocs) > 0 {
+				names := make([]string, len(msg.UpdatedDocs))
+				for i, d := range msg.UpdatedDocs {
+					names[i] = filepath.Base(d)
+				}
+				m.statusMessage += fmt.Sprintf(" · updated %d doc(s): %s", len(names), strings.Join(names, ", "))
+				// Our own change to .context-docs.md isn't one to ask about on the next save
+				if m.docRegistry != nil {
+					oldRel, err1 := filepath.Rel(m.rootPath, m.fileOpTargetPath)
+					newRel, err2 := filepath.Rel(m.rootPath, msg.NewPath)
+					if err1 == nil && err2 == nil {
+						m.docRegistry.RenamedOnDisk(m.rootPath, oldRel, newRel)
+					}
+				}
+				reloadRegistry = m.loadRegistryAsync()
+			}
+			if msg.DocsErr != nil {
+				m.statusMessage += " · docs not updated: " + msg.DocsErr.Error()
+			}
 		} else {
 			m.statusMessage = "Error: " + msg.Error.Error()
 		}
 		m.statusMessageTime = time.Now()
-		return m, ClearStatusAfter(5 * time.Second)
+		// Notes follow a renamed entry and go with a deleted one
+		if msg.Success && msg.Op == FileOpRename {
+			m.moveNotes(m.fileOpTargetPath, msg.NewPath)
+		}
+		if msg.Success && msg.Op == FileOpDelete {
+			m.moveNotes(m.fileOpTargetPath, "")
+		}
+		if msg.Success && msg.Op == FileOpChmod && msg.NewPath == m.previewPath {
+			if info, err := os.Stat(msg.NewPath); err == nil {
+				m.previewPerm = info.Mode().String()
+			}
+		}
+		if msg.Success && msg.Op == FileOpDuplicate {
+			m = m.addFileToTree(msg.NewPath)
+			var cmd tea.Cmd
+			m, cmd = m.UpdatePreview()
+			return m, tea.Batch(cmd, ClearStatusAfter(5*time.Second), reloadRegistry)
+		}
+		return m, tea.Batch(ClearStatusAfter(5*time.Second), reloadRegistry)
 	}
 
 	// Detect file drop via bracketed paste
@@ -232,6 +523,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Motion with no button held only moves the hover highlight
+	if mouseMsg, ok := msg.(tea.MouseMsg); ok && mouseMsg.Action == tea.MouseActionMotion && mouseMsg.Button == tea.MouseButtonNone {
+		return m.handleMouseMotion(mouseMsg)
+	}
+
 	// Handle help toggle (works from any mode)
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "?" {
 		m.showingHelp = !m.showingHelp
@@ -244,7 +540,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle help overlay - close on q/esc, scroll with j/k
 	if m.showingHelp {
 		// Calculate max scroll for clamping
-		helpContentLines := 21 // Number of content lines in help
+		helpContentLines := len(m.helpLines())
 		maxContentHeight := m.height - 6 - 4
 		if maxContentHeight < 5 {
 			maxContentHeight = 5
@@ -292,11 +588,111 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateSearch(msg)
 	}
 
+	// Handle the tree's right-click menu
+	if m.showingContextMenu {
+		return m.updateContextMenu(msg)
+	}
+
+	// Handle the first-run setup
+	if m.showingOnboarding {
+		return m.updateOnboarding(msg)
+	}
+
+	// Handle the prompt about a registry that changed on disk
+	if m.showingRegistryConflict {
+		return m.updateRegistryConflict(msg)
+	}
+
+	// Handle the doc edit preview (opened from the docs panel)
+	if m.showingDocEdits {
+		return m.updateDocEdits(msg)
+	}
+
+	// Handle verify overlay (opened from the docs panel)
+	if m.showingVerify {
+		return m.updateVerify(msg)
+	}
+
+	// Handle the doc reader (opened from the docs panel)
+	if m.showingReader {
+		return m.updateDocReader(msg)
+	}
+
 	// Handle docs panel mode
 	if m.showingDocs {
 		return m.updateDocs(msg)
 	}
 
+	// Handle regions overlay
+	if m.showingRegions {
+		return m.updateRegions(msg)
+	}
+
+	// Handle release notes overlay
+	if m.showingReleases {
+		return m.updateReleaseNotes(msg)
+	}
+
+	// Handle the doc picker for a copy-mode snippet
+	if m.showingSnippetDocs {
+		return m.updateSnippetDocs(msg)
+	}
+
+	// Handle related files overlay
+	if m.showingRelated {
+		return m.updateRelated(msg)
+	}
+
+	// Handle copy history overlay
+	if m.showingCopyHistory {
+		return m.updateCopyHistory(msg)
+	}
+
+	// Handle basket overlay
+	if m.showingBasket {
+		return m.updateBasket(msg)
+	}
+
+	// Handle export filename prompt
+	if m.showingExport {
+		return m.updateExport(msg)
+	}
+
+	// Handle note editor
+	if m.showingNote {
+		return m.updateNote(msg)
+	}
+
+	// Handle links overlay
+	if m.showingLinks {
+		return m.updateLinks(msg)
+	}
+
+	// Handle debug overlay
+	if m.showingDebug {
+		return m.updateDebug(msg)
+	}
+
+	// Handle recent projects overlay
+	if m.showingProjects {
+		return m.updateProjects(msg)
+	}
+
+	// Handle command runner overlay
+	if m.showingCommand {
+		return m.updateCommand(msg)
+	}
+
+	// Handle errors overlay
+	if m.showingErrors {
+		return m.updateErrors(msg)
+	}
+
+	// Handle theme picker
+	if m.showingThemes {
+		return m.updateThemes(msg)
+	}
+
 	// Handle visual selection mode
 	if m.selectMode {
 		return m.updateSelect(msg)
@@ -314,20 +710,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case FileLoadedMsg:
+		// Results of superseded render requests are dropped
+		if msg.RequestID != 0 && msg.RequestID != m.previewRequestID {
+			return m, nil
+		}
+		if msg.Partial {
+			if msg.Path == m.previewPath {
+				m.preview.SetContent(msg.Content)
+				m.preview.GotoTop()
+				m.previewLines = strings.Split(msg.Content, "\n")
+				m.previewPartial = true
+			}
+			return m, waitForHighlight(msg.next)
+		}
 		// Only update if this is still the file we're waiting for
 		if msg.Path == m.previewPath {
 			m.loading = false
 			m.preview.SetContent(msg.Content)
-			m.preview.GotoTop()
+			// Keep the position if the top of the file was already shown
+			if !m.previewPartial {
+				m.preview.GotoTop()
+			}
+			m.previewPartial = false
+			// A file that changed on disk keeps its place (clamped to the new length)
+			if m.previewRestoreOffset > 0 {
+				m.preview.SetYOffset(m.previewRestoreOffset)
+				m.previewRestoreOffset = 0
+			}
+			m.structured = msg.Structured
 			// Store lines for copy mode selection
 			m.previewLines = strings.Split(msg.Content, "\n")
 			// Cache the rendered content
 			if !msg.ModTime.IsZero() {
-				m.previewCache[msg.Path] = CachedPreview{
-					Content: msg.Content,
-					ModTime: msg.ModTime,
+				cacheKey := msg.Path
+				if msg.Blame {
+					cacheKey = blameCacheKey(msg.Path)
 				}
+				m.previewCache.Put(cacheKey, CachedPreview{
+					Content:    msg.Content,
+					ModTime:    msg.ModTime,
+					Structured: msg.Structured,
+					More:       msg.More,
+					MoreLine:   msg.MoreLine,
+				})
 			}
+			m.startStream(msg.Path, filepath.Base(msg.Path), msg.Content, msg.More, msg.MoreLine)
 		}
 		return m, nil
 
@@ -355,9 +782,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Cache the rendered image if no error
 			if msg.Error == nil && !msg.ModTime.IsZero() {
 				if m.imageCache == nil {
-					m.imageCache = make(map[string]CachedImage)
+					m.imageCache = newImageCache(m.config)
 				}
-				m.imageCache[msg.Path] = CachedImage{
+				m.imageCache.Put(msg.Path, CachedImage{
 					RenderData: msg.RenderData,
 					Width:      msg.Width,
 					Height:     msg.Height,
@@ -366,12 +793,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ViewportW:  m.preview.Width,
 					ViewportH:  m.preview.Height,
 					ModTime:    msg.ModTime,
-				}
+					Protocol:   m.termCaps.Graphics,
+					TrueColor:  m.termCaps.TrueColor,
+					Theme:      m.themeName,
+				})
 			}
 		}
 		return m, nil
 
 	case tea.MouseMsg:
+		if m.fileHistoryMode || m.tocMode {
+			// Only the wheel scrolls the diff; the commit list is keyboard driven
+			switch msg.Button {
+			case tea.MouseButtonWheelUp:
+				m.preview.LineUp(3)
+			case tea.MouseButtonWheelDown:
+				m.preview.LineDown(3)
+			}
+			return m, nil
+		}
 		divX := m.DividerX()
 
 		// Handle divider dragging
@@ -379,7 +819,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.Action == tea.MouseActionRelease {
 				m.draggingSplit = false
 				// Save config when drag ends
-				config.Save(m.rootPath, config.Config{SplitRatio: m.splitRatio})
+				m.saveConfig()
 			} else if msg.Action == tea.MouseActionMotion {
 				// Update split ratio based on mouse X position
 				newRatio := float64(msg.X) / float64(m.width)
@@ -389,10 +829,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					newRatio = 0.8
 				}
 				m.splitRatio = newRatio
-				// Update viewport widths
-				m.tree.Width = m.LeftPaneWidth() - 2
-				m.preview.Width = m.RightPaneWidth() - 2
-				m.tree.SetContent(m.RenderTree())
+				m.applyPaneWidths()
 			}
 			return m, nil
 		}
@@ -400,7 +837,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Check if clicking on divider (within 2 pixels)
 		nearDivider := msg.X >= divX-2 && msg.X <= divX+2
 
-		if msg.Button == tea.MouseButtonLeft && nearDivider {
+		if msg.Button == tea.MouseButtonLeft && nearDivider && m.shownLayout() == layoutSplit {
 			m.draggingSplit = true
 			return m, nil
 		}
@@ -414,22 +851,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if msg.Button == tea.MouseButtonWheelUp {
 			if m.activePane == TreePane {
-				m.tree.LineUp(3)
+				m.scrollTree(-3)
 			} else {
 				m.preview.LineUp(3)
 			}
 		} else if msg.Button == tea.MouseButtonWheelDown {
 			if m.activePane == TreePane {
-				m.tree.LineDown(3)
+				m.scrollTree(3)
 			} else {
 				m.preview.LineDown(3)
 			}
+		} else if msg.Button == tea.MouseButtonRight && msg.Action == tea.MouseActionPress && m.activePane == TreePane {
+			return m.openContextMenu(msg.X, msg.Y)
 		} else if msg.Button == tea.MouseButtonLeft && m.activePane == TreePane {
 			// Click in tree pane - calculate which entry was clicked
 			// Account for header (1 line) + border (1 line) + viewport scroll
 			headerOffset := 2
 			clickedLine := msg.Y - headerOffset
-			clickedIndex := clickedLine + m.tree.YOffset
+			clickedIndex := clickedLine + m.treeOffset
 
 			flat := m.FlatEntries()
 			if clickedIndex >= 0 && clickedIndex < len(flat) {
@@ -443,7 +882,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if e.IsDir {
 						m.cursor = clickedIndex
 						m = m.ToggleExpand(e.Path)
-						m.tree.SetContent(m.RenderTree())
+						m.clampTreeOffset()
 					} else {
 						// For files, ensure preview is triggered
 						m.cursor = clickedIndex
@@ -455,7 +894,6 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					// Single click: move cursor and update preview
 					m.cursor = clickedIndex
-					m.tree.SetContent(m.RenderTree())
 					var cmd tea.Cmd
 					m, cmd = m.UpdatePreview()
 					cmds = append(cmds, cmd)
@@ -467,27 +905,57 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if m.fileHistoryMode {
+			return m.updateFileHistoryKey(msg)
+		}
+		if m.tocMode {
+			return m.updateTOCKey(msg)
+		}
+		if m.pendingMarkKey != "" {
+			return m.handleMarkKey(msg.String())
+		}
+		if m.typeAhead {
+			return m.handleTypeAheadKey(msg)
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 
+		case markSet, markJump:
+			// Wait for the mark name
+			m.pendingMarkKey = msg.String()
+			m.statusMessage = msg.String() + "…"
+			m.statusMessageTime = time.Now()
+			return m, nil
+
 		case "tab":
 			if m.activePane == TreePane {
 				m.activePane = PreviewPane
 			} else {
 				m.activePane = TreePane
 			}
+			m.followActivePane()
+
+		case "[":
+			// Zoom the tree to the full width, or go back to both panes
+			return m.setLayout(layoutTree)
+
+		case "]":
+			// Zoom the preview to the full width, or go back to both panes
+			return m.setLayout(layoutPreview)
+
+		case "|":
+			// Both panes, split evenly
+			return m.resetLayout()
 
 		case "j", "down":
 			if m.activePane == TreePane {
 				flat := m.FlatEntries()
 				if m.cursor < len(flat)-1 {
 					m.cursor++
-					m.tree.SetContent(m.RenderTree())
 					// Auto-scroll to keep cursor visible
-					if m.cursor >= m.tree.YOffset+m.tree.Height {
-						m.tree.LineDown(1)
-					}
+					m.ensureTreeCursorVisible()
 				}
 			} else {
 				var cmd tea.Cmd
@@ -499,11 +967,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.activePane == TreePane {
 				if m.cursor > 0 {
 					m.cursor--
-					m.tree.SetContent(m.RenderTree())
 					// Auto-scroll to keep cursor visible
-					if m.cursor < m.tree.YOffset {
-						m.tree.LineUp(1)
-					}
+					m.ensureTreeCursorVisible()
 				}
 			} else {
 				var cmd tea.Cmd
@@ -512,6 +977,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		case "enter", "l":
+			if msg.String() == "enter" && m.activePane == TreePane && (m.options.Choose || m.options.ChooseDir) {
+				return m.choose()
+			}
+			// Follow the links of a previewed markdown file
+			if msg.String() == "enter" && m.activePane == PreviewPane && strings.HasSuffix(strings.ToLower(m.previewPath), ".md") {
+				return m.openLinks()
+			}
 			// First check if we should enter image overlay mode
 			if m.previewIsImage && m.currentImage != nil &&
 				m.termCaps.Graphics == terminal.ProtocolKitty {
@@ -527,11 +999,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				flat := m.FlatEntries()
 				if m.cursor < len(flat) {
 					e := flat[m.cursor]
-					if e.IsDir {
+					if e.More > 0 {
+						m = m.LoadMore(e.Path)
+						return m.UpdatePreview()
+					}
+					if e.IsDir && e.Denied {
+						m.statusMessage = "Permission denied: " + e.RelPath
+						m.statusMessageTime = time.Now()
+						return m, ClearStatusAfter(3 * time.Second)
+					} else if e.IsDir {
 						m = m.ToggleExpand(e.Path)
-						m.tree.SetContent(m.RenderTree())
+						m.clampTreeOffset()
 					} else {
 						// Trigger preview for files
+						m.noteVisit(e.Path)
 						var cmd tea.Cmd
 						m, cmd = m.UpdatePreview()
 						cmds = append(cmds, cmd)
@@ -546,18 +1027,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					e := flat[m.cursor]
 					if e.IsDir {
 						m = m.Collapse(e.Path)
-						m.tree.SetContent(m.RenderTree())
+						m.clampTreeOffset()
 					}
 				}
 			}
 
 		case "right":
-			// Resize: right arrow increases tree pane
-			m.HandlePaneResize("right")
+			// Pan an unwrapped preview, otherwise resize: right arrow increases tree pane
+			if m.activePane != PreviewPane || !m.panPreview(previewPanStep) {
+				m.HandlePaneResize("right")
+			}
 
 		case "left":
-			// Resize: left arrow decreases tree pane (increases preview)
-			m.HandlePaneResize("left")
+			// Pan an unwrapped preview, otherwise resize: left arrow decreases tree pane (increases preview)
+			if m.activePane != PreviewPane || !m.panPreview(-previewPanStep) {
+				m.HandlePaneResize("left")
+			}
+
+		case "L":
+			m.panPreview(previewPanStep)
+
+		case "w":
+			// Toggle preview line wrapping
+			return m.togglePreviewWrap()
+
+		case "b":
+			// Toggle blame annotations in the preview
+			return m.toggleBlame()
 
 		case "c":
 			// Copy selected file to clipboard
@@ -565,7 +1061,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.cursor < len(flat) {
 				e := flat[m.cursor]
 				if !e.IsDir {
-					if err := clipboard.CopyFilePath(e.Path); err != nil {
+					if err := m.copyText("file", "@"+e.Path); err != nil {
 						m.statusMessage = "Clipboard unavailable"
 					} else {
 						m.statusMessage = "Copied!"
@@ -575,13 +1071,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
+		case "#":
+			// Open an issue reference of the previewed file
+			return m.openIssueRefs()
+
+		case "U":
+			// Copy the entry's web link on the origin remote
+			flat := m.FlatEntries()
+			if m.cursor < len(flat) && flat[m.cursor].More == 0 {
+				e := flat[m.cursor]
+				return m.copyPermalink(e.Path, e.IsDir, 0, 0)
+			}
+
 		case "n":
 			// Create new file
 			if m.activePane == TreePane {
+				if m.readOnly {
+					return m.readOnlyNotice()
+				}
 				m.clearAllOverlays()
 				m.fileOpMode = FileOpCreateFile
 				m.fileOpInput.SetValue("")
-				m.fileOpInput.Placeholder = "filename"
+				m.fileOpInput.Placeholder = "filename or path/to/file"
 				m.fileOpInput.Focus()
 				m.fileOpTargetPath = m.getTargetDirectory()
 				return m, textinput.Blink
@@ -590,10 +1101,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "N":
 			// Create new folder
 			if m.activePane == TreePane {
+				if m.readOnly {
+					return m.readOnlyNotice()
+				}
 				m.clearAllOverlays()
 				m.fileOpMode = FileOpCreateFolder
 				m.fileOpInput.SetValue("")
-				m.fileOpInput.Placeholder = "folder name"
+				m.fileOpInput.Placeholder = "folder name or path/to/folder"
 				m.fileOpInput.Focus()
 				m.fileOpTargetPath = m.getTargetDirectory()
 				return m, textinput.Blink
@@ -602,39 +1116,93 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			// Rename file or folder
 			if m.activePane == TreePane {
+				return m.startRename()
+			}
+
+		case "y":
+			// Duplicate the file, e.g. as a scratch copy to rewrite
+			if m.activePane == TreePane {
+				if m.readOnly {
+					return m.readOnlyNotice()
+				}
 				flat := m.FlatEntries()
 				if m.cursor < len(flat) {
 					e := flat[m.cursor]
+					if e.IsDir {
+						m.statusMessage = "Only files can be duplicated"
+						m.statusMessageTime = time.Now()
+						return m, ClearStatusAfter(3 * time.Second)
+					}
+					name, err := duplicateName(e.Path)
+					if err != nil {
+						m.statusMessage = "Error: " + err.Error()
+						m.statusMessageTime = time.Now()
+						return m, ClearStatusAfter(3 * time.Second)
+					}
 					m.clearAllOverlays()
-					m.fileOpMode = FileOpRename
-					m.fileOpInput.SetValue(e.Name)
-					m.fileOpInput.Placeholder = "new name"
+					m.fileOpMode = FileOpDuplicate
+					m.fileOpSourcePath = e.Path
+					m.fileOpTargetPath = filepath.Dir(e.Path)
+					m.fileOpInput.SetValue(name)
+					m.fileOpInput.Placeholder = "copy name"
 					m.fileOpInput.Focus()
-					// Select all text for easy replacement
 					m.fileOpInput.CursorEnd()
-					m.fileOpTargetPath = e.Path
 					return m, textinput.Blink
 				}
 			}
 
-		case "d", "x":
-			// Delete file or folder
+		case "X":
+			// Change permissions, e.g. make a script executable
 			if m.activePane == TreePane {
+				if m.readOnly {
+					return m.readOnlyNotice()
+				}
 				flat := m.FlatEntries()
 				if m.cursor < len(flat) {
 					e := flat[m.cursor]
+					info, err := os.Stat(e.Path)
+					if err != nil {
+						m.statusMessage = "Error: " + err.Error()
+						m.statusMessageTime = time.Now()
+						return m, ClearStatusAfter(3 * time.Second)
+					}
 					m.clearAllOverlays()
-					m.fileOpMode = FileOpDelete
+					m.fileOpMode = FileOpChmod
 					m.fileOpTargetPath = e.Path
-					return m, nil
+					m.fileOpInput.SetValue(formatOctalMode(modeToOctal(info.Mode())))
+					m.fileOpInput.Placeholder = "octal mode"
+					m.fileOpInput.Focus()
+					m.fileOpInput.CursorEnd()
+					return m, textinput.Blink
+				}
+			}
+
+		case "d", "x":
+			// d in the preview pane toggles the file's diff against HEAD
+			if msg.String() == "d" && m.activePane == PreviewPane {
+				return m.toggleFileDiff()
+			}
+			// Delete file or folder (on a "more" node, list the next page instead)
+			if m.activePane == TreePane {
+				flat := m.FlatEntries()
+				if m.cursor < len(flat) && flat[m.cursor].More > 0 {
+					m = m.LoadMore(flat[m.cursor].Path)
+					return m.UpdatePreview()
 				}
+				return m.startDelete()
+			}
+
+		case "e":
+			// Edit the note on the file or folder
+			if m.activePane == TreePane {
+				return m.openNoteEditor()
 			}
 
 		case "o":
 			// Open file in OS default application
 			var filePath string
-			if m.gitStatusMode && m.gitStatusCursor < len(m.gitChanges) {
-				filePath = filepath.Join(m.gitRepoRoot, m.gitChanges[m.gitStatusCursor].Path)
+			if change, ok := m.selectedGitChange(); m.gitStatusMode && ok {
+				filePath = filepath.Join(m.gitRepoRoot, change.Path)
 			} else if m.activePane == TreePane {
 				flat := m.FlatEntries()
 				if m.cursor < len(flat) {
@@ -673,6 +1241,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectStart = -1
 				m.selectEnd = -1
 				m.isSelecting = false
+				m.selectCursor = m.preview.YOffset
+				m.visualLine = false
 			} else {
 				m.selectMode = false
 			}
@@ -685,6 +1255,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.clearAllOverlays()
 					m.gitStatusMode = true
 					m.gitStatusCursor = 0
+					m.gitStashMode = false
+					m.gitFolds = nil // Folds last while the view is open
 					// Initialize viewport and trigger async git status refresh
 					m.gitList.GotoTop()
 					m.loadingMessage = "Loading git status..."
@@ -696,14 +1268,142 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "T":
+			return m.openThemePicker()
+
+		case "E":
+			m.clearAllOverlays()
+			m.showingErrors = true
+			return m, nil
+
+		case "R":
+			m.clearAllOverlays()
+			m.showingRegions = true
+			m.regionCursor = 0
+			return m, nil
+
+		case "S":
+			// Send the file under the cursor to the agent session
+			flat := m.FlatEntries()
+			if m.cursor < len(flat) {
+				return m.sendRefs([]string{"@" + flat[m.cursor].Path})
+			}
+
+		case "i":
+			// Show the imports of the file under the cursor
+			if m.activePane == TreePane {
+				return m.openImports()
+			}
+
+		case "G":
+			// Browse the commits touching the file under the cursor
+			if m.activePane == TreePane {
+				return m.openFileHistory()
+			}
+
+		case "C":
+			// Show the files most often committed together with the file under the cursor
+			if m.activePane == TreePane {
+				return m.openCoChanged()
+			}
+
+		case "D":
+			// Draft a context doc for the directory under the cursor
+			if m.activePane == TreePane {
+				return m.scaffoldDoc()
+			}
+
+		case "t":
+			// Table of contents of the previewed markdown file
+			return m.openTOC()
+
+		case "a":
+			// Stage the file under the cursor in the basket
+			return m.addTreeEntryToBasket()
+
+		case "A":
+			return m.openBasket()
+
+		case "Y":
+			m.clearAllOverlays()
+			m.showingCopyHistory = true
+			m.copyHistoryCursor = 0
+			return m, nil
+
+		case "I":
+			m.clearAllOverlays()
+			m.showingDebug = true
+			m.errorBanners = nil
+			return m, nil
+
+		case "P":
+			return m.openProjects()
+
+		case "!":
+			return m.openCommand()
+
+		case "u":
+			return m.toggleCoverage()
+
+		case "esc":
+			if m.treeFilter != nil {
+				return m.clearTreeFilter()
+			}
+			m.errorBanners = nil
+
+		case typeAheadKey:
+			return m.startTypeAhead()
+
+		case "-":
+			// Collapse all directories
+			path := m.cursorPath()
+			m = m.CollapseAll()
+			return m.afterTreeReshape(path, "Collapsed all")
+
+		case "+", "=":
+			// Expand everything one level deeper
+			return m.expandTreeTo(m.ExpandedDepth() + 1)
+
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			// Expand everything to depth N
+			return m.expandTreeTo(int(msg.String()[0] - '0'))
+
+		case "H":
+			// Pan an unwrapped preview left, or collapse the directories next to the cursor
+			if m.activePane == PreviewPane && m.panPreview(-previewPanStep) {
+				return m, nil
+			}
+			path := m.cursorPath()
+			if path == "" {
+				return m, nil
+			}
+			m = m.CollapseSiblings(path)
+			return m.afterTreeReshape(path, "Collapsed siblings")
+
+		case "z":
+			// Fold/unfold the JSON/YAML node at the top of the preview
+			if m.structured != nil {
+				line := m.structured.sourceLine(m.preview.YOffset)
+				line = m.structured.toggleFold(line)
+				m.refreshStructuredPreview()
+				m.preview.SetYOffset(m.structured.rowOf(line))
+			}
+			return m, nil
+
+		case "Z":
+			// Fold all top-level nodes, or unfold everything
+			if m.structured != nil {
+				m.structured.toggleFoldAll()
+				m.refreshStructuredPreview()
+				m.preview.GotoTop()
+			}
+			return m, nil
+
 		case ".":
 			// Toggle dotfile visibility
 			m.showDotfiles = !m.showDotfiles
 			// Save to config
-			config.Save(m.rootPath, config.Config{
-				SplitRatio:   m.splitRatio,
-				ShowDotfiles: m.showDotfiles,
-			})
+			m.saveConfig()
 			// Trigger async reload
 			m.loadingMessage = "Refreshing..."
 			m.pendingLoads = 2
@@ -724,6 +1424,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "f":
 			// Git fetch
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
 			if m.isGitRepo && !m.gitFetching {
 				m.gitFetching = true
 				repoRoot := m.gitRepoRoot
@@ -734,36 +1437,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+	}
 
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
+	return m, tea.Batch(cmds...)
+}
 
-		// Use dynamic pane widths based on splitRatio
-		paneHeight := m.height - 4
-		treeWidth := m.LeftPaneWidth() - 2 // subtract padding
-		previewWidth := m.RightPaneWidth() - 2
+// resize lays the panes out for a new terminal size
+func (m *Model) resize(msg tea.WindowSizeMsg) {
+	m.width = msg.Width
+	m.height = msg.Height
+
+	// Use dynamic pane widths based on splitRatio
+	paneHeight := m.height - 4
+	treeWidth := max(m.LeftPaneWidth()-2, 0) // subtract padding
+	previewWidth := max(m.RightPaneWidth()-2, 0)
+
+	if !m.ready {
+		m.tree = viewport.New(treeWidth, paneHeight)
+		m.preview = viewport.New(previewWidth, paneHeight)
+		m.preview.SetContent("Select a file to preview")
+		// gitList is 2 lines shorter to account for "Git Status\n\n" header
+		m.gitList = viewport.New(treeWidth, paneHeight-2)
+		m.ready = true
+	} else {
+		m.tree.Width = treeWidth
+		m.tree.Height = paneHeight
+		m.ensureTreeCursorVisible()
+		m.preview.Width = previewWidth
+		m.preview.Height = paneHeight
+		m.gitList.Width = treeWidth
+		m.gitList.Height = paneHeight - 2
+	}
+}
 
-		if !m.ready {
-			m.tree = viewport.New(treeWidth, paneHeight)
-			m.tree.SetContent(m.RenderTree())
-			m.preview = viewport.New(previewWidth, paneHeight)
-			m.preview.SetContent("Select a file to preview")
-			// gitList is 2 lines shorter to account for "Git Status\n\n" header
-			m.gitList = viewport.New(treeWidth, paneHeight-2)
-			m.ready = true
-		} else {
-			m.tree.Width = treeWidth
-			m.tree.Height = paneHeight
-			m.tree.SetContent(m.RenderTree())
-			m.preview.Width = previewWidth
-			m.preview.Height = paneHeight
-			m.gitList.Width = treeWidth
-			m.gitList.Height = paneHeight - 2
+// resizeImageOverlay redraws the image overlay for a new terminal size
+// The new frame is built before it replaces the old one, and starts by deleting the
+// old image placement so no part of it is left behind.
+func (m Model) resizeImageOverlay(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	// The panes are laid out too, for when the overlay closes
+	m.resize(msg)
+	if m.currentImage != nil {
+		overlayData, err := LoadImageForOverlay(m.currentImage.Path, m.width, m.height)
+		if err == nil && overlayData != "" {
+			m.imageOverlayData = ClearKittyImages() + overlayData
+			return m, nil
 		}
 	}
-
-	return m, tea.Batch(cmds...)
+	m.imageOverlayMode = false
+	m.imageOverlayData = ""
+	return m, tea.Sequence(
+		tea.Printf("%s", ClearKittyImages()),
+		tea.ClearScreen,
+	)
 }
 
 // updateSearch handles events in search mode
@@ -791,6 +1516,7 @@ func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.lastSearchQuery = ""
 				// Navigate to the file
 				m = m.NavigateToFile(result.Path)
+				m.noteVisit(filepath.Join(m.rootPath, result.Path))
 				var cmd tea.Cmd
 				m, cmd = m.UpdatePreview()
 				return m, cmd
@@ -813,6 +1539,13 @@ func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.ensureSearchCursorVisible()
 			}
 			return m, nil
+
+		case "ctrl+s":
+			// Stage the current result in the basket, staying in search
+			if m.searchCursor < len(m.searchResults) {
+				return m.addToBasket(basket.Item{Kind: basket.KindFile, Path: m.searchResults[m.searchCursor].Path})
+			}
+			return m, nil
 		}
 
 	case tea.MouseMsg:
@@ -841,19 +1574,7 @@ func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle debounced search message - perform search with current pending query
 	if _, ok := msg.(SearchDebounceMsg); ok {
 		m.searchDebounceActive = false
-		query := m.pendingSearchQuery
-		// Only perform search if we have a pending query and it differs from last search
-		if query != "" && query != m.lastSearchQuery {
-			matches := fuzzy.Find(query, m.allFiles)
-			m.searchResults = make([]SearchResult, 0, len(matches))
-			for _, match := range matches {
-				m.searchResults = append(m.searchResults, SearchResult{
-					Path:        m.allFiles[match.Index],
-					DisplayName: m.allFiles[match.Index],
-				})
-			}
-			m.lastSearchQuery = query
-		}
+		m.runSearch(m.pendingSearchQuery)
 		return m, nil
 	}
 
@@ -870,15 +1591,22 @@ func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.searchCursor = 0
 		m.searchScrollOffset = 0
 
+		delay := m.config.SearchDebounce()
+		idle := time.Since(m.lastSearchKeyTime) >= delay
+		m.lastSearchKeyTime = time.Now()
+
 		if query == "" {
 			// Immediate clear for empty query
 			m.searchResults = nil
 			m.lastSearchQuery = ""
 			m.searchDebounceActive = false
+		} else if idle && !m.searchDebounceActive {
+			// First keystroke after a pause - search right away
+			m.runSearch(query)
 		} else if !m.searchDebounceActive {
-			// Schedule debounced search (100ms delay) - only one timer at a time
+			// Mid-burst - schedule debounced search, only one timer at a time
 			m.searchDebounceActive = true
-			cmds = append(cmds, tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+			cmds = append(cmds, tea.Tick(delay, func(t time.Time) tea.Msg {
 				return SearchDebounceMsg{}
 			}))
 		}
@@ -888,6 +1616,62 @@ func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// runSearch fuzzy-matches query against all files, after applying its ext:, dir:,
+// group: and note: filters (see parseSearchQuery)
+// Skips the work if the query is empty or unchanged since the last search
+func (m *Model) runSearch(query string) {
+	if query == "" || query == m.lastSearchQuery {
+		return
+	}
+	m.lastSearchQuery = query
+	filter, term := parseSearchQuery(query)
+	files := m.allFiles
+	if filter.active() {
+		files = filter.apply(files, m.rootPath, m.docRegistry, m.notes)
+	}
+
+	// Filters alone list every file they keep, most visited first
+	now := time.Now()
+	if term == "" {
+		m.searchResults = nil
+		if filter.active() {
+			for _, path := range files {
+				m.searchResults = append(m.searchResults, SearchResult{Path: path, DisplayName: path})
+			}
+			sort.SliceStable(m.searchResults, func(i, j int) bool {
+				return m.frecency.Score(m.searchResults[i].Path, now) > m.frecency.Score(m.searchResults[j].Path, now)
+			})
+		}
+		return
+	}
+
+	// Files opened often and recently rank above equally good matches
+	matches := fuzzy.Find(term, files)
+	for i := range matches {
+		matches[i].Score += m.frecency.Boost(files[matches[i].Index], now)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	m.searchResults = make([]SearchResult, 0, len(matches))
+	for _, match := range matches {
+		m.searchResults = append(m.searchResults, SearchResult{
+			Path:        files[match.Index],
+			DisplayName: files[match.Index],
+		})
+	}
+}
+
+// noteVisit records that a file was opened, to rank it higher in search
+func (m Model) noteVisit(path string) {
+	rel, err := filepath.Rel(m.rootPath, path)
+	if err != nil || m.frecency == nil {
+		return
+	}
+	m.frecency.Visit(rel, time.Now())
+	if m.keepsProjectData() {
+		debuglog.Report("save visits", frecency.Save(m.rootPath, m.frecency))
+	}
+}
+
 // getSearchMaxVisibleResults calculates max visible results based on viewport
 func (m Model) getSearchMaxVisibleResults() int {
 	fixedHeight := m.height - 6
@@ -936,9 +1720,39 @@ func (m Model) updateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.pendingRegionMark {
+			m.pendingRegionMark = false
+			if isMarkName(msg.String()) {
+				return m.markRegion(msg.String())
+			}
+			return m, nil
+		}
+
 		switch msg.String() {
+		case "m":
+			// Wait for the region name
+			m.pendingRegionMark = true
+			return m, nil
+
+		case "V":
+			// Visual line mode: anchor at the cursor, j/k extend
+			if m.visualLine {
+				m.visualLine = false
+			} else {
+				m.visualLine = true
+				m.selectStart = m.selectCursor
+				m.selectEnd = m.selectCursor
+			}
+			return m, nil
+
 		case "esc", "q":
-			// Exit copy mode
+			// Leave visual line mode first, then copy mode
+			if m.visualLine {
+				m.visualLine = false
+				m.selectStart = -1
+				m.selectEnd = -1
+				return m, nil
+			}
 			m.selectMode = false
 			m.selectStart = -1
 			m.selectEnd = -1
@@ -969,24 +1783,50 @@ func (m Model) updateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		// Scrolling
+		case "r":
+			// Copy the selection as a path + line range reference
+			return m.copySelectionRef()
+
+		case "U":
+			// Copy the selection's web link on the origin remote
+			if _, _, startLine, endLine, ok := m.selectionSource(); ok {
+				return m.copyPermalink(m.previewPath, false, startLine, endLine)
+			}
+			return m, nil
+
+		case "a":
+			// Stage the selection in the basket
+			return m.addSelectionToBasket()
+
+		case "e":
+			// Keep the selection as an example in a context doc
+			return m.openSnippetDocPicker()
+
+		case "s":
+			// Send the selection as a path + line range reference
+			if _, _, startLine, endLine, ok := m.selectionSource(); ok {
+				return m.sendRefs([]string{"@" + lineRef(m.previewRelPath(), startLine, endLine)})
+			}
+			return m, nil
+
+		// Cursor movement (scrolls to keep the cursor visible)
 		case "j", "down":
-			m.preview.LineDown(1)
+			m.moveSelectCursor(1)
 			return m, nil
 		case "k", "up":
-			m.preview.LineUp(1)
+			m.moveSelectCursor(-1)
 			return m, nil
 		case "d", "ctrl+d":
-			m.preview.HalfViewDown()
+			m.moveSelectCursor(m.preview.Height / 2)
 			return m, nil
 		case "u", "ctrl+u":
-			m.preview.HalfViewUp()
+			m.moveSelectCursor(-m.preview.Height / 2)
 			return m, nil
 		case "g":
-			m.preview.GotoTop()
+			m.moveSelectCursor(-len(m.previewLines))
 			return m, nil
 		case "G":
-			m.preview.GotoBottom()
+			m.moveSelectCursor(len(m.previewLines))
 			return m, nil
 		}
 
@@ -1001,8 +1841,10 @@ func (m Model) updateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.Button == tea.MouseButtonLeft {
 				// Start selection
 				m.isSelecting = true
+				m.visualLine = false
 				m.selectStart = clickedLine
 				m.selectEnd = clickedLine
+				m.selectCursor = clickedLine
 			}
 
 		case tea.MouseActionRelease:
@@ -1051,9 +1893,29 @@ func (m Model) updateSelect(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// moveSelectCursor moves the copy-mode cursor, extending the selection in visual line mode
+func (m *Model) moveSelectCursor(delta int) {
+	m.selectCursor += delta
+	if m.selectCursor >= len(m.previewLines) {
+		m.selectCursor = len(m.previewLines) - 1
+	}
+	if m.selectCursor < 0 {
+		m.selectCursor = 0
+	}
+	if m.visualLine {
+		m.selectEnd = m.selectCursor
+	}
+
+	if m.selectCursor < m.preview.YOffset {
+		m.preview.SetYOffset(m.selectCursor)
+	} else if m.selectCursor >= m.preview.YOffset+m.preview.Height {
+		m.preview.SetYOffset(m.selectCursor - m.preview.Height + 1)
+	}
+}
+
 // copySelection copies the selected lines from preview to clipboard
-func (m Model) copySelection() error {
-	return clipboard.CopyLines(m.previewLines, m.selectStart, m.selectEnd, StripLineNumbers)
+func (m *Model) copySelection() error {
+	return m.copyText("selection", clipboard.ExtractLines(m.previewLines, m.selectStart, m.selectEnd, StripLineNumbers))
 }
 
 // detectFileDrop checks if pasted text is a file path and returns the cleaned path
@@ -1103,6 +1965,20 @@ func isWindowsPath(text string) bool {
 	return false
 }
 
+// readOnlyNotice explains why a change was refused
+func (m Model) readOnlyNotice() (tea.Model, tea.Cmd) {
+	m.statusMessage = "Read-only mode: changes are disabled"
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
+// rootEntryNotice explains that an extra root can't be renamed or deleted from the tree
+func (m Model) rootEntryNotice() (tea.Model, tea.Cmd) {
+	m.statusMessage = "Roots given on the command line can't be renamed or deleted"
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
+
 // handleFileDrop initiates the file import workflow
 func (m Model) handleFileDrop(sourcePath string) (tea.Model, tea.Cmd) {
 	// Don't allow if another overlay is active
@@ -1110,6 +1986,9 @@ func (m Model) handleFileDrop(sourcePath string) (tea.Model, tea.Cmd) {
 		m.gitStatusMode || m.fileOpMode != FileOpNone {
 		return m, nil
 	}
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
 
 	m.clearAllOverlays()
 	m.fileOpMode = FileOpImport
@@ -5,9 +5,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/connorleisz/contexTUI/internal/groups"
 )
 
 // updateFileOp handles file operation overlay interactions
@@ -22,12 +26,27 @@ func (m Model) updateFileOp(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.fileOpError = ""
 			m.fileOpConfirm = false
 			m.fileOpScrollOffset = 0
+			m.fileOpCompletions = nil
 			m.fileOpSourcePath = "" // Clear import source
 			return m, nil
 
+		case "tab", "shift+tab":
+			if m.fileOpMode == FileOpChmod {
+				if perm, err := parseOctalMode(m.fileOpInput.Value()); err == nil {
+					m.fileOpInput.SetValue(formatOctalMode(toggleExecutable(perm)))
+					m.fileOpInput.CursorEnd()
+				}
+				return m, nil
+			}
+			// Complete the last path segment with a directory, cycling on repeat
+			if m.fileOpMode != FileOpDelete {
+				m.completeFileOpPath(msg.String() == "shift+tab")
+				return m, nil
+			}
+
 		case "enter":
 			if m.fileOpMode == FileOpDelete {
-				if !m.fileOpConfirm {
+				if !m.fileOpConfirm && m.shouldConfirm(true) {
 					// First enter shows confirmation
 					m.fileOpConfirm = true
 					return m, nil
@@ -35,12 +54,17 @@ func (m Model) updateFileOp(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Second enter executes delete
 				return m, m.executeFileOp()
 			}
-			// For create/rename, validate and execute
+			// For create/rename/chmod, validate and execute
 			name := m.fileOpInput.Value()
-			if err := m.validateFileName(name); err != nil {
+			if err := m.validateFileOpInput(name); err != nil {
 				m.fileOpError = err.Error()
 				return m, nil
 			}
+			// With the "always" confirm policy, a second enter goes ahead
+			if !m.fileOpConfirm && m.shouldConfirm(false) {
+				m.fileOpConfirm = true
+				return m, nil
+			}
 			return m, m.executeFileOp()
 
 		case "y", "Y":
@@ -63,39 +87,248 @@ func (m Model) updateFileOp(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Update text input for create/rename
+	// Update text input for create/rename, validating as the user types
 	if m.fileOpMode != FileOpDelete {
 		var cmd tea.Cmd
+		before := m.fileOpInput.Value()
 		m.fileOpInput, cmd = m.fileOpInput.Update(msg)
-		m.fileOpError = "" // Clear error on typing
+		if value := m.fileOpInput.Value(); value != before {
+			m.fileOpCompletions = nil
+			m.fileOpError = ""
+			m.fileOpConfirm = false
+			if value != "" {
+				if err := m.validateFileOpInput(value); err != nil {
+					m.fileOpError = err.Error()
+				}
+			}
+		}
 		return m, cmd
 	}
 
 	return m, nil
 }
 
+// startRename opens the rename prompt for the tree entry under the cursor
+func (m Model) startRename() (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) {
+		return m, nil
+	}
+	e := flat[m.cursor]
+	if e.Root {
+		return m.rootEntryNotice()
+	}
+	m.clearAllOverlays()
+	m.fileOpMode = FileOpRename
+	m.fileOpInput.SetValue(e.Name)
+	m.fileOpInput.Placeholder = "new name"
+	m.fileOpInput.Focus()
+	// Select all text for easy replacement
+	m.fileOpInput.CursorEnd()
+	m.fileOpTargetPath = e.Path
+	return m, textinput.Blink
+}
+
+// startDelete asks to delete the tree entry under the cursor
+func (m Model) startDelete() (tea.Model, tea.Cmd) {
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	flat := m.FlatEntries()
+	if m.cursor >= len(flat) {
+		return m, nil
+	}
+	e := flat[m.cursor]
+	if e.Root {
+		return m.rootEntryNotice()
+	}
+	m.clearAllOverlays()
+	m.fileOpMode = FileOpDelete
+	m.fileOpTargetPath = e.Path
+	return m, nil
+}
+
 // executeFileOp performs the actual file system operation asynchronously
 func (m Model) executeFileOp() tea.Cmd {
 	switch m.fileOpMode {
 	case FileOpCreateFile:
-		newPath := filepath.Join(m.fileOpTargetPath, m.fileOpInput.Value())
-		return createFileAsync(newPath)
+		return createFileAsync(m.fileOpPath())
 	case FileOpCreateFolder:
-		newPath := filepath.Join(m.fileOpTargetPath, m.fileOpInput.Value())
-		return createFolderAsync(newPath)
+		return createFolderAsync(m.fileOpPath())
 	case FileOpRename:
-		dir := filepath.Dir(m.fileOpTargetPath)
-		newPath := filepath.Join(dir, m.fileOpInput.Value())
-		return renameAsync(m.fileOpTargetPath, newPath)
+		newPath := m.fileOpPath()
+		var docs []groups.ContextDoc
+		if m.docRegistry != nil {
+			docs = append(docs, m.docRegistry.Docs...)
+		}
+		return renameAsync(m.rootPath, m.fileOpTargetPath, newPath, docs, m.config.BackupFiles)
 	case FileOpDelete:
 		return deleteAsync(m.fileOpTargetPath)
 	case FileOpImport:
-		destPath := filepath.Join(m.fileOpTargetPath, m.fileOpInput.Value())
-		return copyFileAsync(m.fileOpSourcePath, destPath)
+		return copyFileAsync(FileOpImport, m.fileOpSourcePath, m.fileOpPath())
+	case FileOpDuplicate:
+		return copyFileAsync(FileOpDuplicate, m.fileOpSourcePath, m.fileOpPath())
+	case FileOpChmod:
+		perm, _ := parseOctalMode(m.fileOpInput.Value())
+		return chmodAsync(m.fileOpTargetPath, octalToMode(perm))
 	}
 	return nil
 }
 
+// fileOpBaseDir returns the directory the typed name is relative to: the parent
+// of the renamed entry, otherwise the target directory
+func (m Model) fileOpBaseDir() string {
+	if m.fileOpMode == FileOpRename {
+		return filepath.Dir(m.fileOpTargetPath)
+	}
+	return m.fileOpTargetPath
+}
+
+// fileOpPath returns the path the typed name resolves to
+func (m Model) fileOpPath() string {
+	return filepath.Join(m.fileOpBaseDir(), filepath.FromSlash(m.fileOpInput.Value()))
+}
+
+// completeFileOpPath completes the last segment of the typed path with a matching
+// directory. Repeating it cycles through the matches (backwards if reverse); typing
+// anything else starts over from what was typed.
+func (m *Model) completeFileOpPath(reverse bool) {
+	if m.fileOpCompletions == nil {
+		value := m.fileOpInput.Value()
+		dirPart, partial := "", value
+		if i := strings.LastIndex(value, "/"); i >= 0 {
+			dirPart, partial = value[:i+1], value[i+1:]
+		}
+		entries, err := os.ReadDir(filepath.Join(m.fileOpBaseDir(), filepath.FromSlash(dirPart)))
+		if err != nil {
+			return
+		}
+		var matches []string
+		for _, e := range entries {
+			name := e.Name()
+			if !e.IsDir() || !strings.HasPrefix(strings.ToLower(name), strings.ToLower(partial)) {
+				continue
+			}
+			// Hidden directories only when asked for
+			if strings.HasPrefix(name, ".") && !strings.HasPrefix(partial, ".") {
+				continue
+			}
+			matches = append(matches, dirPart+name+"/")
+		}
+		if len(matches) == 0 {
+			m.fileOpError = "no matching folders"
+			return
+		}
+		m.fileOpCompletions = matches
+		m.fileOpCompletion = 0
+		if reverse {
+			m.fileOpCompletion = len(matches) - 1
+		}
+	} else {
+		n := len(m.fileOpCompletions)
+		if reverse {
+			m.fileOpCompletion = (m.fileOpCompletion + n - 1) % n
+		} else {
+			m.fileOpCompletion = (m.fileOpCompletion + 1) % n
+		}
+	}
+	m.fileOpInput.SetValue(m.fileOpCompletions[m.fileOpCompletion])
+	m.fileOpInput.CursorEnd()
+	m.fileOpError = ""
+}
+
+// maxDuplicateNames bounds the names duplicateName tries
+const maxDuplicateNames = 1000
+
+// duplicateName suggests a name for a copy of the file: name.copy.ext, then
+// name.copy2.ext and so on until one is free
+func duplicateName(path string) (string, error) {
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	if stem == "" { // Dotfiles like .env have no extension
+		stem, ext = base, ""
+	}
+	for i := 1; i <= maxDuplicateNames; i++ {
+		suffix := ".copy"
+		if i > 1 {
+			suffix = fmt.Sprintf(".copy%d", i)
+		}
+		name := stem + suffix + ext
+		_, err := os.Stat(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no free name for a copy of %s", base)
+}
+
+// addFileToTree shows a file just created by the app in the tree and search and
+// selects it, without waiting for the watcher to reload the directory
+func (m Model) addFileToTree(path string) Model {
+	relPath, err := filepath.Rel(m.rootPath, path)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return m
+	}
+	e := Entry{Name: filepath.Base(path), Path: path, RelPath: relPath}
+	if parent := filepath.Dir(path); parent == m.rootPath {
+		m.entries = insertEntry(m.entries, e)
+	} else {
+		m.entries = insertChildEntry(m.entries, parent, e)
+	}
+	found := false
+	for _, f := range m.allFiles {
+		if f == relPath {
+			found = true
+			break
+		}
+	}
+	if !found {
+		m.allFiles = append(m.allFiles, relPath)
+	}
+	m = m.NavigateToFile(relPath)
+	m.ensureTreeCursorVisible()
+	return m
+}
+
+// insertChildEntry adds e to the children of the expanded directory parent
+// Collapsed directories are skipped; they read the file from disk when expanded.
+func insertChildEntry(entries []Entry, parent string, e Entry) []Entry {
+	for i, d := range entries {
+		if !d.IsDir || !d.Expanded {
+			continue
+		}
+		if d.Path == parent {
+			e.Depth = d.Depth + 1
+			entries[i].Children = insertEntry(d.Children, e)
+			return entries
+		}
+		if strings.HasPrefix(parent, d.Path+string(filepath.Separator)) {
+			entries[i].Children = insertChildEntry(d.Children, parent, e)
+			return entries
+		}
+	}
+	return entries
+}
+
+// insertEntry adds e in name order, like os.ReadDir lists it, unless it is listed already
+func insertEntry(entries []Entry, e Entry) []Entry {
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].Name >= e.Name })
+	if i < len(entries) && entries[i].Name == e.Name {
+		return entries
+	}
+	entries = append(entries, Entry{})
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+	return entries
+}
+
 // getTargetDirectory returns the directory for creating new files
 // If cursor is on a directory, returns that directory
 // If cursor is on a file, returns its parent directory
@@ -111,36 +344,125 @@ func (m Model) getTargetDirectory() string {
 	return m.rootPath
 }
 
+// validateFileOpInput checks the overlay's input: a mode for chmod, otherwise a name
+func (m Model) validateFileOpInput(value string) error {
+	if m.fileOpMode == FileOpChmod {
+		_, err := parseOctalMode(value)
+		return err
+	}
+	return m.validateFileName(value)
+}
+
+// parseOctalMode parses a mode like 755 or 4755
+func parseOctalMode(s string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || len(s) < 3 || len(s) > 4 {
+		return 0, fmt.Errorf("mode must be 3 or 4 octal digits, like 755")
+	}
+	return uint32(v), nil
+}
+
+// formatOctalMode formats a mode as parseOctalMode reads it
+func formatOctalMode(perm uint32) string {
+	if perm > 0777 {
+		return fmt.Sprintf("%04o", perm)
+	}
+	return fmt.Sprintf("%03o", perm)
+}
+
+// modeToOctal returns the octal mode chmod(1) would show for mode
+func modeToOctal(mode os.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= 01000
+	}
+	return perm
+}
+
+// octalToMode converts an octal mode into what os.Chmod expects
+func octalToMode(perm uint32) os.FileMode {
+	mode := os.FileMode(perm & 0777)
+	if perm&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if perm&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if perm&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+// toggleExecutable clears every executable bit, or if there are none, sets them for
+// whoever can read the file, like chmod +x
+func toggleExecutable(perm uint32) uint32 {
+	if perm&0111 != 0 {
+		return perm &^ 0111
+	}
+	return perm | (perm&0444)>>2
+}
+
 // validateFileName checks if a filename is valid
+// It may be a path (a/b/c.txt); missing folders along it are created.
 func (m Model) validateFileName(name string) error {
 	if name == "" {
 		return fmt.Errorf("name cannot be empty")
 	}
-	if strings.Contains(name, "/") || strings.Contains(name, "\\") {
-		return fmt.Errorf("name cannot contain path separators")
+	if strings.HasPrefix(name, "/") {
+		return fmt.Errorf("path must be relative")
 	}
-	if strings.Contains(name, "\x00") {
-		return fmt.Errorf("name contains invalid characters")
+	if strings.Contains(name, "\\") {
+		return fmt.Errorf("use / to separate folders")
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("name contains invalid characters")
+		}
+	}
+	segments := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	for _, seg := range segments {
+		switch seg {
+		case "":
+			return fmt.Errorf("path contains an empty folder name")
+		case ".", "..":
+			return fmt.Errorf("path cannot contain '%s'", seg)
+		}
+	}
+	if strings.HasSuffix(name, "/") && m.fileOpMode != FileOpCreateFolder {
+		return fmt.Errorf("name cannot end with /")
 	}
 
+	fullPath := m.fileOpPath()
+
 	// For rename, check if renaming to same name (allow it as no-op)
 	if m.fileOpMode == FileOpRename {
-		currentName := filepath.Base(m.fileOpTargetPath)
-		if name == currentName {
+		if fullPath == m.fileOpTargetPath {
 			return nil // Same name is fine
 		}
+		if strings.HasPrefix(fullPath, m.fileOpTargetPath+string(filepath.Separator)) {
+			return fmt.Errorf("cannot move a folder into itself")
+		}
 	}
 
-	// Check if file already exists (for create and rename to different name)
-	var targetDir string
-	if m.fileOpMode == FileOpRename {
-		targetDir = filepath.Dir(m.fileOpTargetPath)
-	} else {
-		targetDir = m.fileOpTargetPath
+	// Folders along the path must not be files
+	dir := m.fileOpBaseDir()
+	for _, seg := range segments[:len(segments)-1] {
+		dir = filepath.Join(dir, seg)
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			return fmt.Errorf("'%s' is a file", seg)
+		}
 	}
-	fullPath := filepath.Join(targetDir, name)
+
+	// Check if file already exists (for create and rename to different name)
 	if _, err := os.Stat(fullPath); err == nil {
-		return fmt.Errorf("'%s' already exists", name)
+		return fmt.Errorf("'%s' already exists", strings.TrimSuffix(name, "/"))
 	}
 
 	return nil
@@ -174,17 +496,37 @@ func createFolderAsync(path string) tea.Cmd {
 	}
 }
 
-func renameAsync(oldPath, newPath string) tea.Cmd {
+// renameAsync renames a file or folder, then rewrites the doc references to its old path
+func renameAsync(rootPath, oldPath, newPath string, docs []groups.ContextDoc, backup bool) tea.Cmd {
 	return func() tea.Msg {
 		// Check if renaming to same path (no-op)
 		if oldPath == newPath {
 			return FileOpCompleteMsg{Op: FileOpRename, Success: true, NewPath: newPath}
 		}
+		// Create parent directories if needed
+		if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return FileOpCompleteMsg{Op: FileOpRename, Success: false, Error: err}
+		}
 		err := os.Rename(oldPath, newPath)
 		if err != nil {
 			return FileOpCompleteMsg{Op: FileOpRename, Success: false, Error: err}
 		}
-		return FileOpCompleteMsg{Op: FileOpRename, Success: true, NewPath: newPath}
+		msg := FileOpCompleteMsg{Op: FileOpRename, Success: true, NewPath: newPath}
+		oldRel, err1 := filepath.Rel(rootPath, oldPath)
+		newRel, err2 := filepath.Rel(rootPath, newPath)
+		if err1 == nil && err2 == nil && len(docs) > 0 {
+			msg.UpdatedDocs, msg.DocsErr = groups.RenamePackageKeyFiles(rootPath, oldRel, newRel, docs, backup)
+		}
+		return msg
+	}
+}
+
+func chmodAsync(path string, mode os.FileMode) tea.Cmd {
+	return func() tea.Msg {
+		if err := os.Chmod(path, mode); err != nil {
+			return FileOpCompleteMsg{Op: FileOpChmod, Success: false, Error: err}
+		}
+		return FileOpCompleteMsg{Op: FileOpChmod, Success: true, NewPath: path}
 	}
 }
 
@@ -198,36 +540,37 @@ func deleteAsync(path string) tea.Cmd {
 	}
 }
 
-func copyFileAsync(src, dst string) tea.Cmd {
+// copyFileAsync copies a file, for imports and duplicates
+func copyFileAsync(op FileOpMode, src, dst string) tea.Cmd {
 	return func() tea.Msg {
 		srcFile, err := os.Open(src)
 		if err != nil {
-			return FileOpCompleteMsg{Op: FileOpImport, Success: false, Error: err}
+			return FileOpCompleteMsg{Op: op, Success: false, Error: err}
 		}
 		defer srcFile.Close()
 
 		srcInfo, err := srcFile.Stat()
 		if err != nil {
-			return FileOpCompleteMsg{Op: FileOpImport, Success: false, Error: err}
+			return FileOpCompleteMsg{Op: op, Success: false, Error: err}
 		}
 
 		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-			return FileOpCompleteMsg{Op: FileOpImport, Success: false, Error: err}
+			return FileOpCompleteMsg{Op: op, Success: false, Error: err}
 		}
 
 		dstFile, err := os.Create(dst)
 		if err != nil {
-			return FileOpCompleteMsg{Op: FileOpImport, Success: false, Error: err}
+			return FileOpCompleteMsg{Op: op, Success: false, Error: err}
 		}
 		defer dstFile.Close()
 
 		if _, err := io.Copy(dstFile, srcFile); err != nil {
-			return FileOpCompleteMsg{Op: FileOpImport, Success: false, Error: err}
+			return FileOpCompleteMsg{Op: op, Success: false, Error: err}
 		}
 
 		// Preserve permissions (non-fatal if fails)
 		os.Chmod(dst, srcInfo.Mode())
 
-		return FileOpCompleteMsg{Op: FileOpImport, Success: true, NewPath: dst}
+		return FileOpCompleteMsg{Op: op, Success: true, NewPath: dst}
 	}
 }
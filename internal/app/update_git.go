@@ -7,8 +7,6 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/connorleisz/contexTUI/internal/clipboard"
-	"github.com/connorleisz/contexTUI/internal/config"
 	"github.com/connorleisz/contexTUI/internal/git"
 )
 
@@ -16,6 +14,9 @@ import (
 func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.gitStashMode && !stashSharedKeys[msg.String()] {
+			return m.updateStashKey(msg)
+		}
 		switch msg.String() {
 		// Exit git status
 		case "esc", "s":
@@ -29,13 +30,11 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Navigation - behavior depends on active pane
 		case "j", "down":
 			if m.activePane == TreePane {
-				if m.gitStatusCursor < len(m.gitChanges)-1 {
-					m.gitStatusCursor++
-					// Update viewport content and auto-scroll
+				if m.moveGitCursor(1) {
+					// Update viewport content and auto-scroll (rows are lines)
 					m.gitList.SetContent(m.renderGitFileList())
-					cursorLine := m.gitCursorToLine(m.gitStatusCursor)
-					if cursorLine >= m.gitList.YOffset+m.gitList.Height {
-						m.gitList.LineDown(1)
+					if m.gitStatusCursor >= m.gitList.YOffset+m.gitList.Height {
+						m.gitList.SetYOffset(m.gitStatusCursor - m.gitList.Height + 1)
 					}
 					return m.UpdateGitStatusPreview()
 				}
@@ -46,13 +45,15 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "k", "up":
 			if m.activePane == TreePane {
-				if m.gitStatusCursor > 0 {
-					m.gitStatusCursor--
-					// Update viewport content and auto-scroll
+				if m.moveGitCursor(-1) {
+					// Update viewport content and auto-scroll, showing the section heading above
 					m.gitList.SetContent(m.renderGitFileList())
-					cursorLine := m.gitCursorToLine(m.gitStatusCursor)
-					if cursorLine < m.gitList.YOffset {
-						m.gitList.LineUp(1)
+					top := m.gitStatusCursor
+					if rows := m.gitRows(); top > 0 && rows[top-1].kind == gitRowSection {
+						top--
+					}
+					if top < m.gitList.YOffset {
+						m.gitList.SetYOffset(top)
 					}
 					return m.UpdateGitStatusPreview()
 				}
@@ -62,39 +63,76 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "enter", "l":
+			// Fold or unfold a directory
+			if row, ok := m.selectedGitRow(); ok && row.kind == gitRowDir {
+				m.toggleGitFold(false)
+				m.gitList.SetContent(m.renderGitFileList())
+				return m, nil
+			}
 			// Navigate to file in tree view
-			if m.gitStatusCursor < len(m.gitChanges) {
-				change := m.gitChanges[m.gitStatusCursor]
+			if change, ok := m.selectedGitChange(); ok {
 				m.gitStatusMode = false
 				m = m.NavigateToFile(change.Path)
-				m.tree.SetContent(m.RenderTree())
+				m.ensureTreeCursorVisible()
 				var cmd tea.Cmd
 				m, cmd = m.UpdatePreview()
 				return m, cmd
 			}
 
+		// Fold the directory under the cursor, or all of them
+		case "h":
+			m.toggleGitFold(true)
+			m.gitList.SetContent(m.renderGitFileList())
+			return m.UpdateGitStatusPreview()
+		case "Z":
+			m.toggleAllGitFolds()
+			m.gitList.SetContent(m.renderGitFileList())
+			return m.UpdateGitStatusPreview()
+
 		case "tab":
 			if m.activePane == TreePane {
 				m.activePane = PreviewPane
 			} else {
 				m.activePane = TreePane
 			}
+			m.followActivePane()
 			return m, nil
 
+		// Pane layouts - SHARED
+		case "[":
+			return m.setLayout(layoutTree)
+		case "]":
+			return m.setLayout(layoutPreview)
+		case "|":
+			return m.resetLayout()
+
 		// Pane resize - SHARED
 		case "left":
-			m.HandlePaneResize("left")
+			if m.activePane != PreviewPane || !m.panPreview(-previewPanStep) {
+				m.HandlePaneResize("left")
+			}
 			return m, nil
 		case "right":
-			m.HandlePaneResize("right")
+			if m.activePane != PreviewPane || !m.panPreview(previewPanStep) {
+				m.HandlePaneResize("right")
+			}
+			return m, nil
+		case "H":
+			m.panPreview(-previewPanStep)
+			return m, nil
+		case "L":
+			m.panPreview(previewPanStep)
 			return m, nil
 
+		// Toggle preview line wrapping - SHARED
+		case "w":
+			return m.togglePreviewWrap()
+
 		// Copy file path - SHARED
 		case "c":
-			if m.gitStatusCursor < len(m.gitChanges) {
-				change := m.gitChanges[m.gitStatusCursor]
+			if change, ok := m.selectedGitChange(); ok {
 				fullPath := filepath.Join(m.gitRepoRoot, change.Path)
-				if err := clipboard.CopyFilePath(fullPath); err != nil {
+				if err := m.copyText("file", "@"+fullPath); err != nil {
 					m.statusMessage = "Clipboard unavailable"
 				} else {
 					m.statusMessage = "Copied!"
@@ -104,6 +142,22 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		// Copy the diff of the file or directory as a fenced patch
+		case "y":
+			return m.copyGitPatch()
+
+		// Copy a commit message prompt for the staged changes
+		case "m":
+			return m.copyCommitPrompt()
+
+		// Copy a pull request description prompt for the branch
+		case "P":
+			return m.copyPRPrompt()
+
+		// Stage the change's diff in the basket
+		case "a":
+			return m.addGitChangeToBasket()
+
 		// Enter search mode - SHARED
 		case "/":
 			m.clearAllOverlays()
@@ -129,10 +183,15 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.selectStart = -1
 			m.selectEnd = -1
 			m.isSelecting = false
+			m.selectCursor = m.preview.YOffset
+			m.visualLine = false
 			return m, nil
 
 		// Git fetch - SHARED
 		case "f":
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
 			if m.isGitRepo && !m.gitFetching {
 				m.gitFetching = true
 				repoRoot := m.gitRepoRoot
@@ -143,6 +202,14 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		// Release notes from changelog and tags
+		case "n":
+			return m.openReleaseNotes()
+
+		// List stashes in place of the changes
+		case "z":
+			return m.openStashes()
+
 		// Preview scrolling
 		case "ctrl+d":
 			m.HandlePreviewScroll("half-down")
@@ -162,7 +229,7 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.draggingSplit {
 			if msg.Action == tea.MouseActionRelease {
 				m.draggingSplit = false
-				config.Save(m.rootPath, config.Config{SplitRatio: m.splitRatio})
+				m.saveConfig()
 			} else if msg.Action == tea.MouseActionMotion {
 				newRatio := float64(msg.X) / float64(m.width)
 				if newRatio < 0.2 {
@@ -171,15 +238,14 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 					newRatio = 0.8
 				}
 				m.splitRatio = newRatio
-				m.tree.Width = m.LeftPaneWidth() - 2
-				m.preview.Width = m.RightPaneWidth() - 2
+				m.applyPaneWidths()
 			}
 			return m, nil
 		}
 
 		// Check for divider click
 		nearDivider := msg.X >= divX-2 && msg.X <= divX+2
-		if msg.Button == tea.MouseButtonLeft && nearDivider {
+		if msg.Button == tea.MouseButtonLeft && nearDivider && m.shownLayout() == layoutSplit {
 			m.draggingSplit = true
 			return m, nil
 		}
@@ -213,9 +279,8 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 			clickedLine := msg.Y - headerOffset
 			// Add viewport offset to get actual content line
 			contentLine := clickedLine + m.gitList.YOffset
-			clickedIndex := m.gitLineToIndex(contentLine)
-			if clickedIndex >= 0 && clickedIndex < len(m.gitChanges) {
-				m.gitStatusCursor = clickedIndex
+			if rows := m.gitRows(); contentLine >= 0 && contentLine < len(rows) && rows[contentLine].selectable() {
+				m.gitStatusCursor = contentLine
 				m.gitList.SetContent(m.renderGitFileList())
 				return m.UpdateGitStatusPreview()
 			}
@@ -232,12 +297,12 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.preview.GotoTop()
 
 			if m.previewCache == nil {
-				m.previewCache = make(map[string]CachedPreview)
+				m.previewCache = newPreviewCache(m.config)
 			}
-			m.previewCache[msg.Path] = CachedPreview{
+			m.previewCache.Put(msg.Path, CachedPreview{
 				Content: msg.Content,
 				ModTime: msg.ModTime,
-			}
+			})
 		}
 		return m, nil
 
@@ -256,14 +321,14 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Cache the quick diff
 		if m.diffCache == nil {
-			m.diffCache = make(map[DiffCacheKey]CachedDiff)
+			m.diffCache = newDiffCache(m.config)
 		}
 		quickKey := DiffCacheKey{Path: msg.Path, Staged: msg.Staged, ContextSize: quickDiffContext}
-		m.diffCache[quickKey] = CachedDiff{
+		m.diffCache.Put(quickKey, CachedDiff{
 			Content:     msg.Content,
 			ModTime:     msg.ModTime,
 			ContextSize: quickDiffContext,
-		}
+		})
 
 		// Trigger background full diff load
 		m.fullDiffLoading = msg.Path
@@ -271,7 +336,7 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Extract relative path for git command
 		relPath, _ := filepath.Rel(m.gitRepoRoot, msg.Path)
-		previewWidth := m.preview.Width
+		previewWidth := m.previewRenderWidth()
 		requestID := msg.RequestID
 		staged := msg.Staged
 		repoRoot := m.gitRepoRoot
@@ -304,14 +369,14 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Cache the full diff
 		if m.diffCache == nil {
-			m.diffCache = make(map[DiffCacheKey]CachedDiff)
+			m.diffCache = newDiffCache(m.config)
 		}
 		fullKey := DiffCacheKey{Path: msg.Path, Staged: msg.Staged, ContextSize: fullDiffContext}
-		m.diffCache[fullKey] = CachedDiff{
+		m.diffCache.Put(fullKey, CachedDiff{
 			Content:     msg.Content,
 			ModTime:     msg.ModTime,
 			ContextSize: fullDiffContext,
-		}
+		})
 
 		// Clear loading state
 		m.fullDiffLoading = ""
@@ -320,112 +385,3 @@ func (m Model) updateGitStatus(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 	return m, nil
 }
-
-// gitLineToIndex converts a content line number to an index in gitChanges
-// This accounts for category headers in the rendered output
-func (m Model) gitLineToIndex(clickedLine int) int {
-	staged, unstaged, untracked := m.CategorizeGitChanges()
-
-	currentLine := 0 // Viewport content starts at line 0
-	idx := 0
-
-	if len(staged) > 0 {
-		currentLine++ // "Staged Changes" header
-		for range staged {
-			if clickedLine == currentLine {
-				return idx
-			}
-			currentLine++
-			idx++
-		}
-		currentLine++ // Blank line after section
-	}
-
-	if len(unstaged) > 0 {
-		currentLine++ // "Changes not staged" header
-		for range unstaged {
-			if clickedLine == currentLine {
-				return idx
-			}
-			currentLine++
-			idx++
-		}
-		currentLine++ // Blank line
-	}
-
-	if len(untracked) > 0 {
-		currentLine++ // "Untracked files" header
-		for range untracked {
-			if clickedLine == currentLine {
-				return idx
-			}
-			currentLine++
-			idx++
-		}
-	}
-
-	return -1 // Not on a file line
-}
-
-// CategorizeGitChanges returns staged, unstaged, and untracked files separately
-func (m Model) CategorizeGitChanges() (staged, unstaged, untracked []git.FileStatus) {
-	for _, c := range m.gitChanges {
-		if c.Status == "?" {
-			untracked = append(untracked, c)
-		} else if c.Staged {
-			staged = append(staged, c)
-		} else {
-			unstaged = append(unstaged, c)
-		}
-	}
-	return
-}
-
-// gitCursorToLine converts a cursor index into the line number in the rendered git list
-// This accounts for section headers and blank lines between sections
-func (m Model) gitCursorToLine(cursor int) int {
-	staged, unstaged, untracked := m.CategorizeGitChanges()
-
-	line := 0
-	idx := 0
-
-	// Staged section
-	if len(staged) > 0 {
-		line++ // "Staged Changes" header
-		for range staged {
-			if idx == cursor {
-				return line
-			}
-			line++
-			idx++
-		}
-		line++ // Blank line after section
-	}
-
-	// Unstaged section
-	if len(unstaged) > 0 {
-		line++ // "Changes not staged" header
-		for range unstaged {
-			if idx == cursor {
-				return line
-			}
-			line++
-			idx++
-		}
-		line++ // Blank line after section
-	}
-
-	// Untracked section
-	if len(untracked) > 0 {
-		line++ // "Untracked files" header
-		for range untracked {
-			if idx == cursor {
-				return line
-			}
-			line++
-			idx++
-		}
-	}
-
-	return line
-}
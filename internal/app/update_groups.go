@@ -7,17 +7,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/connorleisz/contexTUI/internal/clipboard"
+	"github.com/connorleisz/contexTUI/internal/basket"
 	"github.com/connorleisz/contexTUI/internal/groups"
 )
 
-// StructureNeededTag is inserted into files that need context doc structuring
-const StructureNeededTag = "<!-- contexTUI: structure-needed -->\n"
-
 // StructuringPrompt is copied when user presses 'p' in docs overlay
-const StructuringPrompt = `Find all markdown files in this project containing the comment:
-<!-- contexTUI: structure-needed -->
+const StructuringPrompt = `Find all markdown files in this project containing a comment block starting with:
+<!-- contexTUI: structure-needed
 
 For each file, read .context-docs.md to understand the required structure,
 then update the file to include:
@@ -37,7 +35,32 @@ Key Files format (required):
 Each entry must start with "- " followed by the file path. Description after " - " is optional.
 Tables are NOT supported for Key Files.
 
-Remove the <!-- contexTUI: structure-needed --> tag after structuring.`
+The tag block lists the fields that are still missing. Leave it in place:
+contexTUI updates it as fields are filled in and removes it once the doc is complete.`
+
+// exportAgentsFile writes the doc registry into the generated section of an agent instruction file
+func (m Model) exportAgentsFile(fileName string) (tea.Model, tea.Cmd) {
+	if m.docRegistry == nil {
+		return m, nil
+	}
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	if !m.confirmAgain("export\x00"+fileName, false, "Press again to update "+fileName) {
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+	changed, err := groups.ExportAgentsFile(m.rootPath, fileName, m.docRegistry, m.config.BackupFiles)
+	switch {
+	case err != nil:
+		m.statusMessage = fmt.Sprintf("Error: %v", err)
+	case changed:
+		m.statusMessage = "Updated " + fileName
+	default:
+		m.statusMessage = fileName + " already up to date"
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(3 * time.Second)
+}
 
 // updateDocs handles the context docs overlay
 func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -45,6 +68,12 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.addingDoc {
 		return m.updateAddDoc(msg)
 	}
+	if m.managingCategories {
+		return m.updateCategories(msg)
+	}
+	if m.docsSearchInput.Focused() {
+		return m.updateDocsSearch(msg)
+	}
 
 	// Get docs for current category
 	currentDocs := m.getDocsForSelectedCategory()
@@ -54,14 +83,38 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "esc":
+			// The first esc drops the filter
+			if m.docsQuery != "" {
+				m.docsQuery = ""
+				m.docsSearchInput.SetValue("")
+				m.docCursor = 0
+				m.docsScrollOffset = 0
+				return m, nil
+			}
 			// Save immediately if dirty before closing
 			if m.registryDirty && !m.registrySaving {
-				groups.SaveContextDocRegistry(m.rootPath, m.docRegistry)
 				m.registryDirty = false
+				m.saveRegistry()
 			}
 			m.showingDocs = false
 			return m, nil
 
+		case "#":
+			// Filter by the next tag, then back to no filter
+			if m.docRegistry != nil {
+				m.docsQuery = nextTagQuery(m.docRegistry.Tags(), m.docsQuery)
+				m.docsSearchInput.SetValue(m.docsQuery)
+				m.docCursor = 0
+				m.docsScrollOffset = 0
+			}
+			return m, nil
+
+		case "/":
+			m.docsSearchInput.SetValue(m.docsQuery)
+			m.docsSearchInput.CursorEnd()
+			m.docsSearchInput.Focus()
+			return m, textinput.Blink
+
 		case "left", "h":
 			// Previous category
 			if m.docRegistry != nil && len(m.docRegistry.Categories) > 0 {
@@ -101,8 +154,12 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "K", "shift+up":
-			// Move doc up in category
-			if m.docCursor > 0 {
+			// Move doc up in category (results of a filter have no order to change)
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
+			if m.docCursor > 0 && m.docsQuery == "" {
+				m.recordUndo("Reorder docs")
 				m.moveDocInCategory(m.docCursor, m.docCursor-1)
 				m.docCursor--
 				m.ensureDocVisible()
@@ -116,7 +173,11 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "J", "shift+down":
 			// Move doc down in category
-			if m.docCursor < totalDocs-1 {
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
+			if m.docCursor < totalDocs-1 && m.docsQuery == "" {
+				m.recordUndo("Reorder docs")
 				m.moveDocInCategory(m.docCursor, m.docCursor+1)
 				m.docCursor++
 				m.ensureDocVisible()
@@ -137,7 +198,7 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 					refs = append(refs, "@"+path)
 				}
 				combined := strings.Join(refs, "\n")
-				if err := clipboard.CopyRaw(combined); err != nil {
+				if err := m.copyText("doc refs", combined); err != nil {
 					m.statusMessage = "Clipboard unavailable"
 				} else {
 					m.statusMessage = fmt.Sprintf("Copied %d references", len(refs))
@@ -149,7 +210,7 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else if m.docCursor < totalDocs {
 				// Copy single current doc as @filepath reference
 				doc := currentDocs[m.docCursor]
-				if err := clipboard.CopyFilePath(doc.FilePath); err != nil {
+				if err := m.copyText("doc", "@"+doc.FilePath); err != nil {
 					m.statusMessage = "Clipboard unavailable"
 				} else {
 					m.statusMessage = fmt.Sprintf("Copied: @%s", doc.FilePath)
@@ -159,7 +220,58 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case "e":
+			return m.exportAgentsFile("CLAUDE.md")
+
+		case "E":
+			return m.exportAgentsFile("AGENTS.md")
+
+		case "S":
+			// Send selected docs (or current) to the agent session
+			refs := m.docRefs()
+			m.selectedDocs = make(map[string]bool)
+			return m.sendRefs(refs)
+
+		case "B":
+			// Stage selected docs (or current) in the basket
+			var items []basket.Item
+			for _, ref := range m.docRefs() {
+				items = append(items, basket.Item{Kind: basket.KindDoc, Path: strings.TrimPrefix(ref, "@")})
+			}
+			if len(items) == 0 {
+				return m, nil
+			}
+			m.selectedDocs = make(map[string]bool)
+			return m.addToBasket(items...)
+
+		case "C":
+			// Copy selected docs (or current) together with their key files
+			refs := m.docGroupRefs()
+			if len(refs) == 0 {
+				return m, nil
+			}
+			if err := m.copyText("doc + key files", strings.Join(refs, "\n")); err != nil {
+				m.statusMessage = "Clipboard unavailable"
+			} else {
+				m.statusMessage = fmt.Sprintf("Copied %d references (docs + key files)", len(refs))
+			}
+			m.selectedDocs = make(map[string]bool)
+			m.statusMessageTime = time.Now()
+			return m, ClearStatusAfter(5 * time.Second)
+
+		case "w":
+			// Write selected docs (or current) and their key files to a file
+			refs := m.docGroupRefs()
+			if len(refs) == 0 {
+				return m, nil
+			}
+			m.selectedDocs = make(map[string]bool)
+			return m.openExport("docs + key files", strings.Join(refs, "\n")+"\n")
+
 		case "a":
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
 			// Find available .md files to add
 			mdFiles, _ := groups.FindMarkdownFiles(m.rootPath)
 			// Filter out already-added files
@@ -186,9 +298,72 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.addingDoc = true
 			return m, nil
 
+		case "v":
+			// Run the doc's verify command
+			if m.docCursor < totalDocs {
+				return m.openVerify(currentDocs[m.docCursor], groups.HookVerify)
+			}
+			return m, nil
+
+		case "t":
+			// Run the doc's test command
+			if m.docCursor < totalDocs {
+				return m.openVerify(currentDocs[m.docCursor], groups.HookTest)
+			}
+			return m, nil
+
+		case "b":
+			// Run the doc's build command
+			if m.docCursor < totalDocs {
+				return m.openVerify(currentDocs[m.docCursor], groups.HookBuild)
+			}
+			return m, nil
+
+		case "f":
+			// Focus the tree on the doc's key files
+			if m.docCursor < totalDocs {
+				return m.focusTreeOnDoc(currentDocs[m.docCursor])
+			}
+			return m, nil
+
+		case "o":
+			// Read the doc full-screen
+			if m.docCursor < totalDocs {
+				return m.openDocReader(currentDocs[m.docCursor])
+			}
+			return m, nil
+
+		case "M":
+			return m.openCategories()
+
+		case "m":
+			// Move the selected docs (or current) to another category
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
+			return m.openMoveDocs()
+
+		case "F":
+			// Filter the tree to the doc's key files
+			if m.docCursor < totalDocs {
+				return m.filterTreeToDoc(currentDocs[m.docCursor])
+			}
+			return m, nil
+
+		case "u":
+			// Take back the last change to the registry or docs
+			return m.undoDocChange(false)
+
+		case "ctrl+r":
+			return m.undoDocChange(true)
+
+		case "U":
+			// Update structure tags found out of date, asked instead of written on load
+			return m.updateOutdatedTags()
+
 		case "p":
 			// Copy the structuring prompt to clipboard
-			if err := clipboard.CopyFilePath(StructuringPrompt); err != nil {
+			if err := m.copyText("prompt", StructuringPrompt); err != nil {
 				m.statusMessage = "Clipboard unavailable"
 			} else {
 				m.statusMessage = "Copied structuring prompt!"
@@ -198,8 +373,15 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "d", "x":
 			// Remove the selected doc from registry
+			if m.readOnly {
+				return m.readOnlyNotice()
+			}
 			if m.docCursor < totalDocs && m.docRegistry != nil {
 				doc := currentDocs[m.docCursor]
+				if !m.confirmAgain("remove\x00"+doc.FilePath, true, "Press d again to remove "+doc.Name+" from the registry") {
+					return m, ClearStatusAfter(3 * time.Second)
+				}
+				snap := m.takeUndo("Remove " + doc.Name)
 
 				// Remove from Docs slice
 				for i, d := range m.docRegistry.Docs {
@@ -247,17 +429,21 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.docCursor = 0
 				}
 
-				// Strip contexTUI metadata from the markdown file
-				stripContextDocMetadata(m.rootPath, doc.FilePath)
-
 				// Save registry
-				if err := groups.SaveContextDocRegistry(m.rootPath, m.docRegistry); err != nil {
+				if err := m.saveRegistry(); err != nil {
 					m.statusMessage = fmt.Sprintf("Error: %v", err)
 				} else {
+					m.pushUndo(snap)
 					m.statusMessage = fmt.Sprintf("Removed %s", doc.Name)
 				}
+
+				// Offer to strip contexTUI metadata from the markdown file
+				var reviewCmd tea.Cmd
+				if edit, ok := docEditFor(m.rootPath, docEditStrip, doc.FilePath, stripContextDocMetadata); ok {
+					reviewCmd = m.reviewDocEdits([]docEdit{edit})
+				}
 				m.statusMessageTime = time.Now()
-				return m, ClearStatusAfter(5 * time.Second)
+				return m, tea.Batch(ClearStatusAfter(5*time.Second), reviewCmd)
 			}
 			return m, nil
 
@@ -317,7 +503,7 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 						refs = append(refs, "@"+path)
 					}
 					combined := strings.Join(refs, "\n")
-					if err := clipboard.CopyRaw(combined); err != nil {
+					if err := m.copyText("doc refs", combined); err != nil {
 						m.statusMessage = "Clipboard unavailable"
 					} else {
 						m.statusMessage = fmt.Sprintf("Copied %d references", len(refs))
@@ -326,7 +512,7 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					// Copy the clicked doc as @filepath reference
 					doc := currentDocs[clickedIdx]
-					if err := clipboard.CopyFilePath(doc.FilePath); err != nil {
+					if err := m.copyText("doc", "@"+doc.FilePath); err != nil {
 						m.statusMessage = "Clipboard unavailable"
 					} else {
 						m.statusMessage = fmt.Sprintf("Copied: @%s", doc.FilePath)
@@ -362,6 +548,40 @@ func (m Model) updateDocs(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// focusTreeOnDoc collapses the tree and expands only the directories holding
+// the doc's key files, leaving the cursor on the first one
+func (m Model) focusTreeOnDoc(doc groups.ContextDoc) (tea.Model, tea.Cmd) {
+	var keyFiles []string
+	for _, kf := range doc.KeyFiles {
+		if groups.IsExternalKeyFile(m.rootPath, kf) {
+			continue
+		}
+		if _, err := os.Stat(groups.ResolveKeyFile(m.rootPath, kf)); err == nil {
+			keyFiles = append(keyFiles, filepath.Clean(kf))
+		}
+	}
+	if len(keyFiles) == 0 {
+		m.statusMessage = "No key files in the tree for " + doc.Name
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	m.clearAllOverlays()
+	m.activePane = TreePane
+	m = m.CollapseAll()
+	// Navigate in reverse so the cursor ends on the first key file
+	for i := len(keyFiles) - 1; i >= 0; i-- {
+		m = m.NavigateToFile(keyFiles[i])
+	}
+	m.ensureTreeCursorVisible()
+
+	m.statusMessage = fmt.Sprintf("Focused on %s (%d key files)", doc.Name, len(keyFiles))
+	m.statusMessageTime = time.Now()
+	var cmd tea.Cmd
+	m, cmd = m.UpdatePreview()
+	return m, tea.Batch(cmd, ClearStatusAfter(3*time.Second))
+}
+
 // ensureDocVisible ensures the selected doc is visible
 func (m *Model) ensureDocVisible() {
 	if m.docRegistry == nil {
@@ -460,8 +680,8 @@ func (m Model) getCardHeight(doc groups.ContextDoc) int {
 		cardLines += descLines
 	}
 
-	// Meta line (key files + token estimate)
-	if len(doc.KeyFiles) > 0 || doc.TokenEstimate > 0 {
+	// Meta line (key files + token estimate + verify result)
+	if len(doc.KeyFiles) > 0 || doc.TokenEstimate > 0 || doc.Verify != "" {
 		cardLines++
 	}
 
@@ -529,7 +749,7 @@ func (m Model) findClickedNav(clickX, clickY int) int {
 	}
 
 	// Box dimensions (must match view.go - now uses dynamic width based on columns)
-	cardWidth := 68
+	cardWidth := m.docsCardWidth()
 	numCols := m.getDocsColumnCount()
 	contentWidth := (cardWidth+4)*numCols + (numCols-1)*2
 	boxWidth := contentWidth + 8
@@ -579,7 +799,7 @@ func (m Model) findClickedDoc(clickX, clickY int) int {
 	}
 
 	// Box dimensions (must match view.go - now uses dynamic width based on columns)
-	cardWidth := 68
+	cardWidth := m.docsCardWidth()
 	numCols := m.getDocsColumnCount()
 	contentWidth := (cardWidth+4)*numCols + (numCols-1)*2
 	boxWidth := contentWidth + 8
@@ -752,12 +972,14 @@ func (m Model) updateAddDoc(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Add each file
+			snap := m.takeUndo(fmt.Sprintf("Add %d doc(s)", len(filesToAdd)))
 			addedCount := 0
 			incompleteCount := 0
 			var lastError error
+			var edits []docEdit
 			for _, selectedPath := range filesToAdd {
 				// Parse the doc
-				doc, err := groups.ParseContextDoc(m.rootPath, selectedPath)
+				doc, err := m.docRegistry.ParseDoc(m.rootPath, selectedPath)
 				if err != nil {
 					lastError = err
 					continue
@@ -767,54 +989,39 @@ func (m Model) updateAddDoc(msg tea.Msg) (tea.Model, tea.Cmd) {
 				doc.ValidateKeyFiles(m.rootPath)
 				doc.CheckStaleness(m.rootPath)
 
-				// If file is missing required structure, insert tag
+				// Offer to annotate files missing required structure
+				if edit, ok := structureTagEdit(m.rootPath, docEditAnnotate, *doc); ok {
+					edits = append(edits, edit)
+				}
 				if len(doc.MissingFields) > 0 {
-					insertStructureTag(m.rootPath, selectedPath)
 					incompleteCount++
 				}
 
 				// Add to registry
-				m.docRegistry.Docs = append(m.docRegistry.Docs, *doc)
-
-				// Update ByCategory map
-				catID := strings.ToLower(strings.ReplaceAll(doc.Category, " ", "-"))
-				if catID == "" {
-					catID = "uncategorized"
-				}
-				m.docRegistry.ByCategory[catID] = append(m.docRegistry.ByCategory[catID], *doc)
-
-				// If adding to uncategorized, ensure category exists in list
-				if catID == "uncategorized" {
-					hasUncategorized := false
-					for _, cat := range m.docRegistry.Categories {
-						if cat.ID == "uncategorized" {
-							hasUncategorized = true
-							break
-						}
-					}
-					if !hasUncategorized {
-						m.docRegistry.Categories = append([]groups.Category{{ID: "uncategorized", Name: "Uncategorized"}}, m.docRegistry.Categories...)
-					}
-				}
+				m.docRegistry.AddDoc(*doc)
 				addedCount++
 			}
 
 			// Save registry
-			if err := groups.SaveContextDocRegistry(m.rootPath, m.docRegistry); err != nil {
+			if err := m.saveRegistry(); err != nil {
 				m.statusMessage = fmt.Sprintf("Error saving: %v", err)
 			} else if addedCount == 0 && lastError != nil {
 				m.statusMessage = fmt.Sprintf("Error: %v", lastError)
-			} else if incompleteCount > 0 {
-				m.statusMessage = fmt.Sprintf("Added %d (%d incomplete)! Press 'p' for structuring prompt", addedCount, incompleteCount)
 			} else {
-				m.statusMessage = fmt.Sprintf("Added %d doc(s)!", addedCount)
+				m.pushUndo(snap)
+				if incompleteCount > 0 {
+					m.statusMessage = fmt.Sprintf("Added %d (%d incomplete)! Press 'p' for structuring prompt", addedCount, incompleteCount)
+				} else {
+					m.statusMessage = fmt.Sprintf("Added %d doc(s)!", addedCount)
+				}
 			}
 
 			// Clear selections
 			m.selectedAddFiles = make(map[string]bool)
 			m.statusMessageTime = time.Now()
 			m.addingDoc = false
-			return m, ClearStatusAfter(5 * time.Second)
+			reviewCmd := m.reviewDocEdits(edits)
+			return m, tea.Batch(ClearStatusAfter(5*time.Second), reviewCmd)
 		}
 
 	case tea.MouseMsg:
@@ -834,23 +1041,19 @@ func (m Model) updateAddDoc(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// Parse the doc
-				doc, err := groups.ParseContextDoc(m.rootPath, selectedPath)
+				doc, err := m.docRegistry.ParseDoc(m.rootPath, selectedPath)
 				if err != nil {
 					m.statusMessage = fmt.Sprintf("Error: %v", err)
 					m.statusMessageTime = time.Now()
 					m.addingDoc = false
 					return m, ClearStatusAfter(5 * time.Second)
 				}
+				snap := m.takeUndo("Add " + doc.Name)
 
 				// Validate and check staleness
 				doc.ValidateKeyFiles(m.rootPath)
 				doc.CheckStaleness(m.rootPath)
 
-				// If file is missing required structure, insert tag
-				if len(doc.MissingFields) > 0 {
-					insertStructureTag(m.rootPath, selectedPath)
-				}
-
 				// Add to registry
 				m.docRegistry.Docs = append(m.docRegistry.Docs, *doc)
 
@@ -876,19 +1079,28 @@ func (m Model) updateAddDoc(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// Save registry
-				if err := groups.SaveContextDocRegistry(m.rootPath, m.docRegistry); err != nil {
+				if err := m.saveRegistry(); err != nil {
 					m.statusMessage = fmt.Sprintf("Error saving: %v", err)
-				} else if len(doc.MissingFields) > 0 {
-					m.statusMessage = "Added (incomplete)! Press 'p' for structuring prompt"
 				} else {
-					m.statusMessage = fmt.Sprintf("Added %s!", doc.Name)
+					m.pushUndo(snap)
+					if len(doc.MissingFields) > 0 {
+						m.statusMessage = "Added (incomplete)! Press 'p' for structuring prompt"
+					} else {
+						m.statusMessage = fmt.Sprintf("Added %s!", doc.Name)
+					}
+				}
+
+				// Offer to annotate files missing required structure
+				var reviewCmd tea.Cmd
+				if edit, ok := structureTagEdit(m.rootPath, docEditAnnotate, *doc); ok {
+					reviewCmd = m.reviewDocEdits([]docEdit{edit})
 				}
 
 				// Clear selections
 				m.selectedAddFiles = make(map[string]bool)
 				m.statusMessageTime = time.Now()
 				m.addingDoc = false
-				return m, ClearStatusAfter(5 * time.Second)
+				return m, tea.Batch(ClearStatusAfter(5*time.Second), reviewCmd)
 			}
 			return m, nil
 		} else if msg.Button == tea.MouseButtonWheelUp {
@@ -934,7 +1146,7 @@ func (m Model) findClickedAddDocFile(clickX, clickY int) int {
 	}
 
 	// Box dimensions (must match view.go renderAddDocOverlay)
-	boxWidth := 70 + 6 // width + padding*2 + border*2
+	boxWidth := m.overlayWidth(70) + 6 // width + padding*2 + border*2
 
 	// Calculate box position (centered)
 	boxLeft := (m.width - boxWidth) / 2
@@ -1015,11 +1227,15 @@ func (m Model) findClickedAddDocFile(clickX, clickY int) int {
 	return fileIdx
 }
 
-// getDocsForSelectedCategory returns docs for the currently selected category
+// getDocsForSelectedCategory returns docs for the currently selected category,
+// or the docs of every category matching the filter
 func (m Model) getDocsForSelectedCategory() []groups.ContextDoc {
 	if m.docRegistry == nil || len(m.docRegistry.Categories) == 0 {
 		return nil
 	}
+	if m.docsQuery != "" {
+		return m.searchDocs(m.docsQuery)
+	}
 
 	// Clamp selected category
 	catIdx := m.selectedCategory
@@ -1051,34 +1267,9 @@ func (m Model) getSelectedCategoryName() string {
 	return m.docRegistry.Categories[catIdx].Name
 }
 
-// insertStructureTag adds the structure-needed tag to a file if not already present
-func insertStructureTag(rootPath, filePath string) error {
-	fullPath := filepath.Join(rootPath, filePath)
-
-	content, err := os.ReadFile(fullPath)
-	if err != nil {
-		return err
-	}
-
-	// Check if tag already exists
-	if strings.Contains(string(content), "<!-- contexTUI: structure-needed -->") {
-		return nil
-	}
-
-	// Prepend tag to file
-	newContent := StructureNeededTag + string(content)
-	return os.WriteFile(fullPath, []byte(newContent), 0644)
-}
-
-// stripContextDocMetadata removes contexTUI-specific metadata from a markdown file
-func stripContextDocMetadata(rootPath, filePath string) error {
-	fullPath := filepath.Join(rootPath, filePath)
-	content, err := os.ReadFile(fullPath)
-	if err != nil {
-		return err
-	}
-
-	lines := strings.Split(string(content), "\n")
+// stripContextDocMetadata returns a markdown file's content without contexTUI-specific metadata
+func stripContextDocMetadata(content string) string {
+	lines := strings.Split(groups.RemoveStructureAnnotation(content), "\n")
 	var newLines []string
 
 	for _, line := range lines {
@@ -1087,13 +1278,16 @@ func stripContextDocMetadata(rootPath, filePath string) error {
 		if strings.HasPrefix(trimmed, "**Category:**") ||
 			strings.HasPrefix(trimmed, "**Status:**") ||
 			strings.HasPrefix(trimmed, "**Related:**") ||
-			strings.Contains(trimmed, "<!-- contexTUI: structure-needed -->") {
+			strings.HasPrefix(trimmed, "**Verify:**") ||
+			strings.HasPrefix(trimmed, "**Test:**") ||
+			strings.HasPrefix(trimmed, "**Build:**") ||
+			strings.HasPrefix(trimmed, "**Tags:**") {
 			continue
 		}
 		newLines = append(newLines, line)
 	}
 
-	return os.WriteFile(fullPath, []byte(strings.Join(newLines, "\n")), 0644)
+	return strings.Join(newLines, "\n")
 }
 
 // moveDocInCategory swaps two docs within the current category
@@ -1145,11 +1339,11 @@ func (m *Model) moveDocInCategory(fromIdx, toIdx int) {
 
 // saveRegistryAsync returns a command that saves the registry in the background
 func (m *Model) saveRegistryAsync() tea.Cmd {
-	registry := m.docRegistry // Capture current state
-	rootPath := m.rootPath
+	registry := m.docRegistry.Copy() // Capture current state; the original keeps changing
+	rootPath, backup := m.rootPath, m.config.BackupFiles
 
 	return func() tea.Msg {
-		err := groups.SaveContextDocRegistry(rootPath, registry)
-		return RegistrySavedMsg{Err: err}
+		err := groups.SaveContextDocRegistry(rootPath, registry, backup)
+		return RegistrySavedMsg{Err: err, Saved: registry.Saved()}
 	}
 }
@@ -0,0 +1,247 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/ui/styles"
+	"github.com/muesli/reflow/truncate"
+)
+
+// hookResultKey keys a doc command's last result
+func hookResultKey(docPath, kind string) string {
+	return kind + "\x00" + docPath
+}
+
+// openVerify shows the command overlay for one of a doc's commands
+// Commands run immediately once trusted; otherwise the user is asked first
+func (m Model) openVerify(doc groups.ContextDoc, kind string) (tea.Model, tea.Cmd) {
+	// Like commands run with !, hooks may change files
+	if m.readOnly {
+		return m.readOnlyNotice()
+	}
+	if doc.Hook(kind) == "" {
+		m.statusMessage = "No **" + kind + ":** command in this doc"
+		m.statusMessageTime = time.Now()
+		return m, ClearStatusAfter(3 * time.Second)
+	}
+
+	m.showingVerify = true
+	m.verifyDoc = doc
+	m.verifyKind = kind
+	m.verifyScroll = 0
+	if !m.isCommandTrusted(doc.Hook(kind)) {
+		m.verifyConfirm = true
+		return m, nil
+	}
+	return m.startVerify()
+}
+
+// isCommandTrusted reports whether the user has allowed a doc command to run in this project
+func (m Model) isCommandTrusted(command string) bool {
+	return config.IsCommandTrusted(m.rootPath, command)
+}
+
+// startVerify runs the shown doc command in the background, streaming its output
+// Only one command runs at a time.
+func (m Model) startVerify() (tea.Model, tea.Cmd) {
+	if m.verifyRunning {
+		return m, nil
+	}
+	key := hookResultKey(m.verifyDoc.FilePath, m.verifyKind)
+	m.verifyConfirm = false
+	m.verifyRunning = true
+	m.verifyRunKey = key
+	m.verifyOutput = nil
+	m.verifyScroll = 0
+	delete(m.verifyResults, key)
+
+	m.loadingMessage = "Running " + strings.ToLower(m.verifyKind) + "..."
+	m.pendingLoads++
+
+	command, rootPath := m.verifyDoc.Hook(m.verifyKind), m.rootPath
+	id, cancel, cmd := runStreaming(func(ctx context.Context, output func(string)) error {
+		return groups.RunHook(ctx, rootPath, command, output)
+	})
+	m.verifyRunID, m.verifyCancel = id, cancel
+	return m, tea.Batch(cmd, SpinnerTick())
+}
+
+// handleHookOutput adds streamed output and records the result once the command is done
+func (m Model) handleHookOutput(msg CommandOutputMsg) (tea.Model, tea.Cmd) {
+	m.verifyOutput = append(m.verifyOutput, msg.Lines...)
+	if !msg.Done {
+		return m, msg.next
+	}
+
+	m.verifyResults[m.verifyRunKey] = VerifyResult{
+		Passed:   msg.Err == nil,
+		Output:   strings.Join(m.verifyOutput, "\n"),
+		Err:      msg.Err,
+		Duration: msg.Duration,
+	}
+	m.verifyRunning = false
+	m.verifyCancel = nil
+	m.verifyOutput = nil
+	m.checkLoadingComplete()
+
+	kind := strings.SplitN(m.verifyRunKey, "\x00", 2)[0]
+	switch {
+	case msg.Err == nil:
+		m.statusMessage = kind + " passed"
+	case errors.Is(msg.Err, context.Canceled):
+		m.statusMessage = kind + " stopped"
+	default:
+		m.statusMessage = kind + " failed"
+	}
+	m.statusMessageTime = time.Now()
+	return m, ClearStatusAfter(5 * time.Second)
+}
+
+// updateVerify handles input in the command overlay
+func (m Model) updateVerify(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	if m.verifyConfirm {
+		switch keyMsg.String() {
+		case "y":
+			debuglog.Report("trust command", config.TrustCommand(m.rootPath, m.verifyDoc.Hook(m.verifyKind)))
+			return m.startVerify()
+		case "n", "esc", "q":
+			m.verifyConfirm = false
+			m.showingVerify = false
+		}
+		return m, nil
+	}
+
+	key := hookResultKey(m.verifyDoc.FilePath, m.verifyKind)
+	switch keyMsg.String() {
+	case "esc", "q":
+		// A running command keeps going; its result still lands on the card
+		m.showingVerify = false
+	case "r":
+		return m.startVerify()
+	case "x":
+		if m.verifyRunning && m.verifyRunKey == key && m.verifyCancel != nil {
+			m.verifyCancel()
+		}
+	case "j", "down":
+		if result, ok := m.verifyResults[key]; ok && m.verifyScroll < strings.Count(result.Output, "\n") {
+			m.verifyScroll++
+		}
+	case "k", "up":
+		if m.verifyScroll > 0 {
+			m.verifyScroll--
+		}
+	}
+	return m, nil
+}
+
+// renderVerifyOverlay renders the verify command, trust prompt or output
+func (m Model) renderVerifyOverlay(background string) string {
+	boxWidth := m.width * 80 / 100
+	if boxWidth > 100 {
+		boxWidth = 100
+	}
+	if boxWidth < 50 {
+		boxWidth = 50
+	}
+	boxWidth = m.overlayWidth(boxWidth)
+	fixedHeight := m.height - 6
+	if fixedHeight < 12 {
+		fixedHeight = 12
+	}
+
+	var lines []string
+	key := hookResultKey(m.verifyDoc.FilePath, m.verifyKind)
+	lines = append(lines, styles.Title.Render(m.verifyKind+": "+m.verifyDoc.Name))
+	lines = append(lines, styles.Key.Render("$ "+m.verifyDoc.Hook(m.verifyKind)))
+	lines = append(lines, "")
+	outputRows := func() int { return max(3, fixedHeight-len(lines)-6) }
+
+	var footer string
+	switch {
+	case m.verifyConfirm:
+		lines = append(lines, styles.StatusWarning.Render("This doc wants to run a shell command in "+m.rootPath+"."))
+		lines = append(lines, styles.Muted.Render("Only trust commands you have read. Trusted commands are remembered"))
+		lines = append(lines, styles.Muted.Render("for this project in your user config and won't ask again."))
+		footer = "[y] trust and run  [n] cancel"
+
+	case m.verifyRunning && m.verifyRunKey != key:
+		lines = append(lines, styles.Muted.Render("Another doc command is running; re-run with [r] once it finishes."))
+		footer = "[r] run  [esc] close"
+
+	case m.verifyRunning:
+		spinner := string(SpinnerChars[m.spinnerFrame])
+		lines = append(lines, styles.StatusWarning.Render(spinner+" Running..."))
+		lines = append(lines, "")
+
+		// Follow the end of the output as it streams in
+		tail := m.verifyOutput[max(0, len(m.verifyOutput)-outputRows()):]
+		for _, line := range tail {
+			lines = append(lines, styles.Normal.Render(truncate.StringWithTail(line, uint(boxWidth-8), "…")))
+		}
+		footer = "[x] stop  [esc] close (keeps running)"
+
+	default:
+		result, ok := m.verifyResults[key]
+		if !ok {
+			break
+		}
+		if result.Passed {
+			lines = append(lines, styles.StatusSuccess.Render(fmt.Sprintf("✓ Passed in %s", result.Duration.Round(time.Millisecond))))
+		} else {
+			lines = append(lines, styles.StatusError.Render(fmt.Sprintf("✗ Failed after %s: %v", result.Duration.Round(time.Millisecond), result.Err)))
+		}
+		lines = append(lines, "")
+
+		// Scrollable output
+		output := strings.Split(strings.TrimRight(result.Output, "\n"), "\n")
+		maxOutput := outputRows()
+		scroll := m.verifyScroll
+		if scroll > len(output)-maxOutput {
+			scroll = len(output) - maxOutput
+		}
+		if scroll < 0 {
+			scroll = 0
+		}
+		end := scroll + maxOutput
+		if end > len(output) {
+			end = len(output)
+		}
+		for _, line := range output[scroll:end] {
+			lines = append(lines, styles.Normal.Render(truncate.StringWithTail(line, uint(boxWidth-8), "…")))
+		}
+		if end < len(output) {
+			lines = append(lines, styles.Faint.Render("  ▼ more below"))
+		}
+		footer = "[j/k] scroll  [r] re-run  [esc] close"
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, styles.Faint.Render(footer))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.BorderActive).
+		Padding(1, 2).
+		Width(boxWidth).
+		Height(fixedHeight)
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		boxStyle.Render(strings.Join(lines, "\n")),
+	)
+}
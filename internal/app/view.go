@@ -7,6 +7,10 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/connorleisz/contexTUI/internal/clipboard"
+	"github.com/connorleisz/contexTUI/internal/groups"
+	"github.com/connorleisz/contexTUI/internal/tokens"
 	"github.com/connorleisz/contexTUI/internal/ui/styles"
 )
 
@@ -25,7 +29,47 @@ func (m Model) View() string {
 	headerStyle := styles.Header.Copy().Padding(0, 1)
 
 	header := headerStyle.Render("contexTUI") +
-		styles.Faint.Render(" " + m.rootPath)
+		styles.Faint.Render(" "+m.rootPath)
+
+	// Size of the previewed file in a prompt
+	if m.previewTokens > 0 && !m.gitStatusMode && !m.fileHistoryMode && !m.previewIsImage {
+		header += styles.Muted.Render("  " + tokens.Format(m.previewTokens))
+	}
+
+	// The previewed entry is a symlink, shown through to its target
+	if m.previewLink != "" && !m.gitStatusMode && !m.fileHistoryMode {
+		header += styles.Muted.Render("  ↪ link to " + m.previewLink)
+	}
+
+	// Permissions of the previewed file
+	if m.previewPerm != "" && !m.gitStatusMode && !m.fileHistoryMode {
+		header += styles.Faint.Render("  " + m.previewPerm)
+	}
+
+	// The preview shows the file's changes instead of its content
+	if m.previewDiff && m.isGitRepo && !m.gitStatusMode && !m.fileHistoryMode && !m.previewIsImage {
+		header += styles.Muted.Render("  ± diff vs HEAD")
+	}
+
+	// The previewed file was reloaded after an edit outside contexTUI
+	if m.previewReloaded && !m.gitStatusMode && !m.fileHistoryMode {
+		header += styles.Faint.Render("  ↻ changed on disk")
+	}
+
+	// Note on the previewed entry or its folder, e.g. "generated, don't edit"
+	if !m.gitStatusMode && !m.fileHistoryMode {
+		if note := m.previewNote(); note != "" {
+			room := max(m.width-lipgloss.Width(header)-6, 20)
+			header += styles.StatusWarning.Render("  ✎ " + ansi.Truncate(note, room, "…"))
+		}
+	}
+
+	// Key path of the JSON/YAML node at the top of the preview
+	if m.structured != nil && !m.gitStatusMode && !m.previewIsImage {
+		if crumb := m.structured.breadcrumb(m.preview.YOffset); crumb != "" {
+			header += styles.Muted.Render("  ⌖ " + crumb)
+		}
+	}
 
 	// Add loading spinner to header if loading
 	if m.loadingMessage != "" {
@@ -64,52 +108,91 @@ func (m Model) View() string {
 			if start > end {
 				start, end = end, start
 			}
-			footer = selectStyle.Render(fmt.Sprintf(" COPY MODE [%d-%d] ", start+1, end+1)) +
-				footerStyle.Render("drag to select  [c/ctrl+c] copy  [j/k] scroll  [v] copy+exit  [esc] cancel")
+			label := "COPY MODE"
+			if m.visualLine {
+				label = "VISUAL LINE"
+			}
+			selected := clipboard.ExtractLines(m.previewLines, m.selectStart, m.selectEnd, StripLineNumbers)
+			footer = selectStyle.Render(fmt.Sprintf(" %s [%d-%d] %s ", label, start+1, end+1, tokens.Format(tokens.Estimate(selected)))) +
+				footerStyle.Render("[V] visual line  [c/ctrl+c] copy  [r] copy @ref  [U] copy link  [s] send @ref  [a] basket  [e] add to doc examples  [m a-z] mark region  [j/k] move  [v] copy+exit  [esc] cancel")
 		} else {
 			footer = selectStyle.Render(" COPY MODE ") +
-				footerStyle.Render("drag to select  [c/ctrl+c] copy  [j/k] scroll  [v/esc] exit")
+				footerStyle.Render("drag or [V] to select  [c/ctrl+c] copy  [j/k] move  [v/esc] exit")
 		}
 	} else if m.gitStatusMode {
 		// Git status view - show changed files list and preview
 		body = m.renderGitStatusView(paneHeight)
 		gitStyle := styles.StatusSuccess
-		footer = m.renderBranchStatus() + gitStyle.Render("GIT") + footerStyle.Render("  / search  f fetch  esc close  ? help")
+		footer = gitStyle.Render("GIT") + "  " + m.renderStatusBar(m.narrowHint()+"/ search  f fetch  n release notes  z stashes  esc close  ? help")
+		if m.gitStashMode {
+			footer = gitStyle.Render("STASHES") + "  " +
+				m.renderStatusBar("[j/k] stash  [enter/a] apply  [p] pop  [d] drop  [c] copy ref  [esc] changes")
+		}
 	} else {
 		// Normal mode - show both panes
 		leftWidth := m.LeftPaneWidth()
 		rightWidth := m.RightPaneWidth()
 
-		var treeStyle lipgloss.Style
-		if m.activePane == TreePane {
-			treeStyle = styles.ActiveBorder()
-		} else {
-			treeStyle = styles.InactiveBorder()
+		var treeContent string
+		switch {
+		case m.tocMode:
+			treeContent = m.renderTOC(leftWidth-2, paneHeight)
+		case m.fileHistoryMode:
+			treeContent = m.renderFileHistoryList(leftWidth-2, paneHeight)
+		default:
+			treeContent = m.RenderTree()
 		}
-		treeStyle = treeStyle.
-			Width(leftWidth).
-			Height(paneHeight).
-			Padding(0, 1)
+		tree := m.framePane("left", treeContent, leftWidth, paneHeight, m.activePane == TreePane)
 
-		tree := treeStyle.Render(m.tree.View())
+		// Render preview content - viewport handles both images and text
+		// (image content is set in the viewport when ImageLoadedMsg is received)
+		preview := m.framePane("right", m.previewView(), rightWidth, paneHeight, m.activePane == PreviewPane)
 
-		var previewStyle lipgloss.Style
-		if m.activePane == PreviewPane {
-			previewStyle = styles.ActiveBorder()
-		} else {
-			previewStyle = styles.InactiveBorder()
+		body = m.joinPanes(tree, preview)
+		footer = m.renderStatusBar(m.narrowHint() + "/ search  g docs  v select  s git  q quit  ? help")
+		if m.showCoverage {
+			footer = coverageLegend() + footer
 		}
-		previewStyle = previewStyle.
-			Width(rightWidth).
-			Height(paneHeight).
-			Padding(0, 1)
+		if m.treeFilter != nil {
+			footer = styles.Header.Render(" DOC: "+m.treeFilterDoc+" ") + " " + footerStyle.Render("[esc] show all") + "  " + footer
+		}
+		if m.readOnly {
+			footer = styles.Header.Render(" READ-ONLY ") + " " + footer
+		}
+		if m.options.Choose || m.options.ChooseDir {
+			footer = styles.Header.Render(" CHOOSE ") + " " + footerStyle.Render("[enter] pick  [q] cancel") + "  " + footer
+		}
+		if m.fileHistoryMode {
+			footer = styles.Header.Render(" HISTORY ") + " " +
+				footerStyle.Render("[j/k] commit  [J/K] scroll diff  [c] copy hash  [esc] back")
+		}
+		if m.tocMode {
+			footer = styles.Header.Render(" CONTENTS ") + " " +
+				footerStyle.Render("[j/k] section  [J/K] scroll  [c] copy section  [r] copy @ref  [esc] back")
+		}
+		if m.typeAhead {
+			footer = styles.Header.Render(" JUMP ") + " " + m.typeAheadPrefix + "▏  " +
+				footerStyle.Render("[tab] next match  [enter] done  [esc] cancel")
+		}
+	}
 
-		// Render preview content - viewport handles both images and text
-		// (image content is set in the viewport when ImageLoadedMsg is received)
-		preview := previewStyle.Render(m.preview.View())
+	// Warn about paths that couldn't be indexed
+	if len(m.deniedPaths) > 0 && !m.selectMode {
+		footer = styles.StatusWarning.Render(fmt.Sprintf("⚠ %d skipped (E)", len(m.deniedPaths))) + "  " + footer
+	}
 
-		body = lipgloss.JoinHorizontal(lipgloss.Top, tree, preview)
-		footer = m.renderBranchStatus() + footerStyle.Render("/ search  g docs  v select  s git  q quit  ? help")
+	// Show what's staged in the basket
+	if len(m.basket) > 0 && !m.selectMode {
+		footer = footerStyle.Render(fmt.Sprintf("basket %d (A)", len(m.basket))) + "  " + footer
+	}
+
+	// Failures stay until dismissed
+	if n := len(m.errorBanners); n > 0 {
+		banner := "✗ " + ansi.Truncate(m.errorBanners[n-1].Message, max(20, m.width/2), "…")
+		if n > 1 {
+			banner += fmt.Sprintf(" (+%d more)", n-1)
+		}
+		footer = styles.StatusError.Render(banner) + " " + footerStyle.Render("[esc] dismiss  [I] details") + "  " + footer
 	}
 
 	// Prepend status message to footer if present and recent
@@ -117,6 +200,9 @@ func (m Model) View() string {
 		footer = styles.StatusSuccess.Render(m.statusMessage) + "  " + footer
 	}
 
+	// Cut what doesn't fit rather than wrapping onto another line
+	footer = ansi.Truncate(footer, m.width, "…")
+
 	mainView := header + "\n" + body + "\n" + footer
 
 	// Overlay help if active
@@ -129,6 +215,36 @@ func (m Model) View() string {
 		return m.renderSearchOverlay(mainView)
 	}
 
+	// Overlay the tree's right-click menu if active
+	if m.showingContextMenu {
+		return m.renderContextMenu(mainView)
+	}
+
+	// Overlay the first-run setup if active
+	if m.showingOnboarding {
+		return m.renderOnboardingOverlay(mainView)
+	}
+
+	// Overlay the registry conflict prompt if active
+	if m.showingRegistryConflict {
+		return m.renderRegistryConflictOverlay(mainView)
+	}
+
+	// Overlay the doc edit preview if active (sits above the docs panel)
+	if m.showingDocEdits {
+		return m.renderDocEditsOverlay(mainView)
+	}
+
+	// Overlay verify output if active (sits above the docs panel)
+	if m.showingVerify {
+		return m.renderVerifyOverlay(mainView)
+	}
+
+	// The doc reader covers the whole screen
+	if m.showingReader {
+		return m.renderDocReaderOverlay(mainView)
+	}
+
 	// Overlay docs if active
 	if m.showingDocs {
 		return m.renderDocsOverlay(mainView)
@@ -139,6 +255,76 @@ func (m Model) View() string {
 		return m.renderFileOpOverlay(mainView)
 	}
 
+	// Overlay regions if active
+	if m.showingRegions {
+		return m.renderRegionsOverlay(mainView)
+	}
+
+	// Overlay release notes if active
+	if m.showingReleases {
+		return m.renderReleaseNotesOverlay(mainView)
+	}
+
+	// Overlay snippet doc picker if active
+	if m.showingSnippetDocs {
+		return m.renderSnippetDocsOverlay(mainView)
+	}
+
+	// Overlay related files if active
+	if m.showingRelated {
+		return m.renderRelatedOverlay(mainView)
+	}
+
+	// Overlay copy history if active
+	if m.showingCopyHistory {
+		return m.renderCopyHistoryOverlay(mainView)
+	}
+
+	// Overlay basket if active
+	if m.showingBasket {
+		return m.renderBasketOverlay(mainView)
+	}
+
+	// Overlay export prompt if active
+	if m.showingExport {
+		return m.renderExportOverlay(mainView)
+	}
+
+	// Overlay note editor if active
+	if m.showingNote {
+		return m.renderNoteOverlay(mainView)
+	}
+
+	// Overlay links if active
+	if m.showingLinks {
+		return m.renderLinksOverlay(mainView)
+	}
+
+	// Overlay debug info if active
+	if m.showingDebug {
+		return m.renderDebugOverlay(mainView)
+	}
+
+	// Overlay recent projects if active
+	if m.showingProjects {
+		return m.renderProjectsOverlay(mainView)
+	}
+
+	// Overlay command runner if active
+	if m.showingCommand {
+		return m.renderCommandOverlay(mainView)
+	}
+
+	// Overlay errors if active
+	if m.showingErrors {
+		return m.renderErrorsOverlay(mainView)
+	}
+
+	// Overlay theme picker if active
+	if m.showingThemes {
+		return m.renderThemeOverlay(mainView)
+	}
+
 	return mainView
 }
 
@@ -179,6 +365,7 @@ func (m Model) renderPreviewWithSelection(width, height int) string {
 
 	// Highlight style - strip existing colors and apply solid background
 	highlightStyle := styles.Highlight
+	cursorStyle := lipgloss.NewStyle().Underline(true)
 
 	// Determine selection range
 	selStart, selEnd := -1, -1
@@ -200,14 +387,15 @@ func (m Model) renderPreviewWithSelection(width, height int) string {
 		line := m.previewLines[i]
 
 		// Check if this line is in the selection
-		if selStart >= 0 && i >= selStart && i <= selEnd {
+		inSelection := selStart >= 0 && i >= selStart && i <= selEnd
+		if inSelection {
 			// Strip ANSI codes and apply highlight (selection overrides syntax colors)
 			cleanLine := stripAnsi(line)
 			// Pad line to full width for solid highlight block
-			if len(cleanLine) < width {
-				cleanLine = cleanLine + strings.Repeat(" ", width-len(cleanLine))
-			}
-			line = highlightStyle.Render(cleanLine)
+			line = highlightStyle.Render(padRight(cleanLine, width))
+		} else if i == m.selectCursor {
+			// Keyboard cursor
+			line = cursorStyle.Render(stripAnsi(line))
 		}
 
 		b.WriteString(line)
@@ -219,6 +407,12 @@ func (m Model) renderPreviewWithSelection(width, height int) string {
 	return b.String()
 }
 
+// padRight pads s with spaces to width terminal cells
+// Wide characters (CJK, emoji) take two cells, so byte or rune counts misalign.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-ansi.StringWidth(s)))
+}
+
 // stripAnsi removes ANSI escape codes from a string
 func stripAnsi(s string) string {
 	var result strings.Builder
@@ -250,6 +444,7 @@ func (m Model) renderSearchOverlay(background string) string {
 	if boxWidth < 40 {
 		boxWidth = 40
 	}
+	boxWidth = m.overlayWidth(boxWidth)
 
 	fixedHeight := m.height - 6
 	if fixedHeight < 10 {
@@ -340,28 +535,73 @@ func (m Model) renderSearchOverlay(background string) string {
 	return centeredBox
 }
 
-// RenderTree renders the tree pane content
+// framePane renders content inside a pane border, reusing the last rendering of
+// the named pane when its content, size and focus are unchanged
+func (m Model) framePane(name, content string, width, height int, active bool) string {
+	if cached, ok := m.panes[name]; ok && cached.content == content &&
+		cached.width == width && cached.height == height && cached.active == active {
+		return cached.rendered
+	}
+
+	style := styles.InactiveBorder()
+	if active {
+		style = styles.ActiveBorder()
+	}
+	rendered := style.
+		Width(width).
+		Height(height).
+		Padding(0, 1).
+		Render(content)
+
+	if m.panes != nil {
+		m.panes[name] = framedPane{content: content, width: width, height: height, active: active, rendered: rendered}
+	}
+	return rendered
+}
+
+// RenderTree renders the rows of the tree pane that are in view
+// Only the visible window is built, and styled rows are reused until their entry changes.
 func (m Model) RenderTree() string {
-	var b strings.Builder
 	flat := m.FlatEntries()
+	start, end := m.treeWindow(len(flat))
 
 	// Git status styles
 	gitStyles := styles.GitStatusStyles()
 	dirIndicatorStyle := lipgloss.NewStyle().Foreground(styles.TextFaint)
 
-	for i, e := range flat {
+	var coverage coverageIndex
+	var covStyles map[coverageState]lipgloss.Style
+	if m.showCoverage {
+		coverage = newCoverageIndex(m.rootPath, m.docRegistry)
+		covStyles = coverageStyles()
+	}
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		e := flat[i]
 		indent := strings.Repeat("  ", e.Depth)
 
 		icon := "  "
 		if e.IsDir {
-			if e.Expanded {
+			switch {
+			case styles.Accessible() && e.Expanded:
+				icon = "- " // "> " marks the selected row
+			case styles.Accessible():
+				icon = "+ "
+			case e.Expanded:
 				icon = "v "
-			} else {
+			default:
 				icon = "> "
 			}
 		}
 
 		line := indent + icon + e.Name
+		if e.Link != "" {
+			line += " -> " + e.Link
+		}
+		if e.Denied {
+			line += " 🔒"
+		}
 
 		// Use cached relative path if available, otherwise compute it
 		relPath := e.RelPath
@@ -369,33 +609,78 @@ func (m Model) RenderTree() string {
 			relPath, _ = filepath.Rel(m.rootPath, e.Path)
 		}
 
-		// Add git status badge
-		if m.isGitRepo {
+		// Entries of an extra root have its own git status; coverage is the project's
+		isGit, gitStatus, gitDirStatus := m.isGitRepo, m.gitStatus, m.gitDirStatus
+		extra := m.extraRootFor(e.Path)
+		if extra != nil {
+			isGit, gitStatus, gitDirStatus = extra.IsGit, extra.GitStatus, extra.DirStatus
+			if e.Root {
+				line += "  " + styles.Faint.Render(extra.summary())
+				isGit = false
+			}
+		}
+
+		// Git status badge, kept unstyled until the row is rendered
+		badge := ""
+		if isGit {
 			if e.IsDir {
 				// Directory indicator - show dot if contains changes
-				if _, ok := m.gitDirStatus[relPath]; ok {
-					line += " " + dirIndicatorStyle.Render("●")
-				}
-			} else {
-				// File status badge
-				if status, ok := m.gitStatus[relPath]; ok {
-					if style, ok := gitStyles[status.Status]; ok {
-						line += " " + style.Render(status.Status)
-					}
+				if _, ok := gitDirStatus[relPath]; ok {
+					badge = "●"
 				}
+			} else if status, ok := gitStatus[relPath]; ok {
+				badge = status.Status
 			}
 		}
 
+		// Doc coverage badge
+		cov := coverageState(-1)
+		if m.showCoverage && extra == nil {
+			cov = coverage.state(relPath, e.IsDir)
+		}
+
+		key := fmt.Sprintf("%s\x00%s\x00%t\x00%t\x00%d\x00%t", line, badge, i == m.cursor, e.IsDir, cov, i == m.treeHover)
+		if cached, ok := m.treeLines[e.Path]; ok && cached.key == key {
+			lines = append(lines, cached.rendered)
+			continue
+		}
+
+		if cov >= 0 {
+			line += " " + covStyles[cov].Render(coverageBadge(cov))
+		}
+		if badge != "" {
+			if e.IsDir {
+				line += " " + dirIndicatorStyle.Render(styles.Badge(badge))
+			} else if style, ok := gitStyles[badge]; ok {
+				line += " " + style.Render(styles.Badge(badge))
+			}
+		}
 		if i == m.cursor {
 			line = styles.Selected.Render(line)
+		} else if i == m.treeHover {
+			line = styles.Hover.Render(line)
 		} else if e.IsDir {
 			line = lipgloss.NewStyle().Bold(true).Render(line)
+		} else if e.More > 0 {
+			line = styles.Faint.Render(line)
 		}
 
-		b.WriteString(line + "\n")
+		if m.treeLines != nil {
+			if len(m.treeLines) >= maxTreeLineCache {
+				clear(m.treeLines)
+			}
+			m.treeLines[e.Path] = treeLine{key: key, rendered: line}
+		}
+		lines = append(lines, line)
 	}
 
-	return b.String()
+	// Pad and clip to the pane like a viewport
+	return lipgloss.NewStyle().
+		Width(m.tree.Width).
+		Height(m.tree.Height).
+		MaxWidth(m.tree.Width).
+		MaxHeight(m.tree.Height).
+		Render(strings.Join(lines, "\n"))
 }
 
 func (m Model) renderDocsOverlay(background string) string {
@@ -403,6 +688,9 @@ func (m Model) renderDocsOverlay(background string) string {
 	if m.addingDoc {
 		return m.renderAddDocOverlay(background)
 	}
+	if m.managingCategories {
+		return m.renderCategoriesOverlay(background)
+	}
 
 	// Use doc-based rendering
 	return m.renderContextDocsOverlay(background)
@@ -482,16 +770,16 @@ func (m Model) renderAddDocOverlay(background string) string {
 	content.WriteString("\n")
 	// Show selection count if any files selected
 	if len(m.selectedAddFiles) > 0 {
-		statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Bold(true)
+		statusStyle := lipgloss.NewStyle().Foreground(styles.SuccessBold).Bold(true)
 		content.WriteString(statusStyle.Render(fmt.Sprintf("%d selected  ", len(m.selectedAddFiles))))
 	}
 	content.WriteString(metaStyle.Render("[j/k] navigate  [space] select  [enter] add  [esc] cancel"))
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("205")).
+		BorderForeground(styles.BorderActive).
 		Padding(1, 2).
-		Width(70).
+		Width(m.overlayWidth(70)).
 		MaxHeight(m.height - 4)
 
 	docsBox := boxStyle.Render(content.String())
@@ -523,7 +811,7 @@ func (m Model) getDocsColumnCount() int {
 // Supports multi-column layout for wide terminals
 func (m Model) renderContextDocsOverlay(background string) string {
 	// Card width for description wrapping
-	cardWidth := 68
+	cardWidth := m.docsCardWidth()
 
 	// Calculate column count based on terminal aspect ratio
 	numCols := m.getDocsColumnCount()
@@ -542,6 +830,7 @@ func (m Model) renderContextDocsOverlay(background string) string {
 	descStyle := styles.Muted
 	metaStyle := styles.Faint
 	copiedStyle := lipgloss.NewStyle().Bold(true).Foreground(styles.SuccessBold)
+	tagStyle := lipgloss.NewStyle().Foreground(styles.Info)
 
 	// Card styles
 	selectedCardStyle := lipgloss.NewStyle().
@@ -562,6 +851,8 @@ func (m Model) renderContextDocsOverlay(background string) string {
 	titleLine := titleStyle.Render("Context Docs")
 	if m.statusMessage != "" && strings.HasPrefix(m.statusMessage, "Copied:") {
 		titleLine += "  " + copiedStyle.Render(m.statusMessage)
+	} else if len(m.outdatedTags) > 0 {
+		titleLine += "  " + warningStyle.Render(fmt.Sprintf("%d doc(s) with outdated structure tags · [U] update", len(m.outdatedTags)))
 	}
 	headerLines = append(headerLines, titleLine)
 	headerLines = append(headerLines, "")
@@ -600,6 +891,16 @@ func (m Model) renderContextDocsOverlay(background string) string {
 
 		navLine := prevText + divider + currText + divider + nextText
 
+		// A filter replaces the categories with its matches from all of them
+		if m.docsSearchInput.Focused() || m.docsQuery != "" {
+			m.docsSearchInput.Width = 40
+			query := m.docsSearchInput.View()
+			if !m.docsSearchInput.Focused() {
+				query = activeStyle.Render("/ " + m.docsQuery)
+			}
+			navLine = query + divider + arrowStyle.Render(fmt.Sprintf("%d found in all categories", len(m.getDocsForSelectedCategory())))
+		}
+
 		// Center the navigation bar across full content width
 		centeredNav := lipgloss.NewStyle().Width(contentWidth).Align(lipgloss.Center).Render(navLine)
 		headerLines = append(headerLines, centeredNav)
@@ -619,6 +920,10 @@ func (m Model) renderContextDocsOverlay(background string) string {
 		cardLines = append(cardLines, metaStyle.Render("No context docs defined yet."))
 		cardLines = append(cardLines, "")
 		cardLines = append(cardLines, metaStyle.Render("Press 'a' to add a markdown file as a context doc."))
+	} else if len(docs) == 0 && m.docsQuery != "" {
+		cardLines = append(cardLines, metaStyle.Render("No docs match the filter."))
+		cardLines = append(cardLines, "")
+		cardLines = append(cardLines, metaStyle.Render("Press esc to clear it."))
 	} else if len(docs) == 0 {
 		cardLines = append(cardLines, metaStyle.Render("No docs in this category."))
 		cardLines = append(cardLines, "")
@@ -653,23 +958,27 @@ func (m Model) renderContextDocsOverlay(background string) string {
 
 			// Title line with status indicators
 			cardTitleLine := selectionPrefix + lipgloss.NewStyle().Bold(true).Render(doc.Name)
+			if doc.Package != "" {
+				// Docs from nested registries are prefixed with their package
+				cardTitleLine = selectionPrefix + metaStyle.Render(doc.Package+" › ") + lipgloss.NewStyle().Bold(true).Render(doc.Name)
+			}
 
 			// Status badge
 			statusBadge := ""
 			if doc.Status != "" {
-				statusColor := "244" // gray default
+				statusColor := styles.TextFaint // gray default
 				switch doc.Status {
 				case "Active":
-					statusColor = "82" // green
+					statusColor = styles.SuccessBold
 				case "Deprecated":
-					statusColor = "196" // red
+					statusColor = styles.Error
 				case "Experimental":
-					statusColor = "214" // orange
+					statusColor = styles.Warning
 				case "Planned":
-					statusColor = "105" // purple
+					statusColor = styles.AccentAlt
 				}
 				statusBadge = lipgloss.NewStyle().
-					Foreground(lipgloss.Color(statusColor)).
+					Foreground(statusColor).
 					Render(" [" + doc.Status + "]")
 			}
 
@@ -688,7 +997,11 @@ func (m Model) renderContextDocsOverlay(background string) string {
 			cardContent = append(cardContent, cardTitleLine+statusBadge+strings.Join(indicators, ""))
 
 			// Filepath - show below title for clarity
-			cardContent = append(cardContent, metaStyle.Render(doc.FilePath))
+			filePathLine := metaStyle.Render(doc.FilePath)
+			for _, tag := range doc.Tags {
+				filePathLine += " " + tagStyle.Render("#"+tag)
+			}
+			cardContent = append(cardContent, filePathLine)
 
 			// Description - word wrap to card width
 			if doc.Description != "" {
@@ -702,11 +1015,30 @@ func (m Model) renderContextDocsOverlay(background string) string {
 			// Key files count and token estimate
 			var metaParts []string
 			if len(doc.KeyFiles) > 0 {
-				metaParts = append(metaParts, fmt.Sprintf("%d key files", len(doc.KeyFiles)))
+				keyFilesMeta := fmt.Sprintf("%d key files", len(doc.KeyFiles))
+				external := 0
+				for _, kf := range doc.KeyFiles {
+					if groups.IsExternalKeyFile(m.rootPath, kf) {
+						external++
+					}
+				}
+				if external > 0 {
+					keyFilesMeta += fmt.Sprintf(" (%d external)", external)
+				}
+				metaParts = append(metaParts, keyFilesMeta)
 			}
 			if doc.TokenEstimate > 0 {
 				metaParts = append(metaParts, fmt.Sprintf("~%d tokens", doc.TokenEstimate))
 			}
+			if doc.Verify != "" {
+				if result, ok := m.verifyResults[hookResultKey(doc.FilePath, groups.HookVerify)]; !ok {
+					metaParts = append(metaParts, "verify not run")
+				} else if result.Passed {
+					metaParts = append(metaParts, lipgloss.NewStyle().Foreground(styles.SuccessBold).Render("✓ verified"))
+				} else {
+					metaParts = append(metaParts, errorStyle.Render("✗ verify failed"))
+				}
+			}
 			if len(metaParts) > 0 {
 				cardContent = append(cardContent, metaStyle.Render(strings.Join(metaParts, " · ")))
 			}
@@ -809,8 +1141,8 @@ func (m Model) renderContextDocsOverlay(background string) string {
 	content.WriteString("\n")
 
 	// 5. Footer with status message or selection count
-	footerText := "[h/l] cat  [j/k] nav  [J/K] reorder  [space] select  [c] copy  [a] add  [d] rm  [esc] close"
-	statusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("82")).Bold(true)
+	footerText := "[/] filter  [#] tag  [h/l] cat  [j/k] nav  [J/K] reorder  [space] select  [c/C] copy/+files  [w] write to file  [B] basket  [S] send  [e/E] CLAUDE/AGENTS.md  [f/F] focus/filter tree  [v] verify  [t/b] test/build  [o] read  [m/M] move/categories  [a] add  [d] rm  [u/ctrl+r] undo/redo  [esc] close"
+	statusStyle := lipgloss.NewStyle().Foreground(styles.SuccessBold).Bold(true)
 	if m.statusMessage != "" && time.Since(m.statusMessageTime) < 5*time.Second {
 		// Show status message (copy feedback, etc.)
 		content.WriteString(statusStyle.Render(m.statusMessage))
@@ -829,7 +1161,7 @@ func (m Model) renderContextDocsOverlay(background string) string {
 
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("205")).
+		BorderForeground(styles.BorderActive).
 		Padding(1, 2).
 		Width(boxWidth).
 		Height(fixedHeight)
@@ -857,7 +1189,7 @@ func wrapText(text string, width int) []string {
 
 	currentLine := words[0]
 	for _, word := range words[1:] {
-		if len(currentLine)+1+len(word) <= width {
+		if ansi.StringWidth(currentLine)+1+ansi.StringWidth(word) <= width {
 			currentLine += " " + word
 		} else {
 			lines = append(lines, currentLine)
@@ -869,7 +1201,7 @@ func wrapText(text string, width int) []string {
 	// Limit to 3 lines max
 	if len(lines) > 3 {
 		lines = lines[:3]
-		lines[2] = lines[2][:min(len(lines[2]), width-3)] + "..."
+		lines[2] = ansi.Truncate(lines[2], width-3, "") + "..."
 	}
 
 	return lines
@@ -901,35 +1233,16 @@ func (m Model) renderBranchStatus() string {
 		}
 	}
 
-	return status + "  "
+	return status
 }
 
-// renderHelpOverlay renders the help overlay with all keybindings
-func (m Model) renderHelpOverlay(background string) string {
+// helpLines builds the content lines shown in the help overlay
+func (m Model) helpLines() []string {
 	titleStyle := styles.Title
 	sectionStyle := styles.SectionHeader
 	keyStyle := styles.Key
 	descStyle := styles.Faint
-	metaStyle := styles.Faint
-
-	// Calculate box dimensions based on viewport
-	boxWidth := m.width * 70 / 100
-	if boxWidth > 80 {
-		boxWidth = 80
-	}
-	if boxWidth < 50 {
-		boxWidth = 50
-	}
-
-	fixedHeight := m.height - 6
-	if fixedHeight < 15 {
-		fixedHeight = 15
-	}
-	if fixedHeight > 30 {
-		fixedHeight = 30
-	}
 
-	// Build content as lines array
 	var contentLines []string
 
 	contentLines = append(contentLines, titleStyle.Render("Keyboard Shortcuts"))
@@ -941,6 +1254,13 @@ func (m Model) renderHelpOverlay(background string) string {
 	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("tab"), descStyle.Render("Switch panes")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s  %s", keyStyle.Render("enter/l"), descStyle.Render("Open/expand")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("h"), descStyle.Render("Collapse")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("H"), descStyle.Render("Collapse siblings")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("-"), descStyle.Render("Collapse all")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("1-9"), descStyle.Render("Expand all to depth N")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("+"), descStyle.Render("Expand one more level")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("F"), descStyle.Render("Jump by typing a name")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("m a"), descStyle.Render("Set mark a-z")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("' a"), descStyle.Render("Jump to mark ('' jumps back)")))
 	contentLines = append(contentLines, "")
 
 	// Views
@@ -950,6 +1270,22 @@ func (m Model) renderHelpOverlay(background string) string {
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("/"), descStyle.Render("Search files")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("v"), descStyle.Render("Copy mode")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("."), descStyle.Render("Toggle dotfiles")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("w"), descStyle.Render("Toggle preview wrap")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("b"), descStyle.Render("Toggle git blame")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("d"), descStyle.Render("Toggle diff vs HEAD (preview pane)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("t"), descStyle.Render("Markdown table of contents")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("H/L"), descStyle.Render("Pan unwrapped preview")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("T"), descStyle.Render("Theme picker")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("R"), descStyle.Render("Marked regions")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("Y"), descStyle.Render("Copy history")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("A"), descStyle.Render("Basket")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("I"), descStyle.Render("Debug: caches, git, watcher, errors")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("P"), descStyle.Render("Switch project")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("!"), descStyle.Render("Run a shell command")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("u"), descStyle.Render("Toggle doc coverage badges")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("E"), descStyle.Render("Errors")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("z"), descStyle.Render("Fold JSON/YAML node")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("Z"), descStyle.Render("Fold/unfold all")))
 	contentLines = append(contentLines, "")
 
 	// Actions
@@ -957,12 +1293,33 @@ func (m Model) renderHelpOverlay(background string) string {
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("n"), descStyle.Render("Create file")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("N"), descStyle.Render("Create folder")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("r"), descStyle.Render("Rename")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("y"), descStyle.Render("Duplicate file")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("X"), descStyle.Render("Permissions (chmod)")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("d"), descStyle.Render("Delete")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("o"), descStyle.Render("Open in OS")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("e"), descStyle.Render("Edit the note on file or folder")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s    %s", keyStyle.Render("Enter"), descStyle.Render("Image preview")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s    %s", keyStyle.Render("Enter"), descStyle.Render("Follow link (markdown preview)")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("c"), descStyle.Render("Copy file path")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("#"), descStyle.Render("Open issue reference (preview, commit)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("U"), descStyle.Render("Copy web link on origin (copy mode: with lines)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("S"), descStyle.Render("Send to agent session")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("a"), descStyle.Render("Add to basket (file, diff, selection)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("D"), descStyle.Render("Draft doc for folder")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("i"), descStyle.Render("Imports / imported by")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("C"), descStyle.Render("Co-changed files (git)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("G"), descStyle.Render("File history (git)")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("f"), descStyle.Render("Git fetch")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("n"), descStyle.Render("Release notes (git status)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("z"), descStyle.Render("Stashes: apply, pop, drop (git status)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("y"), descStyle.Render("Copy diff as patch (git status file/dir)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("m"), descStyle.Render("Copy commit message prompt (git status)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("P"), descStyle.Render("Copy PR description prompt (git status)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("h"), descStyle.Render("Fold directory (git status; enter toggles)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("Z"), descStyle.Render("Fold/unfold all directories (git status)")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("←/→"), descStyle.Render("Resize panes")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s      %s", keyStyle.Render("[/]"), descStyle.Render("Zoom tree/preview (again: both panes)")))
+	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("|"), descStyle.Render("Both panes, split evenly (z/Z/= are taken)")))
 	contentLines = append(contentLines, "")
 
 	// General
@@ -970,6 +1327,33 @@ func (m Model) renderHelpOverlay(background string) string {
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("?"), descStyle.Render("Toggle help")))
 	contentLines = append(contentLines, fmt.Sprintf("  %s        %s", keyStyle.Render("q"), descStyle.Render("Quit")))
 
+	return contentLines
+}
+
+// renderHelpOverlay renders the help overlay with all keybindings
+func (m Model) renderHelpOverlay(background string) string {
+	metaStyle := styles.Faint
+
+	// Calculate box dimensions based on viewport
+	boxWidth := m.width * 70 / 100
+	if boxWidth > 80 {
+		boxWidth = 80
+	}
+	if boxWidth < 50 {
+		boxWidth = 50
+	}
+	boxWidth = m.overlayWidth(boxWidth)
+
+	fixedHeight := m.height - 6
+	if fixedHeight < 15 {
+		fixedHeight = 15
+	}
+	if fixedHeight > 30 {
+		fixedHeight = 30
+	}
+
+	contentLines := m.helpLines()
+
 	// Calculate scrolling
 	maxContentHeight := fixedHeight - 4 // Account for box padding/borders
 	totalLines := len(contentLines)
@@ -1015,12 +1399,12 @@ func (m Model) renderHelpOverlay(background string) string {
 
 	// Footer
 	content.WriteString("\n")
-	content.WriteString(descStyle.Render("q/esc close · j/k scroll"))
+	content.WriteString(metaStyle.Render("q/esc close · j/k scroll"))
 
 	// Style the help box
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("205")).
+		BorderForeground(styles.BorderActive).
 		Padding(1, 3).
 		Width(boxWidth).
 		Height(fixedHeight)
@@ -1035,133 +1419,22 @@ func (m Model) renderHelpOverlay(background string) string {
 	)
 }
 
-// renderGitFileList renders just the categorized file list for the git viewport
-func (m Model) renderGitFileList() string {
-	leftWidth := m.LeftPaneWidth()
-
-	if len(m.gitChanges) == 0 {
-		return styles.Faint.Render("Working tree clean")
-	}
-
-	var b strings.Builder
-
-	// Group by status
-	staged, unstaged, untracked := m.CategorizeGitChanges()
-
-	// Style definitions
-	stagedStyle := lipgloss.NewStyle().Foreground(styles.GitAdded).Bold(true)
-	unstagedStyle := lipgloss.NewStyle().Foreground(styles.GitModified).Bold(true)
-	untrackedStyle := lipgloss.NewStyle().Foreground(styles.GitUntracked)
-	selectedStyle := styles.Selected
-
-	statusStyles := styles.GitStatusStyles()
-
-	idx := 0
-
-	// Render staged changes
-	if len(staged) > 0 {
-		b.WriteString(stagedStyle.Render("Staged Changes"))
-		b.WriteString("\n")
-		for _, c := range staged {
-			var line string
-			if idx == m.gitStatusCursor {
-				line = fmt.Sprintf("  %s %s", c.Status, c.Path)
-				if len(line) < leftWidth-4 {
-					line = line + strings.Repeat(" ", leftWidth-4-len(line))
-				}
-				line = selectedStyle.Render(line)
-			} else {
-				statusStyle := statusStyles[c.Status]
-				line = fmt.Sprintf("  %s %s", statusStyle.Render(c.Status), c.Path)
-			}
-			b.WriteString(line + "\n")
-			idx++
-		}
-		b.WriteString("\n")
-	}
-
-	// Render unstaged changes
-	if len(unstaged) > 0 {
-		b.WriteString(unstagedStyle.Render("Changes not staged"))
-		b.WriteString("\n")
-		for _, c := range unstaged {
-			var line string
-			if idx == m.gitStatusCursor {
-				line = fmt.Sprintf("  %s %s", c.Status, c.Path)
-				if len(line) < leftWidth-4 {
-					line = line + strings.Repeat(" ", leftWidth-4-len(line))
-				}
-				line = selectedStyle.Render(line)
-			} else {
-				statusStyle := statusStyles[c.Status]
-				line = fmt.Sprintf("  %s %s", statusStyle.Render(c.Status), c.Path)
-			}
-			b.WriteString(line + "\n")
-			idx++
-		}
-		b.WriteString("\n")
-	}
-
-	// Render untracked files
-	if len(untracked) > 0 {
-		b.WriteString(untrackedStyle.Render("Untracked files"))
-		b.WriteString("\n")
-		for _, c := range untracked {
-			var line string
-			if idx == m.gitStatusCursor {
-				line = fmt.Sprintf("  %s %s", c.Status, c.Path)
-				if len(line) < leftWidth-4 {
-					line = line + strings.Repeat(" ", leftWidth-4-len(line))
-				}
-				line = selectedStyle.Render(line)
-			} else {
-				line = fmt.Sprintf("  %s %s", untrackedStyle.Render(c.Status), c.Path)
-			}
-			b.WriteString(line + "\n")
-			idx++
-		}
-	}
-
-	return strings.TrimSuffix(b.String(), "\n")
-}
-
 // renderGitStatusView renders the git status view with file list and preview
 func (m Model) renderGitStatusView(paneHeight int) string {
 	leftWidth := m.LeftPaneWidth()
 	rightWidth := m.RightPaneWidth()
 
-	// Left pane: Header + scrollable file list
+	// Left pane: Header + scrollable file list (or the stashes)
 	header := styles.Header.Render("Git Status") + "\n\n"
 	leftContent := header + m.gitList.View()
-
-	// Style the left pane
-	var leftPaneStyle lipgloss.Style
-	if m.activePane == TreePane {
-		leftPaneStyle = styles.ActiveBorder()
-	} else {
-		leftPaneStyle = styles.InactiveBorder()
+	if m.gitStashMode {
+		leftContent = styles.Header.Render("Stashes") + "\n\n" + m.renderStashList(leftWidth-2, paneHeight-2)
 	}
-	leftPaneStyle = leftPaneStyle.
-		Width(leftWidth).
-		Height(paneHeight).
-		Padding(0, 1)
 
-	// Right pane: File preview
-	var previewStyle lipgloss.Style
-	if m.activePane == PreviewPane {
-		previewStyle = styles.ActiveBorder()
-	} else {
-		previewStyle = styles.InactiveBorder()
-	}
-	previewStyle = previewStyle.
-		Width(rightWidth).
-		Height(paneHeight).
-		Padding(0, 1)
+	leftPane := m.framePane("left", leftContent, leftWidth, paneHeight, m.activePane == TreePane)
+	rightPane := m.framePane("right", m.previewView(), rightWidth, paneHeight, m.activePane == PreviewPane)
 
-	leftPane := leftPaneStyle.Render(leftContent)
-	rightPane := previewStyle.Render(m.preview.View())
-
-	return lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+	return m.joinPanes(leftPane, rightPane)
 }
 
 // renderFileOpOverlay renders the file operation overlay (create/rename/delete)
@@ -1174,6 +1447,7 @@ func (m Model) renderFileOpOverlay(background string) string {
 	if boxWidth < 50 {
 		boxWidth = 50
 	}
+	boxWidth = m.overlayWidth(boxWidth)
 
 	// Fixed height based on viewport
 	fixedHeight := m.height - 6
@@ -1247,6 +1521,29 @@ func (m Model) renderFileOpOverlay(background string) string {
 			contentLines = append(contentLines, metaStyle.Render("Press Enter to confirm"))
 		}
 
+	case FileOpDuplicate:
+		contentLines = append(contentLines, titleStyle.Render("Duplicate File"))
+		contentLines = append(contentLines, "")
+		wrapped := wrapText("copy of: "+m.fileOpSourcePath, boxWidth-8)
+		for _, line := range wrapped {
+			contentLines = append(contentLines, metaStyle.Render(line))
+		}
+		contentLines = append(contentLines, "")
+		contentLines = append(contentLines, m.fileOpInput.View())
+
+	case FileOpChmod:
+		contentLines = append(contentLines, titleStyle.Render("Permissions"))
+		contentLines = append(contentLines, "")
+		wrapped := wrapText(m.fileOpTargetPath, boxWidth-8)
+		for _, line := range wrapped {
+			contentLines = append(contentLines, metaStyle.Render(line))
+		}
+		contentLines = append(contentLines, "")
+		contentLines = append(contentLines, m.fileOpInput.View())
+		if perm, err := parseOctalMode(m.fileOpInput.Value()); err == nil {
+			contentLines = append(contentLines, "  "+metaStyle.Render(octalToMode(perm).String()))
+		}
+
 	case FileOpImport:
 		contentLines = append(contentLines, titleStyle.Render("Import File"))
 		contentLines = append(contentLines, "")
@@ -1267,15 +1564,40 @@ func (m Model) renderFileOpOverlay(background string) string {
 		contentLines = append(contentLines, m.fileOpInput.View())
 	}
 
+	// Show the folders tab cycles through
+	if len(m.fileOpCompletions) > 1 {
+		var names []string
+		for i, c := range m.fileOpCompletions {
+			name := filepath.Base(c) + "/"
+			if i == m.fileOpCompletion {
+				name = styles.Selected.Render(name)
+			} else {
+				name = metaStyle.Render(name)
+			}
+			names = append(names, name)
+		}
+		contentLines = append(contentLines, "")
+		contentLines = append(contentLines, wrapText(strings.Join(names, "  "), boxWidth-8)...)
+	}
+
 	// Add error message if present
 	if m.fileOpError != "" {
 		contentLines = append(contentLines, "")
 		contentLines = append(contentLines, errorStyle.Render(m.fileOpError))
+	} else if m.fileOpConfirm && m.fileOpMode != FileOpDelete {
+		contentLines = append(contentLines, "")
+		contentLines = append(contentLines, warningStyle.Render("Press Enter again to confirm"))
 	}
 
 	// Add footer hint
 	contentLines = append(contentLines, "")
-	contentLines = append(contentLines, metaStyle.Render("[enter] confirm  [esc] cancel"))
+	if m.fileOpMode == FileOpDelete {
+		contentLines = append(contentLines, metaStyle.Render("[enter] confirm  [esc] cancel"))
+	} else if m.fileOpMode == FileOpChmod {
+		contentLines = append(contentLines, metaStyle.Render("[tab] toggle executable  [enter] apply  [esc] cancel"))
+	} else {
+		contentLines = append(contentLines, metaStyle.Render("[tab] complete folder  [enter] confirm  [esc] cancel"))
+	}
 
 	// Calculate scrolling
 	maxContentHeight := fixedHeight - 4 // Account for box padding/borders
@@ -1322,7 +1644,7 @@ func (m Model) renderFileOpOverlay(background string) string {
 	// Create the box
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("205")).
+		BorderForeground(styles.BorderActive).
 		Padding(1, 2).
 		Width(boxWidth).
 		Height(fixedHeight)
@@ -0,0 +1,98 @@
+package atomicfile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Write replaces the file at path with data through a synced temporary file renamed
+// over it, so a crash mid-write leaves either the old or the new content, never half.
+// A new file gets mode 0644; an existing one keeps its mode. With backup, the version
+// it replaces is kept as a .bak file next to it.
+func Write(path string, data []byte, backup bool) error {
+	return WriteIf(path, data, backup, nil)
+}
+
+// WriteIf is Write with check run right before the swap; if it returns an error the
+// file is left alone and that error returned
+func WriteIf(path string, data []byte, backup bool, check func() error) error {
+	perm := os.FileMode(0644)
+	info, err := os.Stat(path)
+	existed := err == nil
+	if existed {
+		perm = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, ".contexTUI-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+
+	if check != nil {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+	if existed && backup {
+		if err := copyFile(path, path+".bak", perm); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// copyFile copies src to dst, synced
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// syncDir makes a rename in dir durable; not every platform supports it, so
+// failures are ignored
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,117 @@
+package backups
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+)
+
+// Dir is where snapshots are kept, relative to the project root
+const Dir = ".contextui/backups"
+
+// Keep is how many snapshots are kept on disk; older ones are removed
+const Keep = 50
+
+// Snapshot holds files as they were before a change, by slash-separated path
+// relative to the root. A nil content means the file didn't exist.
+type Snapshot struct {
+	Label string             `json:"label"`
+	Time  time.Time          `json:"time"`
+	Files map[string]*string `json:"files"`
+}
+
+// Take reads the files at paths (relative to the root) into a snapshot
+func Take(rootPath, label string, paths []string) Snapshot {
+	s := Snapshot{Label: label, Time: time.Now(), Files: make(map[string]*string)}
+	for _, p := range paths {
+		key := filepath.ToSlash(filepath.Clean(p))
+		if _, ok := s.Files[key]; ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(rootPath, p))
+		if err != nil {
+			s.Files[key] = nil
+			continue
+		}
+		content := string(data)
+		s.Files[key] = &content
+	}
+	return s
+}
+
+// Restore writes the snapshot's files back, removing those that didn't exist, and
+// returns a snapshot of what they held just before, to go back to it again
+// With backup, replaced files are kept as .bak files (see atomicfile.Write).
+func (s Snapshot) Restore(rootPath string, backup bool) (Snapshot, error) {
+	paths := make([]string, 0, len(s.Files))
+	for p := range s.Files {
+		paths = append(paths, p)
+	}
+	current := Take(rootPath, s.Label, paths)
+
+	var firstErr error
+	for p, content := range s.Files {
+		now := current.Files[p]
+		if (content == nil && now == nil) || (content != nil && now != nil && *content == *now) {
+			continue
+		}
+		fullPath := filepath.Join(rootPath, filepath.FromSlash(p))
+		var err error
+		if content == nil {
+			err = os.Remove(fullPath)
+		} else {
+			err = atomicfile.Write(fullPath, []byte(*content), backup)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return current, firstErr
+}
+
+// Save writes a snapshot to the backups directory and removes the oldest ones
+// beyond Keep
+func Save(rootPath string, s Snapshot) error {
+	dir := filepath.Join(rootPath, Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d.json", s.Time.UnixNano())
+	if err := atomicfile.Write(filepath.Join(dir, name), append(data, '\n'), false); err != nil {
+		return err
+	}
+	prune(dir)
+	return nil
+}
+
+// prune removes all but the newest Keep snapshots
+func prune(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= Keep {
+		return
+	}
+	// Names are nanosecond timestamps of the same length for centuries, so they sort by age
+	sort.Strings(names)
+	for _, name := range names[:len(names)-Keep] {
+		os.Remove(filepath.Join(dir, name))
+	}
+}
@@ -0,0 +1,149 @@
+package basket
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+	"github.com/connorleisz/contexTUI/internal/tokens"
+)
+
+// FileName is where the basket is kept between sessions, relative to the project root
+const FileName = ".contextui/basket.json"
+
+// Kind is what a basket item holds
+type Kind string
+
+const (
+	KindFile      Kind = "file"      // A file, sent as an @ reference
+	KindDoc       Kind = "doc"       // A context doc, sent as an @ reference
+	KindSelection Kind = "selection" // Lines of a file, sent with their text
+	KindDiff      Kind = "diff"      // Uncommitted changes of a file, sent with the diff
+)
+
+// Item is one thing staged in the basket
+type Item struct {
+	Kind   Kind   `json:"kind"`
+	Path   string `json:"path"`             // Relative to the project root
+	Start  int    `json:"start,omitempty"`  // First line of a selection (1-based)
+	End    int    `json:"end,omitempty"`    // Last line of a selection (1-based)
+	Staged bool   `json:"staged,omitempty"` // Diff of the index instead of the working tree
+	Text   string `json:"text,omitempty"`   // Selection or diff text when it was added
+}
+
+// Ref returns the item as an @ reference, with the line range of a selection
+func (it Item) Ref() string {
+	switch {
+	case it.Kind != KindSelection:
+		return "@" + it.Path
+	case it.Start == it.End:
+		return fmt.Sprintf("@%s#L%d", it.Path, it.Start)
+	default:
+		return fmt.Sprintf("@%s#L%d-L%d", it.Path, it.Start, it.End)
+	}
+}
+
+// Label describes the item in the basket list
+func (it Item) Label() string {
+	switch {
+	case it.Kind == KindDiff && it.Staged:
+		return it.Path + " (staged diff)"
+	case it.Kind == KindDiff:
+		return it.Path + " (diff)"
+	}
+	return strings.TrimPrefix(it.Ref(), "@")
+}
+
+// Tokens estimates what the item costs as context: the referenced file for files
+// and docs, the captured text otherwise
+func (it Item) Tokens(rootPath string) int {
+	if it.Kind == KindFile || it.Kind == KindDoc {
+		info, err := os.Stat(filepath.Join(rootPath, it.Path))
+		if err != nil {
+			return 0
+		}
+		return tokens.EstimateSize(info.Size())
+	}
+	return tokens.Estimate(it.Text)
+}
+
+// inline returns true for items sent with their text rather than as a reference
+func (it Item) inline() bool {
+	return it.Kind == KindSelection || it.Kind == KindDiff
+}
+
+// same returns true if two items stage the same thing
+func (it Item) same(other Item) bool {
+	return it.Kind == other.Kind && it.Path == other.Path && it.Start == other.Start &&
+		it.End == other.End && it.Staged == other.Staged
+}
+
+// Add appends an item unless the basket already has it
+// Returns false when it was already there.
+func Add(items []Item, item Item) ([]Item, bool) {
+	for _, it := range items {
+		if it.same(item) {
+			return items, false
+		}
+	}
+	return append(items, item), true
+}
+
+// Format builds one payload from the items in order: references one per line,
+// selections and diffs as fenced blocks under their reference
+func Format(items []Item) string {
+	var b strings.Builder
+	for i, it := range items {
+		if i > 0 {
+			b.WriteString("\n")
+			if it.inline() || items[i-1].inline() {
+				b.WriteString("\n")
+			}
+		}
+		if !it.inline() {
+			b.WriteString(it.Ref())
+			continue
+		}
+		header, fence := it.Ref(), "```"
+		if it.Kind == KindDiff {
+			header, fence = "@"+it.Label(), "```diff"
+		}
+		b.WriteString(header + "\n" + fence + "\n" + strings.TrimRight(it.Text, "\n") + "\n```")
+	}
+	return b.String() + "\n"
+}
+
+// Load reads the project's basket, or returns nil if there is none
+func Load(rootPath string) []Item {
+	data, err := os.ReadFile(filepath.Join(rootPath, FileName))
+	if err != nil {
+		return nil
+	}
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil // Malformed basket, start over
+	}
+	return items
+}
+
+// Save writes the project's basket, removing the file once the basket is empty
+func Save(rootPath string, items []Item) error {
+	path := filepath.Join(rootPath, FileName)
+	if len(items) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data, false)
+}
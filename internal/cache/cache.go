@@ -0,0 +1,136 @@
+package cache
+
+import "container/list"
+
+// Cache is a map bounded by entry count and total size, evicting the least recently
+// used entries first. It counts hits, misses and evictions for the cache stats overlay.
+// A Cache is not safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	name       string
+	maxEntries int
+	maxBytes   int64         // 0 = no size limit
+	sizeOf     func(V) int64 // Approximate memory held by a value
+	items      map[K]*list.Element
+	order      *list.List // Most recently used at the front
+	bytes      int64
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+}
+
+// item is an entry in a Cache's recency list
+type item[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+// Stats is a snapshot of a cache's usage
+type Stats struct {
+	Name       string
+	Entries    int
+	MaxEntries int
+	Bytes      int64
+	MaxBytes   int64
+	Hits       uint64
+	Misses     uint64
+	Evictions  uint64
+}
+
+// HitRate returns the fraction of lookups that found an entry
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// New creates an empty cache holding at most maxEntries values and maxBytes of them
+// as measured by sizeOf (maxBytes 0 or a nil sizeOf disables the size limit)
+func New[K comparable, V any](name string, maxEntries int, maxBytes int64, sizeOf func(V) int64) *Cache[K, V] {
+	if sizeOf == nil {
+		maxBytes = 0
+		sizeOf = func(V) int64 { return 0 }
+	}
+	return &Cache[K, V]{
+		name:       name,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		sizeOf:     sizeOf,
+		items:      make(map[K]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get returns the value for key and marks it as recently used
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	if el, ok := c.items[key]; ok {
+		c.hits++
+		c.order.MoveToFront(el)
+		return el.Value.(*item[K, V]).value, true
+	}
+	c.misses++
+	var zero V
+	return zero, false
+}
+
+// Put stores a value, then evicts least recently used entries until the cache is
+// within its limits. A value larger than the size limit on its own is not kept.
+func (c *Cache[K, V]) Put(key K, value V) {
+	size := c.sizeOf(value)
+	if el, ok := c.items[key]; ok {
+		it := el.Value.(*item[K, V])
+		c.bytes += size - it.size
+		it.value, it.size = value, size
+		c.order.MoveToFront(el)
+	} else {
+		c.items[key] = c.order.PushFront(&item[K, V]{key: key, value: value, size: size})
+		c.bytes += size
+	}
+
+	for c.order.Len() > 0 && (c.order.Len() > c.maxEntries || (c.maxBytes > 0 && c.bytes > c.maxBytes)) {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// Delete removes key from the cache
+func (c *Cache[K, V]) Delete(key K) {
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Clear drops every entry, keeping the hit and miss counts
+func (c *Cache[K, V]) Clear() {
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+	c.bytes = 0
+}
+
+// Len returns the number of cached entries
+func (c *Cache[K, V]) Len() int {
+	return c.order.Len()
+}
+
+// Stats returns the cache's current usage
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Name:       c.name,
+		Entries:    c.order.Len(),
+		MaxEntries: c.maxEntries,
+		Bytes:      c.bytes,
+		MaxBytes:   c.maxBytes,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+}
+
+// remove unlinks an entry and releases its size
+func (c *Cache[K, V]) remove(el *list.Element) {
+	it := el.Value.(*item[K, V])
+	c.order.Remove(el)
+	delete(c.items, it.key)
+	c.bytes -= it.size
+}
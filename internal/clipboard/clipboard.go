@@ -1,8 +1,14 @@
 package clipboard
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
+	"os"
+	"os/exec"
+	"runtime"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/x/ansi"
@@ -13,30 +19,64 @@ var ErrUnavailable = errors.New("clipboard unavailable - install xclip, xsel, or
 
 // IsAvailable returns true if clipboard operations are supported
 func IsAvailable() bool {
-	return !clipboard.Unsupported
+	return !clipboard.Unsupported || wslClip() != ""
 }
 
 // CopyFilePath copies a single file path to clipboard with @ prefix
 func CopyFilePath(path string) error {
-	if clipboard.Unsupported {
-		return ErrUnavailable
-	}
-	formatted := "@" + path
-	return clipboard.WriteAll(formatted)
+	return write("@" + path)
 }
 
 // CopyRaw copies raw text to clipboard without any formatting
 func CopyRaw(text string) error {
+	return write(text)
+}
+
+// write copies text to the system clipboard
+// Under WSL the Windows clipboard is used, which works without an X server.
+func write(text string) error {
+	if clip := wslClip(); clip != "" {
+		return copyWindows(clip, text)
+	}
 	if clipboard.Unsupported {
 		return ErrUnavailable
 	}
 	return clipboard.WriteAll(text)
 }
 
+// wslClip returns the path of clip.exe when running under WSL, or ""
+func wslClip() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+	if os.Getenv("WSL_DISTRO_NAME") == "" && os.Getenv("WSL_INTEROP") == "" {
+		return ""
+	}
+	clip, err := exec.LookPath("clip.exe")
+	if err != nil {
+		return ""
+	}
+	return clip
+}
+
+// copyWindows pipes text to clip.exe as UTF-16 with a byte order mark, so
+// non-ASCII text isn't mangled by the console code page
+func copyWindows(clip, text string) error {
+	encoded := utf16.Encode([]rune(text))
+	buf := make([]byte, 2, 2+2*len(encoded))
+	binary.LittleEndian.PutUint16(buf, 0xFEFF)
+	for _, u := range encoded {
+		buf = binary.LittleEndian.AppendUint16(buf, u)
+	}
+	cmd := exec.Command(clip)
+	cmd.Stdin = bytes.NewReader(buf)
+	return cmd.Run()
+}
+
 // CopyLines copies lines from a slice, stripping ANSI codes and line numbers
 // start and end are inclusive indices
 func CopyLines(lines []string, start, end int, stripLineNumbers func(string) string) error {
-	if clipboard.Unsupported {
+	if !IsAvailable() {
 		return ErrUnavailable
 	}
 
@@ -44,6 +84,12 @@ func CopyLines(lines []string, start, end int, stripLineNumbers func(string) str
 		return nil // Nothing to copy, not an error
 	}
 
+	return write(ExtractLines(lines, start, end, stripLineNumbers))
+}
+
+// ExtractLines returns lines[start:end+1] as plain text, stripping ANSI codes and line numbers
+// start and end are inclusive indices and may be given in either order
+func ExtractLines(lines []string, start, end int, stripLineNumbers func(string) string) string {
 	if start > end {
 		start, end = end, start
 	}
@@ -69,5 +115,5 @@ func CopyLines(lines []string, start, end int, stripLineNumbers func(string) str
 		cleanLines = append(cleanLines, clean)
 	}
 
-	return clipboard.WriteAll(strings.Join(cleanLines, "\n"))
+	return strings.Join(cleanLines, "\n")
 }
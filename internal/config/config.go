@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const FileName = ".contexTUI.json"
@@ -11,7 +12,159 @@ const FileName = ".contexTUI.json"
 // Config represents user preferences saved per-project
 type Config struct {
 	SplitRatio   float64 `json:"splitRatio,omitempty"`
+	Layout       string  `json:"layout,omitempty"` // Zoomed pane: "tree", "preview", or both panes when empty
 	ShowDotfiles bool    `json:"showDotfiles,omitempty"`
+	Theme        string  `json:"theme,omitempty"`      // Built-in theme name or path to a theme file
+	NoWrap       bool    `json:"noWrap,omitempty"`     // Show long preview lines unwrapped (pan horizontally)
+	Accessible   bool    `json:"accessible,omitempty"` // Mark selections and statuses with text, not color alone
+
+	// Walk symlinked directories outside the project in search and file watching
+	FollowSymlinks bool `json:"followSymlinks,omitempty"`
+
+	// Terminal integration, e.g. inside tmux/screen (also -no-mouse / -no-altscreen flags)
+	NoMouse     bool `json:"noMouse,omitempty"`     // Don't capture the mouse; use keyboard selection
+	NoAltScreen bool `json:"noAltScreen,omitempty"` // Render in the main screen instead of the alternate screen
+
+	// Tree marks set with m{a-z}, as paths relative to the project root
+	Marks map[string]string `json:"marks,omitempty"`
+
+	// Where S sends references instead of the clipboard (a tmux pane wins over a file)
+	SendTmuxPane string `json:"sendTmuxPane,omitempty"` // tmux pane running the agent, e.g. "claude"
+	SendFile     string `json:"sendFile,omitempty"`     // File to append references to, e.g. ".claude/context.md"
+
+	// Append everything copied to .contextui/history.log
+	CopyHistoryLog bool `json:"copyHistoryLog,omitempty"`
+
+	// Render cache size limits in megabytes (zero uses the defaults)
+	PreviewCacheMB int `json:"previewCacheMB,omitempty"` // Rendered file previews
+	DiffCacheMB    int `json:"diffCacheMB,omitempty"`    // Rendered git diffs
+	ImageCacheMB   int `json:"imageCacheMB,omitempty"`   // Rendered images
+
+	// Debounce tuning in milliseconds (zero uses the defaults)
+	SearchDebounceMs int `json:"searchDebounceMs,omitempty"` // Delay before fuzzy search runs while typing
+	FsDebounceMs     int `json:"fsDebounceMs,omitempty"`     // Initial delay before reloading after a file change
+	FsDebounceMaxMs  int `json:"fsDebounceMaxMs,omitempty"`  // Longest delay while a burst of changes is ongoing
+
+	// Refresh git status this often in seconds, for git commands run elsewhere (zero disables)
+	GitPollSeconds int `json:"gitPollSeconds,omitempty"`
+
+	// Issue references to link besides #123 on the origin remote's tracker
+	IssueLinks []IssueLink `json:"issueLinks,omitempty"`
+
+	// Update structure tags as docs change and create .contextui/ for caches without
+	// asking; off, opening a project never changes it until contexTUI init or a deliberate edit
+	ImplicitWrites bool `json:"implicitWrites,omitempty"`
+
+	// Keep the previous version of .context-docs.md and edited docs as a .bak file
+	BackupFiles bool `json:"backupFiles,omitempty"`
+
+	// Footer segments in order: "branch", "docs", "tokens", "path", "time" and "hints"
+	// (empty shows branch and hints)
+	StatusBar []string `json:"statusBar,omitempty"`
+
+	// Disable every change to files, docs and the repository (also -read-only)
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// When to ask before a change: "destructive" (default), "always" or "never" (also -confirm)
+	Confirm string `json:"confirm,omitempty"`
+}
+
+// Confirm policies
+const (
+	ConfirmDestructive = "destructive" // Ask before deleting files, docs, categories and stashes
+	ConfirmAlways      = "always"      // Also ask before other changes
+	ConfirmNever       = "never"       // Never ask
+)
+
+// ValidConfirm returns true if policy is a confirm policy
+func ValidConfirm(policy string) bool {
+	return policy == ConfirmDestructive || policy == ConfirmAlways || policy == ConfirmNever
+}
+
+// ConfirmPolicy returns when to ask before a change, destructive-only unless set
+func (c Config) ConfirmPolicy() string {
+	if ValidConfirm(c.Confirm) {
+		return c.Confirm
+	}
+	return ConfirmDestructive
+}
+
+// IssueLink turns references matching Pattern into links, e.g. pattern "JIRA-\\d+"
+// with url "https://example.atlassian.net/browse/{id}"
+// {id} is the pattern's first group, or the whole match when it has none.
+type IssueLink struct {
+	Pattern string `json:"pattern"`
+	URL     string `json:"url"`
+}
+
+// Debounce defaults
+const (
+	DefaultSearchDebounce = 100 * time.Millisecond
+	DefaultFsDebounce     = 100 * time.Millisecond
+	DefaultFsDebounceMax  = 1000 * time.Millisecond
+)
+
+// Render cache defaults in megabytes
+const (
+	DefaultPreviewCacheMB = 64
+	DefaultDiffCacheMB    = 32
+	DefaultImageCacheMB   = 128
+)
+
+// PreviewCacheBytes returns the size limit of the preview cache
+func (c Config) PreviewCacheBytes() int64 {
+	return megabytes(c.PreviewCacheMB, DefaultPreviewCacheMB)
+}
+
+// DiffCacheBytes returns the size limit of the diff cache
+func (c Config) DiffCacheBytes() int64 {
+	return megabytes(c.DiffCacheMB, DefaultDiffCacheMB)
+}
+
+// ImageCacheBytes returns the size limit of the image cache
+func (c Config) ImageCacheBytes() int64 {
+	return megabytes(c.ImageCacheMB, DefaultImageCacheMB)
+}
+
+// megabytes converts a configured size, or the default when unset, to bytes
+func megabytes(mb, def int) int64 {
+	if mb <= 0 {
+		mb = def
+	}
+	return int64(mb) << 20
+}
+
+// SearchDebounce returns the search debounce delay
+func (c Config) SearchDebounce() time.Duration {
+	if c.SearchDebounceMs > 0 {
+		return time.Duration(c.SearchDebounceMs) * time.Millisecond
+	}
+	return DefaultSearchDebounce
+}
+
+// FsDebounce returns the initial filesystem reload debounce delay
+func (c Config) FsDebounce() time.Duration {
+	if c.FsDebounceMs > 0 {
+		return time.Duration(c.FsDebounceMs) * time.Millisecond
+	}
+	return DefaultFsDebounce
+}
+
+// FsDebounceMax returns the upper bound for the filesystem reload debounce
+func (c Config) FsDebounceMax() time.Duration {
+	limit := DefaultFsDebounceMax
+	if c.FsDebounceMaxMs > 0 {
+		limit = time.Duration(c.FsDebounceMaxMs) * time.Millisecond
+	}
+	return max(limit, c.FsDebounce())
+}
+
+// GitPoll returns how often git status is refreshed, or zero if it isn't polled
+func (c Config) GitPoll() time.Duration {
+	if c.GitPollSeconds > 0 {
+		return time.Duration(c.GitPollSeconds) * time.Second
+	}
+	return 0
 }
 
 // Load loads project-specific configuration
@@ -32,13 +185,13 @@ func Load(rootPath string) Config {
 }
 
 // Save saves project-specific configuration
-func Save(rootPath string, cfg Config) {
+func Save(rootPath string, cfg Config) error {
 	configPath := filepath.Join(rootPath, FileName)
 
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return // Silently fail
+		return err
 	}
 
-	os.WriteFile(configPath, data, 0644)
+	return os.WriteFile(configPath, data, 0644)
 }
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// onboardedPath returns the global file listing projects the onboarding wizard was shown in
+func onboardedPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contexTUI", "onboarded.json"), nil
+}
+
+// Onboarded returns true if the onboarding wizard was already shown for a project
+// It is kept outside the project, so opening a project never writes to it.
+func Onboarded(rootPath string) bool {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		return true // Can't tell; don't nag
+	}
+	for _, p := range onboardedProjects() {
+		if p == abs {
+			return true
+		}
+	}
+	return false
+}
+
+// SetOnboarded records that the onboarding wizard was shown for a project
+func SetOnboarded(rootPath string) error {
+	path, err := onboardedPath()
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(rootPath)
+	if err != nil || Onboarded(abs) {
+		return err
+	}
+	data, err := json.MarshalIndent(append(onboardedProjects(), abs), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// onboardedProjects reads the list of onboarded project roots
+func onboardedProjects() []string {
+	path, err := onboardedPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var projects []string
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil // Malformed list, start over
+	}
+	return projects
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// maxRecentProjects bounds the recent projects list
+const maxRecentProjects = 20
+
+// recentProjectsPath returns the global file listing recently opened projects
+func recentProjectsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contexTUI", "recent.json"), nil
+}
+
+// RecentProjects returns recently opened project roots, most recent first
+func RecentProjects() []string {
+	path, err := recentProjectsPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var projects []string
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil // Malformed list, start over
+	}
+	return projects
+}
+
+// AddRecentProject moves a project root to the front of the recent projects list
+func AddRecentProject(rootPath string) error {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		return err
+	}
+	projects := append([]string{abs}, without(RecentProjects(), abs)...)
+	if len(projects) > maxRecentProjects {
+		projects = projects[:maxRecentProjects]
+	}
+	return saveRecentProjects(projects)
+}
+
+// RemoveRecentProject drops a project root from the recent projects list
+func RemoveRecentProject(rootPath string) error {
+	return saveRecentProjects(without(RecentProjects(), rootPath))
+}
+
+// without returns projects minus path
+func without(projects []string, path string) []string {
+	var kept []string
+	for _, p := range projects {
+		if p != path {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// saveRecentProjects writes the recent projects list
+func saveRecentProjects(projects []string) error {
+	path, err := recentProjectsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
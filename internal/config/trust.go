@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// trustedCommandsPath returns the global file of doc commands the user has allowed to run
+func trustedCommandsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "contexTUI", "trusted-commands.json"), nil
+}
+
+// IsCommandTrusted returns true if the user has allowed a command to run in a project
+// Trust is kept outside the project, so a cloned repo can't ship its own commands trusted.
+func IsCommandTrusted(rootPath, command string) bool {
+	abs, err := filepath.Abs(rootPath)
+	if err != nil {
+		return false
+	}
+	for _, c := range trustedCommands()[abs] {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustCommand records that the user has allowed a command to run in a project
+func TrustCommand(rootPath, command string) error {
+	path, err := trustedCommandsPath()
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(rootPath)
+	if err != nil || IsCommandTrusted(abs, command) {
+		return err
+	}
+	trusted := trustedCommands()
+	if trusted == nil {
+		trusted = make(map[string][]string)
+	}
+	trusted[abs] = append(trusted[abs], command)
+	data, err := json.MarshalIndent(trusted, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// trustedCommands reads the trusted commands, keyed by absolute project root
+func trustedCommands() map[string][]string {
+	path, err := trustedCommandsPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var trusted map[string][]string
+	if err := json.Unmarshal(data, &trusted); err != nil {
+		return nil // Malformed file, trust nothing
+	}
+	return trusted
+}
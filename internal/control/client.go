@@ -0,0 +1,103 @@
+package control
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SocketPath returns the socket a session of the project at root listens on with
+// -listen, so clients can find it from a path inside the project
+func SocketPath(root string) string {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		root = real
+	}
+	sum := sha256.Sum256([]byte(root))
+	return filepath.Join(socketDir(), fmt.Sprintf("%x.sock", sum[:6]))
+}
+
+// socketDir returns the user's own directory for sockets: under $XDG_RUNTIME_DIR,
+// else the user cache directory, never a directory other users can write to
+func socketDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "contexTUI")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "contexTUI", "sockets")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("contexTUI-%d", os.Getuid()))
+}
+
+// Find returns the socket of the listening session whose project holds path,
+// looking from path's directory up to the filesystem root
+// Sockets owned by another user are never dialed.
+func Find(path string) (string, bool) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = real
+	}
+	for {
+		socket := SocketPath(dir)
+		if info, err := os.Lstat(socket); err == nil && ownedByUser(info) {
+			if conn, err := net.DialTimeout("unix", socket, time.Second); err == nil {
+				conn.Close()
+				return socket, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Call sends a JSON-RPC call to the session listening on socket and returns its result
+func Call(socket, method string, params any) (json.RawMessage, error) {
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(replyTimeout + time.Second))
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	c := struct {
+		JSONRPC string `json:"jsonrpc"`
+		call
+	}{"2.0", call{ID: json.RawMessage("1"), Method: method, Params: raw}}
+	if err := json.NewEncoder(conn).Encode(c); err != nil {
+		return nil, err
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *rpcError       `json:"error"`
+	}
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, errors.New(resp.Error.Message)
+	}
+	return resp.Result, nil
+}
@@ -0,0 +1,174 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrUnknownMethod is replied to calls of methods the app doesn't have
+var ErrUnknownMethod = errors.New("unknown method")
+
+// replyTimeout bounds how long a call waits for the app, e.g. while it is busy
+const replyTimeout = 5 * time.Second
+
+// Request is a JSON-RPC 2.0 call read from the socket, handed to the app to answer
+type Request struct {
+	Method string
+	Params json.RawMessage
+	reply  chan response
+}
+
+// Reply answers the call with result, or with err when it isn't nil
+// It never blocks; calls that aren't waiting for an answer drop it.
+func (r Request) Reply(result any, err error) {
+	if r.reply == nil {
+		return
+	}
+	resp := response{Result: result}
+	if err != nil {
+		code := -32000
+		if errors.Is(err, ErrUnknownMethod) {
+			code = -32601
+		}
+		resp = response{Error: &rpcError{Code: code, Message: err.Error()}}
+	}
+	select {
+	case r.reply <- resp:
+	default:
+	}
+}
+
+// call is a line of JSON-RPC read from a client
+type call struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// response is the line written back for a call with an id
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server accepts connections on a unix socket
+type Server struct {
+	ln   net.Listener
+	path string
+}
+
+// Listen creates the socket at path, replacing one left behind by a session that
+// exited without cleaning up
+// A path owned by another user is refused rather than replaced, and the default
+// socket directory (see SocketPath) is kept private to the user.
+func Listen(path string) (*Server, error) {
+	if dir := filepath.Dir(path); dir == socketDir() {
+		if err := privateDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if !ownedByUser(info) || info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New(path + " exists and isn't a socket of this user")
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, errors.New("another session is listening on " + path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// Only the user may drive the session
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &Server{ln: ln, path: path}, nil
+}
+
+// privateDir creates dir for the user alone, refusing one another user owns
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case err != nil:
+		return err
+	case !ownedByUser(info):
+		return errors.New(dir + " belongs to another user")
+	case info.Mode().Perm()&0077 != 0:
+		return os.Chmod(dir, 0700)
+	}
+	return nil
+}
+
+// Serve accepts clients until Close, passing each call to send as a Request
+func (s *Server) Serve(send func(any)) {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn, send)
+	}
+}
+
+// handle answers a client's calls, one JSON object per line
+func (s *Server) handle(conn net.Conn, send func(any)) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var c call
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil || c.Method == "" {
+			enc.Encode(response{JSONRPC: "2.0", ID: json.RawMessage("null"),
+				Error: &rpcError{Code: -32700, Message: "invalid request"}})
+			continue
+		}
+		// Notifications (no id) get no answer
+		if len(c.ID) == 0 {
+			send(Request{Method: c.Method, Params: c.Params})
+			continue
+		}
+		req := Request{Method: c.Method, Params: c.Params, reply: make(chan response, 1)}
+		send(req)
+		var resp response
+		select {
+		case resp = <-req.reply:
+		case <-time.After(replyTimeout):
+			resp = response{Error: &rpcError{Code: -32000, Message: "timed out"}}
+		}
+		resp.JSONRPC, resp.ID = "2.0", c.ID
+		if resp.Result == nil && resp.Error == nil {
+			resp.Result = struct{}{}
+		}
+		enc.Encode(resp)
+	}
+}
+
+// Close stops accepting clients and removes the socket
+func (s *Server) Close() error {
+	err := s.ln.Close()
+	os.Remove(s.path)
+	return err
+}
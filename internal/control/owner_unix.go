@@ -0,0 +1,14 @@
+//go:build unix
+
+package control
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedByUser returns true if the file belongs to the user running contexTUI
+func ownedByUser(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Getuid()
+}
@@ -0,0 +1,144 @@
+package debuglog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of entries, shown as sections of the debug overlay
+const (
+	KindWatch = "watch" // Filesystem events
+	KindGit   = "git"   // Git commands and how long they took
+	KindError = "error" // Failures that are otherwise silent
+)
+
+// keep is how many recent entries of each kind are held for the debug overlay
+const keep = 50
+
+// Entry is a logged event kept for the debug overlay
+type Entry struct {
+	Time    time.Time
+	Kind    string
+	Message string
+}
+
+var (
+	mu      sync.Mutex
+	logger  = slog.New(slog.NewTextHandler(io.Discard, nil))
+	logFile *os.File
+	logPath string
+	recent  = make(map[string][]Entry)
+	reports = make(chan Entry, 32)
+)
+
+// Open starts this session's log file in the user cache directory (e.g.
+// ~/.cache/contexTUI/logs) and returns its path; until then entries are only
+// kept in memory
+func Open() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "contexTUI", "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.log", time.Now().Format("20060102-150405"), os.Getpid()))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	logFile, logPath = f, path
+	logger = slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return path, nil
+}
+
+// Close closes the log file, if one is open
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	if logFile != nil {
+		logFile.Close()
+		logFile = nil
+	}
+}
+
+// Path returns the session's log file, empty if none was opened
+func Path() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return logPath
+}
+
+// Recent returns the latest entries of a kind, oldest first
+func Recent(kind string) []Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Entry(nil), recent[kind]...)
+}
+
+// record keeps an entry for the overlay and writes it to the log file
+func record(kind, message string, level slog.Level, attrs ...any) Entry {
+	mu.Lock()
+	defer mu.Unlock()
+	entry := Entry{Time: time.Now(), Kind: kind, Message: message}
+	entries := append(recent[kind], entry)
+	if len(entries) > keep {
+		entries = entries[len(entries)-keep:]
+	}
+	recent[kind] = entries
+	logger.Log(context.Background(), level, message, append([]any{"kind", kind}, attrs...)...)
+	return entry
+}
+
+// Watch logs a filesystem event
+func Watch(op, path string) {
+	record(KindWatch, op+" "+path, slog.LevelDebug, "op", op, "path", path)
+}
+
+// Command logs an external command with how long it took and how it failed, if it did
+func Command(args []string, took time.Duration, err error) {
+	message := fmt.Sprintf("%s (%s)", strings.Join(args, " "), took.Round(time.Millisecond))
+	if err != nil {
+		message += ": " + err.Error()
+		record(KindGit, message, slog.LevelWarn, "args", args, "took", took, "err", err)
+		return
+	}
+	record(KindGit, message, slog.LevelDebug, "args", args, "took", took)
+}
+
+// Error logs a failure that is otherwise silent, e.g. of the clipboard or watcher
+func Error(what string, err error) {
+	if err == nil {
+		return
+	}
+	record(KindError, what+": "+err.Error(), slog.LevelError, "what", what, "err", err)
+}
+
+// Report logs a failure the user should know about, like Error, and passes it on
+// to Reports to be shown. Reports beyond what the channel holds are only logged.
+func Report(what string, err error) {
+	if err == nil {
+		return
+	}
+	entry := record(KindError, what+": "+err.Error(), slog.LevelError, "what", what, "err", err)
+	select {
+	case reports <- entry:
+	default:
+	}
+}
+
+// Reports returns the channel reported failures arrive on
+func Reports() <-chan Entry {
+	return reports
+}
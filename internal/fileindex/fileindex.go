@@ -0,0 +1,56 @@
+package fileindex
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+)
+
+// FileName is where the file list is kept between sessions, relative to the project root
+const FileName = ".contextui/index.gob"
+
+// version changes whenever the saved layout does, so older files are ignored
+const version = 1
+
+// index is the saved file list
+type index struct {
+	Version      int
+	ShowDotfiles bool     // Dotfiles change which files are listed
+	Files        []string // Paths relative to the root
+	Denied       []string // Paths skipped due to permissions
+}
+
+// Load returns the file list saved by the last session, or ok=false if there is
+// none for these settings. It may be out of date; callers reconcile it with a crawl.
+func Load(rootPath string, showDotfiles bool) (files, denied []string, ok bool) {
+	data, err := os.ReadFile(filepath.Join(rootPath, FileName))
+	if err != nil {
+		return nil, nil, false
+	}
+	var idx index
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&idx); err != nil {
+		return nil, nil, false // Malformed index, crawl instead
+	}
+	if idx.Version != version || idx.ShowDotfiles != showDotfiles {
+		return nil, nil, false
+	}
+	return idx.Files, idx.Denied, true
+}
+
+// Save writes the file list for the next session
+// It is written to a temporary file first so a concurrent Load never sees half of it.
+func Save(rootPath string, showDotfiles bool, files, denied []string) error {
+	var buf bytes.Buffer
+	idx := index{Version: version, ShowDotfiles: showDotfiles, Files: files, Denied: denied}
+	if err := gob.NewEncoder(&buf).Encode(idx); err != nil {
+		return err
+	}
+	path := filepath.Join(rootPath, FileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, buf.Bytes(), false)
+}
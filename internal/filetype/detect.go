@@ -13,6 +13,8 @@ const (
 	KindText FileKind = iota
 	KindImage
 	KindBinary
+	KindArchive
+	KindPDF
 )
 
 // ImageFormat represents specific image formats
@@ -37,6 +39,9 @@ var imageExtensions = map[string]ImageFormat{
 	".svg":  FormatSVG,
 }
 
+// archiveSuffixes lists the archive formats that can be listed
+var archiveSuffixes = []string{".zip", ".jar", ".tar", ".tar.gz", ".tgz"}
+
 // DetectKind determines the general file type from path
 func DetectKind(path string) FileKind {
 	ext := strings.ToLower(filepath.Ext(path))
@@ -46,6 +51,13 @@ func DetectKind(path string) FileKind {
 		return KindImage
 	}
 
+	if IsArchive(path) {
+		return KindArchive
+	}
+	if ext == ".pdf" {
+		return KindPDF
+	}
+
 	// Check for binary by reading first bytes
 	if isBinaryFile(path) {
 		return KindBinary
@@ -73,6 +85,17 @@ func IsImage(path string) bool {
 	return DetectKind(path) == KindImage
 }
 
+// IsArchive returns true if the file is a zip or tar archive
+func IsArchive(path string) bool {
+	lower := strings.ToLower(path)
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
 // isBinaryFile checks if a file appears to be binary by looking for null bytes
 func isBinaryFile(path string) bool {
 	f, err := os.Open(path)
@@ -105,6 +128,10 @@ func (k FileKind) String() string {
 		return "Image"
 	case KindBinary:
 		return "Binary"
+	case KindArchive:
+		return "Archive"
+	case KindPDF:
+		return "PDF"
 	default:
 		return "Text"
 	}
@@ -0,0 +1,100 @@
+package frecency
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+)
+
+// FileName is where file visits are kept, relative to the project root
+const FileName = ".contextui/frecency.json"
+
+// maxEntries bounds the store; the lowest scoring files are dropped past it
+const maxEntries = 500
+
+// Entry is how often and how recently a file was opened
+type Entry struct {
+	Count int   `json:"count"`
+	Last  int64 `json:"last"` // Unix time of the last visit
+}
+
+// Store holds the visits of a project's files, by path relative to the root
+type Store map[string]Entry
+
+// Load reads the project's visits, or returns an empty store if there are none
+func Load(rootPath string) Store {
+	s := make(Store)
+	data, err := os.ReadFile(filepath.Join(rootPath, FileName))
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return make(Store) // Malformed file, start over
+	}
+	return s
+}
+
+// Save writes the project's visits
+func Save(rootPath string, s Store) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(rootPath, FileName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, data, false)
+}
+
+// Visit records that a file was opened
+func (s Store) Visit(path string, now time.Time) {
+	e := s[path]
+	e.Count++
+	e.Last = now.Unix()
+	s[path] = e
+
+	if len(s) > maxEntries {
+		paths := make([]string, 0, len(s))
+		for p := range s {
+			paths = append(paths, p)
+		}
+		sort.Slice(paths, func(i, j int) bool { return s.Score(paths[i], now) < s.Score(paths[j], now) })
+		for _, p := range paths[:len(s)-maxEntries] {
+			delete(s, p)
+		}
+	}
+}
+
+// Score weighs how often a file was opened by how recently, like browser history
+// Files never opened score 0.
+func (s Store) Score(path string, now time.Time) float64 {
+	e, ok := s[path]
+	if !ok {
+		return 0
+	}
+	age := now.Sub(time.Unix(e.Last, 0))
+	weight := 0.25
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 1
+	case age < 30*24*time.Hour:
+		weight = 0.5
+	}
+	return float64(e.Count) * weight
+}
+
+// Boost converts a file's score into points added to a fuzzy match score
+// It grows slowly, so a much better match still wins over a frequently opened file.
+func (s Store) Boost(path string, now time.Time) int {
+	return int(math.Round(8 * math.Log2(1+s.Score(path, now))))
+}
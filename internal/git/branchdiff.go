@@ -0,0 +1,60 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// BaseRef returns the branch a pull request from the current branch would merge
+// into: the remote's default branch, else main or master, else the upstream
+// It returns "" when none of them exist.
+func BaseRef(repoRoot string) string {
+	branch := GetBranchInfo(repoRoot).Branch
+	candidates := []string{"origin/main", "origin/master", "main", "master", "@{upstream}"}
+	if out, err := runOutput(exec.Command("git", "-C", repoRoot, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")); err == nil {
+		candidates = append([]string{strings.TrimSpace(string(out))}, candidates...)
+	}
+	for _, ref := range candidates {
+		if ref == branch {
+			continue
+		}
+		if runCmd(exec.Command("git", "-C", repoRoot, "rev-parse", "--verify", "--quiet", ref+"^{commit}")) == nil {
+			return ref
+		}
+	}
+	return ""
+}
+
+// BranchFiles returns the files changed on the current branch since it left base
+func BranchFiles(repoRoot, base string) ([]FileStatus, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "diff", "--name-status", "--find-renames", base+"...HEAD")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileStatus
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
+		// Renames and copies carry a similarity score, e.g. "R087"
+		f := FileStatus{Status: parts[0][:1], Path: parts[len(parts)-1]}
+		if len(parts) == 3 {
+			f.OldPath = parts[1]
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// BranchDiff returns the changes made on the current branch since it left base
+func BranchDiff(repoRoot, base string) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "diff", "--find-renames", base+"...HEAD")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,144 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BranchInfoTTL is how long branch and upstream info is reused before git is asked again
+// Commits, checkouts and fetches are noticed sooner through the repo's reflog and FETCH_HEAD.
+const BranchInfoTTL = 10 * time.Second
+
+// BranchInfo is the current branch and its position relative to its upstream
+type BranchInfo struct {
+	Branch      string // "HEAD" when detached, "" before the first commit
+	Ahead       int
+	Behind      int
+	HasUpstream bool
+}
+
+// branchInfoEntry is a cached BranchInfo and what it was computed against
+type branchInfoEntry struct {
+	info    BranchInfo
+	loaded  time.Time
+	stamp   string // Modification times of the files that change with HEAD and fetches
+	gitDir  string
+	expired bool
+}
+
+var (
+	branchInfoMu    sync.Mutex
+	branchInfoCache = make(map[string]*branchInfoEntry) // Repo root -> entry
+)
+
+// GetBranchInfo returns the branch and ahead/behind counts, served from a cache until
+// BranchInfoTTL passes or HEAD, the reflog or FETCH_HEAD change
+func GetBranchInfo(repoRoot string) BranchInfo {
+	branchInfoMu.Lock()
+	entry := branchInfoCache[repoRoot]
+	branchInfoMu.Unlock()
+
+	var gitDir string
+	if entry != nil {
+		gitDir = entry.gitDir
+		if !entry.expired && time.Since(entry.loaded) < BranchInfoTTL && gitStamp(gitDir) == entry.stamp {
+			return entry.info
+		}
+	} else {
+		gitDir = GitDir(repoRoot)
+	}
+
+	// Stamp before loading so a change made meanwhile triggers another load
+	stamp := gitStamp(gitDir)
+	info := LoadBranchInfo(repoRoot)
+
+	branchInfoMu.Lock()
+	branchInfoCache[repoRoot] = &branchInfoEntry{info: info, loaded: time.Now(), stamp: stamp, gitDir: gitDir}
+	branchInfoMu.Unlock()
+	return info
+}
+
+// InvalidateBranchInfo makes the next GetBranchInfo ask git again
+func InvalidateBranchInfo(repoRoot string) {
+	branchInfoMu.Lock()
+	defer branchInfoMu.Unlock()
+	if entry := branchInfoCache[repoRoot]; entry != nil {
+		entry.expired = true
+	}
+}
+
+// LoadBranchInfo reads the branch, upstream and ahead/behind counts in a single git call
+func LoadBranchInfo(repoRoot string) BranchInfo {
+	// Only branches at HEAD are listed, so upstream tracking is computed for just those
+	cmd := exec.Command("git", "-C", repoRoot, "for-each-ref", "--points-at=HEAD",
+		"--format=%(HEAD)%00%(refname:short)%00%(upstream:short)%00%(upstream:track,nobracket)", "refs/heads")
+	output, err := runOutput(cmd)
+	if err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			parts := strings.Split(line, "\x00")
+			if len(parts) != 4 || parts[0] != "*" {
+				continue
+			}
+			info := BranchInfo{Branch: parts[1]}
+			if parts[2] != "" && parts[3] != "gone" {
+				info.HasUpstream = true
+				for _, field := range strings.Split(parts[3], ", ") {
+					if n, ok := strings.CutPrefix(field, "ahead "); ok {
+						info.Ahead, _ = strconv.Atoi(n)
+					} else if n, ok := strings.CutPrefix(field, "behind "); ok {
+						info.Behind, _ = strconv.Atoi(n)
+					}
+				}
+			}
+			return info
+		}
+	}
+
+	// Detached HEAD or no commits yet
+	cmd = exec.Command("git", "-C", repoRoot, "rev-parse", "--abbrev-ref", "HEAD")
+	output, err = runOutput(cmd)
+	if err != nil {
+		return BranchInfo{}
+	}
+	return BranchInfo{Branch: strings.TrimSpace(string(output))}
+}
+
+// GitDir returns the repo's git directory, following the .git file of worktrees
+// and submodules
+func GitDir(repoRoot string) string {
+	dotGit := filepath.Join(repoRoot, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil || info.IsDir() {
+		return dotGit
+	}
+	content, err := os.ReadFile(dotGit)
+	if err != nil {
+		return dotGit
+	}
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(content)), "gitdir: ")
+	if !ok {
+		return dotGit
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoRoot, dir)
+	}
+	return dir
+}
+
+// gitStamp summarizes the modification times of the files git rewrites on commit,
+// checkout, reset and fetch
+func gitStamp(gitDir string) string {
+	var sb strings.Builder
+	for _, name := range []string{"HEAD", filepath.Join("logs", "HEAD"), "FETCH_HEAD"} {
+		if info, err := os.Stat(filepath.Join(gitDir, name)); err == nil {
+			sb.WriteString(strconv.FormatInt(info.ModTime().UnixNano(), 10))
+		}
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
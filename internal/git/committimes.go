@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bufio"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// commitTimeCache holds last-commit times for one repo, valid while HEAD is unchanged
+type commitTimeCache struct {
+	head  string
+	times map[string]int64 // Clean slash path -> Unix time (0 = never committed)
+}
+
+var (
+	commitTimesMu sync.Mutex
+	commitTimes   = make(map[string]*commitTimeCache) // Repo root -> cache
+)
+
+// HeadHash returns the commit HEAD points at, or "" for a repo without commits
+func HeadHash(repoRoot string) string {
+	cmd := exec.Command("git", "-C", repoRoot, "rev-parse", "--verify", "-q", "HEAD")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// LastCommitTimes returns the Unix time of the last commit touching each path (relative
+// to repoRoot), keyed by the paths as given. Paths never committed map to 0.
+// Uncached paths are resolved together in a single git log pass that stops once every
+// path has been seen, and results are cached until HEAD moves.
+func LastCommitTimes(repoRoot string, paths []string) map[string]int64 {
+	result := make(map[string]int64, len(paths))
+	head := HeadHash(repoRoot)
+	if head == "" {
+		return result
+	}
+
+	commitTimesMu.Lock()
+	defer commitTimesMu.Unlock()
+
+	cache := commitTimes[repoRoot]
+	if cache == nil || cache.head != head {
+		cache = &commitTimeCache{head: head, times: make(map[string]int64)}
+		commitTimes[repoRoot] = cache
+	}
+
+	pending := make(map[string]bool)
+	for _, p := range paths {
+		if _, ok := cache.times[cleanGitPath(p)]; !ok {
+			pending[cleanGitPath(p)] = true
+		}
+	}
+	if len(pending) > 0 {
+		if found, ok := scanCommitTimes(repoRoot, pending); ok {
+			for p := range pending {
+				cache.times[p] = found[p]
+			}
+		} else {
+			// Don't cache a failed lookup; report what was found
+			for p, t := range found {
+				result[p] = t
+			}
+		}
+	}
+
+	for _, p := range paths {
+		if t, ok := cache.times[cleanGitPath(p)]; ok {
+			result[p] = t
+		} else if t, ok := result[cleanGitPath(p)]; ok {
+			result[p] = t
+		}
+	}
+	return result
+}
+
+// cleanGitPath normalizes a path for use as a cache key and pathspec
+func cleanGitPath(p string) string {
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// scanCommitTimes walks history newest first, recording the first commit that touches
+// each wanted path (a file, or any file below a directory). ok is false if git failed.
+func scanCommitTimes(repoRoot string, wanted map[string]bool) (map[string]int64, bool) {
+	args := []string{"-C", repoRoot, "--literal-pathspecs", "log", "--format=%x1e%ct", "--name-only", "--"}
+	for p := range wanted {
+		args = append(args, p)
+	}
+	cmd := exec.Command("git", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, false
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, false
+	}
+
+	found := make(map[string]int64, len(wanted))
+	var commitTime int64
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() && len(found) < len(wanted) {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\x1e") {
+			commitTime, _ = strconv.ParseInt(strings.TrimPrefix(line, "\x1e"), 10, 64)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		// Credit the file and every directory above it
+		for p := line; p != "." && p != "/"; p = path.Dir(p) {
+			if wanted[p] {
+				if _, seen := found[p]; !seen {
+					found[p] = commitTime
+				}
+			}
+		}
+	}
+
+	complete := len(found) == len(wanted)
+	if complete {
+		// Everything is resolved; the rest of history isn't needed
+		cmd.Process.Kill()
+	}
+	err = cmd.Wait()
+	return found, complete || err == nil
+}
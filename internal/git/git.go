@@ -3,8 +3,10 @@ package git
 import (
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // FileStatus represents the status of a file in git
@@ -19,7 +21,7 @@ type FileStatus struct {
 // Returns (isRepo, repoRoot)
 func IsRepo(path string) (bool, string) {
 	cmd := exec.Command("git", "-C", path, "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	output, err := runOutput(cmd)
 	if err != nil {
 		return false, ""
 	}
@@ -33,7 +35,7 @@ func LoadStatus(repoRoot string) (map[string]FileStatus, []FileStatus) {
 
 	// Run git status --porcelain=v1 for machine-readable output
 	cmd := exec.Command("git", "-C", repoRoot, "status", "--porcelain=v1")
-	output, err := cmd.Output()
+	output, err := runOutput(cmd)
 	if err != nil {
 		return statusMap, changes
 	}
@@ -77,7 +79,8 @@ func LoadStatus(repoRoot string) (map[string]FileStatus, []FileStatus) {
 		}
 
 		if status.Status != "" {
-			statusMap[path] = status
+			// Keyed like the tree's relative paths, which use the OS separator
+			statusMap[filepath.FromSlash(path)] = status
 			changes = append(changes, status)
 		}
 	}
@@ -106,46 +109,12 @@ func ComputeDirStatus(statusMap map[string]FileStatus) map[string]string {
 	return dirStatus
 }
 
-// GetBranch returns the current branch name
-func GetBranch(repoRoot string) string {
-	cmd := exec.Command("git", "-C", repoRoot, "rev-parse", "--abbrev-ref", "HEAD")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
-}
-
-// GetAheadBehind returns commits ahead and behind upstream
-// Returns (ahead, behind, hasUpstream)
-func GetAheadBehind(repoRoot string) (int, int, bool) {
-	// Check if upstream exists
-	cmd := exec.Command("git", "-C", repoRoot, "rev-parse", "--abbrev-ref", "@{upstream}")
-	if _, err := cmd.Output(); err != nil {
-		return 0, 0, false // No upstream configured
-	}
-
-	// Get ahead/behind counts
-	cmd = exec.Command("git", "-C", repoRoot, "rev-list", "--left-right", "--count", "@{upstream}...HEAD")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, 0, false
-	}
-
-	parts := strings.Fields(strings.TrimSpace(string(output)))
-	if len(parts) != 2 {
-		return 0, 0, false
-	}
-
-	behind, _ := strconv.Atoi(parts[0])
-	ahead, _ := strconv.Atoi(parts[1])
-	return ahead, behind, true
-}
-
 // Fetch runs git fetch for the current branch's upstream
 func Fetch(repoRoot string) error {
 	cmd := exec.Command("git", "-C", repoRoot, "fetch")
-	return cmd.Run()
+	err := runCmd(cmd)
+	InvalidateBranchInfo(repoRoot)
+	return err
 }
 
 // LoadDiff runs git diff and returns the diff output for a file
@@ -161,10 +130,234 @@ func LoadDiff(repoRoot, filePath string, staged bool, contextLines int) (string,
 	}
 
 	cmd := exec.Command("git", args...)
-	output, err := cmd.Output()
+	output, err := runOutput(cmd)
 	if err != nil || len(output) == 0 {
 		return "", err
 	}
 
 	return string(output), nil
 }
+
+// LoadWorkingDiff returns the changes of a file since the last commit, staged or not
+func LoadWorkingDiff(repoRoot, filePath string, contextLines int) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "diff", "-U"+strconv.Itoa(contextLines), "HEAD", "--", filePath)
+	output, err := runOutput(cmd)
+	if err != nil || len(output) == 0 {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// LoadStagedDiff returns everything staged for the next commit
+func LoadStagedDiff(repoRoot string) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "diff", "--cached", "--find-renames")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// LoadNewFileDiff returns an untracked file as a diff that adds it
+func LoadNewFileDiff(repoRoot, filePath string) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "diff", "--no-index", "--", "/dev/null", filePath)
+	// Exits 1 when there are differences, which there always are
+	output, err := runOutput(cmd)
+	if len(output) == 0 {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// Commit is a single entry from git log
+type Commit struct {
+	Hash    string // Abbreviated hash
+	Subject string
+	Author  string
+	Date    string // Short date (YYYY-MM-DD)
+}
+
+// ListTags returns tags ordered newest first (by creation date)
+func ListTags(repoRoot string) []string {
+	cmd := exec.Command("git", "-C", repoRoot, "tag", "--sort=-creatordate")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if tag := strings.TrimSpace(line); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// LogRange returns commits reachable from to but not from (from may be empty for all history)
+func LogRange(repoRoot, from, to string) ([]Commit, error) {
+	rev := to
+	if from != "" {
+		rev = from + ".." + to
+	}
+	cmd := exec.Command("git", "-C", repoRoot, "log", "--no-merges", "--format=%h%x1f%s%x1f%an%x1f%ad", "--date=short", rev)
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 4 {
+			continue
+		}
+		commits = append(commits, Commit{Hash: parts[0], Subject: parts[1], Author: parts[2], Date: parts[3]})
+	}
+	return commits, nil
+}
+
+// Co-change analysis limits
+const (
+	coChangeCommits  = 300 // Most recent commits touching the file that are analyzed
+	coChangeMaxFiles = 50  // Larger commits (mass renames, formatting) are ignored
+)
+
+// CoChange is a file that was committed together with another file
+type CoChange struct {
+	Path  string // Relative to the repo root
+	Count int    // Commits touching both files
+}
+
+// CoChanged returns the files most often committed together with filePath (relative to
+// the repo root), most frequent first, and how many commits touching the file were analyzed
+func CoChanged(repoRoot, filePath string) ([]CoChange, int, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "log", "--no-merges", "--format=%H",
+		"-n", strconv.Itoa(coChangeCommits), "--", filePath)
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil, 0, err
+	}
+	hashes := strings.Fields(string(output))
+	if len(hashes) == 0 {
+		return nil, 0, nil
+	}
+
+	// The path-limited log only lists filePath itself, so list each commit's full file set
+	args := append([]string{"-C", repoRoot, "log", "--no-walk=unsorted", "--format=%x1e", "--name-only"}, hashes...)
+	output, err = runOutput(exec.Command("git", args...))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts := make(map[string]int)
+	for _, commit := range strings.Split(string(output), "\x1e") {
+		var files []string
+		for _, line := range strings.Split(commit, "\n") {
+			if line = strings.TrimSpace(line); line != "" && line != filePath {
+				files = append(files, line)
+			}
+		}
+		if len(files) >= coChangeMaxFiles {
+			continue
+		}
+		for _, f := range files {
+			counts[f]++
+		}
+	}
+
+	changes := make([]CoChange, 0, len(counts))
+	for path, count := range counts {
+		changes = append(changes, CoChange{Path: path, Count: count})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Count != changes[j].Count {
+			return changes[i].Count > changes[j].Count
+		}
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, len(hashes), nil
+}
+
+// BlameLine is the last commit that touched one line of a file
+type BlameLine struct {
+	Hash   string // Short hash; all zeros for uncommitted lines
+	Author string
+	Time   time.Time
+	Text   string
+}
+
+// Blame returns the blame of every line of filePath (relative to the repo root)
+func Blame(repoRoot, filePath string) ([]BlameLine, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "blame", "--line-porcelain", "--", filePath)
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []BlameLine
+	var cur BlameLine
+	for _, line := range strings.Split(string(output), "\n") {
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			cur.Text = line[1:]
+			lines = append(lines, cur)
+			cur = BlameLine{}
+		case strings.HasPrefix(line, "author "):
+			cur.Author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			if ts, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64); err == nil {
+				cur.Time = time.Unix(ts, 0)
+			}
+		case cur.Hash == "" && len(line) >= 40 && !strings.Contains(line[:40], " "):
+			cur.Hash = line[:7]
+		}
+	}
+	return lines, nil
+}
+
+// FileCommit is a commit touching a file, with the file's path at that commit
+type FileCommit struct {
+	Commit
+	Path string // Relative to the repo root; differs from today's path before a rename
+}
+
+// FileLog returns the commits touching filePath (relative to the repo root), newest
+// first, following renames
+func FileLog(repoRoot, filePath string) ([]FileCommit, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "log", "--follow", "--name-only",
+		"--format=%x1e%h%x1f%s%x1f%an%x1f%ad", "--date=short", "--", filePath)
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []FileCommit
+	for _, entry := range strings.Split(string(output), "\x1e") {
+		lines := strings.Split(strings.TrimSpace(entry), "\n")
+		parts := strings.Split(lines[0], "\x1f")
+		if len(parts) != 4 {
+			continue
+		}
+		c := FileCommit{
+			Commit: Commit{Hash: parts[0], Subject: parts[1], Author: parts[2], Date: parts[3]},
+			Path:   filePath,
+		}
+		for _, line := range lines[1:] {
+			if line = strings.TrimSpace(line); line != "" {
+				c.Path = line
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// ShowFileDiff returns the changes a commit made to filePath
+func ShowFileDiff(repoRoot, hash, filePath string) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "show", "--format=", "--find-renames", hash, "--", filePath)
+	output, err := runOutput(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// IgnoredTTL is how long the ignored paths of a directory are reused before git is
+// asked again; InvalidateIgnored forces it sooner
+const IgnoredTTL = 10 * time.Second
+
+// ignoredEntry is a cached set of ignored paths
+type ignoredEntry struct {
+	paths  map[string]bool
+	loaded time.Time
+}
+
+var (
+	ignoredMu    sync.Mutex
+	ignoredCache = make(map[string]*ignoredEntry) // Directory -> entry
+)
+
+// Ignored returns the paths under dir that git ignores, as absolute paths, using
+// every source git status does: .gitignore files, .git/info/exclude and the user's
+// core.excludesFile. Ignored directories are listed rather than their contents.
+// Outside a repo the set is empty.
+func Ignored(dir string) map[string]bool {
+	ignoredMu.Lock()
+	entry := ignoredCache[dir]
+	ignoredMu.Unlock()
+	if entry != nil && time.Since(entry.loaded) < IgnoredTTL {
+		return entry.paths
+	}
+
+	paths := LoadIgnored(dir)
+	ignoredMu.Lock()
+	ignoredCache[dir] = &ignoredEntry{paths: paths, loaded: time.Now()}
+	ignoredMu.Unlock()
+	return paths
+}
+
+// InvalidateIgnored makes the next Ignored call ask git again, e.g. after a
+// .gitignore changed
+func InvalidateIgnored(dir string) {
+	ignoredMu.Lock()
+	defer ignoredMu.Unlock()
+	delete(ignoredCache, dir)
+}
+
+// LoadIgnored asks git for the ignored paths under dir (see Ignored)
+func LoadIgnored(dir string) map[string]bool {
+	paths := make(map[string]bool)
+	cmd := exec.Command("git", "-C", dir, "ls-files", "--others", "--ignored", "--exclude-standard", "--directory", "-z")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return paths
+	}
+	// Paths are relative to dir; ignored directories end in a slash
+	for _, p := range bytes.Split(output, []byte{0}) {
+		if len(p) > 0 {
+			paths[filepath.Join(dir, filepath.FromSlash(string(bytes.TrimSuffix(p, []byte("/")))))] = true
+		}
+	}
+	return paths
+}
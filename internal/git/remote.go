@@ -0,0 +1,111 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// WebURL turns a remote URL into the repository's web address, e.g.
+// "git@github.com:owner/repo.git" into "https://github.com/owner/repo"
+func WebURL(remote string) (string, bool) {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), "/")
+	remote = strings.TrimSuffix(remote, ".git")
+
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		// https://host/owner/repo or ssh://git@host:22/owner/repo
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, "@"); ok && !strings.Contains(at, "/") {
+		// scp-like: git@host:owner/repo
+		host, path, ok = strings.Cut(rest, ":")
+		if !ok {
+			return "", false
+		}
+	} else {
+		return "", false
+	}
+	path = strings.Trim(path, "/")
+	if host == "" || path == "" {
+		return "", false
+	}
+	return "https://" + host + "/" + path, true
+}
+
+// OriginWebURL returns the web address of the origin remote
+func OriginWebURL(repoRoot string) (string, bool) {
+	out, err := runOutput(exec.Command("git", "-C", repoRoot, "remote", "get-url", "origin"))
+	if err != nil {
+		return "", false
+	}
+	return WebURL(string(out))
+}
+
+// IssueURLTemplate returns the origin remote's issue URL with {id} in place of the
+// number, or "" without a web remote. GitHub redirects issue URLs of pull requests.
+func IssueURLTemplate(repoRoot string) string {
+	web, ok := OriginWebURL(repoRoot)
+	if !ok {
+		return ""
+	}
+	if strings.Contains(web, "gitlab") {
+		return web + "/-/issues/{id}"
+	}
+	return web + "/issues/{id}"
+}
+
+// Permalink returns the web URL of a file (relative to the repo root) at the HEAD
+// commit on the origin remote, with an optional line range (start 0 for none)
+// GitLab and Bitbucket get their own URL layouts; anything else is assumed to
+// lay out URLs like GitHub (as Gitea and Forgejo do).
+func Permalink(repoRoot, relPath string, isDir bool, start, end int) (string, error) {
+	web, ok := OriginWebURL(repoRoot)
+	if !ok {
+		return "", fmt.Errorf("no web remote for origin")
+	}
+	out, err := runOutput(exec.Command("git", "-C", repoRoot, "rev-parse", "HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("no commits yet")
+	}
+	commit := strings.TrimSpace(string(out))
+
+	var segments []string
+	for _, s := range strings.Split(relPath, "/") {
+		segments = append(segments, url.PathEscape(s))
+	}
+	path := strings.Join(segments, "/")
+
+	kind := "blob"
+	if isDir {
+		kind = "tree"
+	}
+	switch {
+	case strings.Contains(web, "gitlab"):
+		link := fmt.Sprintf("%s/-/%s/%s/%s", web, kind, commit, path)
+		if start > 0 {
+			link += fmt.Sprintf("#L%d", start)
+			if end > start {
+				link += fmt.Sprintf("-%d", end)
+			}
+		}
+		return link, nil
+	case strings.Contains(web, "bitbucket"):
+		link := fmt.Sprintf("%s/src/%s/%s", web, commit, path)
+		if start > 0 {
+			link += fmt.Sprintf("#lines-%d", start)
+			if end > start {
+				link += fmt.Sprintf(":%d", end)
+			}
+		}
+		return link, nil
+	}
+	link := fmt.Sprintf("%s/%s/%s/%s", web, kind, commit, path)
+	if start > 0 {
+		link += fmt.Sprintf("#L%d", start)
+		if end > start {
+			link += fmt.Sprintf("-L%d", end)
+		}
+	}
+	return link, nil
+}
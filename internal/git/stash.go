@@ -0,0 +1,59 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// Stash is an entry of the stash list
+type Stash struct {
+	Ref     string // stash@{n}, which shifts as stashes are dropped
+	Hash    string // Abbreviated stash commit, stable while it exists
+	Subject string // e.g. "WIP on main: 1a2b3c4 Fix parser"
+	Age     string // e.g. "2 hours ago"
+}
+
+// ListStashes returns the stashes, newest first
+func ListStashes(repoRoot string) ([]Stash, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "stash", "list", "--format=%gd%x1f%h%x1f%gs%x1f%cr")
+	output, err := runOutput(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var stashes []Stash
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 4 {
+			continue
+		}
+		stashes = append(stashes, Stash{Ref: parts[0], Hash: parts[1], Subject: parts[2], Age: parts[3]})
+	}
+	return stashes, nil
+}
+
+// StashDiff returns the changes a stash holds
+func StashDiff(repoRoot, ref string) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "stash", "show", "-p", "--find-renames", ref)
+	output, err := runOutput(cmd)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// StashAction runs git stash apply, pop or drop on a stash
+// The error carries git's message, e.g. about conflicts with local changes.
+func StashAction(repoRoot, action, ref string) error {
+	cmd := exec.Command("git", "-C", repoRoot, "stash", action, ref)
+	output, err := runCombined(cmd)
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			// The first line says what went wrong; the rest is advice
+			return errors.New(strings.TrimPrefix(strings.SplitN(msg, "\n", 2)[0], "error: "))
+		}
+		return err
+	}
+	return nil
+}
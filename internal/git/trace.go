@@ -0,0 +1,42 @@
+package git
+
+import (
+	"os/exec"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+)
+
+// runOutput runs a git command and returns its stdout, logging how long it took
+func runOutput(cmd *exec.Cmd) ([]byte, error) {
+	start := time.Now()
+	out, err := cmd.Output()
+	logCommand(cmd, start, err)
+	return out, err
+}
+
+// runCombined runs a git command and returns its stdout and stderr, logging how long it took
+func runCombined(cmd *exec.Cmd) ([]byte, error) {
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	logCommand(cmd, start, err)
+	return out, err
+}
+
+// runCmd runs a git command, logging how long it took
+func runCmd(cmd *exec.Cmd) error {
+	start := time.Now()
+	err := cmd.Run()
+	logCommand(cmd, start, err)
+	return err
+}
+
+// logCommand logs a finished command for the debug overlay, without the -C repo
+// argument every command has
+func logCommand(cmd *exec.Cmd, start time.Time, err error) {
+	args := cmd.Args
+	if len(args) > 2 && args[1] == "-C" {
+		args = append([]string{args[0]}, args[3:]...)
+	}
+	debuglog.Command(args, time.Since(start), err)
+}
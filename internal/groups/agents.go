@@ -0,0 +1,114 @@
+package groups
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+)
+
+// Markers around the generated section in CLAUDE.md / AGENTS.md
+// Everything between them is replaced on export; the rest of the file is left alone.
+const (
+	AgentsSectionStart = "<!-- contexTUI: context-docs start -->"
+	AgentsSectionEnd   = "<!-- contexTUI: context-docs end -->"
+)
+
+// maxAgentsSummary caps the description excerpt per doc
+const maxAgentsSummary = 200
+
+// RenderAgentsSection assembles the registry into the generated section, markers included
+func RenderAgentsSection(registry *ContextDocRegistry, rootPath string) string {
+	var sb strings.Builder
+
+	sb.WriteString(AgentsSectionStart + "\n")
+	sb.WriteString("## Context Docs\n\n")
+	sb.WriteString("This section is generated from `.context-docs.md` by contexTUI. Edit the docs, not this list.\n")
+	sb.WriteString("Read the doc for an area before changing its key files.\n")
+
+	for _, cat := range registry.Categories {
+		catDocs := registry.ByCategory[cat.ID]
+		if len(catDocs) == 0 {
+			continue
+		}
+		sb.WriteString("\n### " + cat.Name + "\n\n")
+		for _, d := range catDocs {
+			line := "- **" + d.Name + "** (`" + d.FilePath + "`)"
+			if d.Status != "" && d.Status != "Active" {
+				line += " - " + d.Status
+			}
+			if summary := docSummary(d.Description); summary != "" {
+				line += ": " + summary
+			}
+			sb.WriteString(line + "\n")
+			if refs := d.KeyFileRefs(rootPath); len(refs) > 0 {
+				sb.WriteString("  - Key files: `" + strings.Join(refs, "`, `") + "`\n")
+			}
+		}
+	}
+
+	if len(registry.Docs) == 0 {
+		sb.WriteString("\nNo context docs registered yet.\n")
+	}
+	sb.WriteString(AgentsSectionEnd + "\n")
+	return sb.String()
+}
+
+// docSummary returns the first paragraph of a description as a single line
+func docSummary(description string) string {
+	var parts []string
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		parts = append(parts, line)
+	}
+	summary := strings.Join(parts, " ")
+	if runes := []rune(summary); len(runes) > maxAgentsSummary {
+		summary = strings.TrimSpace(string(runes[:maxAgentsSummary])) + "…"
+	}
+	return summary
+}
+
+// ApplyAgentsSection returns content with the generated section replaced or appended
+// An empty file gets a skeleton titled after the file.
+func ApplyAgentsSection(content, section, fileName string) string {
+	if strings.TrimSpace(content) == "" {
+		return "# " + fileName + "\n\n" + section
+	}
+
+	start := strings.Index(content, AgentsSectionStart)
+	if start >= 0 {
+		if end := strings.Index(content[start:], AgentsSectionEnd); end >= 0 {
+			end += start + len(AgentsSectionEnd)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			return content[:start] + section + content[end:]
+		}
+	}
+
+	return strings.TrimRight(content, "\n") + "\n\n" + section
+}
+
+// ExportAgentsFile writes the registry into fileName (e.g. CLAUDE.md) under the root
+// Returns false if the file was already up to date.
+func ExportAgentsFile(rootPath, fileName string, registry *ContextDocRegistry, backup bool) (bool, error) {
+	fullPath := filepath.Join(rootPath, fileName)
+	original, err := os.ReadFile(fullPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	updated := ApplyAgentsSection(string(original), RenderAgentsSection(registry, rootPath), filepath.Base(fileName))
+	if updated == string(original) {
+		return false, nil
+	}
+	if err != nil {
+		// Didn't exist yet
+		return true, atomicfile.Write(fullPath, []byte(updated), backup)
+	}
+	return true, writeIfUnchanged(fullPath, original, []byte(updated), backup)
+}
@@ -0,0 +1,140 @@
+package groups
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+)
+
+// StructureAnnotationMarker opens the managed block in docs that need structuring
+//
+//	<!-- contexTUI: structure-needed
+//	missing: Category, Key Files
+//	added: 2024-01-31
+//	-->
+const StructureAnnotationMarker = "<!-- contexTUI: structure-needed"
+
+// ErrConcurrentEdit is returned when a doc changed while its annotation was being updated
+var ErrConcurrentEdit = errors.New("file changed during update")
+
+// annotationSpan locates the annotation block as a line range [start, end)
+// Also matches the legacy single-line "<!-- contexTUI: structure-needed -->" tag.
+func annotationSpan(lines []string) (int, int, bool) {
+	for i, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), StructureAnnotationMarker) {
+			continue
+		}
+		for j := i; j < len(lines); j++ {
+			if strings.Contains(lines[j], "-->") {
+				return i, j + 1, true
+			}
+		}
+		return i, len(lines), true // Unterminated, take the rest
+	}
+	return 0, 0, false
+}
+
+// HasStructureAnnotation reports whether content carries the structure-needed block
+func HasStructureAnnotation(content string) bool {
+	_, _, ok := annotationSpan(strings.Split(content, "\n"))
+	return ok
+}
+
+// ApplyStructureAnnotation returns content with the annotation block matching missing
+// The block is inserted at the top, rewritten in place when the missing fields change,
+// and removed when nothing is missing. The original "added" date is kept.
+func ApplyStructureAnnotation(content string, missing []string, now time.Time) string {
+	lines := strings.Split(content, "\n")
+	start, end, found := annotationSpan(lines)
+
+	if len(missing) == 0 {
+		if !found {
+			return content
+		}
+		return strings.Join(append(lines[:start:start], lines[end:]...), "\n")
+	}
+
+	added := now.Format("2006-01-02")
+	if found {
+		for _, line := range lines[start:end] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(line), "added:"); ok && strings.TrimSpace(v) != "" {
+				added = strings.TrimSpace(v)
+			}
+		}
+	}
+
+	block := []string{
+		StructureAnnotationMarker,
+		"missing: " + strings.Join(missing, ", "),
+		"added: " + added,
+		"-->",
+	}
+
+	var result []string
+	if found {
+		result = append(result, lines[:start]...)
+		result = append(result, block...)
+		result = append(result, lines[end:]...)
+	} else {
+		result = append(block, lines...)
+	}
+	return strings.Join(result, "\n")
+}
+
+// StructureAnnotationOutdated reports whether content carries an annotation block
+// that SyncStructureAnnotation would change
+func StructureAnnotationOutdated(content string, missing []string) bool {
+	return HasStructureAnnotation(content) && ApplyStructureAnnotation(content, missing, time.Now()) != content
+}
+
+// RemoveStructureAnnotation strips the annotation block from content
+func RemoveStructureAnnotation(content string) string {
+	return ApplyStructureAnnotation(content, nil, time.Time{})
+}
+
+// SyncStructureAnnotation brings a doc's annotation block in line with missing
+// The file is only written when the block actually changes, and the write is
+// skipped with ErrConcurrentEdit if the file was modified in the meantime.
+func SyncStructureAnnotation(rootPath, filePath string, missing []string, backup bool) error {
+	fullPath := filepath.Join(rootPath, filePath)
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return err
+	}
+
+	updated := ApplyStructureAnnotation(string(original), missing, time.Now())
+	if updated == string(original) {
+		return nil
+	}
+	return writeIfUnchanged(fullPath, original, []byte(updated), backup)
+}
+
+// ReplaceDoc writes updated over a doc read as original, with ErrConcurrentEdit
+// if the file changed since, e.g. while its edit was previewed
+func ReplaceDoc(rootPath, filePath, original, updated string, backup bool) error {
+	return writeIfUnchanged(filepath.Join(rootPath, filePath), []byte(original), []byte(updated), backup)
+}
+
+// writeIfUnchanged replaces the file atomically, unless its content no longer matches original
+// With backup, the replaced version is kept as a .bak file.
+func writeIfUnchanged(fullPath string, original, updated []byte, backup bool) error {
+	if _, err := os.Stat(fullPath); err != nil {
+		return err
+	}
+	// Last check right before the swap to keep the race window small
+	return atomicfile.WriteIf(fullPath, updated, backup, func() error {
+		current, err := os.ReadFile(fullPath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, original) {
+			return ErrConcurrentEdit
+		}
+		return nil
+	})
+}
@@ -0,0 +1,214 @@
+package groups
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// categoryLineRe matches a doc's **Category:** line
+var categoryLineRe = regexp.MustCompile(`(?i)^(\s*\*\*Category:\*\*\s*)(.*)$`)
+
+// CategoryID returns the identifier of a category name
+func CategoryID(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
+
+// SetDocCategory rewrites a doc's **Category:** line (or frontmatter category), adding
+// one after the H1 title (or at the top) when the doc has none
+func SetDocCategory(rootPath, docPath, category string, backup bool) error {
+	fullPath := filepath.Join(rootPath, docPath)
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(original), "\n")
+	replaced, inCodeBlock, h1 := false, false, -1
+	fields, body := parseFrontmatter(lines)
+	for i, line := range lines {
+		if i < body {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if h1 < 0 && strings.HasPrefix(trimmed, "# ") {
+			h1 = i
+		}
+		if m := categoryLineRe.FindStringSubmatch(line); m != nil {
+			lines[i] = m[1] + category
+			replaced = true
+			break
+		}
+	}
+	if _, ok := fields["category"]; ok && !replaced {
+		// A doc that keeps its category in frontmatter keeps it there
+		lines, replaced = setFrontmatterField(lines, "category", category)
+	}
+	if !replaced {
+		entry := "**Category:** " + category
+		if h1 >= 0 {
+			lines = append(lines[:h1+1], append([]string{"", entry}, lines[h1+1:]...)...)
+		} else if body > 0 {
+			lines = append(lines[:body], append([]string{"", entry}, lines[body:]...)...)
+		} else {
+			lines = append([]string{entry, ""}, lines...)
+		}
+	}
+	return writeIfUnchanged(fullPath, original, []byte(strings.Join(lines, "\n")), backup)
+}
+
+// CategoryIndex returns the position of a category by ID, or -1
+func (r *ContextDocRegistry) CategoryIndex(id string) int {
+	for i, c := range r.Categories {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddCategory appends an empty category, returning an error if it already exists
+func (r *ContextDocRegistry) AddCategory(name string) error {
+	name = strings.TrimSpace(name)
+	id := CategoryID(name)
+	if id == "" {
+		return fmt.Errorf("category name is empty")
+	}
+	if r.CategoryIndex(id) >= 0 {
+		return fmt.Errorf("category %q already exists", name)
+	}
+	r.Categories = append(r.Categories, Category{ID: id, Name: name})
+	return nil
+}
+
+// MoveCategory moves the category at index from to index to
+func (r *ContextDocRegistry) MoveCategory(from, to int) {
+	if from < 0 || from >= len(r.Categories) || to < 0 || to >= len(r.Categories) {
+		return
+	}
+	r.Categories[from], r.Categories[to] = r.Categories[to], r.Categories[from]
+}
+
+// MoveDocs sets the category of docs, rewriting their **Category:** lines
+// The category is created if needed. Returns how many docs were moved.
+func (r *ContextDocRegistry) MoveDocs(rootPath string, docPaths []string, category string, backup bool) (int, error) {
+	id := CategoryID(category)
+	if id == "" {
+		return 0, fmt.Errorf("category name is empty")
+	}
+	if i := r.CategoryIndex(id); i >= 0 {
+		category = r.Categories[i].Name
+	} else {
+		r.Categories = append(r.Categories, Category{ID: id, Name: category})
+	}
+
+	move := make(map[string]bool, len(docPaths))
+	for _, p := range docPaths {
+		move[p] = true
+	}
+	moved := 0
+	var firstErr error
+	for i := range r.Docs {
+		d := &r.Docs[i]
+		if !move[d.FilePath] || CategoryID(d.Category) == id {
+			continue
+		}
+		if err := SetDocCategory(rootPath, d.FilePath, category, backup); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		d.Category = category
+		moved++
+	}
+	r.regroup()
+	return moved, firstErr
+}
+
+// RenameCategory renames a category and rewrites the **Category:** line of its docs
+func (r *ContextDocRegistry) RenameCategory(rootPath, id, name string, backup bool) error {
+	i := r.CategoryIndex(id)
+	if i < 0 {
+		return fmt.Errorf("no category %q", id)
+	}
+	newID := CategoryID(name)
+	if newID == "" {
+		return fmt.Errorf("category name is empty")
+	}
+	if j := r.CategoryIndex(newID); j >= 0 && j != i {
+		return fmt.Errorf("category %q already exists", name)
+	}
+	r.Categories[i] = Category{ID: newID, Name: strings.TrimSpace(name)}
+
+	var firstErr error
+	for k := range r.Docs {
+		d := &r.Docs[k]
+		if docCategoryID(*d) != id {
+			continue
+		}
+		if err := SetDocCategory(rootPath, d.FilePath, r.Categories[i].Name, backup); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		d.Category = r.Categories[i].Name
+	}
+	r.regroup()
+	return firstErr
+}
+
+// DeleteCategory removes a category, first moving its docs to another one
+func (r *ContextDocRegistry) DeleteCategory(rootPath, id, moveTo string, backup bool) error {
+	var docPaths []string
+	for _, d := range r.ByCategory[id] {
+		docPaths = append(docPaths, d.FilePath)
+	}
+	if len(docPaths) > 0 {
+		if CategoryID(moveTo) == id {
+			return fmt.Errorf("docs must move to another category")
+		}
+		if _, err := r.MoveDocs(rootPath, docPaths, moveTo, backup); err != nil {
+			return err
+		}
+	}
+	if i := r.CategoryIndex(id); i >= 0 {
+		r.Categories = append(r.Categories[:i], r.Categories[i+1:]...)
+	}
+	r.regroup()
+	return nil
+}
+
+// docCategoryID returns the category ID a doc is filed under
+func docCategoryID(d ContextDoc) string {
+	if id := CategoryID(d.Category); id != "" {
+		return id
+	}
+	return "uncategorized"
+}
+
+// regroup rebuilds ByCategory from Docs, keeping registry order, and keeps the
+// Uncategorized category first while it has docs
+func (r *ContextDocRegistry) regroup() {
+	r.ByCategory = make(map[string][]ContextDoc)
+	for _, d := range r.Docs {
+		id := docCategoryID(d)
+		r.ByCategory[id] = append(r.ByCategory[id], d)
+	}
+	i := r.CategoryIndex("uncategorized")
+	switch {
+	case i >= 0 && len(r.ByCategory["uncategorized"]) == 0:
+		r.Categories = append(r.Categories[:i], r.Categories[i+1:]...)
+	case i < 0 && len(r.ByCategory["uncategorized"]) > 0:
+		r.Categories = append([]Category{{ID: "uncategorized", Name: "Uncategorized"}}, r.Categories...)
+	}
+}
@@ -0,0 +1,100 @@
+package groups
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Problems CheckRegistry reports
+const (
+	CheckMissingDoc    = "missing-doc"     // Registry entry whose file doesn't exist
+	CheckMissingFields = "missing-fields"  // Doc without all required metadata
+	CheckBrokenKeyFile = "broken-key-file" // Key file that doesn't exist
+	CheckStale         = "stale"           // Key files changed after the doc
+)
+
+// Severities of a check problem
+const (
+	SeverityError   = "error"   // Fails the check
+	SeverityWarning = "warning" // Reported only
+	SeverityOff     = "off"     // Not reported
+)
+
+// CheckIssue is one problem found in a doc
+type CheckIssue struct {
+	Doc      string `json:"doc"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CheckReport is the result of checking a registry
+type CheckReport struct {
+	Docs     int          `json:"docs"`
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+	Issues   []CheckIssue `json:"issues"`
+}
+
+// DefaultCheckSeverity returns the severity of each check problem when not overridden
+func DefaultCheckSeverity() map[string]string {
+	return map[string]string{
+		CheckMissingDoc:    SeverityError,
+		CheckMissingFields: SeverityError,
+		CheckBrokenKeyFile: SeverityError,
+		CheckStale:         SeverityError,
+	}
+}
+
+// ParseSeverity validates a severity name
+func ParseSeverity(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case SeverityError:
+		return SeverityError, nil
+	case SeverityWarning, "warn":
+		return SeverityWarning, nil
+	case SeverityOff, "ignore", "none":
+		return SeverityOff, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want error, warning or off)", s)
+}
+
+// CheckRegistry loads the registry and reports missing docs, missing required fields,
+// broken key files and stale docs at the given severities
+func CheckRegistry(rootPath string, severity map[string]string) (*CheckReport, error) {
+	registry, err := LoadContextDocRegistry(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CheckReport{Docs: len(registry.Docs), Issues: []CheckIssue{}}
+	add := func(doc, kind, message string) {
+		sev := severity[kind]
+		switch sev {
+		case SeverityError:
+			report.Errors++
+		case SeverityWarning:
+			report.Warnings++
+		default:
+			return
+		}
+		report.Issues = append(report.Issues, CheckIssue{Doc: doc, Kind: kind, Severity: sev, Message: message})
+	}
+
+	for _, d := range registry.Docs {
+		if len(d.MissingFields) == 1 && d.MissingFields[0] == "File not found" {
+			add(d.FilePath, CheckMissingDoc, "listed in .context-docs.md but not found")
+			continue
+		}
+		if len(d.MissingFields) > 0 {
+			add(d.FilePath, CheckMissingFields, "missing "+strings.Join(d.MissingFields, ", "))
+		}
+		for _, kf := range d.BrokenKeyFiles {
+			add(d.FilePath, CheckBrokenKeyFile, "key file not found: "+kf)
+		}
+		if d.IsStale {
+			add(d.FilePath, CheckStale, "key files changed since the doc was last updated")
+		}
+	}
+	return report, nil
+}
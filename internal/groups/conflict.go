@@ -0,0 +1,125 @@
+package groups
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrRegistryChanged is returned when .context-docs.md changed on disk (e.g. with
+// a pull or merge) since the registry being saved was loaded
+var ErrRegistryChanged = errors.New(".context-docs.md changed on disk")
+
+// registryModTime returns when rootPath's .context-docs.md was last modified, zero if
+// it doesn't exist
+func registryModTime(rootPath string) time.Time {
+	info, err := os.Stat(filepath.Join(rootPath, ".context-docs.md"))
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// markSaved records the registry as matching what is on disk
+func (r *ContextDocRegistry) markSaved(rootPath string) {
+	r.ModTime = registryModTime(rootPath)
+	r.base = make(map[string]bool, len(r.Docs))
+	for _, d := range r.Docs {
+		r.base[d.FilePath] = true
+	}
+}
+
+// SavedState is what a registry matched on disk when it was loaded or last saved
+type SavedState struct {
+	ModTime time.Time
+	base    map[string]bool
+}
+
+// Copy returns a copy of the registry that can be saved in the background while the
+// original keeps changing; apply its Saved state to the original once done
+func (r *ContextDocRegistry) Copy() *ContextDocRegistry {
+	c := *r
+	c.Categories = append([]Category(nil), r.Categories...)
+	c.Docs = append([]ContextDoc(nil), r.Docs...)
+	c.Packages = append([]string(nil), r.Packages...)
+	c.ByCategory = make(map[string][]ContextDoc, len(r.ByCategory))
+	for id, docs := range r.ByCategory {
+		c.ByCategory[id] = append([]ContextDoc(nil), docs...)
+	}
+	c.base = make(map[string]bool, len(r.base))
+	for path := range r.base {
+		c.base[path] = true
+	}
+	return &c
+}
+
+// Saved returns what the registry matched on disk when loaded or last saved
+func (r *ContextDocRegistry) Saved() SavedState {
+	return SavedState{ModTime: r.ModTime, base: r.base}
+}
+
+// SetSaved records a save made from a copy of the registry
+func (r *ContextDocRegistry) SetSaved(s SavedState) {
+	r.ModTime, r.base = s.ModTime, s.base
+}
+
+// RenamedOnDisk follows a rename the app itself made to .context-docs.md (see
+// RenameKeyFiles), so unsaved changes keep the new paths and the next save isn't
+// taken for a conflict
+func (r *ContextDocRegistry) RenamedOnDisk(rootPath, oldRel, newRel string) {
+	oldRel, newRel = filepath.ToSlash(filepath.Clean(oldRel)), filepath.ToSlash(filepath.Clean(newRel))
+	for i, d := range r.Docs {
+		if moved, ok := renamedPath(d.FilePath, oldRel, newRel); ok {
+			r.Docs[i].FilePath = filepath.FromSlash(moved)
+			if r.base[d.FilePath] {
+				delete(r.base, d.FilePath)
+				r.base[r.Docs[i].FilePath] = true
+			}
+		}
+	}
+	r.regroup()
+	r.ModTime = registryModTime(rootPath)
+}
+
+// OverwriteContextDocRegistry saves the registry even if .context-docs.md changed on
+// disk, replacing those changes
+func OverwriteContextDocRegistry(rootPath string, registry *ContextDocRegistry, backup bool) error {
+	registry.ModTime = registryModTime(rootPath)
+	return SaveContextDocRegistry(rootPath, registry, backup)
+}
+
+// MergeContextDocRegistry combines the registry on disk with the changes made to
+// registry since it was loaded: docs added are appended, docs removed stay removed,
+// and otherwise the disk's docs and their order win. The result isn't saved yet.
+func MergeContextDocRegistry(rootPath string, registry *ContextDocRegistry) (*ContextDocRegistry, error) {
+	merged, err := LoadContextDocRegistry(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ours := make(map[string]bool, len(registry.Docs))
+	for _, d := range registry.Docs {
+		ours[d.FilePath] = true
+	}
+	var docs []ContextDoc
+	onDisk := make(map[string]bool, len(merged.Docs))
+	for _, d := range merged.Docs {
+		onDisk[d.FilePath] = true
+		if registry.base[d.FilePath] && !ours[d.FilePath] {
+			continue // Removed here
+		}
+		docs = append(docs, d)
+	}
+	for _, d := range registry.Docs {
+		if !registry.base[d.FilePath] && !onDisk[d.FilePath] {
+			docs = append(docs, d) // Added here
+		}
+	}
+	merged.Docs = docs
+	for _, c := range registry.Categories {
+		merged.AddCategory(c.Name) // Skips duplicates
+	}
+	merged.regroup()
+	return merged, nil
+}
@@ -0,0 +1,188 @@
+package groups
+
+import (
+	"regexp"
+	"strings"
+)
+
+// frontmatterKeyRe matches a top-level "key: value" line of YAML frontmatter
+var frontmatterKeyRe = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)\s*:\s*(.*)$`)
+
+// frontmatterLen returns how many lines the YAML frontmatter at the top of a doc spans,
+// including both --- fences, or 0 if the doc has none
+func frontmatterLen(lines []string) int {
+	if len(lines) == 0 || strings.TrimRight(lines[0], " \r") != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if fence := strings.TrimRight(lines[i], " \r"); fence == "---" || fence == "..." {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// frontmatterKey returns the normalized key of a top-level frontmatter line, or ""
+func frontmatterKey(line string) (key, value string) {
+	m := frontmatterKeyRe.FindStringSubmatch(strings.TrimRight(line, " \r"))
+	if m == nil {
+		return "", ""
+	}
+	return camelToSnake(strings.ReplaceAll(strings.ToLower(m[1]), "-", "_")), strings.TrimSpace(m[2])
+}
+
+// parseFrontmatter reads the fields of a doc's YAML frontmatter. Only the subset docs
+// use is understood: scalars, [inline, lists], "- item" lists and | or > block text.
+// Returns the fields by normalized key (key-files and keyFiles both become key_files)
+// and the number of lines the frontmatter spans.
+func parseFrontmatter(lines []string) (map[string][]string, int) {
+	n := frontmatterLen(lines)
+	if n == 0 {
+		return nil, 0
+	}
+	fields := make(map[string][]string)
+	key, block := "", false
+	for _, line := range lines[1 : n-1] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indented := line != strings.TrimLeft(line, " \t")
+		if k, value := frontmatterKey(line); k != "" && !indented {
+			key, block = k, false
+			switch {
+			case value == "|" || value == ">" || value == "|-" || value == ">-":
+				block = true
+			case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+				for _, item := range strings.Split(strings.Trim(value, "[]"), ",") {
+					if item = unquoteYAML(item); item != "" {
+						fields[key] = append(fields[key], item)
+					}
+				}
+			case value != "":
+				fields[key] = []string{unquoteYAML(value)}
+			}
+			continue
+		}
+		if key == "" {
+			continue
+		}
+		switch {
+		case block:
+			// Block text is folded into one line
+			if len(fields[key]) == 0 {
+				fields[key] = []string{trimmed}
+			} else {
+				fields[key][0] += " " + trimmed
+			}
+		case strings.HasPrefix(trimmed, "- "):
+			if item := unquoteYAML(strings.TrimPrefix(trimmed, "- ")); item != "" {
+				fields[key] = append(fields[key], item)
+			}
+		}
+	}
+	return fields, n
+}
+
+// camelToSnake turns a lowercased key like "keyfiles" from keyFiles into key_files
+func camelToSnake(key string) string {
+	switch key {
+	case "keyfiles":
+		return "key_files"
+	case "outofscope":
+		return "out_of_scope"
+	}
+	return key
+}
+
+// unquoteYAML trims a YAML scalar's whitespace and surrounding quotes
+func unquoteYAML(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		s = s[1 : len(s)-1]
+	}
+	return strings.TrimSpace(s)
+}
+
+// applyFrontmatter sets a doc's metadata from its frontmatter fields
+// Fields written in the markdown body are parsed afterwards and take precedence.
+func applyFrontmatter(doc *ContextDoc, fields map[string][]string) {
+	scalar := func(key string) string {
+		return strings.Join(fields[key], ", ")
+	}
+	list := func(key string) []string {
+		var items []string
+		for _, v := range fields[key] {
+			// A scalar list ("tags: auth, logging") is comma-separated
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimPrefix(strings.Trim(strings.TrimSpace(item), "`"), "#"); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+		return items
+	}
+	doc.Name = scalar("title")
+	doc.Category = scalar("category")
+	doc.Status = scalar("status")
+	doc.Description = scalar("description")
+	doc.OutOfScope = scalar("out_of_scope")
+	doc.Verify = scalar("verify")
+	doc.Test = scalar("test")
+	doc.Build = scalar("build")
+	doc.Tags = list("tags")
+	doc.Related = list("related")
+	for _, kf := range fields["key_files"] {
+		if kf = strings.Trim(kf, "`"); kf != "" {
+			doc.KeyFiles = append(doc.KeyFiles, kf)
+		}
+	}
+}
+
+// setFrontmatterField replaces the value of a scalar frontmatter field, adding it at the
+// end of the frontmatter if missing. Returns false if the doc has no frontmatter.
+func setFrontmatterField(lines []string, key, value string) ([]string, bool) {
+	n := frontmatterLen(lines)
+	if n == 0 {
+		return lines, false
+	}
+	for i := 1; i < n-1; i++ {
+		if k, _ := frontmatterKey(lines[i]); k == key && lines[i] == strings.TrimLeft(lines[i], " \t") {
+			name := lines[i][:strings.Index(lines[i], ":")]
+			lines[i] = name + ": " + value
+			return lines, true
+		}
+	}
+	return append(lines[:n-1], append([]string{key + ": " + value}, lines[n-1:]...)...), true
+}
+
+// renameFrontmatterKeyFiles rewrites renamed paths listed under key_files in a doc's
+// frontmatter, in both inline and "- item" lists
+func renameFrontmatterKeyFiles(lines []string, oldRel, newRel string) bool {
+	n := frontmatterLen(lines)
+	inKeyFiles, changed := false, false
+	for i := 1; i < n-1; i++ {
+		line := lines[i]
+		var items []string
+		if k, value := frontmatterKey(line); k != "" && line == strings.TrimLeft(line, " \t") {
+			inKeyFiles = k == "key_files"
+			if !inKeyFiles || !strings.HasPrefix(value, "[") {
+				continue
+			}
+			items = strings.Split(strings.Trim(value, "[]"), ",")
+		} else if trimmed := strings.TrimSpace(line); inKeyFiles && strings.HasPrefix(trimmed, "- ") {
+			items = []string{strings.TrimPrefix(trimmed, "- ")}
+		}
+		for _, item := range items {
+			path := strings.Trim(unquoteYAML(item), "`")
+			if path == "" {
+				continue
+			}
+			if moved, ok := renamedPath(path, oldRel, newRel); ok {
+				lines[i] = strings.Replace(lines[i], path, moved, 1)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
@@ -6,8 +6,15 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+	"github.com/connorleisz/contexTUI/internal/git"
+	"github.com/connorleisz/contexTUI/internal/tokens"
 )
 
 // ContextDoc represents a documentation-first context doc (v2)
@@ -18,10 +25,15 @@ type ContextDoc struct {
 	Category    string   // Category: Feature, Documentation, Data Layer, etc.
 	Status      string   // Active, Deprecated, Experimental, Planned
 	Related     []string // Paths to related documentation files
+	Tags        []string // Cross-cutting concerns (auth, logging) that span categories
 	Description string   // Content of the Description section
 	KeyFiles    []string // Code entry points (relative paths)
 	OutOfScope  string   // What this doesn't cover
+	Verify      string   // Shell command that checks this area (e.g. go build ./...)
+	Test        string   // Shell command that runs this area's tests
+	Build       string   // Shell command that builds this area
 	RawContent  string   // Full markdown content for copying
+	Package     string   // Directory of the nested registry listing the doc ("" for the root)
 
 	// Metrics
 	TokenEstimate int // Approximate token count (len/4)
@@ -53,6 +65,9 @@ type ContextDocRegistry struct {
 	Categories []Category              // Available categories (defaults + custom)
 	Docs       []ContextDoc            // All registered context docs
 	ByCategory map[string][]ContextDoc // Docs organized by category ID
+	Packages   []string                // Directories of nested registries (relative to root)
+	ModTime    time.Time               // Of .context-docs.md when loaded or last saved, zero if it didn't exist
+	base       map[string]bool         // Docs listed when loaded or last saved, to merge against
 }
 
 // ParseContextDoc parses a markdown file and extracts context doc metadata
@@ -66,7 +81,7 @@ func ParseContextDoc(rootPath, filePath string) (*ContextDoc, error) {
 	doc := &ContextDoc{
 		FilePath:      filePath,
 		RawContent:    string(content),
-		TokenEstimate: len(content) / 4, // Rough approximation for English text
+		TokenEstimate: tokens.Estimate(string(content)),
 	}
 
 	// Get file modification time
@@ -77,6 +92,10 @@ func ParseContextDoc(rootPath, filePath string) (*ContextDoc, error) {
 
 	lines := strings.Split(string(content), "\n")
 
+	// YAML frontmatter (category, status, tags, ...) is read first; bold fields override it
+	fields, bodyStart := parseFrontmatter(lines)
+	applyFrontmatter(doc, fields)
+
 	// State machine for parsing
 	var currentSection string
 	var descriptionLines []string
@@ -88,8 +107,12 @@ func ParseContextDoc(rootPath, filePath string) (*ContextDoc, error) {
 	categoryRe := regexp.MustCompile(`(?i)^\*\*Category:\*\*\s*(.+)$`)
 	statusRe := regexp.MustCompile(`(?i)^\*\*Status:\*\*\s*(.+)$`)
 	relatedRe := regexp.MustCompile(`(?i)^\*\*Related:\*\*\s*(.+)$`)
+	verifyRe := regexp.MustCompile(`(?i)^\*\*Verify:\*\*\s*(.+)$`)
+	testRe := regexp.MustCompile(`(?i)^\*\*Test:\*\*\s*(.+)$`)
+	buildRe := regexp.MustCompile(`(?i)^\*\*Build:\*\*\s*(.+)$`)
+	tagsRe := regexp.MustCompile(`(?i)^\*\*Tags:\*\*\s*(.+)$`)
 
-	for _, line := range lines {
+	for _, line := range lines[bodyStart:] {
 		trimmed := strings.TrimSpace(line)
 
 		// Track code blocks - skip content inside them
@@ -116,6 +139,28 @@ func ParseContextDoc(rootPath, filePath string) (*ContextDoc, error) {
 			doc.Status = strings.TrimSpace(match[1])
 			continue
 		}
+		if match := verifyRe.FindStringSubmatch(trimmed); match != nil {
+			doc.Verify = strings.Trim(strings.TrimSpace(match[1]), "`")
+			continue
+		}
+		if match := testRe.FindStringSubmatch(trimmed); match != nil {
+			doc.Test = strings.Trim(strings.TrimSpace(match[1]), "`")
+			continue
+		}
+		if match := buildRe.FindStringSubmatch(trimmed); match != nil {
+			doc.Build = strings.Trim(strings.TrimSpace(match[1]), "`")
+			continue
+		}
+		if match := tagsRe.FindStringSubmatch(trimmed); match != nil {
+			// Comma-separated, with optional backticks or leading #
+			for _, tag := range strings.Split(match[1], ",") {
+				tag = strings.TrimPrefix(strings.Trim(strings.TrimSpace(tag), "`"), "#")
+				if tag != "" {
+					doc.Tags = append(doc.Tags, tag)
+				}
+			}
+			continue
+		}
 		if match := relatedRe.FindStringSubmatch(trimmed); match != nil {
 			relatedStr := strings.TrimSpace(match[1])
 			// Parse comma-separated list
@@ -173,9 +218,17 @@ func ParseContextDoc(rootPath, filePath string) (*ContextDoc, error) {
 	}
 
 	// Set parsed values
-	doc.Description = strings.Join(descriptionLines, " ")
-	doc.KeyFiles = keyFileLines
-	doc.OutOfScope = strings.Join(outOfScopeLines, " ")
+	if len(descriptionLines) > 0 {
+		doc.Description = strings.Join(descriptionLines, " ")
+	}
+	for _, kf := range keyFileLines {
+		if !slices.Contains(doc.KeyFiles, kf) {
+			doc.KeyFiles = append(doc.KeyFiles, kf)
+		}
+	}
+	if len(outOfScopeLines) > 0 {
+		doc.OutOfScope = strings.Join(outOfScopeLines, " ")
+	}
 
 	// Fallback name to filename
 	if doc.Name == "" {
@@ -207,11 +260,72 @@ func validateContextDoc(doc *ContextDoc) []string {
 	return missing
 }
 
+// ResolveKeyFile returns the absolute path of a key file
+// Key files are relative to the root, but may point outside it
+// (e.g. ../other-repo/src/api.ts) or be absolute
+func ResolveKeyFile(rootPath, keyFile string) string {
+	if filepath.IsAbs(keyFile) {
+		return filepath.Clean(keyFile)
+	}
+	return filepath.Join(rootPath, keyFile)
+}
+
+// IsExternalKeyFile reports whether a key file lives outside the root
+func IsExternalKeyFile(rootPath, keyFile string) bool {
+	rel, err := filepath.Rel(rootPath, ResolveKeyFile(rootPath, keyFile))
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// KeyFileRefs returns key files as paths for @references
+// Files inside the root stay relative; files outside it become absolute
+func (d *ContextDoc) KeyFileRefs(rootPath string) []string {
+	refs := make([]string, 0, len(d.KeyFiles))
+	for _, kf := range d.KeyFiles {
+		if IsExternalKeyFile(rootPath, kf) {
+			refs = append(refs, ResolveKeyFile(rootPath, kf))
+		} else {
+			refs = append(refs, filepath.Clean(kf))
+		}
+	}
+	return refs
+}
+
+// GroupRefs returns docs followed by their key files as @ references, without duplicates
+func GroupRefs(rootPath string, docs []ContextDoc) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, d := range docs {
+		for _, ref := range append([]string{d.FilePath}, d.KeyFileRefs(rootPath)...) {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, "@"+ref)
+			}
+		}
+	}
+	return refs
+}
+
+// FindGroup returns the docs of the category called name, or else the doc with that
+// name or path. Names match case-insensitively.
+func (r *ContextDocRegistry) FindGroup(name string) []ContextDoc {
+	if id := CategoryID(name); id != "" {
+		if docs := r.ByCategory[id]; len(docs) > 0 {
+			return docs
+		}
+	}
+	for _, d := range r.Docs {
+		if strings.EqualFold(d.Name, name) || d.FilePath == filepath.Clean(name) {
+			return []ContextDoc{d}
+		}
+	}
+	return nil
+}
+
 // ValidateKeyFiles checks which key files exist and returns broken paths
 func (d *ContextDoc) ValidateKeyFiles(rootPath string) []string {
 	var broken []string
 	for _, kf := range d.KeyFiles {
-		fullPath := filepath.Join(rootPath, kf)
+		fullPath := ResolveKeyFile(rootPath, kf)
 		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 			broken = append(broken, kf)
 		}
@@ -220,8 +334,9 @@ func (d *ContextDoc) ValidateKeyFiles(rootPath string) []string {
 	return broken
 }
 
-// LoadContextDocRegistry loads the v2 context docs from .context-docs.md registry
-func LoadContextDocRegistry(rootPath string) (*ContextDocRegistry, error) {
+// loadRegistryFile loads the docs listed in rootPath's .context-docs.md, without
+// checking their staleness
+func loadRegistryFile(rootPath string) (*ContextDocRegistry, error) {
 	registry := &ContextDocRegistry{
 		Categories: DefaultCategories(),
 		Docs:       []ContextDoc{},
@@ -240,20 +355,30 @@ func LoadContextDocRegistry(rootPath string) (*ContextDocRegistry, error) {
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
-	inActiveDocs := false
-	// Track per-category doc order from file structure
-	categoryDocOrder := make(map[string][]ContextDoc)
+	inActiveDocs, inCategories := false, false
+	var listedCategories []string // Category names in registry order
+	var parsed []*ContextDoc      // Registry order
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
 		// Detect sections
 		if strings.HasPrefix(line, "## Active Docs") {
-			inActiveDocs = true
+			inActiveDocs, inCategories = true, false
+			continue
+		}
+		if strings.HasPrefix(line, "## Categories") {
+			inActiveDocs, inCategories = false, true
+			listedCategories = []string{}
 			continue
 		}
 		if strings.HasPrefix(line, "## ") && !strings.HasPrefix(line, "### ") {
-			inActiveDocs = false
+			inActiveDocs, inCategories = false, false
+			continue
+		}
+
+		if inCategories && strings.HasPrefix(line, "- ") {
+			listedCategories = append(listedCategories, strings.TrimSpace(strings.TrimPrefix(line, "- ")))
 			continue
 		}
 
@@ -273,6 +398,7 @@ func LoadContextDocRegistry(rootPath string) (*ContextDocRegistry, error) {
 			} else {
 				docPath = strings.TrimSpace(entry)
 			}
+			docPath = filepath.FromSlash(docPath) // Written with forward slashes
 
 			if docPath != "" {
 				// Parse the document
@@ -287,58 +413,43 @@ func LoadContextDocRegistry(rootPath string) (*ContextDocRegistry, error) {
 				} else {
 					// Validate key file paths exist
 					doc.ValidateKeyFiles(rootPath)
-					// Check staleness via git history
-					doc.CheckStaleness(rootPath)
-				}
-				registry.Docs = append(registry.Docs, *doc)
-
-				// Track order per category (from file order, preserves reordering)
-				catID := strings.ToLower(strings.ReplaceAll(doc.Category, " ", "-"))
-				if catID == "" {
-					catID = "uncategorized"
 				}
-				categoryDocOrder[catID] = append(categoryDocOrder[catID], *doc)
+				parsed = append(parsed, doc)
 			}
 		}
 	}
 
-	// Auto-discover categories from parsed docs
-	// Collect unique categories that are not defaults
-	usedCategories := make(map[string]string) // ID -> Name
+	for _, doc := range parsed {
+		registry.Docs = append(registry.Docs, *doc)
+	}
+
+	// Categories follow the registry's list, keeping its order and empty categories
+	// (a registry without the list starts from the defaults), then any others docs use
+	if listedCategories != nil {
+		registry.Categories = nil
+		for _, name := range listedCategories {
+			registry.AddCategory(name) // Skips duplicates
+		}
+	}
+	var discovered []Category
 	for _, d := range registry.Docs {
-		if d.Category == "" {
+		id := CategoryID(d.Category)
+		if id == "" || registry.CategoryIndex(id) >= 0 {
 			continue
 		}
-		catID := strings.ToLower(strings.ReplaceAll(d.Category, " ", "-"))
-		// Check if it's already a default
-		isDefault := false
-		for _, dc := range DefaultCategories() {
-			if strings.EqualFold(dc.ID, catID) || strings.EqualFold(dc.Name, d.Category) {
-				isDefault = true
-				break
-			}
+		seen := false
+		for _, c := range discovered {
+			seen = seen || c.ID == id
 		}
-		if !isDefault {
-			usedCategories[catID] = d.Category
+		if !seen {
+			discovered = append(discovered, Category{ID: id, Name: d.Category})
 		}
 	}
+	sort.Slice(discovered, func(i, j int) bool { return discovered[i].Name < discovered[j].Name })
+	registry.Categories = append(registry.Categories, discovered...)
 
-	// Add discovered custom categories
-	for id, name := range usedCategories {
-		registry.Categories = append(registry.Categories, Category{
-			ID:   id,
-			Name: name,
-		})
-	}
-
-	// Use the file order for ByCategory (preserves user's reordering)
-	registry.ByCategory = categoryDocOrder
-
-	// Add dynamic "Uncategorized" category if there are uncategorized docs
-	if len(categoryDocOrder["uncategorized"]) > 0 {
-		// Prepend to categories so it appears first
-		registry.Categories = append([]Category{{ID: "uncategorized", Name: "Uncategorized"}}, registry.Categories...)
-	}
+	// Group docs in registry order (preserves the user's reordering)
+	registry.regroup()
 
 	return registry, nil
 }
@@ -445,6 +556,8 @@ func GenerateClaudePrompt(doc *ContextDoc) string {
 
 	sb.WriteString("\nOptionally also add:\n")
 	sb.WriteString("- **Related:** comma-separated list of related doc files\n")
+	sb.WriteString("- **Verify:** a shell command that checks this area still works (e.g. `go test ./internal/api/...`)\n")
+	sb.WriteString("- **Test:** / **Build:** shell commands that test or build this area\n")
 	sb.WriteString("- ## Out of Scope section - What this doesn't cover (helps AI know boundaries)\n")
 
 	return sb.String()
@@ -453,35 +566,96 @@ func GenerateClaudePrompt(doc *ContextDoc) string {
 // CheckStaleness checks if a context doc is stale by comparing git history
 // A doc is stale if any of its key files have been modified more recently than the doc
 func (d *ContextDoc) CheckStaleness(rootPath string) {
-	// Get the git repo root
-	cmd := exec.Command("git", "-C", rootPath, "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
-	if err != nil {
+	CheckStalenessAll(rootPath, []*ContextDoc{d})
+}
+
+// CheckStalenessAll checks the staleness of several docs, looking up the last commit of
+// every doc and key file in one batched git query
+func CheckStalenessAll(rootPath string, docs []*ContextDoc) {
+	isRepo, gitRoot := git.IsRepo(rootPath)
+	if !isRepo {
 		return // Not a git repo or git not available
 	}
-	gitRoot := strings.TrimSpace(string(output))
 
-	// Get last commit time for the doc file
-	docLastCommit := getGitLastCommitTime(gitRoot, d.FilePath)
-	if docLastCommit == 0 {
-		return // File not tracked or no history
+	var paths []string
+	for _, d := range docs {
+		paths = append(paths, d.FilePath)
+		for _, kf := range d.KeyFiles {
+			if !IsExternalKeyFile(rootPath, kf) {
+				paths = append(paths, kf)
+			}
+		}
 	}
-	d.LastDocModified = docLastCommit
+	times := git.LastCommitTimes(gitRoot, paths)
 
-	// Check each key file's last commit time
-	var latestKeyFileTime int64
-	for _, kf := range d.KeyFiles {
-		kfTime := getGitLastCommitTime(gitRoot, kf)
-		if kfTime > latestKeyFileTime {
-			latestKeyFileTime = kfTime
+	for _, d := range docs {
+		// Get last commit time for the doc file
+		docLastCommit := times[d.FilePath]
+		if docLastCommit == 0 {
+			continue // File not tracked or no history
+		}
+		d.LastDocModified = docLastCommit
+
+		// Check each key file's last commit time
+		var latestKeyFileTime int64
+		for _, kf := range d.KeyFiles {
+			var kfTime int64
+			if IsExternalKeyFile(rootPath, kf) {
+				// Outside the root, possibly in a sibling repo - ask git from the file's own directory
+				fullPath := ResolveKeyFile(rootPath, kf)
+				kfTime = getGitLastCommitTime(filepath.Dir(fullPath), fullPath)
+			} else {
+				kfTime = times[kf]
+			}
+			if kfTime > latestKeyFileTime {
+				latestKeyFileTime = kfTime
+			}
 		}
+		d.LastCodeModified = latestKeyFileTime
+
+		// Mark as stale if key files changed after doc
+		d.IsStale = latestKeyFileTime > docLastCommit
 	}
-	d.LastCodeModified = latestKeyFileTime
+}
 
-	// Mark as stale if key files changed after doc
-	if latestKeyFileTime > docLastCommit {
-		d.IsStale = true
+// Tags returns the tags used by any doc, sorted, without case-insensitive duplicates
+func (r *ContextDocRegistry) Tags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, d := range r.Docs {
+		for _, tag := range d.Tags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
 	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i]) < strings.ToLower(tags[j]) })
+	return tags
+}
+
+// HasTag reports whether the doc is tagged with tag, ignoring case
+func (d *ContextDoc) HasTag(tag string) bool {
+	for _, t := range d.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkStale flags a registered doc as stale, returning false if it already was
+func (r *ContextDocRegistry) MarkStale(docPath string) bool {
+	changed := false
+	for i := range r.Docs {
+		if r.Docs[i].FilePath == docPath && !r.Docs[i].IsStale {
+			r.Docs[i].IsStale, changed = true, true
+		}
+	}
+	if changed {
+		r.regroup()
+	}
+	return changed
 }
 
 // getGitLastCommitTime returns the Unix timestamp of the last commit that modified the file
@@ -500,8 +674,28 @@ func getGitLastCommitTime(gitRoot, filePath string) int64 {
 	return timestamp
 }
 
-// SaveContextDocRegistry writes the registry back to .context-docs.md
-func SaveContextDocRegistry(rootPath string, registry *ContextDocRegistry) error {
+// AddDoc registers a parsed doc under its category, creating "Uncategorized" if needed
+func (r *ContextDocRegistry) AddDoc(doc ContextDoc) {
+	r.Docs = append(r.Docs, doc)
+
+	catID := strings.ToLower(strings.ReplaceAll(doc.Category, " ", "-"))
+	if catID == "" {
+		catID = "uncategorized"
+	}
+	r.ByCategory[catID] = append(r.ByCategory[catID], doc)
+
+	if catID == "uncategorized" {
+		for _, cat := range r.Categories {
+			if cat.ID == "uncategorized" {
+				return
+			}
+		}
+		r.Categories = append([]Category{{ID: "uncategorized", Name: "Uncategorized"}}, r.Categories...)
+	}
+}
+
+// saveRegistryFile writes a registry to rootPath's .context-docs.md
+func saveRegistryFile(rootPath string, registry *ContextDocRegistry, backup bool) error {
 	var sb strings.Builder
 
 	sb.WriteString("# Context Docs\n\n")
@@ -511,26 +705,10 @@ func SaveContextDocRegistry(rootPath string, registry *ContextDocRegistry) error
 	sb.WriteString("Categories are auto-discovered from markdown files. To create a custom\n")
 	sb.WriteString("category, just set `**Category:** YourCategory` in any markdown file.\n\n")
 
-	// Collect categories that are actually in use
-	usedCategories := make(map[string]bool)
-	for _, d := range registry.Docs {
-		if d.Category != "" {
-			catID := strings.ToLower(strings.ReplaceAll(d.Category, " ", "-"))
-			usedCategories[catID] = true
-		}
-	}
-
+	// Every category is listed, in order, so empty and reordered categories persist
 	sb.WriteString("## Categories (auto-discovered)\n\n")
 	for _, cat := range registry.Categories {
-		// Only list categories that have docs or are defaults
-		isDefault := false
-		for _, dc := range DefaultCategories() {
-			if dc.ID == cat.ID {
-				isDefault = true
-				break
-			}
-		}
-		if isDefault || usedCategories[cat.ID] {
+		if cat.ID != "uncategorized" {
 			sb.WriteString("- " + cat.Name + "\n")
 		}
 	}
@@ -549,11 +727,11 @@ func SaveContextDocRegistry(rootPath string, registry *ContextDocRegistry) error
 			if status == "" {
 				status = "?"
 			}
-			sb.WriteString("- " + d.FilePath + " (" + d.Category + ", " + status + ")\n")
+			sb.WriteString("- " + filepath.ToSlash(d.FilePath) + " (" + d.Category + ", " + status + ")\n")
 		}
 		sb.WriteString("\n")
 	}
 
 	registryPath := filepath.Join(rootPath, ".context-docs.md")
-	return os.WriteFile(registryPath, []byte(sb.String()), 0644)
+	return atomicfile.Write(registryPath, []byte(sb.String()), backup)
 }
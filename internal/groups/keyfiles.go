@@ -0,0 +1,80 @@
+package groups
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AddKeyFiles appends files to a doc's Key Files section, skipping ones already listed
+// The section is created at the end of the doc if it doesn't exist. Returns how many were added.
+func AddKeyFiles(rootPath, docPath string, files []string, backup bool) (int, error) {
+	fullPath := filepath.Join(rootPath, docPath)
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := ParseContextDoc(rootPath, docPath)
+	if err != nil {
+		return 0, err
+	}
+	// Docs list paths with forward slashes, so they read the same on every platform
+	listed := make(map[string]bool)
+	for _, kf := range doc.KeyFiles {
+		listed[filepath.ToSlash(filepath.Clean(kf))] = true
+	}
+	var entries []string
+	for _, f := range files {
+		f = filepath.ToSlash(filepath.Clean(f))
+		if !listed[f] {
+			listed[f] = true
+			entries = append(entries, "- "+f)
+		}
+	}
+	added := len(entries)
+	if added == 0 {
+		return 0, nil
+	}
+
+	lines := strings.Split(strings.TrimRight(string(original), "\n"), "\n")
+	insertAt := -1
+	inSection, inCodeBlock := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") || strings.HasPrefix(trimmed, "# ") {
+			name := strings.ToLower(strings.TrimLeft(trimmed, "# "))
+			if inSection {
+				break
+			}
+			inSection = strings.HasPrefix(trimmed, "## ") && (strings.Contains(name, "key files") || strings.Contains(name, "key-files"))
+			if inSection {
+				insertAt = i + 1
+			}
+			continue
+		}
+		if inSection && strings.HasPrefix(trimmed, "- ") {
+			insertAt = i + 1 // After the last entry
+		}
+	}
+
+	var result []string
+	if insertAt < 0 {
+		result = append(lines, "", "## Key Files", "")
+		result = append(result, entries...)
+	} else {
+		if !strings.HasPrefix(strings.TrimSpace(lines[insertAt-1]), "- ") {
+			entries = append([]string{""}, entries...) // Blank line after the heading
+		}
+		result = append(result, lines[:insertAt]...)
+		result = append(result, entries...)
+		result = append(result, lines[insertAt:]...)
+	}
+	return added, writeIfUnchanged(fullPath, original, []byte(strings.Join(result, "\n")+"\n"), backup)
+}
@@ -0,0 +1,235 @@
+package groups
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadContextDocRegistry loads the v2 context docs from .context-docs.md registry,
+// merging in the registries of nested packages (e.g. packages/api/.context-docs.md)
+// Docs from a nested registry keep their package and have their paths rebased onto the root.
+func LoadContextDocRegistry(rootPath string) (*ContextDocRegistry, error) {
+	registry, err := loadRegistryFile(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	packages, _ := FindNestedRegistries(rootPath)
+	for _, pkg := range packages {
+		sub, err := loadRegistryFile(filepath.Join(rootPath, pkg))
+		if err != nil {
+			continue // Unreadable nested registry - skip it
+		}
+		registry.Packages = append(registry.Packages, pkg)
+		for _, d := range sub.Docs {
+			registry.Docs = append(registry.Docs, rebaseDoc(d, pkg))
+		}
+		for _, c := range sub.Categories {
+			registry.AddCategory(c.Name) // Skips duplicates
+		}
+	}
+
+	// Check staleness via git history, in one pass for all docs that exist
+	var tracked []*ContextDoc
+	for i := range registry.Docs {
+		d := &registry.Docs[i]
+		if len(d.MissingFields) != 1 || d.MissingFields[0] != "File not found" {
+			tracked = append(tracked, d)
+		}
+	}
+	CheckStalenessAll(rootPath, tracked)
+
+	registry.regroup()
+	registry.markSaved(rootPath)
+	return registry, nil
+}
+
+// SaveContextDocRegistry writes the registry back to .context-docs.md, writing docs
+// from nested packages back to their own registry with package-relative paths
+// The save is refused with ErrRegistryChanged if .context-docs.md changed on disk
+// since the registry was loaded or last saved. With backup, the replaced registries
+// are kept as .bak files.
+func SaveContextDocRegistry(rootPath string, registry *ContextDocRegistry, backup bool) error {
+	if !registryModTime(rootPath).Equal(registry.ModTime) {
+		return ErrRegistryChanged
+	}
+
+	// Categories only nested docs use are listed in their packages' registries
+	nestedOnly := make(map[string]bool)
+	for _, d := range registry.Docs {
+		id := docCategoryID(d)
+		if d.Package == "" {
+			nestedOnly[id] = false
+		} else if _, seen := nestedOnly[id]; !seen {
+			nestedOnly[id] = true
+		}
+	}
+
+	root := &ContextDocRegistry{ByCategory: make(map[string][]ContextDoc)}
+	for _, c := range registry.Categories {
+		if !nestedOnly[c.ID] {
+			root.Categories = append(root.Categories, c)
+		}
+	}
+	for _, d := range registry.Docs {
+		if d.Package == "" {
+			root.Docs = append(root.Docs, d)
+		}
+	}
+	root.regroup()
+	err := saveRegistryFile(rootPath, root, backup)
+
+	for _, pkg := range registry.Packages {
+		sub := &ContextDocRegistry{ByCategory: make(map[string][]ContextDoc)}
+		used := make(map[string]bool)
+		for _, d := range registry.Docs {
+			if d.Package == pkg {
+				used[docCategoryID(d)] = true
+				d.FilePath = d.LocalPath(d.FilePath)
+				sub.Docs = append(sub.Docs, d)
+			}
+		}
+		for _, c := range registry.Categories {
+			if used[c.ID] {
+				sub.Categories = append(sub.Categories, c)
+			}
+		}
+		sub.regroup()
+		if subErr := saveRegistryFile(filepath.Join(rootPath, pkg), sub, backup); subErr != nil && err == nil {
+			err = subErr
+		}
+	}
+	registry.markSaved(rootPath)
+	return err
+}
+
+// FindNestedRegistries returns the directories below the root, relative to it, that
+// have their own .context-docs.md
+func FindNestedRegistries(rootPath string) ([]string, error) {
+	var packages []string
+	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip errors
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		if path != rootPath && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "dist" || name == "build") {
+			return filepath.SkipDir
+		}
+		if path == rootPath {
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(path, ".context-docs.md")); err == nil {
+			if rel, err := filepath.Rel(rootPath, path); err == nil {
+				packages = append(packages, rel)
+			}
+		}
+		return nil
+	})
+	return packages, err
+}
+
+// PackageFor returns the deepest nested package containing path, or "" for the root
+func (r *ContextDocRegistry) PackageFor(path string) string {
+	best := ""
+	for _, pkg := range r.Packages {
+		if strings.HasPrefix(path, pkg+string(filepath.Separator)) && len(pkg) > len(best) {
+			best = pkg
+		}
+	}
+	return best
+}
+
+// ParseDoc parses a doc relative to the registry that owns it, so the key files of a
+// doc inside a nested package resolve from that package
+func (r *ContextDocRegistry) ParseDoc(rootPath, docPath string) (*ContextDoc, error) {
+	pkg := r.PackageFor(docPath)
+	if pkg == "" {
+		return ParseContextDoc(rootPath, docPath)
+	}
+	local, err := filepath.Rel(pkg, docPath)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ParseContextDoc(filepath.Join(rootPath, pkg), local)
+	if err != nil {
+		return nil, err
+	}
+	rebased := rebaseDoc(*doc, pkg)
+	return &rebased, nil
+}
+
+// LocalPath returns a root-relative path as written in the doc's own package
+// Paths outside the package climb out of it with "..".
+func (d *ContextDoc) LocalPath(path string) string {
+	if d.Package == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if rel, err := filepath.Rel(d.Package, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// rebaseDoc moves the paths of a doc parsed inside a nested package onto the root
+func rebaseDoc(d ContextDoc, pkg string) ContextDoc {
+	rebase := func(paths []string) []string {
+		if paths == nil {
+			return nil
+		}
+		out := make([]string, len(paths))
+		for i, p := range paths {
+			if filepath.IsAbs(p) {
+				out[i] = p
+			} else {
+				out[i] = filepath.Join(pkg, p)
+			}
+		}
+		return out
+	}
+	d.Package = pkg
+	d.FilePath = filepath.Join(pkg, d.FilePath)
+	d.KeyFiles = rebase(d.KeyFiles)
+	d.BrokenKeyFiles = rebase(d.BrokenKeyFiles)
+	d.Related = rebase(d.Related)
+	return d
+}
+
+// RenamePackageKeyFiles runs RenameKeyFiles for the root registry and every nested one,
+// each with paths relative to its own package. Returned paths are relative to the root.
+func RenamePackageKeyFiles(rootPath, oldRel, newRel string, docs []ContextDoc, backup bool) ([]string, error) {
+	byPackage := map[string][]string{"": nil}
+	packages := []string{""}
+	for _, d := range docs {
+		if _, ok := byPackage[d.Package]; !ok {
+			packages = append(packages, d.Package)
+		}
+		byPackage[d.Package] = append(byPackage[d.Package], d.LocalPath(d.FilePath))
+	}
+
+	var updated []string
+	var firstErr error
+	for _, pkg := range packages {
+		pkgOld, pkgNew := oldRel, newRel
+		if pkg != "" {
+			// Only renames inside the package change its paths
+			var err1, err2 error
+			pkgOld, err1 = filepath.Rel(pkg, oldRel)
+			pkgNew, err2 = filepath.Rel(pkg, newRel)
+			if err1 != nil || err2 != nil || pkgOld == "." || strings.HasPrefix(pkgOld, "..") || strings.HasPrefix(pkgNew, "..") {
+				continue
+			}
+		}
+		paths, err := RenameKeyFiles(filepath.Join(rootPath, pkg), pkgOld, pkgNew, byPackage[pkg], backup)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for _, p := range paths {
+			updated = append(updated, filepath.Join(pkg, p))
+		}
+	}
+	return updated, firstErr
+}
@@ -0,0 +1,133 @@
+package groups
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenameKeyFiles rewrites references to a renamed file or directory in the Key Files
+// sections of docs and in the .context-docs.md registry. oldRel and newRel are relative
+// to rootPath, and docPaths are the registered docs as they were before the rename.
+// Returns the docs that were updated, at their current paths, followed by .context-docs.md
+// when a registered doc was moved.
+func RenameKeyFiles(rootPath, oldRel, newRel string, docPaths []string, backup bool) ([]string, error) {
+	oldRel, newRel = filepath.ToSlash(filepath.Clean(oldRel)), filepath.ToSlash(filepath.Clean(newRel))
+	if oldRel == newRel {
+		return nil, nil
+	}
+
+	var updated []string
+	var firstErr error
+	for _, docPath := range docPaths {
+		if moved, ok := renamedPath(docPath, oldRel, newRel); ok {
+			docPath = moved // The doc itself was moved
+		}
+		fullPath := filepath.Join(rootPath, docPath)
+		original, err := os.ReadFile(fullPath)
+		if err != nil {
+			continue
+		}
+		content, changed := renameKeyFileEntries(string(original), oldRel, newRel)
+		if !changed {
+			continue
+		}
+		if err := writeIfUnchanged(fullPath, original, []byte(content), backup); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		updated = append(updated, docPath)
+	}
+
+	changed, err := renameRegistryEntries(rootPath, oldRel, newRel, backup)
+	if err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if changed && err == nil {
+		updated = append(updated, ".context-docs.md")
+	}
+	return updated, firstErr
+}
+
+// renamedPath returns where path is after oldRel was renamed to newRel
+func renamedPath(path, oldRel, newRel string) (string, bool) {
+	clean := filepath.ToSlash(filepath.Clean(path))
+	if clean == oldRel {
+		return newRel, true
+	}
+	if strings.HasPrefix(clean, oldRel+"/") {
+		return newRel + strings.TrimPrefix(clean, oldRel), true
+	}
+	return path, false
+}
+
+// renameKeyFileEntries rewrites renamed paths in a doc's Key Files section and
+// frontmatter, keeping backticks and descriptions
+func renameKeyFileEntries(content, oldRel, newRel string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	changed := renameFrontmatterKeyFiles(lines, oldRel, newRel)
+	inSection, inCodeBlock := false, false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") {
+			name := strings.ToLower(strings.TrimPrefix(trimmed, "## "))
+			inSection = strings.Contains(name, "key files") || strings.Contains(name, "key-files")
+			continue
+		}
+		if !inSection || !strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		entry := strings.TrimPrefix(trimmed, "- ")
+		path := strings.Trim(strings.TrimSpace(strings.SplitN(entry, " - ", 2)[0]), "`")
+		if path == "" {
+			continue
+		}
+		if moved, ok := renamedPath(path, oldRel, newRel); ok {
+			lines[i] = strings.Replace(line, path, moved, 1)
+			changed = true
+		}
+	}
+	return strings.Join(lines, "\n"), changed
+}
+
+// renameRegistryEntries rewrites the paths of moved docs listed in .context-docs.md
+func renameRegistryEntries(rootPath, oldRel, newRel string, backup bool) (bool, error) {
+	registryPath := filepath.Join(rootPath, ".context-docs.md")
+	original, err := os.ReadFile(registryPath)
+	if err != nil {
+		return false, nil // No registry yet
+	}
+	lines := strings.Split(string(original), "\n")
+	inDocs, changed := false, false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			inDocs = strings.TrimPrefix(line, "## ") == "Active Docs"
+			continue
+		}
+		if !inDocs || !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		// Entries look like: - path/to/doc.md (Category, Status)
+		path := strings.TrimPrefix(line, "- ")
+		if idx := strings.Index(path, " ("); idx >= 0 {
+			path = path[:idx]
+		}
+		if moved, ok := renamedPath(path, oldRel, newRel); ok {
+			lines[i] = "- " + moved + strings.TrimPrefix(line, "- "+path)
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, writeIfUnchanged(registryPath, original, []byte(strings.Join(lines, "\n")), backup)
+}
@@ -0,0 +1,207 @@
+package groups
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ScaffoldDir is where drafted docs are written (relative to the project root)
+const ScaffoldDir = "docs"
+
+const (
+	maxScaffoldKeyFiles = 8    // Key files listed in a draft
+	maxScaffoldDepth    = 3    // Directory levels searched below the scaffolded directory
+	maxScaffoldWalk     = 5000 // Files looked at before picking key files
+)
+
+// scaffoldSourceExts are the file types considered for a draft's Key Files
+var scaffoldSourceExts = map[string]bool{
+	".go": true, ".ts": true, ".tsx": true, ".js": true, ".jsx": true, ".mjs": true,
+	".py": true, ".rs": true, ".java": true, ".kt": true, ".rb": true, ".php": true,
+	".c": true, ".h": true, ".cpp": true, ".cc": true, ".hpp": true, ".cs": true,
+	".swift": true, ".scala": true, ".lua": true, ".sh": true, ".vue": true, ".svelte": true,
+	".sql": true, ".proto": true,
+}
+
+// scaffoldEntryNames are file names that usually mark a package's entry point
+var scaffoldEntryNames = map[string]bool{
+	"main": true, "index": true, "__init__": true, "mod": true, "lib": true,
+	"app": true, "server": true, "cli": true, "api": true, "routes": true,
+}
+
+// scaffoldCandidate is a file considered for a draft's Key Files
+type scaffoldCandidate struct {
+	rel   string
+	score int
+	size  int64
+}
+
+// ScaffoldDoc drafts a context doc for dirPath (relative to the root): its main files as
+// Key Files, an inferred category, the registered docs that already cover it as Related,
+// and a TODO description. Returns the suggested doc path and its content.
+func ScaffoldDoc(rootPath, dirPath string, registry *ContextDocRegistry) (string, string, error) {
+	dirPath = filepath.ToSlash(filepath.Clean(dirPath))
+	keyFiles, err := scaffoldKeyFiles(rootPath, dirPath)
+	if err != nil {
+		return "", "", err
+	}
+	if len(keyFiles) == 0 {
+		return "", "", errors.New("no source files found in " + dirPath)
+	}
+
+	name := filepath.Base(dirPath)
+	if dirPath == "." {
+		name = filepath.Base(rootPath)
+		if abs, err := filepath.Abs(rootPath); err == nil {
+			name = filepath.Base(abs)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# " + scaffoldTitle(name) + "\n\n")
+	sb.WriteString("**Category:** " + scaffoldCategory(dirPath) + "\n")
+	sb.WriteString("**Status:** Active\n")
+	if related := scaffoldRelated(rootPath, dirPath, registry); len(related) > 0 {
+		sb.WriteString("**Related:** " + strings.Join(related, ", ") + "\n")
+	}
+	sb.WriteString("\n## Description\n\n")
+	sb.WriteString(fmt.Sprintf("TODO: Describe what `%s` is responsible for, how it fits into the rest of the system, and the decisions behind it.\n\n", dirPath))
+	sb.WriteString("## Key Files\n\n")
+	for _, f := range keyFiles {
+		sb.WriteString("- " + f + "\n")
+	}
+	sb.WriteString("\n## Out of Scope\n\n")
+	sb.WriteString("TODO: What this doc doesn't cover.\n")
+
+	return scaffoldPath(rootPath, name), sb.String(), nil
+}
+
+// scaffoldKeyFiles picks the directory's main source files: entry points first, then the largest
+func scaffoldKeyFiles(rootPath, dirPath string) ([]string, error) {
+	base := filepath.Join(rootPath, dirPath)
+	var candidates []scaffoldCandidate
+	walked := 0
+
+	err := filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(base, path)
+		if info.IsDir() {
+			if path != base && (skipScaffoldDir(info.Name()) || strings.Count(rel, string(filepath.Separator)) >= maxScaffoldDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if walked++; walked > maxScaffoldWalk {
+			return filepath.SkipAll
+		}
+
+		ext := strings.ToLower(filepath.Ext(info.Name()))
+		if !scaffoldSourceExts[ext] || isTestFile(info.Name()) {
+			return nil
+		}
+		stem := strings.ToLower(strings.TrimSuffix(info.Name(), filepath.Ext(info.Name())))
+		depth := strings.Count(rel, string(filepath.Separator))
+		score := -depth
+		if scaffoldEntryNames[stem] || stem == strings.ToLower(filepath.Base(base)) {
+			score += 10
+		}
+		relRoot, _ := filepath.Rel(rootPath, path)
+		candidates = append(candidates, scaffoldCandidate{rel: filepath.ToSlash(relRoot), score: score, size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		if candidates[i].size != candidates[j].size {
+			return candidates[i].size > candidates[j].size
+		}
+		return candidates[i].rel < candidates[j].rel
+	})
+
+	var keyFiles []string
+	for i := 0; i < len(candidates) && i < maxScaffoldKeyFiles; i++ {
+		keyFiles = append(keyFiles, candidates[i].rel)
+	}
+	return keyFiles, nil
+}
+
+// skipScaffoldDir reports whether a directory is never part of a draft
+func skipScaffoldDir(name string) bool {
+	switch name {
+	case "node_modules", "vendor", "__pycache__", "testdata", "dist", "build":
+		return true
+	}
+	return strings.HasPrefix(name, ".")
+}
+
+// isTestFile reports whether a file name looks like a test
+func isTestFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, "_test.go") ||
+		strings.Contains(lower, ".test.") ||
+		strings.Contains(lower, ".spec.") ||
+		strings.HasPrefix(lower, "test_")
+}
+
+// scaffoldCategory infers a category from the directory's location
+func scaffoldCategory(dirPath string) string {
+	switch strings.ToLower(strings.Split(dirPath, "/")[0]) {
+	case ".", "docs", "doc", "scripts", "tools", "config", "build":
+		return "Meta"
+	}
+	return "Feature"
+}
+
+// scaffoldRelated returns registered docs that have key files in the directory
+func scaffoldRelated(rootPath, dirPath string, registry *ContextDocRegistry) []string {
+	if registry == nil {
+		return nil
+	}
+	prefix := dirPath + "/"
+	var related []string
+	for _, d := range registry.Docs {
+		for _, ref := range d.KeyFileRefs(rootPath) {
+			if dirPath == "." || strings.HasPrefix(filepath.ToSlash(ref), prefix) {
+				related = append(related, d.FilePath)
+				break
+			}
+		}
+	}
+	return related
+}
+
+// scaffoldTitle turns a directory name into a heading, e.g. "file_ops" -> "File Ops"
+func scaffoldTitle(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' || r == ' ' })
+	for i, w := range words {
+		runes := []rune(w)
+		words[i] = strings.ToUpper(string(runes[0])) + string(runes[1:])
+	}
+	if len(words) == 0 {
+		return name
+	}
+	return strings.Join(words, " ")
+}
+
+// scaffoldPath returns a doc path under ScaffoldDir that doesn't exist yet
+func scaffoldPath(rootPath, name string) string {
+	slug := strings.ToLower(strings.Join(strings.Fields(scaffoldTitle(name)), "-"))
+	path := ScaffoldDir + "/" + slug + ".md"
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(rootPath, path)); os.IsNotExist(err) {
+			return path
+		}
+		path = fmt.Sprintf("%s/%s-%d.md", ScaffoldDir, slug, n)
+	}
+}
@@ -0,0 +1,76 @@
+package groups
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AppendSnippet adds lines start-end of a file, as a fenced block under their
+// reference (e.g. internal/app/tree.go#L10-L20), to the end of a doc's Examples or
+// Snippets section. The section is created at the end of the doc if it doesn't exist.
+func AppendSnippet(rootPath, docPath, path string, start, end int, text string, backup bool) error {
+	fullPath := filepath.Join(rootPath, docPath)
+	original, err := os.ReadFile(fullPath)
+	if err != nil {
+		return err
+	}
+
+	// A fence longer than any run of backticks in the snippet keeps it intact
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	path = filepath.ToSlash(path)
+	ref := fmt.Sprintf("%s#L%d-L%d", path, start, end)
+	if start == end {
+		ref = fmt.Sprintf("%s#L%d", path, start)
+	}
+	lang := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	block := []string{"`" + ref + "`", "", fence + lang}
+	block = append(block, strings.Split(strings.TrimRight(text, "\n"), "\n")...)
+	block = append(block, fence)
+
+	lines := strings.Split(strings.TrimRight(string(original), "\n"), "\n")
+	sectionStart, sectionEnd := -1, len(lines)
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock || !(strings.HasPrefix(trimmed, "## ") || strings.HasPrefix(trimmed, "# ")) {
+			continue
+		}
+		if sectionStart >= 0 {
+			sectionEnd = i
+			break
+		}
+		name := strings.ToLower(strings.TrimLeft(trimmed, "# "))
+		if strings.HasPrefix(trimmed, "## ") && (strings.HasPrefix(name, "examples") || strings.HasPrefix(name, "snippets")) {
+			sectionStart = i
+		}
+	}
+
+	var result []string
+	if sectionStart < 0 {
+		result = append(lines, "", "## Examples", "")
+		result = append(result, block...)
+	} else {
+		// After the section's last non-blank line
+		insertAt := sectionEnd
+		for insertAt > sectionStart+1 && strings.TrimSpace(lines[insertAt-1]) == "" {
+			insertAt--
+		}
+		result = append(result, lines[:insertAt]...)
+		result = append(result, "")
+		result = append(result, block...)
+		if sectionEnd < len(lines) {
+			result = append(result, "")
+			result = append(result, lines[sectionEnd:]...)
+		}
+	}
+	return writeIfUnchanged(fullPath, original, []byte(strings.Join(result, "\n")+"\n"), backup)
+}
@@ -0,0 +1,39 @@
+package groups
+
+import (
+	"context"
+	"time"
+
+	"github.com/connorleisz/contexTUI/internal/shell"
+)
+
+// VerifyTimeout bounds how long a doc's command may run
+const VerifyTimeout = 5 * time.Minute
+
+// Commands a doc can declare as **Verify:**, **Test:** and **Build:** metadata
+const (
+	HookVerify = "Verify"
+	HookTest   = "Test"
+	HookBuild  = "Build"
+)
+
+// Hook returns the doc's command of the given kind, or "" if it declares none
+func (d *ContextDoc) Hook(kind string) string {
+	switch kind {
+	case HookVerify:
+		return d.Verify
+	case HookTest:
+		return d.Test
+	case HookBuild:
+		return d.Build
+	}
+	return ""
+}
+
+// RunHook runs a doc command from the project root, passing each line of its combined
+// stdout/stderr to output as it is printed. A non-nil error means the command failed.
+func RunHook(ctx context.Context, rootPath, command string, output func(line string)) error {
+	ctx, cancel := context.WithTimeout(ctx, VerifyTimeout)
+	defer cancel()
+	return shell.Run(ctx, rootPath, command, output)
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/git"
+)
+
+// HEAD is the ref used for unreleased changes
+const HEAD = "HEAD"
+
+// changelogNames are the files searched for release notes, in order
+var changelogNames = []string{"CHANGELOG.md", "CHANGES.md", "HISTORY.md", "RELEASES.md"}
+
+// versionRe matches a version in a changelog heading, e.g. "## [v1.2.0] - 2024-01-01"
+var versionRe = regexp.MustCompile(`v?(\d+\.\d+(?:\.\d+)?(?:[-+][0-9A-Za-z.-]+)?)`)
+
+// Section is one release in a changelog
+type Section struct {
+	Version string // Normalized version ("1.2.0"), or "unreleased"
+	Heading string // Original heading line
+	Body    string // Content up to the next release heading
+}
+
+// normalizeVersion strips a leading "v" so tags and headings compare equal
+func normalizeVersion(v string) string {
+	if m := versionRe.FindStringSubmatch(v); m != nil {
+		return m[1]
+	}
+	return strings.ToLower(strings.TrimSpace(v))
+}
+
+// ParseChangelog splits a changelog into release sections
+// Releases are "## " headings (or "# " when the file has no "## " headings)
+func ParseChangelog(content string) []Section {
+	lines := strings.Split(content, "\n")
+
+	prefix := "## "
+	hasH2 := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			hasH2 = true
+			break
+		}
+	}
+	if !hasH2 {
+		prefix = "# "
+	}
+
+	var sections []Section
+	var current *Section
+	var body []string
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			sections = append(sections, *current)
+		}
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			heading := strings.TrimSpace(strings.TrimPrefix(line, prefix))
+			version := ""
+			if strings.Contains(strings.ToLower(heading), "unreleased") {
+				version = "unreleased"
+			} else if m := versionRe.FindStringSubmatch(heading); m != nil {
+				version = m[1]
+			}
+			if version != "" {
+				flush()
+				current = &Section{Version: version, Heading: line}
+				body = nil
+				continue
+			}
+		}
+		if current != nil {
+			body = append(body, line)
+		}
+	}
+	flush()
+	return sections
+}
+
+// LoadChangelog finds and parses the project's changelog
+// Returns the changelog's file name, or "" when there is none
+func LoadChangelog(rootPath string) (string, []Section) {
+	for _, name := range changelogNames {
+		data, err := os.ReadFile(filepath.Join(rootPath, name))
+		if err == nil {
+			return name, ParseChangelog(string(data))
+		}
+	}
+	return "", nil
+}
+
+// SectionsBetween returns the sections after from, up to and including to
+// Changelogs list newest first, so this is a contiguous run from the top down.
+// to == HEAD starts at the top (including Unreleased); from == "" runs to the end.
+func SectionsBetween(sections []Section, from, to string) []Section {
+	start := -1
+	if to == HEAD {
+		start = 0
+	} else {
+		for i, s := range sections {
+			if s.Version == normalizeVersion(to) {
+				start = i
+				break
+			}
+		}
+	}
+	if start < 0 || start >= len(sections) {
+		return nil
+	}
+
+	end := len(sections)
+	if from != "" {
+		end = start + 1 // Unknown base: just the target release
+		for i := start; i < len(sections); i++ {
+			if sections[i].Version == normalizeVersion(from) {
+				end = i
+				break
+			}
+		}
+	}
+	return sections[start:end]
+}
+
+// ReleaseNotes builds a context block describing changes in from..to
+// Combines the matching changelog sections with the commit log
+func ReleaseNotes(rootPath, repoRoot, from, to string) (string, error) {
+	commits, err := git.LogRange(repoRoot, from, to)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	rangeLabel := to
+	if from != "" {
+		rangeLabel = from + ".." + to
+	}
+	fmt.Fprintf(&b, "# Release notes: %s\n", rangeLabel)
+
+	if name, sections := LoadChangelog(rootPath); name != "" {
+		if matched := SectionsBetween(sections, from, to); len(matched) > 0 {
+			fmt.Fprintf(&b, "\n## From %s\n", name)
+			for _, s := range matched {
+				b.WriteString("\n" + s.Heading + "\n")
+				if s.Body != "" {
+					b.WriteString("\n" + s.Body + "\n")
+				}
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Commits (%d)\n\n", len(commits))
+	for _, c := range commits {
+		fmt.Fprintf(&b, "- %s %s (%s, %s)\n", c.Hash, c.Subject, c.Author, c.Date)
+	}
+	return b.String(), nil
+}
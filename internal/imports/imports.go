@@ -0,0 +1,325 @@
+package imports
+
+import (
+	"bufio"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxFiles caps how many source files are parsed when building a graph
+const MaxFiles = 20000
+
+// Graph maps project files to the project files they import, in both directions
+// Paths are relative to the root with forward slashes. Imports that resolve outside
+// the project (standard library, third-party packages) are dropped.
+type Graph struct {
+	imports    map[string][]string
+	importedBy map[string][]string
+	Partial    bool // True when MaxFiles was reached
+}
+
+// Imports returns the project files that file imports
+func (g *Graph) Imports(file string) []string {
+	return g.imports[filepath.ToSlash(file)]
+}
+
+// ImportedBy returns the project files that import file
+func (g *Graph) ImportedBy(file string) []string {
+	return g.importedBy[filepath.ToSlash(file)]
+}
+
+// IsSource reports whether imports of a file can be analyzed
+func IsSource(file string) bool {
+	switch languageOf(file) {
+	case langGo, langJS, langPython:
+		return true
+	}
+	return false
+}
+
+type language int
+
+const (
+	langNone language = iota
+	langGo
+	langJS
+	langPython
+)
+
+// jsExts are tried in order when resolving an extensionless JS/TS import
+var jsExts = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"}
+
+// languageOf returns the language whose imports are parsed for file
+func languageOf(file string) language {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".go":
+		return langGo
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+		return langJS
+	case ".py":
+		return langPython
+	}
+	return langNone
+}
+
+// resolver holds the project file index used to resolve import specs
+type resolver struct {
+	rootPath   string
+	files      map[string]bool     // All project files
+	dirFiles   map[string][]string // Directory -> non-test Go files in it
+	goModules  map[string]string   // Go module path -> directory holding its go.mod
+	goModPaths []string            // Module paths, longest first
+}
+
+// Build parses the imports of every source file in files (relative to rootPath)
+func Build(rootPath string, files []string) *Graph {
+	r := &resolver{
+		rootPath:  rootPath,
+		files:     make(map[string]bool, len(files)),
+		dirFiles:  make(map[string][]string),
+		goModules: make(map[string]string),
+	}
+	for _, f := range files {
+		f = filepath.ToSlash(f)
+		r.files[f] = true
+		if strings.HasSuffix(f, ".go") && !strings.HasSuffix(f, "_test.go") {
+			r.dirFiles[path.Dir(f)] = append(r.dirFiles[path.Dir(f)], f)
+		}
+		if path.Base(f) == "go.mod" {
+			if mod := readModulePath(filepath.Join(rootPath, f)); mod != "" {
+				r.goModules[mod] = path.Dir(f)
+				r.goModPaths = append(r.goModPaths, mod)
+			}
+		}
+	}
+	sort.Slice(r.goModPaths, func(i, j int) bool { return len(r.goModPaths[i]) > len(r.goModPaths[j]) })
+
+	g := &Graph{
+		imports:    make(map[string][]string),
+		importedBy: make(map[string][]string),
+	}
+	parsed := 0
+	for _, f := range files {
+		f = filepath.ToSlash(f)
+		lang := languageOf(f)
+		if lang == langNone {
+			continue
+		}
+		if parsed == MaxFiles {
+			g.Partial = true
+			break
+		}
+		parsed++
+
+		seen := make(map[string]bool)
+		for _, target := range r.resolveFile(f, lang) {
+			if target == f || seen[target] {
+				continue
+			}
+			seen[target] = true
+			g.imports[f] = append(g.imports[f], target)
+			g.importedBy[target] = append(g.importedBy[target], f)
+		}
+	}
+	for _, m := range []map[string][]string{g.imports, g.importedBy} {
+		for k := range m {
+			sort.Strings(m[k])
+		}
+	}
+	return g
+}
+
+// resolveFile returns the project files imported by f
+func (r *resolver) resolveFile(f string, lang language) []string {
+	fullPath := filepath.Join(r.rootPath, f)
+	switch lang {
+	case langGo:
+		return r.resolveGo(fullPath)
+	case langJS:
+		return r.resolveJS(f, fullPath)
+	case langPython:
+		return r.resolvePython(f, fullPath)
+	}
+	return nil
+}
+
+// readModulePath returns the module path declared in a go.mod file
+func readModulePath(goModPath string) string {
+	file, err := os.Open(goModPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if mod, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`)
+		}
+	}
+	return ""
+}
+
+// resolveGo maps a Go file's package imports to the files of those packages
+func (r *resolver) resolveGo(fullPath string) []string {
+	parsed, err := parser.ParseFile(token.NewFileSet(), fullPath, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, spec := range parsed.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		for _, mod := range r.goModPaths {
+			if importPath != mod && !strings.HasPrefix(importPath, mod+"/") {
+				continue
+			}
+			dir := path.Join(r.goModules[mod], strings.TrimPrefix(importPath, mod))
+			out = append(out, r.dirFiles[path.Clean(dir)]...)
+			break
+		}
+	}
+	return out
+}
+
+// jsImportRe matches import/export-from statements, require() and dynamic import()
+var jsImportRe = regexp.MustCompile(`(?:\bfrom\s*|\bimport\s*\(?\s*|\brequire\s*\(\s*)['"]([^'"\n]+)['"]`)
+
+// resolveJS maps relative JS/TS import specifiers to project files
+// Bare specifiers (packages) and path aliases are not resolved.
+func (r *resolver) resolveJS(f, fullPath string) []string {
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, match := range jsImportRe.FindAllStringSubmatch(string(content), -1) {
+		spec := match[1]
+		if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+			continue
+		}
+		if target := r.resolveJSPath(path.Join(path.Dir(f), spec)); target != "" {
+			out = append(out, target)
+		}
+	}
+	return out
+}
+
+// resolveJSPath tries the path as-is, with each extension, then as a directory index
+func (r *resolver) resolveJSPath(base string) string {
+	if r.files[base] {
+		return base
+	}
+	// TypeScript sources are imported with a .js extension under ESM
+	stem := strings.TrimSuffix(base, path.Ext(base))
+	for _, ext := range jsExts {
+		if r.files[base+ext] {
+			return base + ext
+		}
+		if stem != base && r.files[stem+ext] {
+			return stem + ext
+		}
+	}
+	for _, ext := range jsExts {
+		if index := base + "/index" + ext; r.files[index] {
+			return index
+		}
+	}
+	return ""
+}
+
+var (
+	pyFromRe   = regexp.MustCompile(`^\s*from\s+(\.*)([\w.]*)\s+import\s+(.+)$`)
+	pyImportRe = regexp.MustCompile(`^\s*import\s+(.+)$`)
+)
+
+// resolvePython maps Python imports to project modules and packages
+// Absolute imports are looked up from the project root and a src/ layout.
+func (r *resolver) resolvePython(f, fullPath string) []string {
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var out []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := pyFromRe.FindStringSubmatch(line); match != nil {
+			dots, module := match[1], match[2]
+			var bases []string
+			if dots != "" {
+				// Relative: one dot is the current package, each further dot goes up
+				dir := path.Dir(f)
+				for i := 1; i < len(dots); i++ {
+					dir = path.Dir(dir)
+				}
+				bases = []string{dir}
+			} else {
+				bases = []string{".", "src"}
+			}
+			modPath := strings.ReplaceAll(module, ".", "/")
+			found := false
+			for _, base := range bases {
+				if target := r.resolvePythonModule(path.Join(base, modPath)); target != "" {
+					out = append(out, target)
+					found = true
+					break
+				}
+			}
+			// "from pkg import mod" may name submodules
+			if !found || strings.HasSuffix(out[len(out)-1], "__init__.py") {
+				for _, item := range strings.Split(strings.Trim(match[3], "() "), ",") {
+					fields := strings.Fields(item) // "name as alias"
+					if len(fields) == 0 {
+						continue
+					}
+					for _, base := range bases {
+						if target := r.resolvePythonModule(path.Join(base, modPath, fields[0])); target != "" {
+							out = append(out, target)
+							break
+						}
+					}
+				}
+			}
+			continue
+		}
+		if match := pyImportRe.FindStringSubmatch(line); match != nil {
+			for _, item := range strings.Split(match[1], ",") {
+				fields := strings.Fields(item)
+				if len(fields) == 0 {
+					continue
+				}
+				modPath := strings.ReplaceAll(fields[0], ".", "/")
+				for _, base := range []string{".", "src"} {
+					if target := r.resolvePythonModule(path.Join(base, modPath)); target != "" {
+						out = append(out, target)
+						break
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// resolvePythonModule finds mod.py or mod/__init__.py
+func (r *resolver) resolvePythonModule(mod string) string {
+	mod = path.Clean(mod)
+	if r.files[mod+".py"] {
+		return mod + ".py"
+	}
+	if init := mod + "/__init__.py"; r.files[init] {
+		return init
+	}
+	return ""
+}
@@ -0,0 +1,105 @@
+package notes
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/connorleisz/contexTUI/internal/atomicfile"
+)
+
+// FileName is where notes are kept, relative to the project root
+// Unlike visits, notes are meant to be committed and shared with the team.
+const FileName = ".contextui/notes.json"
+
+// Store holds short notes on files and directories, by slash-separated path
+// relative to the root
+type Store map[string]string
+
+// Load reads the project's notes, or returns an empty store if there are none
+func Load(rootPath string) Store {
+	s := make(Store)
+	data, err := os.ReadFile(filepath.Join(rootPath, FileName))
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return make(Store) // Malformed file, start over
+	}
+	return s
+}
+
+// Save writes the project's notes, removing the file once the last note is gone
+func Save(rootPath string, s Store) error {
+	path := filepath.Join(rootPath, FileName)
+	if len(s) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return atomicfile.Write(path, append(data, '\n'), false)
+}
+
+// key converts a path relative to the root to the store's form
+func key(relPath string) string {
+	return filepath.ToSlash(filepath.Clean(relPath))
+}
+
+// Get returns the note on a file or directory
+func (s Store) Get(relPath string) string {
+	return s[key(relPath)]
+}
+
+// Set attaches a note to a file or directory; an empty note removes it
+func (s Store) Set(relPath, note string) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(s, key(relPath))
+		return
+	}
+	s[key(relPath)] = note
+}
+
+// Nearest returns the note on relPath, or else on its closest directory that has
+// one, with the path it is attached to
+func (s Store) Nearest(relPath string) (string, string) {
+	for p := key(relPath); p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if note, ok := s[p]; ok {
+			return note, p
+		}
+	}
+	return "", ""
+}
+
+// Move re-attaches the notes on oldPath and everything below it to newPath after
+// a rename, or drops them when newPath is empty (deleted); it returns true if any
+// note changed
+func (s Store) Move(oldPath, newPath string) bool {
+	from := key(oldPath)
+	var moved []string
+	for p := range s {
+		if p == from || strings.HasPrefix(p, from+"/") {
+			moved = append(moved, p)
+		}
+	}
+	sort.Strings(moved)
+	for _, p := range moved {
+		note := s[p]
+		delete(s, p)
+		if newPath != "" {
+			s[key(newPath)+strings.TrimPrefix(p, from)] = note
+		}
+	}
+	return len(moved) > 0
+}
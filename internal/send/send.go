@@ -0,0 +1,77 @@
+package send
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotConfigured indicates no send target is set in the config
+var ErrNotConfigured = errors.New("no send target - set sendTmuxPane or sendFile in .contexTUI.json")
+
+// Target is where references are delivered instead of the clipboard
+// A tmux pane takes precedence over a file when both are set.
+type Target struct {
+	TmuxPane string // tmux target pane running the agent, e.g. "claude" or "main:1.0"
+	File     string // File the references are appended to (relative to the project root)
+}
+
+// Configured returns true if the target has somewhere to send to
+func (t Target) Configured() bool {
+	return t.TmuxPane != "" || t.File != ""
+}
+
+// String describes the target for status messages
+func (t Target) String() string {
+	if t.TmuxPane != "" {
+		return "tmux pane " + t.TmuxPane
+	}
+	return t.File
+}
+
+// Send delivers references (one per line) to the target
+func (t Target) Send(rootPath string, refs []string) error {
+	switch {
+	case t.TmuxPane != "":
+		return sendTmux(t.TmuxPane, refs)
+	case t.File != "":
+		path := t.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(rootPath, path)
+		}
+		return appendFile(path, refs)
+	}
+	return ErrNotConfigured
+}
+
+// sendTmux types the references into the pane's prompt without submitting it
+// They are space-separated since a newline would send the prompt.
+func sendTmux(pane string, refs []string) error {
+	text := strings.Join(refs, " ") + " "
+	cmd := exec.Command("tmux", "send-keys", "-t", pane, "-l", text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return errors.New(msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// appendFile appends the references to a file, creating it if needed
+func appendFile(path string, refs []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(strings.Join(refs, "\n") + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,48 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Run runs a shell command in dir, passing each line of its combined stdout/stderr
+// to output as it is printed. A non-nil error means the command failed or was stopped.
+func Run(ctx context.Context, dir, command string, output func(line string)) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = dir
+	cmd.WaitDelay = time.Second // Don't hang on background processes holding the output open
+
+	pr, pw := io.Pipe()
+	cmd.Stdout, cmd.Stderr = pw, pw
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		pw.Close()
+		done <- err
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		output(scanner.Text())
+	}
+	io.Copy(io.Discard, pr) // Past an overlong line, keep draining so the command can finish
+
+	err := <-done
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return err
+}
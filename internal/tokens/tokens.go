@@ -0,0 +1,24 @@
+package tokens
+
+import "fmt"
+
+// bytesPerToken is the average size of a token in English text and code
+const bytesPerToken = 4
+
+// Estimate returns the approximate number of tokens text takes in a prompt
+func Estimate(text string) int {
+	return len(text) / bytesPerToken
+}
+
+// EstimateSize returns the approximate number of tokens of a file of size bytes
+func EstimateSize(size int64) int {
+	return int(size / bytesPerToken)
+}
+
+// Format renders a token count compactly, e.g. ~850 tokens or ~12.4k tokens
+func Format(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("~%.1fk tokens", float64(n)/1000)
+	}
+	return fmt.Sprintf("~%d tokens", n)
+}
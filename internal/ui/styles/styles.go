@@ -1,40 +1,146 @@
 package styles
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // Color constants used throughout the UI
+// These are populated from the active theme by Apply
 var (
 	// Primary colors
-	Accent      = lipgloss.Color("205") // Pink/Magenta - primary accent
-	AccentAlt   = lipgloss.Color("141") // Purple - secondary accent
-	Success     = lipgloss.Color("118") // Green - success states
-	SuccessBold = lipgloss.Color("82")  // Bright green - strong success
-	Warning     = lipgloss.Color("214") // Orange - warnings
-	Error       = lipgloss.Color("196") // Red - errors, deletions
-	Info        = lipgloss.Color("75")  // Blue - informational
+	Accent      lipgloss.Color // Primary accent
+	AccentAlt   lipgloss.Color // Secondary accent
+	Success     lipgloss.Color // Success states
+	SuccessBold lipgloss.Color // Strong success
+	Warning     lipgloss.Color // Warnings
+	Error       lipgloss.Color // Errors, deletions
+	Info        lipgloss.Color // Informational
 
 	// Neutral colors
-	TextNormal   = lipgloss.Color("252") // Light gray - normal text
-	TextMuted    = lipgloss.Color("250") // Lighter gray - descriptions
-	TextFaint    = lipgloss.Color("244") // Gray - faint/disabled text
-	TextOnAccent = lipgloss.Color("0")   // Black - text on accent background
+	TextNormal   lipgloss.Color // Normal text
+	TextMuted    lipgloss.Color // Descriptions
+	TextFaint    lipgloss.Color // Faint/disabled text
+	TextOnAccent lipgloss.Color // Text on accent background
 
 	// Border colors
-	BorderActive   = lipgloss.Color("205") // Pink - active borders
-	BorderInactive = lipgloss.Color("240") // Dark gray - inactive borders
+	BorderActive   lipgloss.Color // Active borders
+	BorderInactive lipgloss.Color // Inactive borders
 
 	// Git status colors
-	GitModified  = lipgloss.Color("226") // Yellow
-	GitAdded     = lipgloss.Color("118") // Green
-	GitDeleted   = lipgloss.Color("196") // Red
-	GitRenamed   = lipgloss.Color("75")  // Blue
-	GitUntracked = lipgloss.Color("244") // Gray
-	GitConflict  = lipgloss.Color("196") // Red
+	GitModified  lipgloss.Color
+	GitAdded     lipgloss.Color
+	GitDeleted   lipgloss.Color
+	GitRenamed   lipgloss.Color
+	GitUntracked lipgloss.Color
+	GitConflict  lipgloss.Color
+	DiffHunk     lipgloss.Color
 )
 
 // Common style components
 var (
 	// Headers and titles
+	Header        lipgloss.Style
+	Title         lipgloss.Style
+	SectionHeader lipgloss.Style
+
+	// Text styles
+	Normal lipgloss.Style
+	Muted  lipgloss.Style
+	Faint  lipgloss.Style
+
+	// Selection and highlighting
+	Selected  lipgloss.Style
+	Highlight lipgloss.Style
+	Hover     lipgloss.Style // Row under the mouse pointer
+
+	// Status indicators
+	StatusSuccess lipgloss.Style
+	StatusWarning lipgloss.Style
+	StatusError   lipgloss.Style
+
+	// Keys in help text
+	Key lipgloss.Style
+
+	// Branch display
+	Branch lipgloss.Style
+)
+
+// current is the active theme
+var current Theme
+
+// accessible avoids signaling with color alone (see SetAccessible)
+var accessible bool
+
+func init() {
+	Apply(DarkTheme)
+}
+
+// Current returns the active theme
+func Current() Theme {
+	return current
+}
+
+// SetAccessible turns accessible mode on or off: selected rows are marked with "> "
+// instead of relying on a background color, and status badges are spelled out in text
+func SetAccessible(on bool) {
+	accessible = on
+	Apply(current)
+}
+
+// Accessible returns true if accessible mode is on
+func Accessible() bool {
+	return accessible
+}
+
+// Badge returns a status marker for display, bracketed in accessible mode so it
+// reads apart from the name next to it
+func Badge(s string) string {
+	if accessible {
+		return "[" + s + "]"
+	}
+	return s
+}
+
+// markSelected prefixes a selected row with "> ", taking the room from its leading
+// or trailing spaces so padded rows keep their width
+func markSelected(s string) string {
+	marked := "> " + strings.TrimPrefix(strings.TrimPrefix(s, " "), " ")
+	for extra := len(marked) - len(s); extra > 0 && strings.HasSuffix(marked, " "); extra-- {
+		marked = marked[:len(marked)-1]
+	}
+	return marked
+}
+
+// Apply makes t the active theme and rebuilds all shared styles from it
+func Apply(t Theme) {
+	current = t
+
+	Accent = t.Accent
+	AccentAlt = t.AccentAlt
+	Success = t.Success
+	SuccessBold = t.SuccessBold
+	Warning = t.Warning
+	Error = t.Error
+	Info = t.Info
+
+	TextNormal = t.TextNormal
+	TextMuted = t.TextMuted
+	TextFaint = t.TextFaint
+	TextOnAccent = t.TextOnAccent
+
+	BorderActive = t.BorderActive
+	BorderInactive = t.BorderInactive
+
+	GitModified = t.GitModified
+	GitAdded = t.GitAdded
+	GitDeleted = t.GitDeleted
+	GitRenamed = t.GitRenamed
+	GitUntracked = t.GitUntracked
+	GitConflict = t.GitConflict
+	DiffHunk = t.DiffHunk
+
 	Header = lipgloss.NewStyle().
 		Bold(true).
 		Foreground(Accent)
@@ -44,10 +150,9 @@ var (
 		Foreground(Accent)
 
 	SectionHeader = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(AccentAlt)
+		Bold(true).
+		Foreground(AccentAlt)
 
-	// Text styles
 	Normal = lipgloss.NewStyle().
 		Foreground(TextNormal)
 
@@ -57,35 +162,44 @@ var (
 	Faint = lipgloss.NewStyle().
 		Faint(true)
 
-	// Selection and highlighting
 	Selected = lipgloss.NewStyle().
-			Background(Accent).
-			Foreground(TextOnAccent)
+		Background(Accent).
+		Foreground(TextOnAccent)
 
 	Highlight = lipgloss.NewStyle().
-			Background(Accent).
-			Foreground(TextOnAccent)
+		Background(Accent).
+		Foreground(TextOnAccent)
+
+	Hover = lipgloss.NewStyle().
+		Underline(true)
+
+	// Reverse video and text markers still show without color (NO_COLOR)
+	if accessible {
+		Selected = lipgloss.NewStyle().
+			Bold(true).
+			Reverse(true).
+			Transform(markSelected)
+		Highlight = lipgloss.NewStyle().
+			Reverse(true)
+	}
 
-	// Status indicators
 	StatusSuccess = lipgloss.NewStyle().
-			Foreground(Success).
-			Bold(true)
+		Foreground(Success).
+		Bold(true)
 
 	StatusWarning = lipgloss.NewStyle().
-			Foreground(Warning)
+		Foreground(Warning)
 
 	StatusError = lipgloss.NewStyle().
-			Foreground(Error)
+		Foreground(Error)
 
-	// Keys in help text
 	Key = lipgloss.NewStyle().
 		Foreground(GitModified)
 
-	// Branch display
 	Branch = lipgloss.NewStyle().
 		Foreground(AccentAlt).
 		Bold(true)
-)
+}
 
 // Border styles for panes
 func ActiveBorder() lipgloss.Style {
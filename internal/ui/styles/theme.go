@@ -0,0 +1,252 @@
+package styles
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme defines the color scheme used by every style in the UI,
+// plus the chroma and glamour styles used for previews
+type Theme struct {
+	Name string `json:"name"`
+
+	// Primary colors
+	Accent      lipgloss.Color `json:"accent"`
+	AccentAlt   lipgloss.Color `json:"accentAlt"`
+	Success     lipgloss.Color `json:"success"`
+	SuccessBold lipgloss.Color `json:"successBold"`
+	Warning     lipgloss.Color `json:"warning"`
+	Error       lipgloss.Color `json:"error"`
+	Info        lipgloss.Color `json:"info"`
+
+	// Neutral colors
+	TextNormal   lipgloss.Color `json:"textNormal"`
+	TextMuted    lipgloss.Color `json:"textMuted"`
+	TextFaint    lipgloss.Color `json:"textFaint"`
+	TextOnAccent lipgloss.Color `json:"textOnAccent"`
+
+	// Border colors
+	BorderActive   lipgloss.Color `json:"borderActive"`
+	BorderInactive lipgloss.Color `json:"borderInactive"`
+
+	// Git status colors
+	GitModified  lipgloss.Color `json:"gitModified"`
+	GitAdded     lipgloss.Color `json:"gitAdded"`
+	GitDeleted   lipgloss.Color `json:"gitDeleted"`
+	GitRenamed   lipgloss.Color `json:"gitRenamed"`
+	GitUntracked lipgloss.Color `json:"gitUntracked"`
+	GitConflict  lipgloss.Color `json:"gitConflict"`
+	DiffHunk     lipgloss.Color `json:"diffHunk"`
+
+	// Preview renderers
+	ChromaStyle  string `json:"chromaStyle"`  // chroma style name (e.g. "monokai")
+	GlamourStyle string `json:"glamourStyle"` // glamour style name or "auto"
+}
+
+// Built-in theme names
+// ThemeAuto picks dark or light based on the terminal background
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+)
+
+// DarkTheme is the default theme (the original contexTUI palette)
+var DarkTheme = Theme{
+	Name:           ThemeDark,
+	Accent:         "205", // Pink/Magenta
+	AccentAlt:      "141", // Purple
+	Success:        "118", // Green
+	SuccessBold:    "82",  // Bright green
+	Warning:        "214", // Orange
+	Error:          "196", // Red
+	Info:           "75",  // Blue
+	TextNormal:     "252", // Light gray
+	TextMuted:      "250", // Lighter gray
+	TextFaint:      "244", // Gray
+	TextOnAccent:   "0",   // Black
+	BorderActive:   "205", // Pink
+	BorderInactive: "240", // Dark gray
+	GitModified:    "226", // Yellow
+	GitAdded:       "118", // Green
+	GitDeleted:     "196", // Red
+	GitRenamed:     "75",  // Blue
+	GitUntracked:   "244", // Gray
+	GitConflict:    "196", // Red
+	DiffHunk:       "81",  // Cyan
+	ChromaStyle:    "monokai",
+	GlamourStyle:   "auto",
+}
+
+// LightTheme is tuned for terminals with a light background
+var LightTheme = Theme{
+	Name:           ThemeLight,
+	Accent:         "162", // Deep pink
+	AccentAlt:      "91",  // Dark purple
+	Success:        "28",  // Dark green
+	SuccessBold:    "22",  // Darker green
+	Warning:        "130", // Brown/orange
+	Error:          "160", // Dark red
+	Info:           "25",  // Dark blue
+	TextNormal:     "235", // Near black
+	TextMuted:      "238", // Dark gray
+	TextFaint:      "242", // Mid gray
+	TextOnAccent:   "255", // White
+	BorderActive:   "162", // Deep pink
+	BorderInactive: "250", // Light gray
+	GitModified:    "136", // Dark yellow
+	GitAdded:       "28",  // Dark green
+	GitDeleted:     "160", // Dark red
+	GitRenamed:     "25",  // Dark blue
+	GitUntracked:   "242", // Mid gray
+	GitConflict:    "160", // Dark red
+	DiffHunk:       "31",  // Teal
+	ChromaStyle:    "github",
+	GlamourStyle:   "light",
+}
+
+// HighContrastTheme uses only bright, saturated colors for maximum legibility
+var HighContrastTheme = Theme{
+	Name:           ThemeHighContrast,
+	Accent:         "226", // Bright yellow
+	AccentAlt:      "51",  // Bright cyan
+	Success:        "46",  // Bright green
+	SuccessBold:    "46",  // Bright green
+	Warning:        "208", // Bright orange
+	Error:          "196", // Bright red
+	Info:           "51",  // Bright cyan
+	TextNormal:     "231", // White
+	TextMuted:      "231", // White
+	TextFaint:      "252", // Light gray
+	TextOnAccent:   "16",  // Black
+	BorderActive:   "226", // Bright yellow
+	BorderInactive: "250", // Light gray
+	GitModified:    "226", // Bright yellow
+	GitAdded:       "46",  // Bright green
+	GitDeleted:     "196", // Bright red
+	GitRenamed:     "51",  // Bright cyan
+	GitUntracked:   "252", // Light gray
+	GitConflict:    "201", // Bright magenta
+	DiffHunk:       "51",  // Bright cyan
+	ChromaStyle:    "native",
+	GlamourStyle:   "dark",
+}
+
+// darkBackground caches terminal background detection
+// The terminal is queried once, before the TUI takes over stdin
+var darkBackground = sync.OnceValue(lipgloss.HasDarkBackground)
+
+// AutoTheme returns the light theme on light terminals and the dark theme otherwise
+func AutoTheme() Theme {
+	if darkBackground() {
+		return DarkTheme
+	}
+	return LightTheme
+}
+
+// BuiltinThemes returns the built-in themes in display order
+func BuiltinThemes() []Theme {
+	return []Theme{DarkTheme, LightTheme, HighContrastTheme}
+}
+
+// UserThemeDir returns the directory scanned for user theme files
+func UserThemeDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "contexTUI", "themes")
+}
+
+// ListThemes returns the names of all available themes (built-in + user)
+func ListThemes() []string {
+	names := []string{ThemeAuto}
+	for _, t := range BuiltinThemes() {
+		names = append(names, t.Name)
+	}
+
+	dir := UserThemeDir()
+	if dir == "" {
+		return names
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+	var user []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			user = append(user, strings.TrimSuffix(f.Name(), ".json"))
+		}
+	}
+	sort.Strings(user)
+	return append(names, user...)
+}
+
+// LoadTheme resolves a theme by name or path
+// Built-in names resolve directly; anything else is looked up as a JSON file,
+// first as a path (relative to rootPath), then in the user theme directory.
+// An empty name means auto. Unknown or malformed themes fall back to auto.
+func LoadTheme(rootPath, name string) Theme {
+	if name == "" || name == ThemeAuto {
+		return AutoTheme()
+	}
+	for _, t := range BuiltinThemes() {
+		if t.Name == name {
+			return t
+		}
+	}
+
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = []string{filepath.Join(rootPath, name)}
+	}
+	if dir := UserThemeDir(); dir != "" {
+		candidates = append(candidates, filepath.Join(dir, name+".json"))
+	}
+
+	for _, path := range candidates {
+		if t, err := loadThemeFile(path); err == nil {
+			return t
+		}
+	}
+	return AutoTheme()
+}
+
+// loadThemeFile reads a user theme file
+// User themes may set "base" to a built-in theme and override only some colors
+func loadThemeFile(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var header struct {
+		Name string `json:"name"`
+		Base string `json:"base"`
+	}
+	json.Unmarshal(data, &header)
+
+	theme := AutoTheme()
+	for _, t := range BuiltinThemes() {
+		if t.Name == header.Base {
+			theme = t
+		}
+	}
+
+	// Unmarshal over the base so unset fields keep the base's values
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, err
+	}
+	if header.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+	}
+	return theme, nil
+}
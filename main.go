@@ -1,35 +1,192 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/connorleisz/contexTUI/internal/app"
+	"github.com/connorleisz/contexTUI/internal/config"
+	"github.com/connorleisz/contexTUI/internal/control"
+	"github.com/connorleisz/contexTUI/internal/debuglog"
+	"github.com/connorleisz/contexTUI/internal/groups"
 	"github.com/muesli/termenv"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		os.Exit(runInit(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "reveal" {
+		os.Exit(runReveal(os.Args[2:]))
+	}
+
+	noMouse := flag.Bool("no-mouse", false, "don't capture the mouse (keeps terminal/tmux selection working)")
+	noAltScreen := flag.Bool("no-altscreen", false, "render in the main screen so output stays in scrollback")
+	tmux := flag.Bool("tmux", false, "shorthand for -no-mouse -no-altscreen")
+	noWatch := flag.Bool("no-watch", false, "don't watch the filesystem for changes")
+	readOnly := flag.Bool("read-only", false, "disable every change to files, docs and the repository (or \"readOnly\": true in the config)")
+	confirm := flag.String("confirm", "", "when to ask before a change: `policy` destructive (default), always or never")
+	accessible := flag.Bool("accessible", false, "mark selected rows with > and statuses with text instead of color alone (on with NO_COLOR)")
+	theme := flag.String("theme", "", "color `theme` to use instead of the configured one (auto, dark, light, high-contrast or a user theme)")
+	selectPath := flag.String("select", "", "select and preview `file` on startup")
+	choose := flag.Bool("choose", false, "print the path picked with enter to stdout on exit (the UI is drawn on stderr)")
+	chooseDir := flag.Bool("choose-dir", false, "like -choose, picking a directory (a file picks its parent), e.g. cd \"$(contexTUI -choose-dir)\"")
+	export := flag.String("export", "", "update the context docs section of `file` (e.g. CLAUDE.md, AGENTS.md) and exit")
+	socket := flag.String("socket", "", "accept JSON-RPC calls (select, copyGroup, refreshGit, state) on the unix socket `path` while running")
+	listen := flag.Bool("listen", false, "like -socket, on a socket for this project that \"contexTUI reveal <file>\" finds")
+	logFile := flag.Bool("log", false, "write watcher events, git commands and errors to a log file for this session (its path is shown with I)")
+	printGroup := flag.String("print-group", "", "print the @ references of a doc category or doc `name` and its key files, and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [path...]\n       %s check [flags] [path]\n       %s init [path]\n       %s reveal [flags] path\n\nPaths after the first are shown as more top-level folders of the tree.\nFile paths piped in on stdin (one per line, e.g. from rg -l or fzf -m) are added to the basket.\n\n", os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// When choosing, stdout carries the result so the UI goes to stderr
+	choosing := *choose || *chooseDir
+	if choosing {
+		lipgloss.DefaultRenderer().SetOutput(termenv.NewOutput(os.Stderr))
+	}
+
 	// Respect NO_COLOR environment variable (https://no-color.org/)
 	if os.Getenv("NO_COLOR") != "" {
 		lipgloss.SetColorProfile(termenv.Ascii)
 	}
 
-	// Default to current directory if no arg provided
+	// Default to current directory if no arg provided; more paths join the tree
 	rootPath := "."
-	if len(os.Args) > 1 {
-		rootPath = os.Args[1]
+	var roots []string
+	if flag.NArg() > 0 {
+		rootPath = flag.Arg(0)
+		roots = flag.Args()[1:]
+	}
+
+	if *export != "" {
+		registry, err := groups.LoadContextDocRegistry(rootPath)
+		if err == nil {
+			_, err = groups.ExportAgentsFile(rootPath, *export, registry, config.Load(rootPath).BackupFiles)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", *export, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *printGroup != "" {
+		registry, err := groups.LoadContextDocRegistry(rootPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading context docs: %v\n", err)
+			os.Exit(1)
+		}
+		docs := registry.FindGroup(*printGroup)
+		if len(docs) == 0 {
+			fmt.Fprintf(os.Stderr, "No category or doc named %q\n", *printGroup)
+			os.Exit(1)
+		}
+		for _, ref := range groups.GroupRefs(rootPath, docs) {
+			fmt.Println(ref)
+		}
+		return
+	}
+
+	// Paths piped in on stdin are staged; keys then come from the terminal
+	var stage []string
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		stage = readPaths(os.Stdin)
+	}
+
+	if *confirm != "" && !config.ValidConfirm(*confirm) {
+		fmt.Fprintf(os.Stderr, "Invalid -confirm %q (want destructive, always or never)\n", *confirm)
+		os.Exit(2)
+	}
+
+	// Flags override the per-project config
+	cfg := config.Load(rootPath)
+	var opts []tea.ProgramOption
+	if !*noAltScreen && !*tmux && !cfg.NoAltScreen {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	if !*noMouse && !*tmux && !cfg.NoMouse {
+		opts = append(opts, tea.WithMouseAllMotion())
+	}
+	// Focus events refresh git status after git commands run in another window
+	opts = append(opts, tea.WithReportFocus())
+	if choosing {
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
+	if stage != nil {
+		opts = append(opts, tea.WithInputTTY())
+	}
+
+	if *logFile {
+		if _, err := debuglog.Open(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening log file: %v\n", err)
+			os.Exit(1)
+		}
+		defer debuglog.Close()
 	}
 
-	p := tea.NewProgram(
-		app.NewModel(rootPath),
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
+	debuglog.Report("save recent projects", config.AddRecentProject(rootPath))
+	p := tea.NewProgram(app.NewModelWithOptions(rootPath, app.Options{
+		NoWatch:    *noWatch,
+		ReadOnly:   *readOnly,
+		Confirm:    *confirm,
+		Theme:      *theme,
+		Accessible: *accessible || os.Getenv("NO_COLOR") != "",
+		Select:     *selectPath,
+		Stage:      stage,
+		Roots:      roots,
+		Choose:     *choose,
+		ChooseDir:  *chooseDir,
+	}), opts...)
 
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
+	// Scripts and editors drive the session through the control socket
+	if *listen && *socket == "" {
+		*socket = control.SocketPath(rootPath)
+	}
+	if *socket != "" {
+		srv, err := control.Listen(*socket)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening control socket: %v\n", err)
+			os.Exit(1)
+		}
+		defer srv.Close()
+		go srv.Serve(func(msg any) { p.Send(msg) })
+	}
+
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
+	if choosing {
+		// Cancelling prints nothing and fails so shell functions can tell
+		m, ok := final.(app.Model)
+		if !ok || m.Chosen() == "" {
+			os.Exit(1)
+		}
+		fmt.Println(m.Chosen())
+	}
+}
+
+// readPaths reads one path per line, skipping blank lines
+func readPaths(r io.Reader) []string {
+	paths := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/connorleisz/contexTUI/internal/control"
+)
+
+// runReveal tells a running session to select and preview a file, returning the exit code
+func runReveal(args []string) int {
+	fs := flag.NewFlagSet("reveal", flag.ExitOnError)
+	socket := fs.String("socket", "", "socket `path` of the session (default: the -listen session of a project holding the file)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s reveal [flags] path\n\nSelects and previews path in a running session started with -listen (or -socket).\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	path, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if *socket == "" {
+		found, ok := control.Find(path)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "No session is listening for %s (start one with -listen)\n", fs.Arg(0))
+			return 1
+		}
+		*socket = found
+	}
+	if _, err := control.Call(*socket, "select", map[string]string{"path": path}); err != nil {
+		fmt.Fprintf(os.Stderr, "Error revealing %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+	return 0
+}